@@ -11,6 +11,7 @@ import (
 
 	_ "github.com/gogo/protobuf/gogoproto"
 	proto "github.com/golang/protobuf/proto"
+	authpb "go.etcd.io/etcd/api/v3/authpb"
 	membershippb "go.etcd.io/etcd/api/v3/membershippb"
 	_ "go.etcd.io/etcd/api/v3/versionpb"
 )
@@ -31,10 +32,21 @@ type RequestHeader struct {
 	// username is a username that is associated with an auth token of gRPC connection
 	Username string `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
 	// auth_revision is a revision number of auth.authStore. It is not related to mvcc
-	AuthRevision         uint64   `protobuf:"varint,3,opt,name=auth_revision,json=authRevision,proto3" json:"auth_revision,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	AuthRevision uint64 `protobuf:"varint,3,opt,name=auth_revision,json=authRevision,proto3" json:"auth_revision,omitempty"`
+	// timestamp is the unix time, in seconds, at which the request was proposed.
+	// It is only set for requests putting keys or granting roles with a ttl, which
+	// is counted from it, and for requests of authenticated users, whose roles
+	// granted with a ttl are checked at it.
+	Timestamp int64 `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// roles are the roles granted to the user by an external identity provider,
+	// in addition to the roles of the user in etcd.
+	Roles []string `protobuf:"bytes,5,rep,name=roles,proto3" json:"roles,omitempty"`
+	// scope restricts the token of the user to a subset of its roles, and to the
+	// keys under a prefix.
+	Scope                *authpb.TokenScope `protobuf:"bytes,6,opt,name=scope,proto3" json:"scope,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
 }
 
 func (m *RequestHeader) Reset()         { *m = RequestHeader{} }
@@ -85,10 +97,14 @@ type InternalRaftRequest struct {
 	LeaseRevoke              *LeaseRevokeRequest                       `protobuf:"bytes,9,opt,name=lease_revoke,json=leaseRevoke,proto3" json:"lease_revoke,omitempty"`
 	Alarm                    *AlarmRequest                             `protobuf:"bytes,10,opt,name=alarm,proto3" json:"alarm,omitempty"`
 	LeaseCheckpoint          *LeaseCheckpointRequest                   `protobuf:"bytes,11,opt,name=lease_checkpoint,json=leaseCheckpoint,proto3" json:"lease_checkpoint,omitempty"`
+	KeyExpire                *KeyExpireRequest                         `protobuf:"bytes,12,opt,name=key_expire,json=keyExpire,proto3" json:"key_expire,omitempty"`
 	AuthEnable               *AuthEnableRequest                        `protobuf:"bytes,1000,opt,name=auth_enable,json=authEnable,proto3" json:"auth_enable,omitempty"`
 	AuthDisable              *AuthDisableRequest                       `protobuf:"bytes,1011,opt,name=auth_disable,json=authDisable,proto3" json:"auth_disable,omitempty"`
 	AuthStatus               *AuthStatusRequest                        `protobuf:"bytes,1013,opt,name=auth_status,json=authStatus,proto3" json:"auth_status,omitempty"`
 	Authenticate             *InternalAuthenticateRequest              `protobuf:"bytes,1012,opt,name=authenticate,proto3" json:"authenticate,omitempty"`
+	AuthTokenList            *AuthTokenListRequest                     `protobuf:"bytes,1014,opt,name=auth_token_list,json=authTokenList,proto3" json:"auth_token_list,omitempty"`
+	AuthTokenRevoke          *AuthTokenRevokeRequest                   `protobuf:"bytes,1015,opt,name=auth_token_revoke,json=authTokenRevoke,proto3" json:"auth_token_revoke,omitempty"`
+	AuthRoleGrantExpire      *AuthRoleGrantExpireRequest               `protobuf:"bytes,1016,opt,name=auth_role_grant_expire,json=authRoleGrantExpire,proto3" json:"auth_role_grant_expire,omitempty"`
 	AuthUserAdd              *AuthUserAddRequest                       `protobuf:"bytes,1100,opt,name=auth_user_add,json=authUserAdd,proto3" json:"auth_user_add,omitempty"`
 	AuthUserDelete           *AuthUserDeleteRequest                    `protobuf:"bytes,1101,opt,name=auth_user_delete,json=authUserDelete,proto3" json:"auth_user_delete,omitempty"`
 	AuthUserGet              *AuthUserGetRequest                       `protobuf:"bytes,1102,opt,name=auth_user_get,json=authUserGet,proto3" json:"auth_user_get,omitempty"`
@@ -97,11 +113,13 @@ type InternalRaftRequest struct {
 	AuthUserRevokeRole       *AuthUserRevokeRoleRequest                `protobuf:"bytes,1105,opt,name=auth_user_revoke_role,json=authUserRevokeRole,proto3" json:"auth_user_revoke_role,omitempty"`
 	AuthUserList             *AuthUserListRequest                      `protobuf:"bytes,1106,opt,name=auth_user_list,json=authUserList,proto3" json:"auth_user_list,omitempty"`
 	AuthRoleList             *AuthRoleListRequest                      `protobuf:"bytes,1107,opt,name=auth_role_list,json=authRoleList,proto3" json:"auth_role_list,omitempty"`
+	AuthUserSetLimits        *AuthUserSetLimitsRequest                 `protobuf:"bytes,1108,opt,name=auth_user_set_limits,json=authUserSetLimits,proto3" json:"auth_user_set_limits,omitempty"`
 	AuthRoleAdd              *AuthRoleAddRequest                       `protobuf:"bytes,1200,opt,name=auth_role_add,json=authRoleAdd,proto3" json:"auth_role_add,omitempty"`
 	AuthRoleDelete           *AuthRoleDeleteRequest                    `protobuf:"bytes,1201,opt,name=auth_role_delete,json=authRoleDelete,proto3" json:"auth_role_delete,omitempty"`
 	AuthRoleGet              *AuthRoleGetRequest                       `protobuf:"bytes,1202,opt,name=auth_role_get,json=authRoleGet,proto3" json:"auth_role_get,omitempty"`
 	AuthRoleGrantPermission  *AuthRoleGrantPermissionRequest           `protobuf:"bytes,1203,opt,name=auth_role_grant_permission,json=authRoleGrantPermission,proto3" json:"auth_role_grant_permission,omitempty"`
 	AuthRoleRevokePermission *AuthRoleRevokePermissionRequest          `protobuf:"bytes,1204,opt,name=auth_role_revoke_permission,json=authRoleRevokePermission,proto3" json:"auth_role_revoke_permission,omitempty"`
+	AuthRoleSetLimits        *AuthRoleSetLimitsRequest                 `protobuf:"bytes,1205,opt,name=auth_role_set_limits,json=authRoleSetLimits,proto3" json:"auth_role_set_limits,omitempty"`
 	ClusterVersionSet        *membershippb.ClusterVersionSetRequest    `protobuf:"bytes,1300,opt,name=cluster_version_set,json=clusterVersionSet,proto3" json:"cluster_version_set,omitempty"`
 	ClusterMemberAttrSet     *membershippb.ClusterMemberAttrSetRequest `protobuf:"bytes,1301,opt,name=cluster_member_attr_set,json=clusterMemberAttrSet,proto3" json:"cluster_member_attr_set,omitempty"`
 	DowngradeInfoSet         *membershippb.DowngradeInfoSetRequest     `protobuf:"bytes,1302,opt,name=downgrade_info_set,json=downgradeInfoSet,proto3" json:"downgrade_info_set,omitempty"`
@@ -182,6 +200,176 @@ func (m *EmptyResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_EmptyResponse proto.InternalMessageInfo
 
+// KeyExpireRequest deletes the keys whose ttl expired. It is proposed by the leader.
+type KeyExpireRequest struct {
+	Keys                 []*ExpiredKey `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *KeyExpireRequest) Reset()         { *m = KeyExpireRequest{} }
+func (m *KeyExpireRequest) String() string { return proto.CompactTextString(m) }
+func (*KeyExpireRequest) ProtoMessage()    {}
+func (*KeyExpireRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b4c9a9be0cfca103, []int{3}
+}
+func (m *KeyExpireRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *KeyExpireRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_KeyExpireRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *KeyExpireRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_KeyExpireRequest.Merge(m, src)
+}
+func (m *KeyExpireRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *KeyExpireRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_KeyExpireRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_KeyExpireRequest proto.InternalMessageInfo
+
+type ExpiredKey struct {
+	Key []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// expire_time is the expire time of the key when it was found expired. The key
+	// is not deleted if it was put with another ttl since.
+	ExpireTime           int64    `protobuf:"varint,2,opt,name=expire_time,json=expireTime,proto3" json:"expire_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExpiredKey) Reset()         { *m = ExpiredKey{} }
+func (m *ExpiredKey) String() string { return proto.CompactTextString(m) }
+func (*ExpiredKey) ProtoMessage()    {}
+func (*ExpiredKey) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b4c9a9be0cfca103, []int{4}
+}
+func (m *ExpiredKey) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ExpiredKey) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ExpiredKey.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ExpiredKey) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExpiredKey.Merge(m, src)
+}
+func (m *ExpiredKey) XXX_Size() int {
+	return m.Size()
+}
+func (m *ExpiredKey) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExpiredKey.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExpiredKey proto.InternalMessageInfo
+
+// AuthRoleGrantExpireRequest revokes the roles whose grant expired. It is
+// proposed by the leader.
+type AuthRoleGrantExpireRequest struct {
+	Grants               []*ExpiredRoleGrant `protobuf:"bytes,1,rep,name=grants,proto3" json:"grants,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *AuthRoleGrantExpireRequest) Reset()         { *m = AuthRoleGrantExpireRequest{} }
+func (m *AuthRoleGrantExpireRequest) String() string { return proto.CompactTextString(m) }
+func (*AuthRoleGrantExpireRequest) ProtoMessage()    {}
+func (*AuthRoleGrantExpireRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b4c9a9be0cfca103, []int{5}
+}
+func (m *AuthRoleGrantExpireRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *AuthRoleGrantExpireRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_AuthRoleGrantExpireRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *AuthRoleGrantExpireRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuthRoleGrantExpireRequest.Merge(m, src)
+}
+func (m *AuthRoleGrantExpireRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *AuthRoleGrantExpireRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuthRoleGrantExpireRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuthRoleGrantExpireRequest proto.InternalMessageInfo
+
+type ExpiredRoleGrant struct {
+	User string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Role string `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	// expire_time is the expire time of the grant when it was found expired. The
+	// role is not revoked if it was granted again with another ttl since.
+	ExpireTime           int64    `protobuf:"varint,3,opt,name=expire_time,json=expireTime,proto3" json:"expire_time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExpiredRoleGrant) Reset()         { *m = ExpiredRoleGrant{} }
+func (m *ExpiredRoleGrant) String() string { return proto.CompactTextString(m) }
+func (*ExpiredRoleGrant) ProtoMessage()    {}
+func (*ExpiredRoleGrant) Descriptor() ([]byte, []int) {
+	return fileDescriptor_b4c9a9be0cfca103, []int{6}
+}
+func (m *ExpiredRoleGrant) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ExpiredRoleGrant) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ExpiredRoleGrant.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ExpiredRoleGrant) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExpiredRoleGrant.Merge(m, src)
+}
+func (m *ExpiredRoleGrant) XXX_Size() int {
+	return m.Size()
+}
+func (m *ExpiredRoleGrant) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExpiredRoleGrant.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExpiredRoleGrant proto.InternalMessageInfo
+
 // What is the difference between AuthenticateRequest (defined in rpc.proto) and InternalAuthenticateRequest?
 // InternalAuthenticateRequest has a member that is filled by etcdserver and shouldn't be user-facing.
 // For avoiding misusage the field, we have an internal version of AuthenticateRequest.
@@ -189,17 +377,27 @@ type InternalAuthenticateRequest struct {
 	Name     string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	Password string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
 	// simple_token is generated in API layer (etcdserver/v3_server.go)
-	SimpleToken          string   `protobuf:"bytes,3,opt,name=simple_token,json=simpleToken,proto3" json:"simple_token,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	SimpleToken string `protobuf:"bytes,3,opt,name=simple_token,json=simpleToken,proto3" json:"simple_token,omitempty"`
+	// hashed_password is the password of the user rehashed in API layer with the
+	// current algorithm and parameters. It replaces the stored hash only if the
+	// auth revision is still the one the password was checked at.
+	HashedPassword          []byte                   `protobuf:"bytes,4,opt,name=hashed_password,json=hashedPassword,proto3" json:"hashed_password,omitempty"`
+	HashedPasswordAlgorithm authpb.PasswordAlgorithm `protobuf:"varint,5,opt,name=hashed_password_algorithm,json=hashedPasswordAlgorithm,proto3,enum=authpb.PasswordAlgorithm" json:"hashed_password_algorithm,omitempty"`
+	AuthRevision            uint64                   `protobuf:"varint,6,opt,name=auth_revision,json=authRevision,proto3" json:"auth_revision,omitempty"`
+	// created, address and scope describe the simple token, see AuthToken.
+	Created              int64              `protobuf:"varint,7,opt,name=created,proto3" json:"created,omitempty"`
+	Address              string             `protobuf:"bytes,8,opt,name=address,proto3" json:"address,omitempty"`
+	Scope                *authpb.TokenScope `protobuf:"bytes,9,opt,name=scope,proto3" json:"scope,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
 }
 
 func (m *InternalAuthenticateRequest) Reset()         { *m = InternalAuthenticateRequest{} }
 func (m *InternalAuthenticateRequest) String() string { return proto.CompactTextString(m) }
 func (*InternalAuthenticateRequest) ProtoMessage()    {}
 func (*InternalAuthenticateRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_b4c9a9be0cfca103, []int{3}
+	return fileDescriptor_b4c9a9be0cfca103, []int{7}
 }
 func (m *InternalAuthenticateRequest) XXX_Unmarshal(b []byte) error {
 	return m.Unmarshal(b)
@@ -232,79 +430,110 @@ func init() {
 	proto.RegisterType((*RequestHeader)(nil), "etcdserverpb.RequestHeader")
 	proto.RegisterType((*InternalRaftRequest)(nil), "etcdserverpb.InternalRaftRequest")
 	proto.RegisterType((*EmptyResponse)(nil), "etcdserverpb.EmptyResponse")
+	proto.RegisterType((*KeyExpireRequest)(nil), "etcdserverpb.KeyExpireRequest")
+	proto.RegisterType((*ExpiredKey)(nil), "etcdserverpb.ExpiredKey")
+	proto.RegisterType((*AuthRoleGrantExpireRequest)(nil), "etcdserverpb.AuthRoleGrantExpireRequest")
+	proto.RegisterType((*ExpiredRoleGrant)(nil), "etcdserverpb.ExpiredRoleGrant")
 	proto.RegisterType((*InternalAuthenticateRequest)(nil), "etcdserverpb.InternalAuthenticateRequest")
 }
 
 func init() { proto.RegisterFile("raft_internal.proto", fileDescriptor_b4c9a9be0cfca103) }
 
 var fileDescriptor_b4c9a9be0cfca103 = []byte{
-	// 1054 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x56, 0x5d, 0x6f, 0x1b, 0x45,
-	0x14, 0xad, 0xd3, 0x34, 0x89, 0xc7, 0x49, 0x9a, 0x4e, 0x52, 0x3a, 0x38, 0x92, 0x71, 0x03, 0x2d,
-	0x01, 0x8a, 0x53, 0x1c, 0x78, 0xe1, 0x05, 0x5c, 0x3b, 0x4a, 0x83, 0x4a, 0x15, 0x6d, 0x0b, 0xaa,
-	0x84, 0xd0, 0x32, 0xde, 0xbd, 0xb1, 0xb7, 0x59, 0xef, 0x2e, 0x33, 0x63, 0x37, 0x7d, 0xe5, 0x91,
-	0x67, 0x40, 0xfc, 0x0c, 0x3e, 0xff, 0x43, 0x85, 0xf8, 0x28, 0xf0, 0x07, 0x20, 0xbc, 0xf0, 0x0e,
-	0xbc, 0xa3, 0xf9, 0xd8, 0x5d, 0xaf, 0x3d, 0xce, 0xdb, 0xfa, 0xde, 0x73, 0xcf, 0x39, 0x33, 0x73,
-	0xef, 0x78, 0xd0, 0x3a, 0xa3, 0x47, 0xc2, 0x0d, 0x22, 0x01, 0x2c, 0xa2, 0x61, 0x23, 0x61, 0xb1,
-	0x88, 0xf1, 0x32, 0x08, 0xcf, 0xe7, 0xc0, 0x46, 0xc0, 0x92, 0x6e, 0x75, 0xa3, 0x17, 0xf7, 0x62,
-	0x95, 0xd8, 0x91, 0x5f, 0x1a, 0x53, 0x5d, 0xcb, 0x31, 0x26, 0x52, 0x66, 0x89, 0x67, 0x3e, 0xeb,
-	0x32, 0xb9, 0x43, 0x93, 0x60, 0x67, 0x04, 0x8c, 0x07, 0x71, 0x94, 0x74, 0xd3, 0x2f, 0x83, 0xb8,
-	0x9e, 0x21, 0x06, 0x30, 0xe8, 0x02, 0xe3, 0xfd, 0x20, 0x49, 0xba, 0x63, 0x3f, 0x34, 0x6e, 0x8b,
-	0xa1, 0x15, 0x07, 0x3e, 0x1e, 0x02, 0x17, 0xb7, 0x81, 0xfa, 0xc0, 0xf0, 0x2a, 0x9a, 0x3b, 0xe8,
-	0x90, 0x52, 0xbd, 0xb4, 0x3d, 0xef, 0xcc, 0x1d, 0x74, 0x70, 0x15, 0x2d, 0x0d, 0xb9, 0x34, 0x3f,
-	0x00, 0x32, 0x57, 0x2f, 0x6d, 0x97, 0x9d, 0xec, 0x37, 0xbe, 0x81, 0x56, 0xe8, 0x50, 0xf4, 0x5d,
-	0x06, 0xa3, 0x40, 0x6a, 0x93, 0xf3, 0xb2, 0xec, 0xd6, 0xe2, 0xa7, 0xdf, 0x93, 0xf3, 0xbb, 0x8d,
-	0xd7, 0x9c, 0x65, 0x99, 0x75, 0x4c, 0xf2, 0xcd, 0xc5, 0x4f, 0x54, 0xf8, 0xe6, 0xd6, 0x0f, 0x18,
-	0xad, 0x1f, 0x98, 0x1d, 0x71, 0xe8, 0x91, 0x30, 0x06, 0xf0, 0x2e, 0x5a, 0xe8, 0x2b, 0x13, 0xc4,
-	0xaf, 0x97, 0xb6, 0x2b, 0xcd, 0xcd, 0xc6, 0xf8, 0x3e, 0x35, 0x0a, 0x3e, 0x1d, 0x03, 0x9d, 0xf2,
-	0x7b, 0x0d, 0xcd, 0x8d, 0x9a, 0xca, 0x69, 0xa5, 0x79, 0xd9, 0x4a, 0xe0, 0xcc, 0x8d, 0x9a, 0xf8,
-	0x26, 0xba, 0xc0, 0x68, 0xd4, 0x03, 0x65, 0xb9, 0xd2, 0xac, 0x4e, 0x20, 0x65, 0x2a, 0x85, 0x6b,
-	0x20, 0x7e, 0x19, 0x9d, 0x4f, 0x86, 0x82, 0xcc, 0x2b, 0x3c, 0x29, 0xe2, 0x0f, 0x87, 0xe9, 0x22,
-	0x1c, 0x09, 0xc2, 0x6d, 0xb4, 0xec, 0x43, 0x08, 0x02, 0x5c, 0x2d, 0x72, 0x41, 0x15, 0xd5, 0x8b,
-	0x45, 0x1d, 0x85, 0x28, 0x48, 0x55, 0xfc, 0x3c, 0x26, 0x05, 0xc5, 0x49, 0x44, 0x16, 0x6c, 0x82,
-	0xf7, 0x4f, 0xa2, 0x4c, 0x50, 0x9c, 0x44, 0xf8, 0x2d, 0x84, 0xbc, 0x78, 0x90, 0x50, 0x4f, 0xc8,
-	0x63, 0x58, 0x54, 0x25, 0xcf, 0x15, 0x4b, 0xda, 0x59, 0x3e, 0xad, 0x1c, 0x2b, 0xc1, 0x6f, 0xa3,
-	0x4a, 0x08, 0x94, 0x83, 0xdb, 0x63, 0x34, 0x12, 0x64, 0xc9, 0xc6, 0x70, 0x47, 0x02, 0xf6, 0x65,
-	0x3e, 0x63, 0x08, 0xb3, 0x90, 0x5c, 0xb3, 0x66, 0x60, 0x30, 0x8a, 0x8f, 0x81, 0x94, 0x6d, 0x6b,
-	0x56, 0x14, 0x8e, 0x02, 0x64, 0x6b, 0x0e, 0xf3, 0x98, 0x3c, 0x16, 0x1a, 0x52, 0x36, 0x20, 0xc8,
-	0x76, 0x2c, 0x2d, 0x99, 0xca, 0x8e, 0x45, 0x01, 0xf1, 0x03, 0xb4, 0xa6, 0x65, 0xbd, 0x3e, 0x78,
-	0xc7, 0x49, 0x1c, 0x44, 0x82, 0x54, 0x54, 0xf1, 0x0b, 0x16, 0xe9, 0x76, 0x06, 0x32, 0x34, 0x69,
-	0xb3, 0xbe, 0xee, 0x5c, 0x0c, 0x8b, 0x00, 0xdc, 0x42, 0x15, 0xd5, 0xdd, 0x10, 0xd1, 0x6e, 0x08,
-	0xe4, 0x6f, 0xeb, 0xae, 0xb6, 0x86, 0xa2, 0xbf, 0xa7, 0x00, 0xd9, 0x9e, 0xd0, 0x2c, 0x84, 0x3b,
-	0x48, 0x8d, 0x80, 0xeb, 0x07, 0x5c, 0x71, 0xfc, 0xb3, 0x68, 0xdb, 0x14, 0xc9, 0xd1, 0xd1, 0x88,
-	0x6c, 0x53, 0x68, 0x1e, 0xc3, 0xef, 0x18, 0x23, 0x5c, 0x50, 0x31, 0xe4, 0xe4, 0xbf, 0x99, 0x46,
-	0xee, 0x29, 0xc0, 0xc4, 0xca, 0xde, 0xd0, 0x8e, 0x74, 0x0e, 0xdf, 0xd5, 0x8e, 0x20, 0x12, 0x81,
-	0x47, 0x05, 0x90, 0x7f, 0x35, 0xd9, 0x4b, 0x45, 0xb2, 0x74, 0x3a, 0x5b, 0x63, 0xd0, 0xd4, 0x5a,
-	0xa1, 0x1e, 0xef, 0x99, 0x2b, 0x40, 0xde, 0x09, 0x2e, 0xf5, 0x7d, 0xf2, 0xe3, 0xd2, 0xac, 0x25,
-	0xbe, 0xc7, 0x81, 0xb5, 0x7c, 0xbf, 0xb0, 0x44, 0x13, 0xc3, 0x77, 0xd1, 0x5a, 0x4e, 0xa3, 0x87,
-	0x80, 0xfc, 0xa4, 0x99, 0x9e, 0xb7, 0x33, 0x99, 0xe9, 0x31, 0x64, 0xab, 0xb4, 0x10, 0x2e, 0xda,
-	0xea, 0x81, 0x20, 0x3f, 0x9f, 0x69, 0x6b, 0x1f, 0xc4, 0x94, 0xad, 0x7d, 0x10, 0xb8, 0x87, 0x9e,
-	0xcd, 0x69, 0xbc, 0xbe, 0x1c, 0x4b, 0x37, 0xa1, 0x9c, 0x3f, 0x8a, 0x99, 0x4f, 0x7e, 0xd1, 0x94,
-	0xaf, 0xd8, 0x29, 0xdb, 0x0a, 0x7d, 0x68, 0xc0, 0x29, 0xfb, 0x33, 0xd4, 0x9a, 0xc6, 0x0f, 0xd0,
-	0xc6, 0x98, 0x5f, 0x39, 0x4f, 0x2e, 0x8b, 0x43, 0x20, 0x4f, 0xb5, 0xc6, 0xf5, 0x19, 0xb6, 0xd5,
-	0x2c, 0xc6, 0x79, 0xdb, 0x5c, 0xa2, 0x93, 0x19, 0xfc, 0x01, 0xba, 0x9c, 0x33, 0xeb, 0xd1, 0xd4,
-	0xd4, 0xbf, 0x6a, 0xea, 0x17, 0xed, 0xd4, 0x66, 0x46, 0xc7, 0xb8, 0x31, 0x9d, 0x4a, 0xe1, 0xdb,
-	0x68, 0x35, 0x27, 0x0f, 0x03, 0x2e, 0xc8, 0x6f, 0x9a, 0xf5, 0xaa, 0x9d, 0xf5, 0x4e, 0xc0, 0x45,
-	0xa1, 0x8f, 0xd2, 0x60, 0xc6, 0x24, 0xad, 0x69, 0xa6, 0xdf, 0x67, 0x32, 0x49, 0xe9, 0x29, 0xa6,
-	0x34, 0x98, 0x1d, 0xbd, 0x62, 0x92, 0x1d, 0xf9, 0x55, 0x79, 0xd6, 0xd1, 0xcb, 0x9a, 0xc9, 0x8e,
-	0x34, 0xb1, 0xac, 0x23, 0x15, 0x8d, 0xe9, 0xc8, 0xaf, 0xcb, 0xb3, 0x3a, 0x52, 0x56, 0x59, 0x3a,
-	0x32, 0x0f, 0x17, 0x6d, 0xc9, 0x8e, 0xfc, 0xe6, 0x4c, 0x5b, 0x93, 0x1d, 0x69, 0x62, 0xf8, 0x21,
-	0xaa, 0x8e, 0xd1, 0xa8, 0x46, 0x49, 0x80, 0x0d, 0x02, 0xae, 0xfe, 0x7f, 0xbf, 0xd5, 0x9c, 0x37,
-	0x66, 0x70, 0x4a, 0xf8, 0x61, 0x86, 0x4e, 0xf9, 0xaf, 0x50, 0x7b, 0x1e, 0x0f, 0xd0, 0x66, 0xae,
-	0x65, 0x5a, 0x67, 0x4c, 0xec, 0x3b, 0x2d, 0xf6, 0xaa, 0x5d, 0x4c, 0x77, 0xc9, 0xb4, 0x1a, 0xa1,
-	0x33, 0x00, 0xf8, 0x23, 0xb4, 0xee, 0x85, 0x43, 0x2e, 0x80, 0xb9, 0xe6, 0x2d, 0xe3, 0x72, 0x10,
-	0xe4, 0x33, 0x64, 0x46, 0x60, 0xfc, 0x21, 0xd3, 0x68, 0x6b, 0xe4, 0xfb, 0x1a, 0x78, 0x0f, 0xc4,
-	0xd4, 0xad, 0x77, 0xc9, 0x9b, 0x84, 0xe0, 0x87, 0xe8, 0x4a, 0xaa, 0xa0, 0xc9, 0x5c, 0x2a, 0x04,
-	0x53, 0x2a, 0x9f, 0x23, 0x73, 0x0f, 0xda, 0x54, 0xde, 0x55, 0xb1, 0x96, 0x10, 0xcc, 0x26, 0xb4,
-	0xe1, 0x59, 0x50, 0xf8, 0x43, 0x84, 0xfd, 0xf8, 0x51, 0xd4, 0x63, 0xd4, 0x07, 0x37, 0x88, 0x8e,
-	0x62, 0x25, 0xf3, 0x85, 0x96, 0xb9, 0x56, 0x94, 0xe9, 0xa4, 0xc0, 0x83, 0xe8, 0x28, 0xb6, 0x49,
-	0xac, 0xf9, 0x13, 0x88, 0xfc, 0x31, 0x75, 0x11, 0xad, 0xec, 0x0d, 0x12, 0xf1, 0xd8, 0x01, 0x9e,
-	0xc4, 0x11, 0x87, 0xad, 0xc7, 0x68, 0xf3, 0x8c, 0xeb, 0x1b, 0x63, 0x34, 0xaf, 0xde, 0x72, 0x25,
-	0xf5, 0x96, 0x53, 0xdf, 0xf2, 0x8d, 0x97, 0xdd, 0x6a, 0xe6, 0x8d, 0x97, 0xfe, 0xc6, 0x57, 0xd1,
-	0x32, 0x0f, 0x06, 0x49, 0x08, 0xae, 0x88, 0x8f, 0x41, 0x3f, 0xf1, 0xca, 0x4e, 0x45, 0xc7, 0xee,
-	0xcb, 0x50, 0xe6, 0xe5, 0xd6, 0xc6, 0x93, 0x3f, 0x6b, 0xe7, 0x9e, 0x9c, 0xd6, 0x4a, 0x4f, 0x4f,
-	0x6b, 0xa5, 0x3f, 0x4e, 0x6b, 0xa5, 0x2f, 0xff, 0xaa, 0x9d, 0xeb, 0x2e, 0xa8, 0x97, 0xe6, 0xee,
-	0xff, 0x01, 0x00, 0x00, 0xff, 0xff, 0x30, 0x36, 0x53, 0xc6, 0x0b, 0x0b, 0x00, 0x00,
+	// 1483 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x57, 0x4b, 0x73, 0x1b, 0x45,
+	0x10, 0x8e, 0x2c, 0xdb, 0x8a, 0x46, 0xb2, 0x2d, 0x8f, 0x9d, 0x64, 0xa2, 0x14, 0x8a, 0x62, 0x48,
+	0x30, 0x10, 0x94, 0x44, 0x01, 0x1f, 0xb8, 0x80, 0x62, 0x9b, 0xc4, 0x24, 0xa4, 0x52, 0x6b, 0x43,
+	0xa5, 0xa0, 0xc2, 0x32, 0xd2, 0xb6, 0xa5, 0x8d, 0xf6, 0xc5, 0xce, 0xc8, 0xb1, 0xaf, 0x1c, 0x39,
+	0x03, 0xc5, 0xcf, 0x80, 0x40, 0xfe, 0x43, 0x0e, 0x3c, 0xc2, 0xe3, 0x07, 0x40, 0xb8, 0x50, 0xc5,
+	0x31, 0xbc, 0x8e, 0xd4, 0xcc, 0xec, 0x5b, 0x2b, 0x53, 0x9c, 0xb4, 0xea, 0xfe, 0xfa, 0xfb, 0x7a,
+	0x66, 0x7a, 0x1e, 0x8d, 0x96, 0x7c, 0xba, 0xcb, 0x75, 0xd3, 0xe1, 0xe0, 0x3b, 0xd4, 0x6a, 0x79,
+	0xbe, 0xcb, 0x5d, 0x5c, 0x05, 0xde, 0x33, 0x18, 0xf8, 0x7b, 0xe0, 0x7b, 0xdd, 0xfa, 0x72, 0xdf,
+	0xed, 0xbb, 0xd2, 0x71, 0x41, 0x7c, 0x29, 0x4c, 0xbd, 0x16, 0x63, 0x02, 0x4b, 0xd9, 0xf7, 0x7a,
+	0xc1, 0x67, 0x53, 0x38, 0x2f, 0x50, 0xcf, 0xbc, 0xb0, 0x07, 0x3e, 0x33, 0x5d, 0xc7, 0xeb, 0x86,
+	0x5f, 0x01, 0xe2, 0x5c, 0x84, 0xb0, 0xc1, 0xee, 0x82, 0xcf, 0x06, 0xa6, 0xe7, 0x75, 0x13, 0x7f,
+	0x02, 0x5c, 0x3d, 0xc2, 0xd1, 0x11, 0x1f, 0x78, 0x5d, 0xf9, 0xa3, 0x7c, 0x2b, 0xbf, 0x17, 0xd0,
+	0x9c, 0x06, 0x1f, 0x8c, 0x80, 0xf1, 0x6b, 0x40, 0x0d, 0xf0, 0xf1, 0x3c, 0x9a, 0xda, 0xda, 0x20,
+	0x85, 0x66, 0x61, 0x75, 0x5a, 0x9b, 0xda, 0xda, 0xc0, 0x75, 0x74, 0x74, 0xc4, 0xc4, 0xc8, 0x6c,
+	0x20, 0x53, 0xcd, 0xc2, 0x6a, 0x59, 0x8b, 0xfe, 0xe3, 0xf3, 0x68, 0x4e, 0x70, 0xe9, 0x3e, 0xec,
+	0x99, 0x22, 0x31, 0x52, 0x14, 0x61, 0x57, 0x4a, 0x1f, 0x3d, 0x20, 0xc5, 0xcb, 0xad, 0x4b, 0x5a,
+	0x55, 0x78, 0xb5, 0xc0, 0x89, 0xcf, 0xa2, 0x32, 0x37, 0x6d, 0x60, 0x9c, 0xda, 0x1e, 0x99, 0x6e,
+	0x16, 0x56, 0x8b, 0x21, 0x72, 0x4d, 0x8b, 0x3d, 0xf8, 0x29, 0x34, 0xe3, 0xbb, 0x16, 0x30, 0x32,
+	0xd3, 0x2c, 0xae, 0x96, 0x63, 0x88, 0xb2, 0xe2, 0x4b, 0x68, 0x86, 0xf5, 0x5c, 0x0f, 0xc8, 0x6c,
+	0xb3, 0xb0, 0x5a, 0x69, 0xe3, 0x96, 0x1a, 0x54, 0x6b, 0xc7, 0x1d, 0x82, 0xb3, 0x2d, 0x3c, 0x89,
+	0x10, 0x89, 0x7c, 0xa5, 0xf4, 0xa1, 0xfc, 0x7f, 0x71, 0xe5, 0xc9, 0x71, 0xb4, 0xb4, 0x15, 0xac,
+	0x93, 0x46, 0x77, 0x79, 0x30, 0x72, 0x7c, 0x19, 0xcd, 0x0e, 0xe4, 0xe8, 0x89, 0x21, 0x49, 0x4f,
+	0xb5, 0x92, 0xab, 0xd7, 0x4a, 0x4d, 0x90, 0x36, 0x3b, 0xc8, 0x9f, 0xa8, 0xb3, 0x68, 0x6a, 0xaf,
+	0x2d, 0xa7, 0xa8, 0xd2, 0x3e, 0x96, 0x4b, 0xa0, 0x4d, 0xed, 0xb5, 0xf1, 0x45, 0x34, 0xe3, 0x53,
+	0xa7, 0x0f, 0x72, 0xae, 0x2a, 0xed, 0x7a, 0x06, 0x29, 0x5c, 0x21, 0x5c, 0x01, 0xf1, 0xf3, 0xa8,
+	0xe8, 0x8d, 0xb8, 0x9c, 0xb1, 0x4a, 0x9b, 0xa4, 0xf1, 0xb7, 0x46, 0xe1, 0x20, 0x34, 0x01, 0xc2,
+	0xeb, 0xa8, 0x6a, 0x80, 0x05, 0x1c, 0x74, 0x25, 0x32, 0x23, 0x83, 0x9a, 0xe9, 0xa0, 0x0d, 0x89,
+	0x48, 0x49, 0x55, 0x8c, 0xd8, 0x26, 0x04, 0xf9, 0xbe, 0x43, 0x66, 0xf3, 0x04, 0x77, 0xf6, 0x9d,
+	0x48, 0x90, 0xef, 0x3b, 0xf8, 0x55, 0x84, 0x7a, 0xae, 0xed, 0xd1, 0x1e, 0x17, 0xeb, 0x5f, 0x92,
+	0x21, 0xa7, 0xd3, 0x21, 0xeb, 0x91, 0x3f, 0x8c, 0x4c, 0x84, 0xe0, 0xd7, 0x50, 0xc5, 0x02, 0xca,
+	0x40, 0xef, 0xfb, 0xd4, 0xe1, 0xe4, 0x68, 0x1e, 0xc3, 0x0d, 0x01, 0xb8, 0x2a, 0xfc, 0x11, 0x83,
+	0x15, 0x99, 0xc4, 0x98, 0x15, 0x83, 0x0f, 0x7b, 0xee, 0x10, 0x48, 0x39, 0x6f, 0xcc, 0x92, 0x42,
+	0x93, 0x80, 0x68, 0xcc, 0x56, 0x6c, 0x13, 0xcb, 0x42, 0x2d, 0xea, 0xdb, 0x04, 0xe5, 0x2d, 0x4b,
+	0x47, 0xb8, 0xa2, 0x65, 0x91, 0x40, 0x7c, 0x1b, 0xd5, 0x94, 0x6c, 0x6f, 0x00, 0xbd, 0xa1, 0xe7,
+	0x9a, 0x0e, 0x27, 0x15, 0x19, 0xfc, 0x4c, 0x8e, 0xf4, 0x7a, 0x04, 0x0a, 0x68, 0xc2, 0x2a, 0x7d,
+	0x49, 0x5b, 0xb0, 0xd2, 0x00, 0xfc, 0x3a, 0x42, 0x43, 0x38, 0xd0, 0x61, 0xdf, 0x33, 0x7d, 0x20,
+	0x55, 0xc9, 0xd9, 0x48, 0x73, 0x5e, 0x87, 0x83, 0x4d, 0xe9, 0xce, 0xb0, 0xad, 0x69, 0xe5, 0x61,
+	0xe8, 0xc2, 0x1d, 0x54, 0x91, 0xdb, 0x13, 0x1c, 0xda, 0xb5, 0x80, 0xfc, 0x96, 0xbb, 0x3a, 0x9d,
+	0x11, 0x1f, 0x6c, 0x4a, 0x40, 0x34, 0xb7, 0x34, 0x32, 0xe1, 0x0d, 0x24, 0xf7, 0xb0, 0x6e, 0x98,
+	0x4c, 0x72, 0x3c, 0x29, 0xe5, 0x4d, 0xae, 0xe0, 0xd8, 0x30, 0x59, 0x92, 0xa4, 0x42, 0x63, 0x1b,
+	0x7e, 0x23, 0x48, 0x84, 0x71, 0xca, 0x47, 0x8c, 0xfc, 0x39, 0x31, 0x91, 0x6d, 0x09, 0xc8, 0x8c,
+	0xe9, 0x65, 0x95, 0x91, 0xf2, 0xe1, 0x9b, 0x2a, 0x23, 0x70, 0xb8, 0xd9, 0xa3, 0x1c, 0xc8, 0x1f,
+	0x8a, 0xec, 0xb9, 0x34, 0x59, 0xb8, 0xcb, 0x3b, 0x09, 0x68, 0x98, 0x5a, 0x2a, 0x1e, 0xef, 0xa0,
+	0x05, 0x99, 0x1b, 0x17, 0xe7, 0x87, 0x6e, 0x99, 0x8c, 0x93, 0xbf, 0x14, 0xe5, 0xca, 0x78, 0x7e,
+	0xf2, 0x90, 0xb9, 0x61, 0x32, 0x3e, 0x36, 0xed, 0x73, 0x34, 0xe9, 0xc6, 0xef, 0xa0, 0xc5, 0x04,
+	0x6b, 0x50, 0x98, 0x7f, 0x97, 0xf2, 0xca, 0x23, 0xe2, 0x4d, 0x55, 0x67, 0xcc, 0xbc, 0x40, 0xd3,
+	0x00, 0xdc, 0x47, 0xc7, 0x25, 0xb7, 0x38, 0x0f, 0xd5, 0xae, 0x09, 0x4b, 0xe5, 0x1f, 0x25, 0xb0,
+	0x3a, 0x2e, 0xa0, 0xb9, 0x96, 0xda, 0x2d, 0x13, 0xaa, 0x66, 0x89, 0x8e, 0x83, 0xf0, 0x66, 0x70,
+	0xbc, 0x8f, 0x18, 0xf8, 0x3a, 0x35, 0x0c, 0xf2, 0xf5, 0xd1, 0x49, 0xab, 0xff, 0x16, 0x03, 0xbf,
+	0x63, 0x18, 0xa9, 0xd5, 0x0f, 0x6c, 0xf8, 0x26, 0xaa, 0xc5, 0x34, 0xea, 0x9c, 0x21, 0xdf, 0x28,
+	0xa6, 0xa7, 0xf3, 0x99, 0x82, 0x03, 0x2a, 0x20, 0x9b, 0xa7, 0x29, 0x73, 0x3a, 0xad, 0x3e, 0x70,
+	0xf2, 0xed, 0xa1, 0x69, 0x5d, 0x05, 0x3e, 0x96, 0xd6, 0x55, 0xe0, 0xb8, 0x8f, 0x4e, 0xc6, 0x34,
+	0xbd, 0x81, 0x38, 0xf9, 0x74, 0x8f, 0x32, 0x76, 0xcf, 0xf5, 0x0d, 0xf2, 0x9d, 0xa2, 0x7c, 0x21,
+	0x9f, 0x72, 0x5d, 0xa2, 0x6f, 0x05, 0xe0, 0x90, 0xfd, 0x38, 0xcd, 0x75, 0xe3, 0xdb, 0x68, 0x39,
+	0x91, 0xaf, 0x5c, 0x2f, 0xb1, 0x74, 0xe4, 0x91, 0xd2, 0x38, 0x37, 0x21, 0x6d, 0x01, 0x14, 0x2b,
+	0x12, 0xd2, 0x2f, 0xd2, 0xac, 0x07, 0xbf, 0x8b, 0x8e, 0xc5, 0xcc, 0xaa, 0xc8, 0x14, 0xf5, 0xf7,
+	0x8a, 0xfa, 0xd9, 0x7c, 0xea, 0xa0, 0xd0, 0x12, 0xdc, 0x98, 0x8e, 0xb9, 0xf0, 0x35, 0x34, 0x1f,
+	0x93, 0xcb, 0x7d, 0xf1, 0x83, 0x62, 0x3d, 0x93, 0xcf, 0x9a, 0xd8, 0x16, 0x6a, 0x8b, 0x85, 0xc6,
+	0x88, 0x49, 0x16, 0xac, 0x64, 0xfa, 0x71, 0x22, 0x93, 0x90, 0x1e, 0x63, 0x0a, 0x8d, 0x98, 0x26,
+	0xa7, 0x92, 0x01, 0xd7, 0x2d, 0xd3, 0x36, 0x39, 0x23, 0x3f, 0x1d, 0x3a, 0x95, 0xdb, 0xc0, 0x6f,
+	0x48, 0xdc, 0x58, 0xd9, 0x2f, 0xd2, 0x2c, 0x24, 0xaa, 0x2e, 0x99, 0xac, 0x28, 0xfa, 0xcf, 0xcb,
+	0x93, 0xaa, 0x4b, 0xa4, 0x95, 0x2d, 0xfa, 0xc0, 0x16, 0x15, 0xbd, 0xa4, 0x09, 0x8a, 0xfe, 0x8b,
+	0xf2, 0xa4, 0xa2, 0x17, 0x51, 0x39, 0x45, 0x1f, 0x9b, 0xd3, 0x69, 0x89, 0xa2, 0xbf, 0x7f, 0x68,
+	0x5a, 0xd9, 0xa2, 0x0f, 0x6c, 0xf8, 0x2e, 0xaa, 0x67, 0xcf, 0x0e, 0x0f, 0x7c, 0xdb, 0x64, 0xf2,
+	0xf9, 0xf6, 0xa5, 0xe2, 0x3c, 0x7f, 0xc8, 0xf9, 0x71, 0x2b, 0x42, 0x87, 0xfc, 0x27, 0x68, 0xbe,
+	0x1f, 0xdb, 0xe8, 0x54, 0xac, 0x15, 0x54, 0x67, 0x42, 0xec, 0x2b, 0x25, 0xf6, 0x62, 0xbe, 0x98,
+	0x2a, 0xc4, 0x71, 0x35, 0x42, 0x27, 0x00, 0xa2, 0xda, 0x90, 0x72, 0x89, 0xda, 0x78, 0x50, 0x9e,
+	0x54, 0x1b, 0x82, 0xe6, 0x3f, 0x6a, 0x23, 0x05, 0xc1, 0xef, 0xa3, 0xa5, 0x9e, 0x35, 0x62, 0x1c,
+	0x7c, 0x3d, 0x78, 0x8a, 0x0b, 0x21, 0xf2, 0x31, 0x0a, 0x14, 0x92, 0xef, 0xf0, 0xd6, 0xba, 0x42,
+	0xbe, 0xad, 0x80, 0xdb, 0xc0, 0xc7, 0xae, 0xb5, 0xc5, 0x5e, 0x16, 0x82, 0xef, 0xa2, 0x13, 0xa1,
+	0x82, 0x22, 0xd3, 0x29, 0xe7, 0xb2, 0xd4, 0xc9, 0x27, 0x28, 0xb8, 0xe8, 0xf2, 0x54, 0xde, 0x94,
+	0xb6, 0x0e, 0xe7, 0x7e, 0x9e, 0xd0, 0x72, 0x2f, 0x07, 0x85, 0xef, 0x20, 0x6c, 0xb8, 0xf7, 0x9c,
+	0xbe, 0x4f, 0x0d, 0xd0, 0x4d, 0x67, 0xd7, 0x95, 0x32, 0x9f, 0x2a, 0x99, 0xb3, 0x69, 0x99, 0x8d,
+	0x10, 0xb8, 0xe5, 0xec, 0xba, 0x79, 0x12, 0x35, 0x23, 0x83, 0x88, 0x5f, 0xdd, 0x0b, 0x68, 0x6e,
+	0xd3, 0xf6, 0xf8, 0x81, 0x06, 0xcc, 0x73, 0x1d, 0x06, 0x2b, 0x5b, 0xa8, 0x96, 0x7d, 0xbf, 0xe0,
+	0xf3, 0x68, 0x7a, 0x08, 0x07, 0x8c, 0x14, 0x9a, 0xc5, 0xf1, 0x47, 0xa7, 0x82, 0x1a, 0xd7, 0xe1,
+	0x40, 0x93, 0xa8, 0x90, 0x7b, 0x6d, 0xe5, 0x1a, 0x42, 0xb1, 0x13, 0xd7, 0x50, 0x71, 0x08, 0x07,
+	0xf2, 0x4d, 0x5e, 0xd5, 0xc4, 0x27, 0x3e, 0x8d, 0x2a, 0xea, 0x6e, 0xd4, 0x45, 0x83, 0x21, 0x5f,
+	0xe7, 0x45, 0x0d, 0x29, 0xd3, 0x8e, 0x69, 0x43, 0xcc, 0x74, 0x07, 0xd5, 0x27, 0x5f, 0x94, 0x78,
+	0x0d, 0xcd, 0xca, 0xdd, 0x12, 0x26, 0xd8, 0xc8, 0x4d, 0x30, 0x0a, 0xd6, 0x02, 0x74, 0x4c, 0x6f,
+	0xa0, 0x5a, 0x16, 0x84, 0x31, 0x9a, 0x1e, 0x31, 0xf0, 0x65, 0xbe, 0x65, 0x4d, 0x7e, 0x0b, 0x9b,
+	0x3c, 0xc1, 0x55, 0xab, 0x25, 0xbf, 0xb3, 0x83, 0x28, 0x4e, 0x1e, 0xc4, 0xfd, 0x22, 0x3a, 0x75,
+	0xc8, 0xd3, 0x47, 0xb0, 0xcb, 0x46, 0x2e, 0x50, 0x14, 0xdf, 0xa2, 0xc1, 0x8b, 0xae, 0xbd, 0xa0,
+	0xc1, 0x0b, 0xff, 0xe3, 0x33, 0xa8, 0xca, 0x4c, 0xdb, 0xb3, 0x40, 0x3d, 0x64, 0xa4, 0x74, 0x59,
+	0xab, 0x28, 0x9b, 0x7c, 0x93, 0xe0, 0x8b, 0x68, 0x61, 0x40, 0xd9, 0x00, 0x8c, 0xf8, 0xf2, 0x14,
+	0x9d, 0x4a, 0x35, 0xde, 0x48, 0xf3, 0xca, 0x1f, 0xdd, 0x87, 0xef, 0xa1, 0x93, 0x99, 0x08, 0x9d,
+	0x5a, 0x7d, 0xd7, 0x37, 0xf9, 0xc0, 0x96, 0x0d, 0xcb, 0x7c, 0xfb, 0x64, 0xd8, 0xd5, 0x85, 0x41,
+	0x9d, 0x10, 0x10, 0xd3, 0x9e, 0x48, 0xd3, 0x46, 0x88, 0xf1, 0xae, 0x74, 0x36, 0xd9, 0x95, 0xae,
+	0x65, 0xba, 0xd2, 0x33, 0xa8, 0xd4, 0xf3, 0x81, 0x72, 0x30, 0x48, 0x29, 0xdd, 0x93, 0x86, 0x76,
+	0x01, 0xa1, 0x86, 0xe1, 0x03, 0x63, 0xb2, 0x3d, 0x49, 0xf4, 0xa4, 0xa1, 0x3d, 0xee, 0x4a, 0xcb,
+	0xff, 0xbb, 0x2b, 0xbd, 0xb2, 0xfc, 0xf0, 0x97, 0xc6, 0x91, 0x87, 0x8f, 0x1b, 0x85, 0x47, 0x8f,
+	0x1b, 0x85, 0x9f, 0x1f, 0x37, 0x0a, 0x9f, 0xfd, 0xda, 0x38, 0xd2, 0x9d, 0x95, 0x0d, 0xfa, 0xe5,
+	0x7f, 0x07, 0x00, 0x21, 0x9d, 0x3c, 0xab, 0x5e, 0x10, 0x00, 0x00,
 }
 
 func (m *RequestHeader) Marshal() (dAtA []byte, err error) {
@@ -331,6 +560,32 @@ func (m *RequestHeader) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.Scope != nil {
+		{
+			size, err := m.Scope.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRaftInternal(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x32
+	}
+	if len(m.Roles) > 0 {
+		for iNdEx := len(m.Roles) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Roles[iNdEx])
+			copy(dAtA[i:], m.Roles[iNdEx])
+			i = encodeVarintRaftInternal(dAtA, i, uint64(len(m.Roles[iNdEx])))
+			i--
+			dAtA[i] = 0x2a
+		}
+	}
+	if m.Timestamp != 0 {
+		i = encodeVarintRaftInternal(dAtA, i, uint64(m.Timestamp))
+		i--
+		dAtA[i] = 0x20
+	}
 	if m.AuthRevision != 0 {
 		i = encodeVarintRaftInternal(dAtA, i, uint64(m.AuthRevision))
 		i--
@@ -417,6 +672,20 @@ func (m *InternalRaftRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i--
 		dAtA[i] = 0xa2
 	}
+	if m.AuthRoleSetLimits != nil {
+		{
+			size, err := m.AuthRoleSetLimits.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRaftInternal(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x4b
+		i--
+		dAtA[i] = 0xaa
+	}
 	if m.AuthRoleRevokePermission != nil {
 		{
 			size, err := m.AuthRoleRevokePermission.MarshalToSizedBuffer(dAtA[:i])
@@ -487,6 +756,20 @@ func (m *InternalRaftRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i--
 		dAtA[i] = 0x82
 	}
+	if m.AuthUserSetLimits != nil {
+		{
+			size, err := m.AuthUserSetLimits.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRaftInternal(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x45
+		i--
+		dAtA[i] = 0xa2
+	}
 	if m.AuthRoleList != nil {
 		{
 			size, err := m.AuthRoleList.MarshalToSizedBuffer(dAtA[:i])
@@ -599,6 +882,48 @@ func (m *InternalRaftRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i--
 		dAtA[i] = 0xe2
 	}
+	if m.AuthRoleGrantExpire != nil {
+		{
+			size, err := m.AuthRoleGrantExpire.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRaftInternal(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x3f
+		i--
+		dAtA[i] = 0xc2
+	}
+	if m.AuthTokenRevoke != nil {
+		{
+			size, err := m.AuthTokenRevoke.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRaftInternal(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x3f
+		i--
+		dAtA[i] = 0xba
+	}
+	if m.AuthTokenList != nil {
+		{
+			size, err := m.AuthTokenList.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRaftInternal(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x3f
+		i--
+		dAtA[i] = 0xb2
+	}
 	if m.AuthStatus != nil {
 		{
 			size, err := m.AuthStatus.MarshalToSizedBuffer(dAtA[:i])
@@ -669,6 +994,18 @@ func (m *InternalRaftRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i--
 		dAtA[i] = 0xa2
 	}
+	if m.KeyExpire != nil {
+		{
+			size, err := m.KeyExpire.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRaftInternal(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x62
+	}
 	if m.LeaseCheckpoint != nil {
 		{
 			size, err := m.LeaseCheckpoint.MarshalToSizedBuffer(dAtA[:i])
@@ -824,7 +1161,7 @@ func (m *EmptyResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func (m *InternalAuthenticateRequest) Marshal() (dAtA []byte, err error) {
+func (m *KeyExpireRequest) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
 	n, err := m.MarshalToSizedBuffer(dAtA[:size])
@@ -834,12 +1171,12 @@ func (m *InternalAuthenticateRequest) Marshal() (dAtA []byte, err error) {
 	return dAtA[:n], nil
 }
 
-func (m *InternalAuthenticateRequest) MarshalTo(dAtA []byte) (int, error) {
+func (m *KeyExpireRequest) MarshalTo(dAtA []byte) (int, error) {
 	size := m.Size()
 	return m.MarshalToSizedBuffer(dAtA[:size])
 }
 
-func (m *InternalAuthenticateRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+func (m *KeyExpireRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	i := len(dAtA)
 	_ = i
 	var l int
@@ -848,49 +1185,257 @@ func (m *InternalAuthenticateRequest) MarshalToSizedBuffer(dAtA []byte) (int, er
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	if len(m.SimpleToken) > 0 {
-		i -= len(m.SimpleToken)
-		copy(dAtA[i:], m.SimpleToken)
-		i = encodeVarintRaftInternal(dAtA, i, uint64(len(m.SimpleToken)))
-		i--
-		dAtA[i] = 0x1a
+	if len(m.Keys) > 0 {
+		for iNdEx := len(m.Keys) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Keys[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRaftInternal(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
 	}
-	if len(m.Password) > 0 {
-		i -= len(m.Password)
-		copy(dAtA[i:], m.Password)
-		i = encodeVarintRaftInternal(dAtA, i, uint64(len(m.Password)))
+	return len(dAtA) - i, nil
+}
+
+func (m *ExpiredKey) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ExpiredKey) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ExpiredKey) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.ExpireTime != 0 {
+		i = encodeVarintRaftInternal(dAtA, i, uint64(m.ExpireTime))
 		i--
-		dAtA[i] = 0x12
+		dAtA[i] = 0x10
 	}
-	if len(m.Name) > 0 {
-		i -= len(m.Name)
-		copy(dAtA[i:], m.Name)
-		i = encodeVarintRaftInternal(dAtA, i, uint64(len(m.Name)))
+	if len(m.Key) > 0 {
+		i -= len(m.Key)
+		copy(dAtA[i:], m.Key)
+		i = encodeVarintRaftInternal(dAtA, i, uint64(len(m.Key)))
 		i--
 		dAtA[i] = 0xa
 	}
 	return len(dAtA) - i, nil
 }
 
-func encodeVarintRaftInternal(dAtA []byte, offset int, v uint64) int {
-	offset -= sovRaftInternal(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *AuthRoleGrantExpireRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return base
+	return dAtA[:n], nil
 }
-func (m *RequestHeader) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+
+func (m *AuthRoleGrantExpireRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *AuthRoleGrantExpireRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if m.ID != 0 {
-		n += 1 + sovRaftInternal(uint64(m.ID))
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Grants) > 0 {
+		for iNdEx := len(m.Grants) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Grants[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintRaftInternal(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ExpiredRoleGrant) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ExpiredRoleGrant) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ExpiredRoleGrant) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.ExpireTime != 0 {
+		i = encodeVarintRaftInternal(dAtA, i, uint64(m.ExpireTime))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Role) > 0 {
+		i -= len(m.Role)
+		copy(dAtA[i:], m.Role)
+		i = encodeVarintRaftInternal(dAtA, i, uint64(len(m.Role)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.User) > 0 {
+		i -= len(m.User)
+		copy(dAtA[i:], m.User)
+		i = encodeVarintRaftInternal(dAtA, i, uint64(len(m.User)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *InternalAuthenticateRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *InternalAuthenticateRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *InternalAuthenticateRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Scope != nil {
+		{
+			size, err := m.Scope.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintRaftInternal(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x4a
+	}
+	if len(m.Address) > 0 {
+		i -= len(m.Address)
+		copy(dAtA[i:], m.Address)
+		i = encodeVarintRaftInternal(dAtA, i, uint64(len(m.Address)))
+		i--
+		dAtA[i] = 0x42
+	}
+	if m.Created != 0 {
+		i = encodeVarintRaftInternal(dAtA, i, uint64(m.Created))
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.AuthRevision != 0 {
+		i = encodeVarintRaftInternal(dAtA, i, uint64(m.AuthRevision))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.HashedPasswordAlgorithm != 0 {
+		i = encodeVarintRaftInternal(dAtA, i, uint64(m.HashedPasswordAlgorithm))
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.HashedPassword) > 0 {
+		i -= len(m.HashedPassword)
+		copy(dAtA[i:], m.HashedPassword)
+		i = encodeVarintRaftInternal(dAtA, i, uint64(len(m.HashedPassword)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.SimpleToken) > 0 {
+		i -= len(m.SimpleToken)
+		copy(dAtA[i:], m.SimpleToken)
+		i = encodeVarintRaftInternal(dAtA, i, uint64(len(m.SimpleToken)))
+		i--
+		dAtA[i] = 0x1a
+	}
+	if len(m.Password) > 0 {
+		i -= len(m.Password)
+		copy(dAtA[i:], m.Password)
+		i = encodeVarintRaftInternal(dAtA, i, uint64(len(m.Password)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Name) > 0 {
+		i -= len(m.Name)
+		copy(dAtA[i:], m.Name)
+		i = encodeVarintRaftInternal(dAtA, i, uint64(len(m.Name)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintRaftInternal(dAtA []byte, offset int, v uint64) int {
+	offset -= sovRaftInternal(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *RequestHeader) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.ID != 0 {
+		n += 1 + sovRaftInternal(uint64(m.ID))
 	}
 	l = len(m.Username)
 	if l > 0 {
@@ -899,6 +1444,19 @@ func (m *RequestHeader) Size() (n int) {
 	if m.AuthRevision != 0 {
 		n += 1 + sovRaftInternal(uint64(m.AuthRevision))
 	}
+	if m.Timestamp != 0 {
+		n += 1 + sovRaftInternal(uint64(m.Timestamp))
+	}
+	if len(m.Roles) > 0 {
+		for _, s := range m.Roles {
+			l = len(s)
+			n += 1 + l + sovRaftInternal(uint64(l))
+		}
+	}
+	if m.Scope != nil {
+		l = m.Scope.Size()
+		n += 1 + l + sovRaftInternal(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -954,6 +1512,10 @@ func (m *InternalRaftRequest) Size() (n int) {
 		l = m.LeaseCheckpoint.Size()
 		n += 1 + l + sovRaftInternal(uint64(l))
 	}
+	if m.KeyExpire != nil {
+		l = m.KeyExpire.Size()
+		n += 1 + l + sovRaftInternal(uint64(l))
+	}
 	if m.Header != nil {
 		l = m.Header.Size()
 		n += 2 + l + sovRaftInternal(uint64(l))
@@ -974,6 +1536,18 @@ func (m *InternalRaftRequest) Size() (n int) {
 		l = m.AuthStatus.Size()
 		n += 2 + l + sovRaftInternal(uint64(l))
 	}
+	if m.AuthTokenList != nil {
+		l = m.AuthTokenList.Size()
+		n += 2 + l + sovRaftInternal(uint64(l))
+	}
+	if m.AuthTokenRevoke != nil {
+		l = m.AuthTokenRevoke.Size()
+		n += 2 + l + sovRaftInternal(uint64(l))
+	}
+	if m.AuthRoleGrantExpire != nil {
+		l = m.AuthRoleGrantExpire.Size()
+		n += 2 + l + sovRaftInternal(uint64(l))
+	}
 	if m.AuthUserAdd != nil {
 		l = m.AuthUserAdd.Size()
 		n += 2 + l + sovRaftInternal(uint64(l))
@@ -1006,6 +1580,10 @@ func (m *InternalRaftRequest) Size() (n int) {
 		l = m.AuthRoleList.Size()
 		n += 2 + l + sovRaftInternal(uint64(l))
 	}
+	if m.AuthUserSetLimits != nil {
+		l = m.AuthUserSetLimits.Size()
+		n += 2 + l + sovRaftInternal(uint64(l))
+	}
 	if m.AuthRoleAdd != nil {
 		l = m.AuthRoleAdd.Size()
 		n += 2 + l + sovRaftInternal(uint64(l))
@@ -1026,6 +1604,10 @@ func (m *InternalRaftRequest) Size() (n int) {
 		l = m.AuthRoleRevokePermission.Size()
 		n += 2 + l + sovRaftInternal(uint64(l))
 	}
+	if m.AuthRoleSetLimits != nil {
+		l = m.AuthRoleSetLimits.Size()
+		n += 2 + l + sovRaftInternal(uint64(l))
+	}
 	if m.ClusterVersionSet != nil {
 		l = m.ClusterVersionSet.Size()
 		n += 2 + l + sovRaftInternal(uint64(l))
@@ -1056,6 +1638,84 @@ func (m *EmptyResponse) Size() (n int) {
 	return n
 }
 
+func (m *KeyExpireRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Keys) > 0 {
+		for _, e := range m.Keys {
+			l = e.Size()
+			n += 1 + l + sovRaftInternal(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ExpiredKey) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovRaftInternal(uint64(l))
+	}
+	if m.ExpireTime != 0 {
+		n += 1 + sovRaftInternal(uint64(m.ExpireTime))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *AuthRoleGrantExpireRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Grants) > 0 {
+		for _, e := range m.Grants {
+			l = e.Size()
+			n += 1 + l + sovRaftInternal(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ExpiredRoleGrant) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.User)
+	if l > 0 {
+		n += 1 + l + sovRaftInternal(uint64(l))
+	}
+	l = len(m.Role)
+	if l > 0 {
+		n += 1 + l + sovRaftInternal(uint64(l))
+	}
+	if m.ExpireTime != 0 {
+		n += 1 + sovRaftInternal(uint64(m.ExpireTime))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func (m *InternalAuthenticateRequest) Size() (n int) {
 	if m == nil {
 		return 0
@@ -1074,6 +1734,27 @@ func (m *InternalAuthenticateRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovRaftInternal(uint64(l))
 	}
+	l = len(m.HashedPassword)
+	if l > 0 {
+		n += 1 + l + sovRaftInternal(uint64(l))
+	}
+	if m.HashedPasswordAlgorithm != 0 {
+		n += 1 + sovRaftInternal(uint64(m.HashedPasswordAlgorithm))
+	}
+	if m.AuthRevision != 0 {
+		n += 1 + sovRaftInternal(uint64(m.AuthRevision))
+	}
+	if m.Created != 0 {
+		n += 1 + sovRaftInternal(uint64(m.Created))
+	}
+	l = len(m.Address)
+	if l > 0 {
+		n += 1 + l + sovRaftInternal(uint64(l))
+	}
+	if m.Scope != nil {
+		l = m.Scope.Size()
+		n += 1 + l + sovRaftInternal(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1185,28 +1866,115 @@ func (m *RequestHeader) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
-		default:
-			iNdEx = preIndex
-			skippy, err := skipRaftInternal(dAtA[iNdEx:])
-			if err != nil {
-				return err
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
 			}
-			if (skippy < 0) || (iNdEx+skippy) < 0 {
-				return ErrInvalidLengthRaftInternal
+			m.Timestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timestamp |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Roles", wireType)
 			}
-			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Roles = append(m.Roles, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 6:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Scope", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Scope == nil {
+				m.Scope = &authpb.TokenScope{}
+			}
+			if err := m.Scope.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRaftInternal(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func (m *InternalRaftRequest) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
@@ -1615,6 +2383,42 @@ func (m *InternalRaftRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 12:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field KeyExpire", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.KeyExpire == nil {
+				m.KeyExpire = &KeyExpireRequest{}
+			}
+			if err := m.KeyExpire.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		case 100:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field Header", wireType)
@@ -1795,6 +2599,114 @@ func (m *InternalRaftRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 1014:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AuthTokenList", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.AuthTokenList == nil {
+				m.AuthTokenList = &AuthTokenListRequest{}
+			}
+			if err := m.AuthTokenList.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 1015:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AuthTokenRevoke", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.AuthTokenRevoke == nil {
+				m.AuthTokenRevoke = &AuthTokenRevokeRequest{}
+			}
+			if err := m.AuthTokenRevoke.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 1016:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AuthRoleGrantExpire", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.AuthRoleGrantExpire == nil {
+				m.AuthRoleGrantExpire = &AuthRoleGrantExpireRequest{}
+			}
+			if err := m.AuthRoleGrantExpire.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		case 1100:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field AuthUserAdd", wireType)
@@ -2083,6 +2995,42 @@ func (m *InternalRaftRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 1108:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AuthUserSetLimits", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.AuthUserSetLimits == nil {
+				m.AuthUserSetLimits = &AuthUserSetLimitsRequest{}
+			}
+			if err := m.AuthUserSetLimits.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		case 1200:
 			if wireType != 2 {
 				return fmt.Errorf("proto: wrong wireType = %d for field AuthRoleAdd", wireType)
@@ -2263,9 +3211,9 @@ func (m *InternalRaftRequest) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
-		case 1300:
+		case 1205:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ClusterVersionSet", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field AuthRoleSetLimits", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2292,16 +3240,16 @@ func (m *InternalRaftRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.ClusterVersionSet == nil {
-				m.ClusterVersionSet = &membershippb.ClusterVersionSetRequest{}
+			if m.AuthRoleSetLimits == nil {
+				m.AuthRoleSetLimits = &AuthRoleSetLimitsRequest{}
 			}
-			if err := m.ClusterVersionSet.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.AuthRoleSetLimits.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 1301:
+		case 1300:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ClusterMemberAttrSet", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ClusterVersionSet", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2328,16 +3276,16 @@ func (m *InternalRaftRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.ClusterMemberAttrSet == nil {
-				m.ClusterMemberAttrSet = &membershippb.ClusterMemberAttrSetRequest{}
+			if m.ClusterVersionSet == nil {
+				m.ClusterVersionSet = &membershippb.ClusterVersionSetRequest{}
 			}
-			if err := m.ClusterMemberAttrSet.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.ClusterVersionSet.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 1302:
+		case 1301:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field DowngradeInfoSet", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ClusterMemberAttrSet", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -2364,10 +3312,371 @@ func (m *InternalRaftRequest) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.DowngradeInfoSet == nil {
+			if m.ClusterMemberAttrSet == nil {
+				m.ClusterMemberAttrSet = &membershippb.ClusterMemberAttrSetRequest{}
+			}
+			if err := m.ClusterMemberAttrSet.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 1302:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field DowngradeInfoSet", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.DowngradeInfoSet == nil {
 				m.DowngradeInfoSet = &membershippb.DowngradeInfoSetRequest{}
 			}
-			if err := m.DowngradeInfoSet.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.DowngradeInfoSet.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRaftInternal(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *EmptyResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRaftInternal
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: EmptyResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: EmptyResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRaftInternal(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *KeyExpireRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRaftInternal
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: KeyExpireRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: KeyExpireRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Keys", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Keys = append(m.Keys, &ExpiredKey{})
+			if err := m.Keys[len(m.Keys)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRaftInternal(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ExpiredKey) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRaftInternal
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ExpiredKey: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ExpiredKey: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = append(m.Key[:0], dAtA[iNdEx:postIndex]...)
+			if m.Key == nil {
+				m.Key = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExpireTime", wireType)
+			}
+			m.ExpireTime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ExpireTime |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRaftInternal(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AuthRoleGrantExpireRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRaftInternal
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AuthRoleGrantExpireRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AuthRoleGrantExpireRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Grants", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Grants = append(m.Grants, &ExpiredRoleGrant{})
+			if err := m.Grants[len(m.Grants)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
@@ -2393,7 +3702,7 @@ func (m *InternalRaftRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *EmptyResponse) Unmarshal(dAtA []byte) error {
+func (m *ExpiredRoleGrant) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -2416,12 +3725,95 @@ func (m *EmptyResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: EmptyResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: ExpiredRoleGrant: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: EmptyResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ExpiredRoleGrant: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field User", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.User = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Role", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Role = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExpireTime", wireType)
+			}
+			m.ExpireTime = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ExpireTime |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRaftInternal(dAtA[iNdEx:])
@@ -2569,6 +3961,165 @@ func (m *InternalAuthenticateRequest) Unmarshal(dAtA []byte) error {
 			}
 			m.SimpleToken = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HashedPassword", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.HashedPassword = append(m.HashedPassword[:0], dAtA[iNdEx:postIndex]...)
+			if m.HashedPassword == nil {
+				m.HashedPassword = []byte{}
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field HashedPasswordAlgorithm", wireType)
+			}
+			m.HashedPasswordAlgorithm = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.HashedPasswordAlgorithm |= authpb.PasswordAlgorithm(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AuthRevision", wireType)
+			}
+			m.AuthRevision = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.AuthRevision |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Created", wireType)
+			}
+			m.Created = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Created |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Address = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Scope", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRaftInternal
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthRaftInternal
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Scope == nil {
+				m.Scope = &authpb.TokenScope{}
+			}
+			if err := m.Scope.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRaftInternal(dAtA[iNdEx:])
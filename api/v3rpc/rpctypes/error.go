@@ -29,6 +29,12 @@ var (
 	ErrGRPCDuplicateKey            = status.Error(codes.InvalidArgument, "etcdserver: duplicate key given in txn request")
 	ErrGRPCInvalidClientAPIVersion = status.Error(codes.InvalidArgument, "etcdserver: invalid client api version")
 	ErrGRPCInvalidSortOption       = status.Error(codes.InvalidArgument, "etcdserver: invalid sort option")
+	ErrGRPCInvalidValueFilter      = status.Error(codes.InvalidArgument, "etcdserver: invalid value filter")
+	ErrGRPCInvalidRetention        = status.Error(codes.InvalidArgument, "etcdserver: invalid compaction retention")
+	ErrGRPCInvalidKeyGlob          = status.Error(codes.InvalidArgument, "etcdserver: invalid key glob")
+	ErrGRPCInvalidContinuation     = status.Error(codes.InvalidArgument, "etcdserver: invalid continuation token")
+	ErrGRPCInvalidResumeToken      = status.Error(codes.InvalidArgument, "etcdserver: invalid watch resume token")
+	ErrGRPCInvalidKeyTTL           = status.Error(codes.OutOfRange, "etcdserver: invalid key TTL")
 	ErrGRPCCompacted               = status.Error(codes.OutOfRange, "etcdserver: mvcc: required revision has been compacted")
 	ErrGRPCFutureRev               = status.Error(codes.OutOfRange, "etcdserver: mvcc: required revision is a future revision")
 	ErrGRPCNoSpace                 = status.Error(codes.ResourceExhausted, "etcdserver: mvcc: database space exceeded")
@@ -38,6 +44,7 @@ var (
 	ErrGRPCLeaseTTLTooLarge = status.Error(codes.OutOfRange, "etcdserver: too large lease TTL")
 
 	ErrGRPCWatchCanceled = status.Error(codes.Canceled, "etcdserver: watch canceled")
+	ErrGRPCWatchTooSlow  = status.Error(codes.ResourceExhausted, "etcdserver: watcher is too slow to receive its events, resync required")
 
 	ErrGRPCMemberExist            = status.Error(codes.FailedPrecondition, "etcdserver: member ID already exist")
 	ErrGRPCPeerURLExist           = status.Error(codes.FailedPrecondition, "etcdserver: Peer URLs already exists")
@@ -69,6 +76,10 @@ var (
 	ErrGRPCInvalidAuthToken     = status.Error(codes.Unauthenticated, "etcdserver: invalid auth token")
 	ErrGRPCInvalidAuthMgmt      = status.Error(codes.InvalidArgument, "etcdserver: invalid auth management")
 	ErrGRPCAuthOldRevision      = status.Error(codes.InvalidArgument, "etcdserver: revision of auth store is old")
+	ErrGRPCLimitExceeded        = status.Error(codes.ResourceExhausted, "etcdserver: user limit exceeded")
+	ErrGRPCTokenNotFound        = status.Error(codes.FailedPrecondition, "etcdserver: token not found")
+	ErrGRPCInvalidTokenScope    = status.Error(codes.InvalidArgument, "etcdserver: invalid token scope")
+	ErrGRPCInvalidGrantTTL      = status.Error(codes.OutOfRange, "etcdserver: invalid role grant TTL")
 
 	ErrGRPCNoLeader                   = status.Error(codes.Unavailable, "etcdserver: no leader")
 	ErrGRPCNotLeader                  = status.Error(codes.FailedPrecondition, "etcdserver: not leader")
@@ -83,6 +94,7 @@ var (
 	ErrGRPCCorrupt                    = status.Error(codes.DataLoss, "etcdserver: corrupt cluster")
 	ErrGRPCNotSupportedForLearner     = status.Error(codes.FailedPrecondition, "etcdserver: rpc not supported for learner")
 	ErrGRPCBadLeaderTransferee        = status.Error(codes.FailedPrecondition, "etcdserver: bad leader transferee")
+	ErrGRPCStaleRead                  = status.Error(codes.FailedPrecondition, "etcdserver: member is too stale to serve the read")
 
 	ErrGRPCWrongDowngradeVersionFormat   = status.Error(codes.InvalidArgument, "etcdserver: wrong downgrade target version format")
 	ErrGRPCInvalidDowngradeTargetVersion = status.Error(codes.InvalidArgument, "etcdserver: invalid downgrade target version")
@@ -99,17 +111,25 @@ var (
 		ErrorDesc(ErrGRPCValueProvided): ErrGRPCValueProvided,
 		ErrorDesc(ErrGRPCLeaseProvided): ErrGRPCLeaseProvided,
 
-		ErrorDesc(ErrGRPCTooManyOps):        ErrGRPCTooManyOps,
-		ErrorDesc(ErrGRPCDuplicateKey):      ErrGRPCDuplicateKey,
-		ErrorDesc(ErrGRPCInvalidSortOption): ErrGRPCInvalidSortOption,
-		ErrorDesc(ErrGRPCCompacted):         ErrGRPCCompacted,
-		ErrorDesc(ErrGRPCFutureRev):         ErrGRPCFutureRev,
-		ErrorDesc(ErrGRPCNoSpace):           ErrGRPCNoSpace,
+		ErrorDesc(ErrGRPCTooManyOps):          ErrGRPCTooManyOps,
+		ErrorDesc(ErrGRPCDuplicateKey):        ErrGRPCDuplicateKey,
+		ErrorDesc(ErrGRPCInvalidSortOption):   ErrGRPCInvalidSortOption,
+		ErrorDesc(ErrGRPCInvalidValueFilter):  ErrGRPCInvalidValueFilter,
+		ErrorDesc(ErrGRPCInvalidRetention):    ErrGRPCInvalidRetention,
+		ErrorDesc(ErrGRPCInvalidKeyGlob):      ErrGRPCInvalidKeyGlob,
+		ErrorDesc(ErrGRPCInvalidContinuation): ErrGRPCInvalidContinuation,
+		ErrorDesc(ErrGRPCInvalidResumeToken):  ErrGRPCInvalidResumeToken,
+		ErrorDesc(ErrGRPCInvalidKeyTTL):       ErrGRPCInvalidKeyTTL,
+		ErrorDesc(ErrGRPCCompacted):           ErrGRPCCompacted,
+		ErrorDesc(ErrGRPCFutureRev):           ErrGRPCFutureRev,
+		ErrorDesc(ErrGRPCNoSpace):             ErrGRPCNoSpace,
 
 		ErrorDesc(ErrGRPCLeaseNotFound):    ErrGRPCLeaseNotFound,
 		ErrorDesc(ErrGRPCLeaseExist):       ErrGRPCLeaseExist,
 		ErrorDesc(ErrGRPCLeaseTTLTooLarge): ErrGRPCLeaseTTLTooLarge,
 
+		ErrorDesc(ErrGRPCWatchTooSlow): ErrGRPCWatchTooSlow,
+
 		ErrorDesc(ErrGRPCMemberExist):            ErrGRPCMemberExist,
 		ErrorDesc(ErrGRPCPeerURLExist):           ErrGRPCPeerURLExist,
 		ErrorDesc(ErrGRPCMemberNotEnoughStarted): ErrGRPCMemberNotEnoughStarted,
@@ -139,6 +159,10 @@ var (
 		ErrorDesc(ErrGRPCInvalidAuthToken):     ErrGRPCInvalidAuthToken,
 		ErrorDesc(ErrGRPCInvalidAuthMgmt):      ErrGRPCInvalidAuthMgmt,
 		ErrorDesc(ErrGRPCAuthOldRevision):      ErrGRPCAuthOldRevision,
+		ErrorDesc(ErrGRPCLimitExceeded):        ErrGRPCLimitExceeded,
+		ErrorDesc(ErrGRPCTokenNotFound):        ErrGRPCTokenNotFound,
+		ErrorDesc(ErrGRPCInvalidTokenScope):    ErrGRPCInvalidTokenScope,
+		ErrorDesc(ErrGRPCInvalidGrantTTL):      ErrGRPCInvalidGrantTTL,
 
 		ErrorDesc(ErrGRPCNoLeader):                   ErrGRPCNoLeader,
 		ErrorDesc(ErrGRPCNotLeader):                  ErrGRPCNotLeader,
@@ -152,6 +176,7 @@ var (
 		ErrorDesc(ErrGRPCCorrupt):                    ErrGRPCCorrupt,
 		ErrorDesc(ErrGRPCNotSupportedForLearner):     ErrGRPCNotSupportedForLearner,
 		ErrorDesc(ErrGRPCBadLeaderTransferee):        ErrGRPCBadLeaderTransferee,
+		ErrorDesc(ErrGRPCStaleRead):                  ErrGRPCStaleRead,
 
 		ErrorDesc(ErrGRPCClusterVersionUnavailable):     ErrGRPCClusterVersionUnavailable,
 		ErrorDesc(ErrGRPCWrongDowngradeVersionFormat):   ErrGRPCWrongDowngradeVersionFormat,
@@ -163,21 +188,29 @@ var (
 
 // client-side error
 var (
-	ErrEmptyKey          = Error(ErrGRPCEmptyKey)
-	ErrKeyNotFound       = Error(ErrGRPCKeyNotFound)
-	ErrValueProvided     = Error(ErrGRPCValueProvided)
-	ErrLeaseProvided     = Error(ErrGRPCLeaseProvided)
-	ErrTooManyOps        = Error(ErrGRPCTooManyOps)
-	ErrDuplicateKey      = Error(ErrGRPCDuplicateKey)
-	ErrInvalidSortOption = Error(ErrGRPCInvalidSortOption)
-	ErrCompacted         = Error(ErrGRPCCompacted)
-	ErrFutureRev         = Error(ErrGRPCFutureRev)
-	ErrNoSpace           = Error(ErrGRPCNoSpace)
+	ErrEmptyKey            = Error(ErrGRPCEmptyKey)
+	ErrKeyNotFound         = Error(ErrGRPCKeyNotFound)
+	ErrValueProvided       = Error(ErrGRPCValueProvided)
+	ErrLeaseProvided       = Error(ErrGRPCLeaseProvided)
+	ErrTooManyOps          = Error(ErrGRPCTooManyOps)
+	ErrDuplicateKey        = Error(ErrGRPCDuplicateKey)
+	ErrInvalidSortOption   = Error(ErrGRPCInvalidSortOption)
+	ErrInvalidValueFilter  = Error(ErrGRPCInvalidValueFilter)
+	ErrInvalidRetention    = Error(ErrGRPCInvalidRetention)
+	ErrInvalidKeyGlob      = Error(ErrGRPCInvalidKeyGlob)
+	ErrInvalidContinuation = Error(ErrGRPCInvalidContinuation)
+	ErrInvalidResumeToken  = Error(ErrGRPCInvalidResumeToken)
+	ErrInvalidKeyTTL       = Error(ErrGRPCInvalidKeyTTL)
+	ErrCompacted           = Error(ErrGRPCCompacted)
+	ErrFutureRev           = Error(ErrGRPCFutureRev)
+	ErrNoSpace             = Error(ErrGRPCNoSpace)
 
 	ErrLeaseNotFound    = Error(ErrGRPCLeaseNotFound)
 	ErrLeaseExist       = Error(ErrGRPCLeaseExist)
 	ErrLeaseTTLTooLarge = Error(ErrGRPCLeaseTTLTooLarge)
 
+	ErrWatchTooSlow = Error(ErrGRPCWatchTooSlow)
+
 	ErrMemberExist            = Error(ErrGRPCMemberExist)
 	ErrPeerURLExist           = Error(ErrGRPCPeerURLExist)
 	ErrMemberNotEnoughStarted = Error(ErrGRPCMemberNotEnoughStarted)
@@ -206,6 +239,10 @@ var (
 	ErrInvalidAuthToken     = Error(ErrGRPCInvalidAuthToken)
 	ErrAuthOldRevision      = Error(ErrGRPCAuthOldRevision)
 	ErrInvalidAuthMgmt      = Error(ErrGRPCInvalidAuthMgmt)
+	ErrLimitExceeded        = Error(ErrGRPCLimitExceeded)
+	ErrTokenNotFound        = Error(ErrGRPCTokenNotFound)
+	ErrInvalidTokenScope    = Error(ErrGRPCInvalidTokenScope)
+	ErrInvalidGrantTTL      = Error(ErrGRPCInvalidGrantTTL)
 	ErrClusterIdMismatch    = Error(ErrGRPCClusterIdMismatch)
 
 	ErrNoLeader                   = Error(ErrGRPCNoLeader)
@@ -220,6 +257,7 @@ var (
 	ErrUnhealthy                  = Error(ErrGRPCUnhealthy)
 	ErrCorrupt                    = Error(ErrGRPCCorrupt)
 	ErrBadLeaderTransferee        = Error(ErrGRPCBadLeaderTransferee)
+	ErrStaleRead                  = Error(ErrGRPCStaleRead)
 
 	ErrClusterVersionUnavailable     = Error(ErrGRPCClusterVersionUnavailable)
 	ErrWrongDowngradeVersionFormat   = Error(ErrGRPCWrongDowngradeVersionFormat)
@@ -43,15 +43,29 @@ type (
 	AuthRoleDeleteResponse           pb.AuthRoleDeleteResponse
 	AuthUserListResponse             pb.AuthUserListResponse
 	AuthRoleListResponse             pb.AuthRoleListResponse
+	AuthUserSetLimitsResponse        pb.AuthUserSetLimitsResponse
+	AuthRoleSetLimitsResponse        pb.AuthRoleSetLimitsResponse
+	AuthTokenListResponse            pb.AuthTokenListResponse
+	AuthTokenRevokeResponse          pb.AuthTokenRevokeResponse
+	AuthExplainResponse              pb.AuthExplainResponse
 
 	PermissionType authpb.Permission_Type
 	Permission     authpb.Permission
+	// Limits are limits on the resources used by a user, zero meaning unlimited.
+	Limits authpb.Limits
+	// TokenScope restricts a token to a subset of the roles of its user, or to
+	// the keys with a prefix.
+	TokenScope authpb.TokenScope
 )
 
 const (
 	PermRead      = authpb.READ
 	PermWrite     = authpb.WRITE
 	PermReadWrite = authpb.READWRITE
+	PermCreate    = authpb.CREATE
+	PermDelete    = authpb.DELETE
+	PermWatch     = authpb.WATCH
+	PermLease     = authpb.LEASE
 )
 
 type UserAddOptions authpb.UserAddOptions
@@ -60,6 +74,9 @@ type Auth interface {
 	// Authenticate login and get token
 	Authenticate(ctx context.Context, name string, password string) (*AuthenticateResponse, error)
 
+	// AuthenticateWithScope login and get a token restricted to the scope
+	AuthenticateWithScope(ctx context.Context, name string, password string, scope TokenScope) (*AuthenticateResponse, error)
+
 	// AuthEnable enables auth of an etcd cluster.
 	AuthEnable(ctx context.Context) (*AuthEnableResponse, error)
 
@@ -84,6 +101,10 @@ type Auth interface {
 	// UserGrantRole grants a role to a user.
 	UserGrantRole(ctx context.Context, user string, role string) (*AuthUserGrantRoleResponse, error)
 
+	// UserGrantRoleWithTTL grants a role to a user for ttl seconds, after which
+	// the role is revoked.
+	UserGrantRoleWithTTL(ctx context.Context, user string, role string, ttl int64) (*AuthUserGrantRoleResponse, error)
+
 	// UserGet gets a detailed information of a user.
 	UserGet(ctx context.Context, name string) (*AuthUserGetResponse, error)
 
@@ -110,6 +131,22 @@ type Auth interface {
 
 	// RoleDelete deletes a role.
 	RoleDelete(ctx context.Context, role string) (*AuthRoleDeleteResponse, error)
+
+	// UserSetLimits sets the limits of a user.
+	UserSetLimits(ctx context.Context, name string, limits Limits) (*AuthUserSetLimitsResponse, error)
+
+	// RoleSetLimits sets the limits of the users granted a role.
+	RoleSetLimits(ctx context.Context, role string, limits Limits) (*AuthRoleSetLimitsResponse, error)
+
+	// TokenList lists the active tokens of a user, or of all users if name is empty.
+	TokenList(ctx context.Context, name string) (*AuthTokenListResponse, error)
+
+	// TokenRevoke revokes an active token.
+	TokenRevoke(ctx context.Context, id uint64) (*AuthTokenRevokeResponse, error)
+
+	// AuthExplain evaluates whether a user is permitted a request of the given
+	// permission type on a key or range, and explains the decision per role.
+	AuthExplain(ctx context.Context, user string, permType PermissionType, key, rangeEnd string) (*AuthExplainResponse, error)
 }
 
 type authClient struct {
@@ -138,6 +175,11 @@ func (auth *authClient) Authenticate(ctx context.Context, name string, password
 	return (*AuthenticateResponse)(resp), toErr(ctx, err)
 }
 
+func (auth *authClient) AuthenticateWithScope(ctx context.Context, name string, password string, scope TokenScope) (*AuthenticateResponse, error) {
+	resp, err := auth.remote.Authenticate(ctx, &pb.AuthenticateRequest{Name: name, Password: password, Scope: (*authpb.TokenScope)(&scope)}, auth.callOpts...)
+	return (*AuthenticateResponse)(resp), toErr(ctx, err)
+}
+
 func (auth *authClient) AuthEnable(ctx context.Context) (*AuthEnableResponse, error) {
 	resp, err := auth.remote.AuthEnable(ctx, &pb.AuthEnableRequest{}, auth.callOpts...)
 	return (*AuthEnableResponse)(resp), toErr(ctx, err)
@@ -178,6 +220,11 @@ func (auth *authClient) UserGrantRole(ctx context.Context, user string, role str
 	return (*AuthUserGrantRoleResponse)(resp), toErr(ctx, err)
 }
 
+func (auth *authClient) UserGrantRoleWithTTL(ctx context.Context, user string, role string, ttl int64) (*AuthUserGrantRoleResponse, error) {
+	resp, err := auth.remote.UserGrantRole(ctx, &pb.AuthUserGrantRoleRequest{User: user, Role: role, Ttl: ttl}, auth.callOpts...)
+	return (*AuthUserGrantRoleResponse)(resp), toErr(ctx, err)
+}
+
 func (auth *authClient) UserGet(ctx context.Context, name string) (*AuthUserGetResponse, error) {
 	resp, err := auth.remote.UserGet(ctx, &pb.AuthUserGetRequest{Name: name}, auth.callOpts...)
 	return (*AuthUserGetResponse)(resp), toErr(ctx, err)
@@ -228,6 +275,37 @@ func (auth *authClient) RoleDelete(ctx context.Context, role string) (*AuthRoleD
 	return (*AuthRoleDeleteResponse)(resp), toErr(ctx, err)
 }
 
+func (auth *authClient) UserSetLimits(ctx context.Context, name string, limits Limits) (*AuthUserSetLimitsResponse, error) {
+	resp, err := auth.remote.UserSetLimits(ctx, &pb.AuthUserSetLimitsRequest{Name: name, Limits: (*authpb.Limits)(&limits)}, auth.callOpts...)
+	return (*AuthUserSetLimitsResponse)(resp), toErr(ctx, err)
+}
+
+func (auth *authClient) RoleSetLimits(ctx context.Context, role string, limits Limits) (*AuthRoleSetLimitsResponse, error) {
+	resp, err := auth.remote.RoleSetLimits(ctx, &pb.AuthRoleSetLimitsRequest{Role: role, Limits: (*authpb.Limits)(&limits)}, auth.callOpts...)
+	return (*AuthRoleSetLimitsResponse)(resp), toErr(ctx, err)
+}
+
+func (auth *authClient) TokenList(ctx context.Context, name string) (*AuthTokenListResponse, error) {
+	resp, err := auth.remote.TokenList(ctx, &pb.AuthTokenListRequest{Name: name}, auth.callOpts...)
+	return (*AuthTokenListResponse)(resp), toErr(ctx, err)
+}
+
+func (auth *authClient) TokenRevoke(ctx context.Context, id uint64) (*AuthTokenRevokeResponse, error) {
+	resp, err := auth.remote.TokenRevoke(ctx, &pb.AuthTokenRevokeRequest{ID: id}, auth.callOpts...)
+	return (*AuthTokenRevokeResponse)(resp), toErr(ctx, err)
+}
+
+func (auth *authClient) AuthExplain(ctx context.Context, user string, permType PermissionType, key, rangeEnd string) (*AuthExplainResponse, error) {
+	req := &pb.AuthExplainRequest{
+		User:     user,
+		PermType: authpb.Permission_Type(permType),
+		Key:      []byte(key),
+		RangeEnd: []byte(rangeEnd),
+	}
+	resp, err := auth.remote.AuthExplain(ctx, req, auth.callOpts...)
+	return (*AuthExplainResponse)(resp), toErr(ctx, err)
+}
+
 func StrToPermissionType(s string) (PermissionType, error) {
 	val, ok := authpb.Permission_Type_value[strings.ToUpper(s)]
 	if ok {
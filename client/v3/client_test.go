@@ -450,6 +450,10 @@ func (mm mockMaintenance) Snapshot(ctx context.Context) (io.ReadCloser, error) {
 	return nil, nil
 }
 
+func (mm mockMaintenance) SnapshotDelta(ctx context.Context, startRev, endRev int64) (io.ReadCloser, error) {
+	return nil, nil
+}
+
 func (mm mockMaintenance) MoveLeader(ctx context.Context, transfereeID uint64) (*MoveLeaderResponse, error) {
 	return nil, nil
 }
@@ -20,8 +20,9 @@ import (
 
 // CompactOp represents a compact operation.
 type CompactOp struct {
-	revision int64
-	physical bool
+	revision  int64
+	physical  bool
+	retention []*pb.CompactionRetention
 }
 
 // CompactOption configures compact operation.
@@ -41,7 +42,7 @@ func OpCompact(rev int64, opts ...CompactOption) CompactOp {
 }
 
 func (op CompactOp) toRequest() *pb.CompactionRequest {
-	return &pb.CompactionRequest{Revision: op.revision, Physical: op.physical}
+	return &pb.CompactionRequest{Revision: op.revision, Physical: op.physical, Retention: op.retention}
 }
 
 // WithCompactPhysical makes Compact wait until all compacted entries are
@@ -49,3 +50,12 @@ func (op CompactOp) toRequest() *pb.CompactionRequest {
 func WithCompactPhysical() CompactOption {
 	return func(op *CompactOp) { op.physical = true }
 }
+
+// WithCompactRetention keeps the history of the keys in [key, end) after the
+// given revision, even if the compaction revision is greater. If end is empty,
+// only key is retained. Retained ranges must not overlap.
+func WithCompactRetention(key, end string, rev int64) CompactOption {
+	return func(op *CompactOp) {
+		op.retention = append(op.retention, &pb.CompactionRetention{Key: []byte(key), RangeEnd: []byte(end), Revision: rev})
+	}
+}
@@ -28,3 +28,17 @@ func TestCompactOp(t *testing.T) {
 		t.Fatalf("expected %+v, got %+v", req2, req1)
 	}
 }
+
+func TestCompactOpWithRetention(t *testing.T) {
+	req1 := OpCompact(100, WithCompactRetention("a", "b", 50), WithCompactRetention("c", "", 60)).toRequest()
+	req2 := &etcdserverpb.CompactionRequest{
+		Revision: 100,
+		Retention: []*etcdserverpb.CompactionRetention{
+			{Key: []byte("a"), RangeEnd: []byte("b"), Revision: 50},
+			{Key: []byte("c"), RangeEnd: []byte{}, Revision: 60},
+		},
+	}
+	if !reflect.DeepEqual(req1, req2) {
+		t.Fatalf("expected %+v, got %+v", req2, req1)
+	}
+}
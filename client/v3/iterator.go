@@ -0,0 +1,88 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clientv3
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrRangeNotContinuable is returned by GetIterator when a page has more keys
+// but no continuation, which happens when the keys are not sorted by key in
+// ascending order.
+var ErrRangeNotContinuable = errors.New("etcdclient: range cannot be continued")
+
+// GetIterator pages through the keys of a range. Every page is read at the
+// revision of the first one, so that the pages are consistent with each other.
+// If that revision is compacted before the last page is read, the iterator
+// fails with rpctypes.ErrCompacted.
+//
+//	it := clientv3.NewGetIterator(kv, "foo", 100, clientv3.WithPrefix())
+//	for it.Next(ctx) {
+//		for _, kv := range it.Response().Kvs {
+//			...
+//		}
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type GetIterator struct {
+	kv   KV
+	key  string
+	opts []OpOption
+
+	resp *GetResponse
+	err  error
+	done bool
+}
+
+// NewGetIterator returns an iterator over the range given by key and opts,
+// getting at most pageSize keys per request.
+func NewGetIterator(kv KV, key string, pageSize int64, opts ...OpOption) *GetIterator {
+	return &GetIterator{
+		kv:   kv,
+		key:  key,
+		opts: append(append([]OpOption{}, opts...), WithLimit(pageSize)),
+	}
+}
+
+// Next gets the next page of the range. It returns false when there are no
+// more pages or when getting the page failed, in which case Err returns the error.
+func (it *GetIterator) Next(ctx context.Context) bool {
+	if it.done {
+		return false
+	}
+	opts := it.opts
+	if it.resp != nil {
+		opts = append(opts[:len(opts):len(opts)], WithContinuation(it.resp.Continuation))
+	}
+	resp, err := it.kv.Get(ctx, it.key, opts...)
+	if err != nil {
+		it.err, it.done = err, true
+		return false
+	}
+	if resp.More && len(resp.Continuation) == 0 {
+		it.err, it.done = ErrRangeNotContinuable, true
+		return false
+	}
+	it.resp, it.done = resp, !resp.More
+	return true
+}
+
+// Response returns the current page.
+func (it *GetIterator) Response() *GetResponse { return it.resp }
+
+// Err returns the error that stopped the iteration, if any.
+func (it *GetIterator) Err() error { return it.err }
@@ -29,6 +29,9 @@ type (
 	GetResponse     pb.RangeResponse
 	DeleteResponse  pb.DeleteRangeResponse
 	TxnResponse     pb.TxnResponse
+
+	RangeStatsResponse pb.RangeStatsResponse
+	MultiRangeResponse pb.MultiRangeResponse
 )
 
 type KV interface {
@@ -54,6 +57,18 @@ type KV interface {
 	// Compact compacts etcd KV history before the given rev.
 	Compact(ctx context.Context, rev int64, opts ...CompactOption) (*CompactResponse, error)
 
+	// RangeStats retrieves the number and the size of the keys selected like Get,
+	// aggregated by the child prefixes ending with the delimiter given by
+	// WithStatsDelimiter. It honors WithRev, WithSerializable and WithLimit,
+	// which bounds the number of child prefixes.
+	RangeStats(ctx context.Context, key string, opts ...OpOption) (*RangeStatsResponse, error)
+
+	// MultiRange retrieves the ranges of the given Get operations at a single revision.
+	// WithRange, WithPrefix, WithFromKey, WithLimit, WithKeysOnly, WithCountOnly and
+	// WithContinuation are honored per operation; the options given to MultiRange,
+	// WithRev and WithSerializable, apply to all the ranges.
+	MultiRange(ctx context.Context, ops []Op, opts ...OpOption) (*MultiRangeResponse, error)
+
 	// Do applies a single Op on KV without a transaction.
 	// Do is useful when creating arbitrary operations to be issued at a
 	// later time; the user can range over the operations, calling Do to
@@ -134,6 +149,22 @@ func (kv *kv) Compact(ctx context.Context, rev int64, opts ...CompactOption) (*C
 	return (*CompactResponse)(resp), err
 }
 
+func (kv *kv) RangeStats(ctx context.Context, key string, opts ...OpOption) (*RangeStatsResponse, error) {
+	resp, err := kv.remote.RangeStats(ctx, OpGet(key, opts...).toRangeStatsRequest(), kv.callOpts...)
+	if err != nil {
+		return nil, toErr(ctx, err)
+	}
+	return (*RangeStatsResponse)(resp), nil
+}
+
+func (kv *kv) MultiRange(ctx context.Context, ops []Op, opts ...OpOption) (*MultiRangeResponse, error) {
+	resp, err := kv.remote.MultiRange(ctx, toMultiRangeRequest(ops, opts), kv.callOpts...)
+	if err != nil {
+		return nil, toErr(ctx, err)
+	}
+	return (*MultiRangeResponse)(resp), nil
+}
+
 func (kv *kv) Txn(ctx context.Context) Txn {
 	return &txn{
 		kv:       kv,
@@ -157,7 +188,7 @@ func (kv *kv) Do(ctx context.Context, op Op) (OpResponse, error) {
 		}
 	case tPut:
 		var resp *pb.PutResponse
-		r := &pb.PutRequest{Key: op.key, Value: op.val, Lease: int64(op.leaseID), PrevKv: op.prevKV, IgnoreValue: op.ignoreValue, IgnoreLease: op.ignoreLease}
+		r := &pb.PutRequest{Key: op.key, Value: op.val, Lease: int64(op.leaseID), PrevKv: op.prevKV, IgnoreValue: op.ignoreValue, IgnoreLease: op.ignoreLease, Ttl: op.ttl}
 		resp, err = kv.remote.Put(ctx, r, kv.callOpts...)
 		if err == nil {
 			return OpResponse{put: (*PutResponse)(resp)}, nil
@@ -60,12 +60,19 @@ type LeaseTimeToLiveResponse struct {
 
 	// Keys is the list of keys attached to this lease.
 	Keys [][]byte `json:"keys"`
+
+	// Parent is the ID of the lease owning this lease, if any.
+	Parent LeaseID `json:"parent,omitempty"`
+
+	// Children is the list of the IDs of the leases owned by this lease.
+	Children []LeaseID `json:"children,omitempty"`
 }
 
 // LeaseStatus represents a lease status.
 type LeaseStatus struct {
 	ID LeaseID `json:"id"`
 	// TODO: TTL int64
+	Parent LeaseID `json:"parent,omitempty"`
 }
 
 // LeaseLeasesResponse wraps the protobuf message LeaseLeasesResponse.
@@ -109,6 +116,11 @@ type Lease interface {
 	// Grant creates a new lease.
 	Grant(ctx context.Context, ttl int64) (*LeaseGrantResponse, error)
 
+	// GrantChild creates a new lease owned by the given parent lease. The child lease
+	// expires and is renewed with its parent, so that keeping the parent alive keeps
+	// the whole group alive, and it is revoked with its parent.
+	GrantChild(ctx context.Context, parent LeaseID) (*LeaseGrantResponse, error)
+
 	// Revoke revokes the given lease.
 	Revoke(ctx context.Context, id LeaseID) (*LeaseRevokeResponse, error)
 
@@ -212,7 +224,14 @@ func NewLeaseFromLeaseClient(remote pb.LeaseClient, c *Client, keepAliveTimeout
 }
 
 func (l *lessor) Grant(ctx context.Context, ttl int64) (*LeaseGrantResponse, error) {
-	r := &pb.LeaseGrantRequest{TTL: ttl}
+	return l.grant(ctx, &pb.LeaseGrantRequest{TTL: ttl})
+}
+
+func (l *lessor) GrantChild(ctx context.Context, parent LeaseID) (*LeaseGrantResponse, error) {
+	return l.grant(ctx, &pb.LeaseGrantRequest{Parent: int64(parent)})
+}
+
+func (l *lessor) grant(ctx context.Context, r *pb.LeaseGrantRequest) (*LeaseGrantResponse, error) {
 	resp, err := l.remote.LeaseGrant(ctx, r, l.callOpts...)
 	if err == nil {
 		gresp := &LeaseGrantResponse{
@@ -247,6 +266,10 @@ func (l *lessor) TimeToLive(ctx context.Context, id LeaseID, opts ...LeaseOption
 		TTL:            resp.TTL,
 		GrantedTTL:     resp.GrantedTTL,
 		Keys:           resp.Keys,
+		Parent:         LeaseID(resp.Parent),
+	}
+	for _, id := range resp.Children {
+		gresp.Children = append(gresp.Children, LeaseID(id))
 	}
 	return gresp, nil
 }
@@ -256,7 +279,7 @@ func (l *lessor) Leases(ctx context.Context) (*LeaseLeasesResponse, error) {
 	if err == nil {
 		leases := make([]LeaseStatus, len(resp.Leases))
 		for i := range resp.Leases {
-			leases[i] = LeaseStatus{ID: LeaseID(resp.Leases[i].ID)}
+			leases[i] = LeaseStatus{ID: LeaseID(resp.Leases[i].ID), Parent: LeaseID(resp.Leases[i].Parent)}
 		}
 		return &LeaseLeasesResponse{ResponseHeader: resp.GetHeader(), Leases: leases}, nil
 	}
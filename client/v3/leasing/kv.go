@@ -117,6 +117,14 @@ func (lkv *leasingKV) Compact(ctx context.Context, rev int64, opts ...v3.Compact
 	return lkv.kv.Compact(ctx, rev, opts...)
 }
 
+func (lkv *leasingKV) RangeStats(ctx context.Context, key string, opts ...v3.OpOption) (*v3.RangeStatsResponse, error) {
+	return lkv.kv.RangeStats(ctx, key, opts...)
+}
+
+func (lkv *leasingKV) MultiRange(ctx context.Context, ops []v3.Op, opts ...v3.OpOption) (*v3.MultiRangeResponse, error) {
+	return lkv.kv.MultiRange(ctx, ops, opts...)
+}
+
 func (lkv *leasingKV) Txn(ctx context.Context) v3.Txn {
 	return &txnLeasing{Txn: lkv.kv.Txn(ctx), lkv: lkv, ctx: ctx}
 }
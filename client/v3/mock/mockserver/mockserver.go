@@ -187,6 +187,14 @@ func (m *mockKVServer) Compact(context.Context, *pb.CompactionRequest) (*pb.Comp
 	return &pb.CompactionResponse{}, nil
 }
 
+func (m *mockKVServer) RangeStats(context.Context, *pb.RangeStatsRequest) (*pb.RangeStatsResponse, error) {
+	return &pb.RangeStatsResponse{}, nil
+}
+
+func (m *mockKVServer) MultiRange(context.Context, *pb.MultiRangeRequest) (*pb.MultiRangeResponse, error) {
+	return &pb.MultiRangeResponse{}, nil
+}
+
 func (m *mockKVServer) Lease(context.Context, *pb.LeaseGrantRequest) (*pb.LeaseGrantResponse, error) {
 	return &pb.LeaseGrantResponse{}, nil
 }
@@ -98,6 +98,37 @@ func (kv *kvPrefix) Do(ctx context.Context, op clientv3.Op) (clientv3.OpResponse
 	return r, nil
 }
 
+func (kv *kvPrefix) RangeStats(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.RangeStatsResponse, error) {
+	if len(key) == 0 && !(clientv3.IsOptsWithFromKey(opts) || clientv3.IsOptsWithPrefix(opts)) {
+		return nil, rpctypes.ErrEmptyKey
+	}
+	op := kv.prefixOp(clientv3.OpGet(key, opts...))
+	// the prefixed range overrides the range given by the options
+	opts = append(opts, clientv3.WithRange(string(op.RangeBytes())))
+	resp, err := kv.KV.RangeStats(ctx, string(op.KeyBytes()), opts...)
+	if err != nil {
+		return nil, err
+	}
+	kv.unprefixRangeStatsResponse(resp)
+	return resp, nil
+}
+
+func (kv *kvPrefix) MultiRange(ctx context.Context, ops []clientv3.Op, opts ...clientv3.OpOption) (*clientv3.MultiRangeResponse, error) {
+	for _, op := range ops {
+		if len(op.KeyBytes()) == 0 {
+			return nil, rpctypes.ErrEmptyKey
+		}
+	}
+	resp, err := kv.KV.MultiRange(ctx, kv.prefixOps(ops), opts...)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range resp.Responses {
+		kv.unprefixGetResponse(&clientv3.GetResponse{Kvs: r.Kvs})
+	}
+	return resp, nil
+}
+
 type txnPrefix struct {
 	clientv3.Txn
 	kv *kvPrefix
@@ -148,6 +179,15 @@ func (kv *kvPrefix) unprefixGetResponse(resp *clientv3.GetResponse) {
 	}
 }
 
+func (kv *kvPrefix) unprefixRangeStatsResponse(resp *clientv3.RangeStatsResponse) {
+	if resp.Total != nil {
+		resp.Total.Prefix = resp.Total.Prefix[len(kv.pfx):]
+	}
+	for _, st := range resp.Children {
+		st.Prefix = st.Prefix[len(kv.pfx):]
+	}
+}
+
 func (kv *kvPrefix) unprefixPutResponse(resp *clientv3.PutResponse) {
 	if resp.PrevKv != nil {
 		resp.PrevKv.Key = resp.PrevKv.Key[len(kv.pfx):]
@@ -14,6 +14,8 @@
 
 package namespace
 
+import "strings"
+
 func prefixInterval(pfx string, key, end []byte) (pfxKey []byte, pfxEnd []byte) {
 	pfxKey = make([]byte, len(pfx)+len(key))
 	copy(pfxKey[copy(pfxKey, pfx):], key)
@@ -40,3 +42,17 @@ func prefixInterval(pfx string, key, end []byte) (pfxKey []byte, pfxEnd []byte)
 
 	return pfxKey, pfxEnd
 }
+
+// escapeGlob escapes the characters of s special to path.Match, so that the
+// pattern only matches s itself.
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '*', '?', '[', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
@@ -16,6 +16,7 @@ package namespace
 
 import (
 	"bytes"
+	"path"
 	"testing"
 )
 
@@ -73,3 +74,27 @@ func TestPrefixInterval(t *testing.T) {
 		}
 	}
 }
+
+func TestEscapeGlob(t *testing.T) {
+	tests := []struct {
+		pfx  string
+		key  string
+		want bool
+	}{
+		{pfx: "pfx/", key: "pfx/a/status", want: true},
+		{pfx: "p*x/", key: "p*x/a/status", want: true},
+		{pfx: "p*x/", key: "pax/a/status", want: false},
+		{pfx: "p?x[1]\\/", key: "p?x[1]\\/a/status", want: true},
+		{pfx: "p?x[1]\\/", key: "pax1/a/status", want: false},
+		{pfx: "\xff/", key: "\xff/a/status", want: true},
+	}
+	for i, tt := range tests {
+		matched, err := path.Match(escapeGlob(tt.pfx)+"*/status", tt.key)
+		if err != nil {
+			t.Fatalf("#%d: unexpected error %v", i, err)
+		}
+		if matched != tt.want {
+			t.Errorf("#%d: expected %q with prefix %q to match %v, got %v", i, tt.key, tt.pfx, tt.want, matched)
+		}
+	}
+}
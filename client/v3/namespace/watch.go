@@ -45,6 +45,10 @@ func (w *watcherPrefix) Watch(ctx context.Context, key string, opts ...clientv3.
 	if pfxEnd != nil {
 		opts = append(opts, clientv3.WithRange(string(pfxEnd)))
 	}
+	if glob := op.KeyGlob(); glob != "" {
+		// the pattern is matched against the prefixed keys
+		opts = append(opts, clientv3.WithKeyGlob(escapeGlob(w.pfx)+glob))
+	}
 
 	wch := w.Watcher.Watch(ctx, string(pfxBegin), opts...)
 
@@ -14,7 +14,11 @@
 
 package clientv3
 
-import pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+import (
+	"time"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+)
 
 type opType int
 
@@ -45,9 +49,27 @@ type Op struct {
 	minCreateRev int64
 	maxCreateRev int64
 
+	// for range, bounds the staleness of a read served by the member
+	maxStalenessRevs int64
+	maxStaleness     time.Duration
+	failStale        bool
+
+	// for range, watch, filters on the value and lease of the key
+	valuePrefix   []byte
+	valueContains []byte
+	minValueSize  int64
+	maxValueSize  int64
+	valueLease    LeaseID
+
 	// for range, watch
 	rev int64
 
+	// for range stats
+	statsDelimiter []byte
+
+	// for range and multi range, resumes a paginated range
+	continuation []byte
+
 	// for watch, put, delete
 	prevKV bool
 
@@ -60,6 +82,7 @@ type Op struct {
 	// for put
 	ignoreValue bool
 	ignoreLease bool
+	ttl         int64
 
 	// progressNotify is for progress updates.
 	progressNotify bool
@@ -68,6 +91,9 @@ type Op struct {
 	// filters for watchers
 	filterPut    bool
 	filterDelete bool
+	keyGlob      string
+	// resumeToken resumes the watcher where a previous one stopped
+	resumeToken []byte
 
 	// for put
 	val     []byte
@@ -140,6 +166,29 @@ func (op Op) MinCreateRev() int64 { return op.minCreateRev }
 // MaxCreateRev returns the operation's maximum create revision.
 func (op Op) MaxCreateRev() int64 { return op.maxCreateRev }
 
+// KeyGlob returns the pattern the keys of the watch events must match.
+func (op Op) KeyGlob() string { return op.keyGlob }
+
+// hasValueFilter returns true if any of the value filters is set.
+func (op Op) hasValueFilter() bool {
+	return len(op.valuePrefix) != 0 || len(op.valueContains) != 0 ||
+		op.minValueSize != 0 || op.maxValueSize != 0 || op.valueLease != 0
+}
+
+// valueFilter returns the value filters of the Op, or nil if none is set.
+func (op Op) valueFilter() *pb.ValueFilter {
+	if !op.hasValueFilter() {
+		return nil
+	}
+	return &pb.ValueFilter{
+		Prefix:   op.valuePrefix,
+		Contains: op.valueContains,
+		MinSize:  op.minValueSize,
+		MaxSize:  op.maxValueSize,
+		Lease:    int64(op.valueLease),
+	}
+}
+
 // WithRangeBytes sets the byte slice for the Op's range end.
 func (op *Op) WithRangeBytes(end []byte) { op.end = end }
 
@@ -165,14 +214,62 @@ func (op Op) toRangeRequest() *pb.RangeRequest {
 		MaxModRevision:    op.maxModRev,
 		MinCreateRevision: op.minCreateRev,
 		MaxCreateRevision: op.maxCreateRev,
+		Continuation:      op.continuation,
+
+		MaxStalenessRevisions: op.maxStalenessRevs,
+		MaxStalenessMs:        int64((op.maxStaleness + time.Millisecond - 1) / time.Millisecond),
+		FailStale:             op.failStale,
 	}
 	if op.sort != nil {
 		r.SortOrder = pb.RangeRequest_SortOrder(op.sort.Order)
 		r.SortTarget = pb.RangeRequest_SortTarget(op.sort.Target)
 	}
+	r.ValueFilter = op.valueFilter()
 	return r
 }
 
+func (op Op) toSubRange() *pb.SubRange {
+	if op.t != tRange {
+		panic("op.t != tRange")
+	}
+	return &pb.SubRange{
+		Key:          op.key,
+		RangeEnd:     op.end,
+		Limit:        op.limit,
+		KeysOnly:     op.keysOnly,
+		CountOnly:    op.countOnly,
+		Continuation: op.continuation,
+	}
+}
+
+func toMultiRangeRequest(ops []Op, opts []OpOption) *pb.MultiRangeRequest {
+	var mop Op
+	mop.applyOpts(opts)
+	r := &pb.MultiRangeRequest{
+		Ranges:       make([]*pb.SubRange, len(ops)),
+		Revision:     mop.rev,
+		Serializable: mop.serializable,
+	}
+	for i := range ops {
+		r.Ranges[i] = ops[i].toSubRange()
+	}
+	return r
+}
+
+func (op Op) toRangeStatsRequest() *pb.RangeStatsRequest {
+	if op.t != tRange {
+		panic("op.t != tRange")
+	}
+	return &pb.RangeStatsRequest{
+		Key:          op.key,
+		RangeEnd:     op.end,
+		Delimiter:    op.statsDelimiter,
+		Revision:     op.rev,
+		Serializable: op.serializable,
+		Limit:        op.limit,
+	}
+}
+
 func (op Op) toTxnRequest() *pb.TxnRequest {
 	thenOps := make([]*pb.RequestOp, len(op.thenOps))
 	for i, tOp := range op.thenOps {
@@ -194,7 +291,7 @@ func (op Op) toRequestOp() *pb.RequestOp {
 	case tRange:
 		return &pb.RequestOp{Request: &pb.RequestOp_RequestRange{RequestRange: op.toRangeRequest()}}
 	case tPut:
-		r := &pb.PutRequest{Key: op.key, Value: op.val, Lease: int64(op.leaseID), PrevKv: op.prevKV, IgnoreValue: op.ignoreValue, IgnoreLease: op.ignoreLease}
+		r := &pb.PutRequest{Key: op.key, Value: op.val, Lease: int64(op.leaseID), PrevKv: op.prevKV, IgnoreValue: op.ignoreValue, IgnoreLease: op.ignoreLease, Ttl: op.ttl}
 		return &pb.RequestOp{Request: &pb.RequestOp_RequestPut{RequestPut: r}}
 	case tDeleteRange:
 		r := &pb.DeleteRangeRequest{Key: op.key, RangeEnd: op.end, PrevKv: op.prevKV}
@@ -249,6 +346,8 @@ func OpDelete(key string, opts ...OpOption) Op {
 	switch {
 	case ret.leaseID != 0:
 		panic("unexpected lease in delete")
+	case ret.ttl != 0:
+		panic("unexpected ttl in delete")
 	case ret.limit != 0:
 		panic("unexpected limit in delete")
 	case ret.rev != 0:
@@ -263,7 +362,9 @@ func OpDelete(key string, opts ...OpOption) Op {
 		panic("unexpected mod revision filter in delete")
 	case ret.minCreateRev != 0, ret.maxCreateRev != 0:
 		panic("unexpected create revision filter in delete")
-	case ret.filterDelete, ret.filterPut:
+	case ret.hasValueFilter():
+		panic("unexpected value filter in delete")
+	case ret.filterDelete, ret.filterPut, ret.keyGlob != "":
 		panic("unexpected filter in delete")
 	case ret.createdNotify:
 		panic("unexpected createdNotify in delete")
@@ -292,7 +393,9 @@ func OpPut(key, val string, opts ...OpOption) Op {
 		panic("unexpected mod revision filter in put")
 	case ret.minCreateRev != 0, ret.maxCreateRev != 0:
 		panic("unexpected create revision filter in put")
-	case ret.filterDelete, ret.filterPut:
+	case ret.hasValueFilter():
+		panic("unexpected value filter in put")
+	case ret.filterDelete, ret.filterPut, ret.keyGlob != "":
 		panic("unexpected filter in put")
 	case ret.createdNotify:
 		panic("unexpected createdNotify in put")
@@ -458,6 +561,66 @@ func WithMinCreateRev(rev int64) OpOption { return func(op *Op) { op.minCreateRe
 // WithMaxCreateRev filters out keys for Get with creation revisions greater than the given revision.
 func WithMaxCreateRev(rev int64) OpOption { return func(op *Op) { op.maxCreateRev = rev } }
 
+// WithValueFilterPrefix filters out keys for Get and events for Watch whose values do not start
+// with the given prefix. The filter is evaluated by the server before the limit is applied, so
+// the count and the more flag of the response only account for the matching keys.
+// Watch only receives delete events if an empty value satisfies all the value filters.
+func WithValueFilterPrefix(prefix string) OpOption {
+	return func(op *Op) { op.valuePrefix = []byte(prefix) }
+}
+
+// WithValueFilterContains filters out keys for Get and events for Watch whose values do not contain the given substring.
+func WithValueFilterContains(substr string) OpOption {
+	return func(op *Op) { op.valueContains = []byte(substr) }
+}
+
+// WithValueFilterMinSize filters out keys for Get and events for Watch whose values are smaller than the given number of bytes.
+func WithValueFilterMinSize(size int64) OpOption { return func(op *Op) { op.minValueSize = size } }
+
+// WithValueFilterMaxSize filters out keys for Get and events for Watch whose values are larger than the given number of bytes.
+// If WithValueFilterMaxSize is given a 0 size, it is treated as no upper bound.
+func WithValueFilterMaxSize(size int64) OpOption { return func(op *Op) { op.maxValueSize = size } }
+
+// WithValueFilterLease filters out keys for Get and events for Watch that are not attached to the given lease.
+func WithValueFilterLease(leaseID LeaseID) OpOption {
+	return func(op *Op) { op.valueLease = leaseID }
+}
+
+// WithStatsDelimiter makes RangeStats aggregate the keys by the prefixes ending with
+// the first delimiter that follows the bytes shared with the requested key.
+func WithStatsDelimiter(delimiter string) OpOption {
+	return func(op *Op) { op.statsDelimiter = []byte(delimiter) }
+}
+
+// WithContinuation resumes a Get, or a range of MultiRange, where the response
+// holding the given continuation stopped, at the revision of that response.
+// The range must be the same.
+func WithContinuation(token []byte) OpOption {
+	return func(op *Op) { op.continuation = token }
+}
+
+// WithMaxStalenessRevisions lets the member serve the 'Get' request locally if
+// it is at most the given number of revisions behind the commit index the
+// leader last sent it. Otherwise the request is linearizable, unless
+// WithFailStale is set.
+func WithMaxStalenessRevisions(revs int64) OpOption {
+	return func(op *Op) { op.maxStalenessRevs = revs }
+}
+
+// WithMaxStaleness lets the member serve the 'Get' request locally if it has
+// applied the commit index the leader sent it at most the given duration ago.
+// Otherwise the request is linearizable, unless WithFailStale is set.
+func WithMaxStaleness(d time.Duration) OpOption {
+	return func(op *Op) { op.maxStaleness = d }
+}
+
+// WithFailStale fails the 'Get' request with rpctypes.ErrStaleRead, instead of
+// making it linearizable, if the member is staler than the bounds set by
+// WithMaxStalenessRevisions and WithMaxStaleness.
+func WithFailStale() OpOption {
+	return func(op *Op) { op.failStale = true }
+}
+
 // WithFirstCreate gets the key with the oldest creation revision in the request range.
 func WithFirstCreate() []OpOption { return withTop(SortByCreateRevision, SortAscend) }
 
@@ -507,6 +670,20 @@ func WithFilterDelete() OpOption {
 	return func(op *Op) { op.filterDelete = true }
 }
 
+// WithKeyGlob discards events from the watcher whose keys do not match the
+// given pattern. The pattern syntax is the one of path.Match; the keys of
+// the watched range are still selected by the key and the range end.
+func WithKeyGlob(pattern string) OpOption {
+	return func(op *Op) { op.keyGlob = pattern }
+}
+
+// WithResumeToken resumes the watcher right after the last response of a
+// previous watcher on the same key range and filters, given its ResumeToken.
+// It takes precedence over WithRev on servers that support resume tokens.
+func WithResumeToken(token []byte) OpOption {
+	return func(op *Op) { op.resumeToken = token }
+}
+
 // WithPrevKV gets the previous key-value pair before the event happens. If the previous KV is already compacted,
 // nothing will be returned.
 func WithPrevKV() OpOption {
@@ -544,6 +721,14 @@ func WithIgnoreLease() OpOption {
 	}
 }
 
+// WithTTL makes the put key expire after the given number of seconds, without
+// a lease. The server deletes the key once it expires.
+func WithTTL(ttl int64) OpOption {
+	return func(op *Op) {
+		op.ttl = ttl
+	}
+}
+
 // LeaseOp represents an Operation that lease can execute.
 type LeaseOp struct {
 	id LeaseID
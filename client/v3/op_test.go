@@ -37,6 +37,34 @@ func TestOpWithSort(t *testing.T) {
 	}
 }
 
+// TestOpWithValueFilter tests that the value filter options are only sent
+// in RangeRequest when at least one of them is given.
+func TestOpWithValueFilter(t *testing.T) {
+	req := OpGet("foo", WithPrefix()).toRangeRequest()
+	if req.ValueFilter != nil {
+		t.Fatalf("expected no value filter, got %+v", req.ValueFilter)
+	}
+
+	req = OpGet("foo", WithPrefix(), WithValueFilterPrefix("bar"), WithValueFilterMaxSize(10), WithValueFilterLease(5)).toRangeRequest()
+	wf := &pb.ValueFilter{Prefix: []byte("bar"), MaxSize: 10, Lease: 5}
+	if !reflect.DeepEqual(req.ValueFilter, wf) {
+		t.Fatalf("expected %+v, got %+v", wf, req.ValueFilter)
+	}
+}
+
+// TestOpWatchWithFilters tests that the value filter and the key glob
+// options are accepted by watch and carried by its create request.
+func TestOpWatchWithFilters(t *testing.T) {
+	ow := opWatch("foo", WithPrefix(), WithValueFilterContains("bar"), WithKeyGlob("foo/*/baz"))
+	if ow.keyGlob != "foo/*/baz" {
+		t.Fatalf("expected key glob %q, got %q", "foo/*/baz", ow.keyGlob)
+	}
+	wf := &pb.ValueFilter{Contains: []byte("bar")}
+	if !reflect.DeepEqual(ow.valueFilter(), wf) {
+		t.Fatalf("expected %+v, got %+v", wf, ow.valueFilter())
+	}
+}
+
 func TestIsSortOptionValid(t *testing.T) {
 	rangeReqs := []struct {
 		sortOrder     pb.RangeRequest_SortOrder
@@ -105,6 +105,14 @@ func (rkv *retryKVClient) Range(ctx context.Context, in *pb.RangeRequest, opts .
 	return rkv.kc.Range(ctx, in, append(opts, withRetryPolicy(repeatable))...)
 }
 
+func (rkv *retryKVClient) RangeStats(ctx context.Context, in *pb.RangeStatsRequest, opts ...grpc.CallOption) (resp *pb.RangeStatsResponse, err error) {
+	return rkv.kc.RangeStats(ctx, in, append(opts, withRetryPolicy(repeatable))...)
+}
+
+func (rkv *retryKVClient) MultiRange(ctx context.Context, in *pb.MultiRangeRequest, opts ...grpc.CallOption) (resp *pb.MultiRangeResponse, err error) {
+	return rkv.kc.MultiRange(ctx, in, append(opts, withRetryPolicy(repeatable))...)
+}
+
 func (rkv *retryKVClient) Put(ctx context.Context, in *pb.PutRequest, opts ...grpc.CallOption) (resp *pb.PutResponse, err error) {
 	return rkv.kc.Put(ctx, in, opts...)
 }
@@ -214,6 +222,10 @@ func (rmc *retryMaintenanceClient) Snapshot(ctx context.Context, in *pb.Snapshot
 	return rmc.mc.Snapshot(ctx, in, append(opts, withRetryPolicy(repeatable))...)
 }
 
+func (rmc *retryMaintenanceClient) SnapshotDelta(ctx context.Context, in *pb.SnapshotDeltaRequest, opts ...grpc.CallOption) (stream pb.Maintenance_SnapshotDeltaClient, err error) {
+	return rmc.mc.SnapshotDelta(ctx, in, append(opts, withRetryPolicy(repeatable))...)
+}
+
 func (rmc *retryMaintenanceClient) MoveLeader(ctx context.Context, in *pb.MoveLeaderRequest, opts ...grpc.CallOption) (resp *pb.MoveLeaderResponse, err error) {
 	return rmc.mc.MoveLeader(ctx, in, append(opts, withRetryPolicy(repeatable))...)
 }
@@ -301,6 +313,30 @@ func (rac *retryAuthClient) RoleRevokePermission(ctx context.Context, in *pb.Aut
 	return rac.ac.RoleRevokePermission(ctx, in, opts...)
 }
 
+func (rac *retryAuthClient) UserSetLimits(ctx context.Context, in *pb.AuthUserSetLimitsRequest, opts ...grpc.CallOption) (resp *pb.AuthUserSetLimitsResponse, err error) {
+	return rac.ac.UserSetLimits(ctx, in, opts...)
+}
+
+func (rac *retryAuthClient) RoleSetLimits(ctx context.Context, in *pb.AuthRoleSetLimitsRequest, opts ...grpc.CallOption) (resp *pb.AuthRoleSetLimitsResponse, err error) {
+	return rac.ac.RoleSetLimits(ctx, in, opts...)
+}
+
+func (rac *retryAuthClient) TokenList(ctx context.Context, in *pb.AuthTokenListRequest, opts ...grpc.CallOption) (resp *pb.AuthTokenListResponse, err error) {
+	return rac.ac.TokenList(ctx, in, append(opts, withRetryPolicy(repeatable))...)
+}
+
+func (rac *retryAuthClient) TokenRevoke(ctx context.Context, in *pb.AuthTokenRevokeRequest, opts ...grpc.CallOption) (resp *pb.AuthTokenRevokeResponse, err error) {
+	return rac.ac.TokenRevoke(ctx, in, opts...)
+}
+
+func (rac *retryAuthClient) AuthExplain(ctx context.Context, in *pb.AuthExplainRequest, opts ...grpc.CallOption) (resp *pb.AuthExplainResponse, err error) {
+	return rac.ac.AuthExplain(ctx, in, append(opts, withRetryPolicy(repeatable))...)
+}
+
+func (rac *retryAuthClient) AuthWatch(ctx context.Context, in *pb.AuthWatchRequest, opts ...grpc.CallOption) (stream pb.Auth_AuthWatchClient, err error) {
+	return rac.ac.AuthWatch(ctx, in, append(opts, withRetryPolicy(repeatable))...)
+}
+
 func (rac *retryAuthClient) Authenticate(ctx context.Context, in *pb.AuthenticateRequest, opts ...grpc.CallOption) (resp *pb.AuthenticateResponse, err error) {
 	return rac.ac.Authenticate(ctx, in, opts...)
 }
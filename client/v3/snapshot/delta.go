@@ -0,0 +1,146 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"go.uber.org/zap"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/client/pkg/v3/fileutil"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// SaveDelta fetches the changes made to the key space after startRev and up to
+// endRev from remote etcd server, and saves them to target path. The delta can
+// then be replayed onto a snapshot saved at startRev to restore the key space at
+// endRev. If endRev is zero, the current revision is used. It returns the end
+// revision of the delta, which the next delta of a chain starts at.
+func SaveDelta(ctx context.Context, lg *zap.Logger, cfg clientv3.Config, startRev, endRev int64, deltaPath string) (int64, error) {
+	cfg.Logger = lg.Named("client")
+	if len(cfg.Endpoints) != 1 {
+		return 0, fmt.Errorf("snapshot delta must be requested to one selected node, not multiple %v", cfg.Endpoints)
+	}
+	cli, err := clientv3.New(cfg)
+	if err != nil {
+		return 0, err
+	}
+	defer cli.Close()
+
+	partpath := deltaPath + ".part"
+	defer os.RemoveAll(partpath)
+
+	var f *os.File
+	f, err = os.OpenFile(partpath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, fileutil.PrivateFileMode)
+	if err != nil {
+		return 0, fmt.Errorf("could not open %s (%v)", partpath, err)
+	}
+	defer f.Close()
+	lg.Info("created temporary delta file", zap.String("path", partpath))
+
+	start := time.Now()
+	rc, err := cli.SnapshotDelta(ctx, startRev, endRev)
+	if err != nil {
+		return 0, err
+	}
+	defer rc.Close()
+	lg.Info("fetching snapshot delta", zap.String("endpoint", cfg.Endpoints[0]))
+	var last *pb.SnapshotDeltaResponse
+	err = readDelta(io.TeeReader(rc, f), func(resp *pb.SnapshotDeltaResponse) error {
+		last = resp
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err = fileutil.Fsync(f); err != nil {
+		return 0, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	size := fi.Size()
+	if err = f.Close(); err != nil {
+		return 0, err
+	}
+	lg.Info("fetched snapshot delta",
+		zap.String("endpoint", cfg.Endpoints[0]),
+		zap.Int64("start-revision", last.StartRevision),
+		zap.Int64("end-revision", last.EndRevision),
+		zap.String("size", humanize.Bytes(uint64(size))),
+		zap.Duration("took", time.Since(start)),
+	)
+
+	if err = os.Rename(partpath, deltaPath); err != nil {
+		return 0, fmt.Errorf("could not rename %s to %s (%v)", partpath, deltaPath, err)
+	}
+	lg.Info("saved", zap.String("path", deltaPath))
+	return last.EndRevision, nil
+}
+
+// ReadDelta calls fn with every message of the delta saved at deltaPath by
+// SaveDelta, in order. The last message has Done set.
+func ReadDelta(deltaPath string, fn func(*pb.SnapshotDeltaResponse) error) error {
+	f, err := os.Open(deltaPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return readDelta(f, fn)
+}
+
+func readDelta(r io.Reader, fn func(*pb.SnapshotDeltaResponse) error) error {
+	br := bufio.NewReader(r)
+	var buf []byte
+	for {
+		n, err := binary.ReadUvarint(br)
+		if err == io.EOF {
+			return fmt.Errorf("snapshot delta is truncated")
+		}
+		if err != nil {
+			return err
+		}
+		if uint64(cap(buf)) < n {
+			buf = make([]byte, n)
+		}
+		if _, err = io.ReadFull(br, buf[:n]); err != nil {
+			return fmt.Errorf("snapshot delta is truncated: %w", err)
+		}
+		resp := &pb.SnapshotDeltaResponse{}
+		if err = resp.Unmarshal(buf[:n]); err != nil {
+			return err
+		}
+		if err = fn(resp); err != nil {
+			return err
+		}
+		if resp.Done {
+			if _, err = br.ReadByte(); err == nil {
+				return fmt.Errorf("unexpected data after the end of snapshot delta")
+			} else if err != io.EOF {
+				return err
+			}
+			return nil
+		}
+	}
+}
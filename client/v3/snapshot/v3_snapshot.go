@@ -15,6 +15,7 @@
 package snapshot
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
 	"fmt"
@@ -100,3 +101,69 @@ func SaveWithVersion(ctx context.Context, lg *zap.Logger, cfg clientv3.Config, d
 	lg.Info("saved", zap.String("path", dbPath))
 	return resp.Version, nil
 }
+
+// Copy copies the snapshot saved at srcPath by SaveWithVersion to dbPath,
+// truncating away the sha256 checksum appended to it once checked. Unless
+// skipHashCheck is true, snapshots missing the checksum, such as a db file
+// copied from a data directory, are rejected.
+func Copy(srcPath, dbPath string, skipHashCheck bool) error {
+	srcf, err := os.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer srcf.Close()
+
+	// get snapshot integrity hash
+	if _, err = srcf.Seek(-sha256.Size, io.SeekEnd); err != nil {
+		return err
+	}
+	sha := make([]byte, sha256.Size)
+	if _, err = srcf.Read(sha); err != nil {
+		return err
+	}
+	if _, err = srcf.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	db, err := os.OpenFile(dbPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if _, err = io.Copy(db, srcf); err != nil {
+		return err
+	}
+
+	// truncate away integrity hash, if any.
+	off, err := db.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	hasHash := hasChecksum(off)
+	if hasHash {
+		if err = db.Truncate(off - sha256.Size); err != nil {
+			return err
+		}
+	}
+
+	if !hasHash && !skipHashCheck {
+		return fmt.Errorf("snapshot missing hash but --skip-hash-check=false")
+	}
+
+	if hasHash && !skipHashCheck {
+		// check for match
+		if _, err = db.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+		h := sha256.New()
+		if _, err = io.Copy(h, db); err != nil {
+			return err
+		}
+		dbsha := h.Sum(nil)
+		if !bytes.Equal(sha, dbsha) {
+			return fmt.Errorf("expected sha256 %v, got %v", sha, dbsha)
+		}
+	}
+	return nil
+}
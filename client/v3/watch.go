@@ -108,6 +108,10 @@ type WatchResponse struct {
 	// Created is used to indicate the creation of the watcher.
 	Created bool
 
+	// ResumeToken resumes a watcher right after this response, see
+	// WithResumeToken. It is nil if the server does not issue resume tokens.
+	ResumeToken []byte
+
 	closeErr error
 
 	// cancelReason is a reason of canceling watch
@@ -219,8 +223,15 @@ type watchRequest struct {
 
 	// filters is the list of events to filter out
 	filters []pb.WatchCreateRequest_FilterType
+	// valueFilter filters out events by the value of the key
+	valueFilter *pb.ValueFilter
+	// keyGlob filters out events whose keys do not match the pattern
+	keyGlob string
 	// get the previous key-value pair before the event happens
 	prevKV bool
+	// resumeToken is the resume token of the last response received, the
+	// server resumes from it rather than from rev if set
+	resumeToken []byte
 	// retc receives a chan WatchResponse once the watcher is established
 	retc chan chan WatchResponse
 }
@@ -319,7 +330,10 @@ func (w *watcher) Watch(ctx context.Context, key string, opts ...OpOption) Watch
 		progressNotify: ow.progressNotify,
 		fragment:       ow.fragment,
 		filters:        filters,
+		valueFilter:    ow.valueFilter(),
+		keyGlob:        ow.keyGlob,
 		prevKV:         ow.prevKV,
+		resumeToken:    ow.resumeToken,
 		retc:           make(chan chan WatchResponse, 1),
 	}
 
@@ -630,7 +644,7 @@ func (w *watchGrpcStream) run() {
 				// reset for next iteration
 				cur = nil
 
-			case pbresp.Canceled && pbresp.CompactRevision == 0:
+			case pbresp.Canceled && pbresp.CompactRevision == 0 && pbresp.CancelReason == "":
 				delete(cancelSet, pbresp.WatchId)
 				if ws, ok := w.substreams[pbresp.WatchId]; ok {
 					// signal to stream goroutine to update closingc
@@ -685,6 +699,8 @@ func (w *watchGrpcStream) run() {
 			if wc, closeErr = w.newWatchClient(); closeErr != nil {
 				return
 			}
+			// fragments of the broken stream are resent in full on resume
+			cur = nil
 			if ws := w.nextResume(); ws != nil {
 				if err := wc.Send(ws.initReq.toPB()); err != nil {
 					w.lg.Debug("error when sending request", zap.Error(err))
@@ -754,6 +770,7 @@ func (w *watchGrpcStream) dispatchEvent(pbresp *pb.WatchResponse) bool {
 		CompactRevision: pbresp.CompactRevision,
 		Created:         pbresp.Created,
 		Canceled:        pbresp.Canceled,
+		ResumeToken:     pbresp.ResumeToken,
 		cancelReason:    pbresp.CancelReason,
 	}
 
@@ -878,9 +895,15 @@ func (w *watchGrpcStream) serveSubstream(ws *watcherStream, resumec chan struct{
 						nextRev = wr.Header.Revision
 					}
 				}
+				if wr.ResumeToken != nil {
+					ws.initReq.resumeToken = wr.ResumeToken
+				}
 			} else {
 				// current progress of watch; <= store revision
 				nextRev = wr.Header.Revision + 1
+				// broadcast progress notifications carry no resume token,
+				// the watcher then resumes from nextRev
+				ws.initReq.resumeToken = wr.ResumeToken
 			}
 
 			if len(wr.Events) > 0 {
@@ -1053,8 +1076,11 @@ func (wr *watchRequest) toPB() *pb.WatchRequest {
 		RangeEnd:       []byte(wr.end),
 		ProgressNotify: wr.progressNotify,
 		Filters:        wr.filters,
+		ValueFilter:    wr.valueFilter,
+		KeyGlob:        wr.keyGlob,
 		PrevKv:         wr.prevKV,
 		Fragment:       wr.fragment,
+		ResumeToken:    wr.resumeToken,
 	}
 	cr := &pb.WatchRequest_CreateRequest{CreateRequest: req}
 	return &pb.WatchRequest{RequestUnion: cr}
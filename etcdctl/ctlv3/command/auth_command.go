@@ -16,10 +16,14 @@ package command
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 
+	"go.etcd.io/etcd/api/v3/authpb"
 	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.etcd.io/etcd/pkg/v3/cobrautl"
 )
 
@@ -33,6 +37,10 @@ func NewAuthCommand() *cobra.Command {
 	ac.AddCommand(newAuthEnableCommand())
 	ac.AddCommand(newAuthDisableCommand())
 	ac.AddCommand(newAuthStatusCommand())
+	ac.AddCommand(newAuthTokenCommand())
+	ac.AddCommand(newAuthExplainCommand())
+	ac.AddCommand(newAuthExportCommand())
+	ac.AddCommand(newAuthImportCommand())
 
 	return ac
 }
@@ -122,3 +130,173 @@ func authDisableCommandFunc(cmd *cobra.Command, args []string) {
 
 	fmt.Println("Authentication Disabled")
 }
+
+var (
+	tokenScopeRoles  []string
+	tokenScopePrefix string
+)
+
+func newAuthTokenCommand() *cobra.Command {
+	tc := &cobra.Command{
+		Use:   "token <subcommand>",
+		Short: "Token related commands",
+	}
+
+	tc.AddCommand(&cobra.Command{
+		Use:   "list [user name]",
+		Short: "Lists the active tokens of a user, or of all users",
+		Run:   authTokenListCommandFunc,
+	})
+	tc.AddCommand(&cobra.Command{
+		Use:   "revoke <token ID>",
+		Short: "Revokes an active token",
+		Run:   authTokenRevokeCommandFunc,
+	})
+
+	ic := &cobra.Command{
+		Use:   "issue",
+		Short: "Issues a token for the user of the command, optionally restricted in scope",
+		Run:   authTokenIssueCommandFunc,
+	}
+	ic.Flags().StringSliceVar(&tokenScopeRoles, "role", nil, "Restrict the token to the given roles of the user")
+	ic.Flags().StringVar(&tokenScopePrefix, "prefix", "", "Restrict the token to the keys with the given prefix")
+	tc.AddCommand(ic)
+
+	return tc
+}
+
+// authTokenListCommandFunc executes the "auth token list" command.
+func authTokenListCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) > 1 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("auth token list command accepts at most one argument"))
+	}
+	var name string
+	if len(args) == 1 {
+		name = args[0]
+	}
+
+	ctx, cancel := commandCtx(cmd)
+	resp, err := mustClientFromCmd(cmd).Auth.TokenList(ctx, name)
+	cancel()
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+
+	display.TokenList(*resp)
+}
+
+// authTokenRevokeCommandFunc executes the "auth token revoke" command.
+func authTokenRevokeCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("auth token revoke command requires token ID as its argument"))
+	}
+	id, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("bad token ID %q: %v", args[0], err))
+	}
+
+	ctx, cancel := commandCtx(cmd)
+	resp, err := mustClientFromCmd(cmd).Auth.TokenRevoke(ctx, id)
+	cancel()
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+
+	display.TokenRevoke(id, *resp)
+}
+
+// authTokenIssueCommandFunc executes the "auth token issue" command.
+func authTokenIssueCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 0 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("auth token issue command does not accept any arguments"))
+	}
+	cfg := clientConfigFromCmd(cmd)
+	if cfg.Auth == nil {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("auth token issue command requires the --user flag"))
+	}
+
+	scope := clientv3.TokenScope{Roles: tokenScopeRoles, Prefix: []byte(tokenScopePrefix)}
+	ctx, cancel := commandCtx(cmd)
+	resp, err := mustClient(cfg).Auth.AuthenticateWithScope(ctx, cfg.Auth.Username, cfg.Auth.Password, scope)
+	cancel()
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+
+	fmt.Println(resp.Token)
+}
+
+var (
+	explainOp     string
+	explainPrefix bool
+)
+
+func newAuthExplainCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "explain [options] <user name> <key> [endkey]",
+		Short: "Explains whether a user is permitted an operation on a key or range",
+		Long: `Explains whether a user is permitted an operation on a key or range, and
+which permissions of each role of the user match it.
+
+The operation is one of get, put, create, delete, watch and lease, or a
+permission type such as read or write.
+`,
+		Run: authExplainCommandFunc,
+	}
+	cmd.Flags().StringVar(&explainOp, "op", "", "Operation to explain")
+	cmd.Flags().BoolVar(&explainPrefix, "prefix", false, "Explain the operation on the keys with the given prefix")
+	return cmd
+}
+
+// authExplainCommandFunc executes the "auth explain" command.
+func authExplainCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) < 2 || len(args) > 3 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("auth explain command requires user name and key as its arguments"))
+	}
+	permType, err := opToPermissionType(explainOp)
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, err)
+	}
+	key, rangeEnd := args[1], ""
+	if len(args) == 3 {
+		if explainPrefix {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("unexpected endkey argument with --prefix flag"))
+		}
+		rangeEnd = args[2]
+	} else if explainPrefix {
+		rangeEnd = clientv3.GetPrefixRangeEnd(key)
+	}
+
+	ctx, cancel := commandCtx(cmd)
+	resp, err := mustClientFromCmd(cmd).Auth.AuthExplain(ctx, args[0], permType, key, rangeEnd)
+	cancel()
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+
+	display.AuthExplain(args[0], *resp)
+}
+
+// opToPermissionType returns the permission type an operation requires.
+func opToPermissionType(op string) (clientv3.PermissionType, error) {
+	ops := map[string]authpb.Permission_Type{
+		"get":    authpb.READ,
+		"range":  authpb.READ,
+		"put":    authpb.WRITE,
+		"create": authpb.CREATE,
+		"delete": authpb.DELETE,
+		"watch":  authpb.WATCH,
+		"lease":  authpb.LEASE,
+	}
+	if len(op) == 0 {
+		return 0, fmt.Errorf("auth explain command requires the --op flag")
+	}
+	if permType, ok := ops[strings.ToLower(op)]; ok {
+		return clientv3.PermissionType(permType), nil
+	}
+	permType, err := clientv3.StrToPermissionType(op)
+	if err != nil {
+		return 0, fmt.Errorf("unknown operation %q", op)
+	}
+	return permType, nil
+}
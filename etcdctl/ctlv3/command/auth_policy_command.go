@@ -0,0 +1,386 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"go.etcd.io/etcd/api/v3/authpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/pkg/v3/cobrautl"
+)
+
+// authPolicy is the set of users, roles and permissions of a cluster, in the
+// form "auth export" writes and "auth import" reads.
+type authPolicy struct {
+	Roles []authPolicyRole `json:"roles,omitempty"`
+	Users []authPolicyUser `json:"users,omitempty"`
+}
+
+type authPolicyRole struct {
+	Name        string                 `json:"name"`
+	Permissions []authPolicyPermission `json:"permissions,omitempty"`
+	Limits      *authpb.Limits         `json:"limits,omitempty"`
+}
+
+type authPolicyPermission struct {
+	Type     string `json:"type"`
+	Key      string `json:"key"`
+	RangeEnd string `json:"range_end,omitempty"`
+}
+
+// authPolicyUser is a user of the policy. Passwords are never exported, but a
+// user created by an import is given the password of the file if any, or no
+// password otherwise. Roles granted for a limited time are not part of the
+// policy.
+type authPolicyUser struct {
+	Name     string         `json:"name"`
+	Password string         `json:"password,omitempty"`
+	Roles    []string       `json:"roles,omitempty"`
+	Limits   *authpb.Limits `json:"limits,omitempty"`
+}
+
+var authImportPrune bool
+
+func newAuthExportCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "export [filename]",
+		Short: "Exports the users, roles and permissions as YAML",
+		Run:   authExportCommandFunc,
+	}
+}
+
+func newAuthImportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <filename>",
+		Short: "Updates the users, roles and permissions to match an exported YAML file",
+		Run:   authImportCommandFunc,
+	}
+	cmd.Flags().BoolVar(&authImportPrune, "prune", false, "Delete the users and roles which are not in the file")
+	return cmd
+}
+
+// authExportCommandFunc executes the "auth export" command.
+func authExportCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) > 1 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("auth export command accepts at most one argument"))
+	}
+
+	ctx, cancel := commandCtx(cmd)
+	policy, err := getAuthPolicy(ctx, mustClientFromCmd(cmd))
+	cancel()
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+	b, err := yaml.Marshal(policy)
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+
+	if len(args) == 0 {
+		os.Stdout.Write(b)
+		return
+	}
+	if err = os.WriteFile(args[0], b, 0600); err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+}
+
+// authImportCommandFunc executes the "auth import" command.
+func authImportCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("auth import command requires filename as its argument"))
+	}
+	b, err := os.ReadFile(args[0])
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, err)
+	}
+	var policy authPolicy
+	if err = yaml.UnmarshalStrict(b, &policy); err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("invalid auth policy %s: %v", args[0], err))
+	}
+
+	ctx, cancel := commandCtx(cmd)
+	err = applyAuthPolicy(ctx, mustClientFromCmd(cmd), &policy, authImportPrune)
+	cancel()
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+}
+
+func getAuthPolicy(ctx context.Context, cli *clientv3.Client) (*authPolicy, error) {
+	policy := &authPolicy{}
+	rresp, err := cli.RoleList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range rresp.Roles {
+		resp, err := cli.RoleGet(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		role := authPolicyRole{Name: name, Limits: nonZeroLimits(resp.Limits)}
+		// root is permitted everything whatever its permissions
+		if name == rootRole {
+			policy.Roles = append(policy.Roles, role)
+			continue
+		}
+		for _, perm := range resp.Perm {
+			role.Permissions = append(role.Permissions, authPolicyPermission{
+				Type:     perm.PermType.String(),
+				Key:      string(perm.Key),
+				RangeEnd: string(perm.RangeEnd),
+			})
+		}
+		policy.Roles = append(policy.Roles, role)
+	}
+
+	uresp, err := cli.UserList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range uresp.Users {
+		resp, err := cli.UserGet(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		user := authPolicyUser{Name: name, Limits: nonZeroLimits(resp.Limits)}
+		for _, role := range resp.Roles {
+			if !hasRoleExpiry(resp.RoleExpiries, role) {
+				user.Roles = append(user.Roles, role)
+			}
+		}
+		policy.Users = append(policy.Users, user)
+	}
+	return policy, nil
+}
+
+// applyAuthPolicy adds, grants and revokes what is needed for the users and
+// roles of the cluster to match the policy, printing every change.
+func applyAuthPolicy(ctx context.Context, cli *clientv3.Client, policy *authPolicy, prune bool) error {
+	rresp, err := cli.RoleList(ctx)
+	if err != nil {
+		return err
+	}
+	roles := make(map[string]bool)
+	for _, role := range policy.Roles {
+		roles[role.Name] = true
+		if err = applyAuthPolicyRole(ctx, cli, role, contains(rresp.Roles, role.Name)); err != nil {
+			return fmt.Errorf("cannot import role %s: %w", role.Name, err)
+		}
+	}
+
+	uresp, err := cli.UserList(ctx)
+	if err != nil {
+		return err
+	}
+	users := make(map[string]bool)
+	for _, user := range policy.Users {
+		users[user.Name] = true
+		if err = applyAuthPolicyUser(ctx, cli, user, contains(uresp.Users, user.Name)); err != nil {
+			return fmt.Errorf("cannot import user %s: %w", user.Name, err)
+		}
+	}
+
+	if !prune {
+		return nil
+	}
+	// root may not be deleted while auth is enabled
+	for _, name := range uresp.Users {
+		if users[name] || name == "root" {
+			continue
+		}
+		if _, err = cli.UserDelete(ctx, name); err != nil {
+			return err
+		}
+		fmt.Printf("User %s deleted\n", name)
+	}
+	for _, name := range rresp.Roles {
+		if roles[name] || name == rootRole {
+			continue
+		}
+		if _, err = cli.RoleDelete(ctx, name); err != nil {
+			return err
+		}
+		fmt.Printf("Role %s deleted\n", name)
+	}
+	return nil
+}
+
+func applyAuthPolicyRole(ctx context.Context, cli *clientv3.Client, role authPolicyRole, exists bool) error {
+	var current *clientv3.AuthRoleGetResponse
+	if exists {
+		resp, err := cli.RoleGet(ctx, role.Name)
+		if err != nil {
+			return err
+		}
+		current = resp
+	} else {
+		if _, err := cli.RoleAdd(ctx, role.Name); err != nil {
+			return err
+		}
+		fmt.Printf("Role %s created\n", role.Name)
+		current = &clientv3.AuthRoleGetResponse{}
+	}
+
+	if role.Name == rootRole {
+		if len(role.Permissions) != 0 {
+			return fmt.Errorf("permissions cannot be granted to root")
+		}
+		current.Perm = nil
+	}
+	wanted := make(map[[2]string]bool)
+	for _, perm := range role.Permissions {
+		permType, err := clientv3.StrToPermissionType(perm.Type)
+		if err != nil {
+			return err
+		}
+		wanted[[2]string{perm.Key, perm.RangeEnd}] = true
+		if hasPermission(current.Perm, authpb.Permission_Type(permType), perm.Key, perm.RangeEnd) {
+			continue
+		}
+		if _, err = cli.RoleGrantPermission(ctx, role.Name, perm.Key, perm.RangeEnd, permType); err != nil {
+			return err
+		}
+		fmt.Printf("Role %s granted %s permission on %s\n", role.Name, strings.ToUpper(perm.Type), formatPermRange(perm.Key, perm.RangeEnd))
+	}
+	for _, perm := range current.Perm {
+		key, rangeEnd := string(perm.Key), string(perm.RangeEnd)
+		if wanted[[2]string{key, rangeEnd}] {
+			continue
+		}
+		if _, err := cli.RoleRevokePermission(ctx, role.Name, key, rangeEnd); err != nil {
+			return err
+		}
+		fmt.Printf("Permission on %s revoked from role %s\n", formatPermRange(key, rangeEnd), role.Name)
+	}
+
+	if !sameLimits(current.Limits, role.Limits) {
+		if _, err := cli.RoleSetLimits(ctx, role.Name, limitsOrZero(role.Limits)); err != nil {
+			return err
+		}
+		fmt.Printf("Limits of role %s updated\n", role.Name)
+	}
+	return nil
+}
+
+func applyAuthPolicyUser(ctx context.Context, cli *clientv3.Client, user authPolicyUser, exists bool) error {
+	var current *clientv3.AuthUserGetResponse
+	if exists {
+		resp, err := cli.UserGet(ctx, user.Name)
+		if err != nil {
+			return err
+		}
+		current = resp
+	} else {
+		opts := &clientv3.UserAddOptions{NoPassword: len(user.Password) == 0}
+		if _, err := cli.UserAddWithOptions(ctx, user.Name, user.Password, opts); err != nil {
+			return err
+		}
+		fmt.Printf("User %s created\n", user.Name)
+		current = &clientv3.AuthUserGetResponse{}
+	}
+
+	for _, role := range user.Roles {
+		if contains(current.Roles, role) {
+			continue
+		}
+		if _, err := cli.UserGrantRole(ctx, user.Name, role); err != nil {
+			return err
+		}
+		fmt.Printf("Role %s is granted to user %s\n", role, user.Name)
+	}
+	for _, role := range current.Roles {
+		// roles granted for a limited time are left to expire
+		if contains(user.Roles, role) || hasRoleExpiry(current.RoleExpiries, role) {
+			continue
+		}
+		if _, err := cli.UserRevokeRole(ctx, user.Name, role); err != nil {
+			return err
+		}
+		fmt.Printf("Role %s is revoked from user %s\n", role, user.Name)
+	}
+
+	if !sameLimits(current.Limits, user.Limits) {
+		if _, err := cli.UserSetLimits(ctx, user.Name, limitsOrZero(user.Limits)); err != nil {
+			return err
+		}
+		fmt.Printf("Limits of user %s updated\n", user.Name)
+	}
+	return nil
+}
+
+func hasPermission(perms []*authpb.Permission, permType authpb.Permission_Type, key, rangeEnd string) bool {
+	for _, perm := range perms {
+		if perm.PermType == permType && string(perm.Key) == key && string(perm.RangeEnd) == rangeEnd {
+			return true
+		}
+	}
+	return false
+}
+
+func hasRoleExpiry(expiries []*authpb.RoleExpiry, role string) bool {
+	for _, re := range expiries {
+		if re.Role == role {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func formatPermRange(key, rangeEnd string) string {
+	switch rangeEnd {
+	case "":
+		return fmt.Sprintf("%q", key)
+	case "\x00":
+		return fmt.Sprintf("[%q, <open ended>", key)
+	}
+	return fmt.Sprintf("[%q, %q)", key, rangeEnd)
+}
+
+func nonZeroLimits(l *authpb.Limits) *authpb.Limits {
+	if l == nil || l.Size() == 0 {
+		return nil
+	}
+	return l
+}
+
+func limitsOrZero(l *authpb.Limits) clientv3.Limits {
+	if l == nil {
+		return clientv3.Limits{}
+	}
+	return clientv3.Limits(*l)
+}
+
+func sameLimits(a, b *authpb.Limits) bool {
+	la, lb := limitsOrZero(a), limitsOrZero(b)
+	return la.RequestRate == lb.RequestRate && la.MaxWatches == lb.MaxWatches &&
+		la.MaxLeases == lb.MaxLeases && la.MaxBytes == lb.MaxBytes
+}
@@ -0,0 +1,85 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/pkg/v3/cobrautl"
+)
+
+var (
+	duConsistency string
+	duDelimiter   string
+	duLimit       int64
+	duRev         int64
+)
+
+// NewDuCommand returns the cobra command for "du".
+func NewDuCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "du [options] [prefix]",
+		Short: "Summarizes the number and the size of the keys under a prefix",
+		Run:   duCommandFunc,
+	}
+
+	cmd.Flags().StringVar(&duConsistency, "consistency", "l", "Linearizable(l) or Serializable(s)")
+	cmd.Flags().StringVar(&duDelimiter, "delimiter", "/", "Delimiter ending the child prefixes to summarize; empty to only summarize the prefix")
+	cmd.Flags().Int64Var(&duLimit, "limit", 0, "Maximum number of child prefixes")
+	cmd.Flags().Int64Var(&duRev, "rev", 0, "Specify the kv revision")
+
+	cmd.RegisterFlagCompletionFunc("consistency", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{"l", "s"}, cobra.ShellCompDirectiveDefault
+	})
+
+	return cmd
+}
+
+// duCommandFunc executes the "du" command.
+func duCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) > 1 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("du command accepts at most one argument as prefix"))
+	}
+
+	key := ""
+	if len(args) == 1 {
+		key = args[0]
+	}
+	opts := []clientv3.OpOption{
+		clientv3.WithStatsDelimiter(duDelimiter),
+		clientv3.WithLimit(duLimit),
+		clientv3.WithRev(duRev),
+	}
+	if key == "" {
+		key = "\x00"
+		opts = append(opts, clientv3.WithFromKey())
+	} else {
+		opts = append(opts, clientv3.WithPrefix())
+	}
+	if IsSerializable(duConsistency) {
+		opts = append(opts, clientv3.WithSerializable())
+	}
+
+	ctx, cancel := commandCtx(cmd)
+	resp, err := mustClientFromCmd(cmd).RangeStats(ctx, key, opts...)
+	cancel()
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+	display.RangeStats(*resp)
+}
@@ -35,6 +35,17 @@ var (
 	getKeysOnly    bool
 	getCountOnly   bool
 	printValueOnly bool
+
+	getValuePrefix   string
+	getValueContains string
+	getMinValueSize  int64
+	getMaxValueSize  int64
+	getValueLease    string
+
+	getStats          bool
+	getStatsDelimiter string
+
+	getPageSize int64
 )
 
 // NewGetCommand returns the cobra command for "get".
@@ -55,6 +66,14 @@ func NewGetCommand() *cobra.Command {
 	cmd.Flags().BoolVar(&getKeysOnly, "keys-only", false, "Get only the keys")
 	cmd.Flags().BoolVar(&getCountOnly, "count-only", false, "Get only the count")
 	cmd.Flags().BoolVar(&printValueOnly, "print-value-only", false, `Only write values when using the "simple" output format`)
+	cmd.Flags().StringVar(&getValuePrefix, "value-prefix", "", "Get only the keys whose value starts with the given prefix")
+	cmd.Flags().StringVar(&getValueContains, "value-contains", "", "Get only the keys whose value contains the given substring")
+	cmd.Flags().Int64Var(&getMinValueSize, "min-value-size", 0, "Get only the keys whose value is at least the given number of bytes")
+	cmd.Flags().Int64Var(&getMaxValueSize, "max-value-size", 0, "Get only the keys whose value is at most the given number of bytes")
+	cmd.Flags().StringVar(&getValueLease, "value-lease", "", "Get only the keys attached to the given lease ID (in hexadecimal)")
+	cmd.Flags().BoolVar(&getStats, "stats", false, "Get the number and the size of the keys aggregated by child prefix instead of the keys")
+	cmd.Flags().StringVar(&getStatsDelimiter, "stats-delimiter", "/", "Delimiter ending the child prefixes aggregated by --stats")
+	cmd.Flags().Int64Var(&getPageSize, "page-size", 0, "Get the keys in pages of the given size, all read at the revision of the first page")
 
 	cmd.RegisterFlagCompletionFunc("consistency", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
 		return []string{"l", "s"}, cobra.ShellCompDirectiveDefault
@@ -72,6 +91,14 @@ func NewGetCommand() *cobra.Command {
 // getCommandFunc executes the "get" command.
 func getCommandFunc(cmd *cobra.Command, args []string) {
 	key, opts := getGetOp(args)
+	if getPageSize > 0 {
+		getPagesCommandFunc(cmd, key, opts)
+		return
+	}
+	if getStats {
+		getStatsCommandFunc(cmd, key, opts)
+		return
+	}
 	ctx, cancel := commandCtx(cmd)
 	resp, err := mustClientFromCmd(cmd).Get(ctx, key, opts...)
 	cancel()
@@ -85,6 +112,13 @@ func getCommandFunc(cmd *cobra.Command, args []string) {
 		}
 	}
 
+	setPrintValueOnly()
+	display.Get(*resp)
+}
+
+// setPrintValueOnly makes the simple printer only write values when
+// --print-value-only is set.
+func setPrintValueOnly() {
 	if printValueOnly {
 		dp, simple := (display).(*simplePrinter)
 		if !simple {
@@ -92,7 +126,51 @@ func getCommandFunc(cmd *cobra.Command, args []string) {
 		}
 		dp.valueOnly = true
 	}
-	display.Get(*resp)
+}
+
+// getStatsCommandFunc executes the "get --stats" command.
+func getStatsCommandFunc(cmd *cobra.Command, key string, opts []clientv3.OpOption) {
+	if getKeysOnly || getCountOnly || printValueOnly {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("`--stats` cannot be set with `--keys-only`, `--count-only` or `--print-value-only`"))
+	}
+	if getValuePrefix != "" || getValueContains != "" || getMinValueSize != 0 || getMaxValueSize != 0 || getValueLease != "" {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("`--stats` cannot be set with value filters"))
+	}
+
+	opts = append(opts, clientv3.WithStatsDelimiter(getStatsDelimiter))
+	ctx, cancel := commandCtx(cmd)
+	resp, err := mustClientFromCmd(cmd).RangeStats(ctx, key, opts...)
+	cancel()
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+	display.RangeStats(*resp)
+}
+
+// getPagesCommandFunc executes the "get --page-size" command.
+func getPagesCommandFunc(cmd *cobra.Command, key string, opts []clientv3.OpOption) {
+	if getLimit != 0 || getCountOnly || getStats {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("`--page-size` cannot be set with `--limit`, `--count-only` or `--stats`"))
+	}
+	if (getSortTarget != "" && strings.ToUpper(getSortTarget) != "KEY") || strings.ToUpper(getSortOrder) == "DESCEND" {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("`--page-size` only supports keys sorted by key in ascending order"))
+	}
+
+	setPrintValueOnly()
+
+	it := clientv3.NewGetIterator(mustClientFromCmd(cmd), key, getPageSize, opts...)
+	for {
+		ctx, cancel := commandCtx(cmd)
+		ok := it.Next(ctx)
+		cancel()
+		if !ok {
+			break
+		}
+		display.Get(*it.Response())
+	}
+	if err := it.Err(); err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
 }
 
 func getGetOp(args []string) (string, []clientv3.OpOption) {
@@ -184,5 +262,21 @@ func getGetOp(args []string) (string, []clientv3.OpOption) {
 		opts = append(opts, clientv3.WithCountOnly())
 	}
 
+	if getValuePrefix != "" {
+		opts = append(opts, clientv3.WithValueFilterPrefix(getValuePrefix))
+	}
+	if getValueContains != "" {
+		opts = append(opts, clientv3.WithValueFilterContains(getValueContains))
+	}
+	if getMinValueSize != 0 {
+		opts = append(opts, clientv3.WithValueFilterMinSize(getMinValueSize))
+	}
+	if getMaxValueSize != 0 {
+		opts = append(opts, clientv3.WithValueFilterMaxSize(getMaxValueSize))
+	}
+	if getValueLease != "" {
+		opts = append(opts, clientv3.WithValueFilterLease(leaseFromArgs(getValueLease)))
+	}
+
 	return key, opts
 }
@@ -41,20 +41,38 @@ func NewLeaseCommand() *cobra.Command {
 	return lc
 }
 
+var leaseGrantParent string
+
 // NewLeaseGrantCommand returns the cobra command for "lease grant".
 func NewLeaseGrantCommand() *cobra.Command {
 	lc := &cobra.Command{
-		Use:   "grant <ttl>",
+		Use:   "grant <ttl | --parent=<leaseID>>",
 		Short: "Creates leases",
 
 		Run: leaseGrantCommandFunc,
 	}
+	lc.Flags().StringVar(&leaseGrantParent, "parent", "", "Grant a child lease expiring, renewed and revoked with the given lease (in hexadecimal)")
 
 	return lc
 }
 
 // leaseGrantCommandFunc executes the "lease grant" command.
 func leaseGrantCommandFunc(cmd *cobra.Command, args []string) {
+	if leaseGrantParent != "" {
+		if len(args) != 0 {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("lease grant command takes no TTL argument with --parent, child leases expire with their parent"))
+		}
+		parent := leaseFromArgs(leaseGrantParent)
+		ctx, cancel := commandCtx(cmd)
+		resp, err := mustClientFromCmd(cmd).GrantChild(ctx, parent)
+		cancel()
+		if err != nil {
+			cobrautl.ExitWithError(cobrautl.ExitError, fmt.Errorf("failed to grant lease (%v)", err))
+		}
+		display.Grant(*resp)
+		return
+	}
+
 	if len(args) != 1 {
 		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("lease grant command needs TTL argument"))
 	}
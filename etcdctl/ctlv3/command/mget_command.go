@@ -0,0 +1,109 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package command
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/pkg/v3/cobrautl"
+)
+
+var (
+	mgetConsistency string
+	mgetLimit       int64
+	mgetPrefix      bool
+	mgetRev         int64
+	mgetKeysOnly    bool
+	mgetCountOnly   bool
+)
+
+// NewMultiGetCommand returns the cobra command for "mget".
+func NewMultiGetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mget [options] <key> [key...]",
+		Short: "Gets several keys or prefixes at the same revision",
+		Run:   multiGetCommandFunc,
+	}
+
+	cmd.Flags().StringVar(&mgetConsistency, "consistency", "l", "Linearizable(l) or Serializable(s)")
+	cmd.Flags().Int64Var(&mgetLimit, "limit", 0, "Maximum number of results per key or prefix")
+	cmd.Flags().BoolVar(&mgetPrefix, "prefix", false, "Get keys with matching prefixes")
+	cmd.Flags().Int64Var(&mgetRev, "rev", 0, "Specify the kv revision")
+	cmd.Flags().BoolVar(&mgetKeysOnly, "keys-only", false, "Get only the keys")
+	cmd.Flags().BoolVar(&mgetCountOnly, "count-only", false, "Get only the count per key or prefix")
+
+	cmd.RegisterFlagCompletionFunc("consistency", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return []string{"l", "s"}, cobra.ShellCompDirectiveDefault
+	})
+
+	return cmd
+}
+
+// multiGetCommandFunc executes the "mget" command.
+func multiGetCommandFunc(cmd *cobra.Command, args []string) {
+	ops, opts := getMultiGetOps(args)
+	ctx, cancel := commandCtx(cmd)
+	resp, err := mustClientFromCmd(cmd).MultiRange(ctx, ops, opts...)
+	cancel()
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+
+	if mgetCountOnly {
+		if _, fields := display.(*fieldsPrinter); !fields {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("--count-only is only for `--write-out=fields`"))
+		}
+	}
+	display.MultiRange(*resp)
+}
+
+func getMultiGetOps(args []string) ([]clientv3.Op, []clientv3.OpOption) {
+	if len(args) == 0 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("mget command needs at least one argument as key"))
+	}
+
+	if mgetKeysOnly && mgetCountOnly {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("`--keys-only` and `--count-only` cannot be set at the same time, choose one"))
+	}
+
+	var opOpts []clientv3.OpOption
+	opOpts = append(opOpts, clientv3.WithLimit(mgetLimit))
+	if mgetPrefix {
+		opOpts = append(opOpts, clientv3.WithPrefix())
+	}
+	if mgetKeysOnly {
+		opOpts = append(opOpts, clientv3.WithKeysOnly())
+	}
+	if mgetCountOnly {
+		opOpts = append(opOpts, clientv3.WithCountOnly())
+	}
+
+	ops := make([]clientv3.Op, len(args))
+	for i, key := range args {
+		ops[i] = clientv3.OpGet(key, opOpts...)
+	}
+
+	var opts []clientv3.OpOption
+	if IsSerializable(mgetConsistency) {
+		opts = append(opts, clientv3.WithSerializable())
+	}
+	if mgetRev > 0 {
+		opts = append(opts, clientv3.WithRev(mgetRev))
+	}
+	return ops, opts
+}
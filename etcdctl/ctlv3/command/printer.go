@@ -15,9 +15,11 @@
 package command
 
 import (
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	v3 "go.etcd.io/etcd/client/v3"
@@ -32,6 +34,8 @@ type printer interface {
 	Put(v3.PutResponse)
 	Txn(v3.TxnResponse)
 	Watch(v3.WatchResponse)
+	RangeStats(v3.RangeStatsResponse)
+	MultiRange(v3.MultiRangeResponse)
 
 	Grant(r v3.LeaseGrantResponse)
 	Revoke(id v3.LeaseID, r v3.LeaseRevokeResponse)
@@ -62,6 +66,7 @@ type printer interface {
 	RoleList(v3.AuthRoleListResponse)
 	RoleGrantPermission(role string, r v3.AuthRoleGrantPermissionResponse)
 	RoleRevokePermission(role string, key string, end string, r v3.AuthRoleRevokePermissionResponse)
+	RoleSetLimits(role string, r v3.AuthRoleSetLimitsResponse)
 
 	UserAdd(user string, r v3.AuthUserAddResponse)
 	UserGet(user string, r v3.AuthUserGetResponse)
@@ -70,6 +75,12 @@ type printer interface {
 	UserGrantRole(user string, role string, r v3.AuthUserGrantRoleResponse)
 	UserRevokeRole(user string, role string, r v3.AuthUserRevokeRoleResponse)
 	UserDelete(user string, r v3.AuthUserDeleteResponse)
+	UserSetLimits(user string, r v3.AuthUserSetLimitsResponse)
+
+	TokenList(r v3.AuthTokenListResponse)
+	TokenRevoke(id uint64, r v3.AuthTokenRevokeResponse)
+
+	AuthExplain(user string, r v3.AuthExplainResponse)
 
 	AuthStatus(r v3.AuthStatusResponse)
 }
@@ -100,6 +111,12 @@ func (p *printerRPC) Get(r v3.GetResponse)     { p.p((*pb.RangeResponse)(&r)) }
 func (p *printerRPC) Put(r v3.PutResponse)     { p.p((*pb.PutResponse)(&r)) }
 func (p *printerRPC) Txn(r v3.TxnResponse)     { p.p((*pb.TxnResponse)(&r)) }
 func (p *printerRPC) Watch(r v3.WatchResponse) { p.p(&r) }
+func (p *printerRPC) RangeStats(r v3.RangeStatsResponse) {
+	p.p((*pb.RangeStatsResponse)(&r))
+}
+func (p *printerRPC) MultiRange(r v3.MultiRangeResponse) {
+	p.p((*pb.MultiRangeResponse)(&r))
+}
 
 func (p *printerRPC) Grant(r v3.LeaseGrantResponse)                      { p.p(r) }
 func (p *printerRPC) Revoke(id v3.LeaseID, r v3.LeaseRevokeResponse)     { p.p(r) }
@@ -137,6 +154,9 @@ func (p *printerRPC) RoleGrantPermission(_ string, r v3.AuthRoleGrantPermissionR
 func (p *printerRPC) RoleRevokePermission(_ string, _ string, _ string, r v3.AuthRoleRevokePermissionResponse) {
 	p.p((*pb.AuthRoleRevokePermissionResponse)(&r))
 }
+func (p *printerRPC) RoleSetLimits(_ string, r v3.AuthRoleSetLimitsResponse) {
+	p.p((*pb.AuthRoleSetLimitsResponse)(&r))
+}
 func (p *printerRPC) UserAdd(_ string, r v3.AuthUserAddResponse) { p.p((*pb.AuthUserAddResponse)(&r)) }
 func (p *printerRPC) UserGet(_ string, r v3.AuthUserGetResponse) { p.p((*pb.AuthUserGetResponse)(&r)) }
 func (p *printerRPC) UserList(r v3.AuthUserListResponse)         { p.p((*pb.AuthUserListResponse)(&r)) }
@@ -152,6 +172,16 @@ func (p *printerRPC) UserRevokeRole(_ string, _ string, r v3.AuthUserRevokeRoleR
 func (p *printerRPC) UserDelete(_ string, r v3.AuthUserDeleteResponse) {
 	p.p((*pb.AuthUserDeleteResponse)(&r))
 }
+func (p *printerRPC) UserSetLimits(_ string, r v3.AuthUserSetLimitsResponse) {
+	p.p((*pb.AuthUserSetLimitsResponse)(&r))
+}
+func (p *printerRPC) TokenList(r v3.AuthTokenListResponse) { p.p((*pb.AuthTokenListResponse)(&r)) }
+func (p *printerRPC) TokenRevoke(_ uint64, r v3.AuthTokenRevokeResponse) {
+	p.p((*pb.AuthTokenRevokeResponse)(&r))
+}
+func (p *printerRPC) AuthExplain(_ string, r v3.AuthExplainResponse) {
+	p.p((*pb.AuthExplainResponse)(&r))
+}
 func (p *printerRPC) AuthStatus(r v3.AuthStatusResponse) {
 	p.p((*pb.AuthStatusResponse)(&r))
 }
@@ -197,6 +227,26 @@ func makeMemberListTable(r v3.MemberListResponse) (hdr []string, rows [][]string
 	return hdr, rows
 }
 
+func makeTokenListTable(r v3.AuthTokenListResponse) (hdr []string, rows [][]string) {
+	hdr = []string{"ID", "User", "Created", "Address", "Scope Roles", "Scope Prefix"}
+	for _, tk := range r.Tokens {
+		var roles []string
+		var prefix string
+		if tk.Scope != nil {
+			roles, prefix = tk.Scope.Roles, string(tk.Scope.Prefix)
+		}
+		rows = append(rows, []string{
+			fmt.Sprint(tk.ID),
+			tk.Name,
+			time.Unix(tk.Created, 0).UTC().Format(time.RFC3339),
+			tk.Address,
+			strings.Join(roles, ","),
+			prefix,
+		})
+	}
+	return hdr, rows
+}
+
 func makeEndpointHealthTable(healthList []epHealth) (hdr []string, rows [][]string) {
 	hdr = []string{"endpoint", "health", "took", "error"}
 	for _, h := range healthList {
@@ -232,6 +282,28 @@ func makeEndpointStatusTable(statusList []epStatus) (hdr []string, rows [][]stri
 	return hdr, rows
 }
 
+func makeRangeStatsTable(r v3.RangeStatsResponse, isHex bool) (hdr []string, rows [][]string) {
+	hdr = []string{"prefix", "keys", "key size", "value size", "max mod revision"}
+	sts := append([]*pb.PrefixStats{r.Total}, r.Children...)
+	for _, st := range sts {
+		rows = append(rows, []string{
+			statsPrefix(st, isHex),
+			fmt.Sprint(st.Count),
+			humanize.Bytes(uint64(st.KeyBytes)),
+			humanize.Bytes(uint64(st.ValueBytes)),
+			fmt.Sprint(st.MaxModRevision),
+		})
+	}
+	return hdr, rows
+}
+
+func statsPrefix(st *pb.PrefixStats, isHex bool) string {
+	if isHex {
+		return addHexPrefix(hex.EncodeToString(st.Prefix))
+	}
+	return string(st.Prefix)
+}
+
 func makeEndpointHashKVTable(hashList []epHashKV) (hdr []string, rows [][]string) {
 	hdr = []string{"endpoint", "hash", "hash_revision"}
 	for _, h := range hashList {
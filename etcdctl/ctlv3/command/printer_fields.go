@@ -17,6 +17,7 @@ package command
 import (
 	"fmt"
 
+	"go.etcd.io/etcd/api/v3/authpb"
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	spb "go.etcd.io/etcd/api/v3/mvccpb"
 	"go.etcd.io/etcd/client/pkg/v3/types"
@@ -39,6 +40,9 @@ func (p *fieldsPrinter) kv(pfx string, kv *spb.KeyValue) {
 	} else {
 		fmt.Printf("\"%sLease\" : %d\n", pfx, kv.Lease)
 	}
+	if kv.ExpireTime != 0 {
+		fmt.Printf("\"%sExpireTime\" : %d\n", pfx, kv.ExpireTime)
+	}
 }
 
 func (p *fieldsPrinter) hdr(h *pb.ResponseHeader) {
@@ -75,6 +79,38 @@ func (p *fieldsPrinter) Get(r v3.GetResponse) {
 	fmt.Println(`"Count" :`, r.Count)
 }
 
+func (p *fieldsPrinter) MultiRange(r v3.MultiRangeResponse) {
+	p.hdr(r.Header)
+	fmt.Println(`"ReadRevision" :`, r.Revision)
+	for _, sr := range r.Responses {
+		for _, kv := range sr.Kvs {
+			p.kv("", kv)
+		}
+		fmt.Println(`"More" :`, sr.More)
+		fmt.Println(`"Count" :`, sr.Count)
+		if sr.More {
+			fmt.Printf("\"Continuation\" : %q\n", string(sr.Continuation))
+		}
+	}
+}
+
+func (p *fieldsPrinter) stats(pfx string, st *pb.PrefixStats) {
+	fmt.Printf("\"%sPrefix\" : %q\n", pfx, string(st.Prefix))
+	fmt.Printf("\"%sCount\" : %d\n", pfx, st.Count)
+	fmt.Printf("\"%sKeyBytes\" : %d\n", pfx, st.KeyBytes)
+	fmt.Printf("\"%sValueBytes\" : %d\n", pfx, st.ValueBytes)
+	fmt.Printf("\"%sMaxModRevision\" : %d\n", pfx, st.MaxModRevision)
+}
+
+func (p *fieldsPrinter) RangeStats(r v3.RangeStatsResponse) {
+	p.hdr(r.Header)
+	p.stats("Total", r.Total)
+	for _, st := range r.Children {
+		p.stats("", st)
+	}
+	fmt.Println(`"More" :`, r.More)
+}
+
 func (p *fieldsPrinter) Put(r v3.PutResponse) {
 	p.hdr(r.Header)
 	if r.PrevKv != nil {
@@ -146,6 +182,20 @@ func (p *fieldsPrinter) TimeToLive(r v3.LeaseTimeToLiveResponse, keys bool) {
 	for _, k := range r.Keys {
 		fmt.Printf("\"Key\" : %q\n", string(k))
 	}
+	if r.Parent != v3.NoLease {
+		p.leaseID("Parent", r.Parent)
+	}
+	for _, id := range r.Children {
+		p.leaseID("Child", id)
+	}
+}
+
+func (p *fieldsPrinter) leaseID(field string, id v3.LeaseID) {
+	if p.isHex {
+		fmt.Printf("\"%s\" : %016x\n", field, id)
+	} else {
+		fmt.Printf("\"%s\" : %d\n", field, id)
+	}
 }
 
 func (p *fieldsPrinter) Leases(r v3.LeaseLeasesResponse) {
@@ -156,6 +206,9 @@ func (p *fieldsPrinter) Leases(r v3.LeaseLeasesResponse) {
 		} else {
 			fmt.Println(`"ID" :`, item.ID)
 		}
+		if item.Parent != v3.NoLease {
+			p.leaseID("Parent", item.Parent)
+		}
 	}
 }
 
@@ -254,6 +307,9 @@ func (p *fieldsPrinter) RoleGrantPermission(role string, r v3.AuthRoleGrantPermi
 func (p *fieldsPrinter) RoleRevokePermission(role string, key string, end string, r v3.AuthRoleRevokePermissionResponse) {
 	p.hdr(r.Header)
 }
+func (p *fieldsPrinter) RoleSetLimits(role string, r v3.AuthRoleSetLimitsResponse) {
+	p.hdr(r.Header)
+}
 func (p *fieldsPrinter) UserAdd(user string, r v3.AuthUserAddResponse)          { p.hdr(r.Header) }
 func (p *fieldsPrinter) UserChangePassword(r v3.AuthUserChangePasswordResponse) { p.hdr(r.Header) }
 func (p *fieldsPrinter) UserGrantRole(user string, role string, r v3.AuthUserGrantRoleResponse) {
@@ -263,3 +319,39 @@ func (p *fieldsPrinter) UserRevokeRole(user string, role string, r v3.AuthUserRe
 	p.hdr(r.Header)
 }
 func (p *fieldsPrinter) UserDelete(user string, r v3.AuthUserDeleteResponse) { p.hdr(r.Header) }
+func (p *fieldsPrinter) UserSetLimits(user string, r v3.AuthUserSetLimitsResponse) {
+	p.hdr(r.Header)
+}
+func (p *fieldsPrinter) TokenList(r v3.AuthTokenListResponse) {
+	p.hdr(r.Header)
+	for _, tk := range r.Tokens {
+		fmt.Println(`"ID" :`, tk.ID)
+		fmt.Printf("\"User\" : %q\n", tk.Name)
+		fmt.Println(`"Created" :`, tk.Created)
+		fmt.Printf("\"Address\" : %q\n", tk.Address)
+		if tk.Scope != nil {
+			fmt.Printf("\"ScopeRoles\" : %q\n", tk.Scope.Roles)
+			fmt.Printf("\"ScopePrefix\" : %q\n", tk.Scope.Prefix)
+		}
+		fmt.Println()
+	}
+}
+func (p *fieldsPrinter) TokenRevoke(id uint64, r v3.AuthTokenRevokeResponse) { p.hdr(r.Header) }
+func (p *fieldsPrinter) AuthExplain(user string, r v3.AuthExplainResponse) {
+	p.hdr(r.Header)
+	fmt.Println(`"Permitted" :`, r.Permitted)
+	printPerms := func(name string, perms []*authpb.Permission) {
+		for _, perm := range perms {
+			fmt.Printf("\"%sPermType\" : %q\n", name, perm.PermType.String())
+			fmt.Printf("\"%sKey\" : %q\n", name, string(perm.Key))
+			fmt.Printf("\"%sRangeEnd\" : %q\n", name, string(perm.RangeEnd))
+		}
+	}
+	for _, re := range r.Roles {
+		fmt.Printf("\"Role\" : %q\n", re.Role)
+		fmt.Println(`"RolePermitted" :`, re.Permitted)
+		printPerms("Matching", re.Matching)
+		printPerms("NonMatching", re.NonMatching)
+		fmt.Println()
+	}
+}
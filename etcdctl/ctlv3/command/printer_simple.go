@@ -18,7 +18,9 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
+	"go.etcd.io/etcd/api/v3/authpb"
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/client/pkg/v3/types"
 	v3 "go.etcd.io/etcd/client/v3"
@@ -44,6 +46,33 @@ func (s *simplePrinter) Get(resp v3.GetResponse) {
 	}
 }
 
+func (s *simplePrinter) MultiRange(resp v3.MultiRangeResponse) {
+	for _, r := range resp.Responses {
+		for _, kv := range r.Kvs {
+			printKV(s.isHex, s.valueOnly, kv)
+		}
+	}
+}
+
+// RangeStats prints the total of the range and its child prefixes as a tree.
+func (s *simplePrinter) RangeStats(r v3.RangeStatsResponse) {
+	_, rows := makeRangeStatsTable(r, s.isHex)
+	for i, row := range rows {
+		indent := ""
+		switch {
+		case i == 0:
+		case i < len(rows)-1 || r.More:
+			indent = "├── "
+		default:
+			indent = "└── "
+		}
+		fmt.Printf("%s%s (%s keys, %s of keys, %s of values, max mod revision %s)\n", indent, row[0], row[1], row[2], row[3], row[4])
+	}
+	if r.More {
+		fmt.Println("└── ...")
+	}
+}
+
 func (s *simplePrinter) Put(r v3.PutResponse) {
 	fmt.Println("OK")
 	if r.PrevKv != nil {
@@ -109,12 +138,26 @@ func (s *simplePrinter) TimeToLive(resp v3.LeaseTimeToLiveResponse, keys bool) {
 		}
 		txt += fmt.Sprintf(", attached keys(%v)", ks)
 	}
+	if resp.Parent != v3.NoLease {
+		txt += fmt.Sprintf(", parent(%016x)", resp.Parent)
+	}
+	if len(resp.Children) != 0 {
+		ids := make([]string, len(resp.Children))
+		for i := range resp.Children {
+			ids[i] = fmt.Sprintf("%016x", resp.Children[i])
+		}
+		txt += fmt.Sprintf(", children(%v)", ids)
+	}
 	fmt.Println(txt)
 }
 
 func (s *simplePrinter) Leases(resp v3.LeaseLeasesResponse) {
 	fmt.Printf("found %d leases\n", len(resp.Leases))
 	for _, item := range resp.Leases {
+		if item.Parent != v3.NoLease {
+			fmt.Printf("%016x (parent %016x)\n", item.ID, item.Parent)
+			continue
+		}
 		fmt.Printf("%016x\n", item.ID)
 	}
 }
@@ -220,25 +263,59 @@ func (s *simplePrinter) RoleGet(role string, r v3.AuthRoleGetResponse) {
 		fmt.Print("\n")
 	}
 
-	for _, perm := range r.Perm {
-		if perm.PermType == v3.PermRead || perm.PermType == v3.PermReadWrite {
-			if len(perm.RangeEnd) == 0 {
-				fmt.Printf("\t%s\n", string(perm.Key))
-			} else {
-				printRange((*v3.Permission)(perm))
+	printPerms := func(types ...authpb.Permission_Type) {
+		for _, perm := range r.Perm {
+			for _, typ := range types {
+				if perm.PermType != typ {
+					continue
+				}
+				if len(perm.RangeEnd) == 0 {
+					fmt.Printf("\t%s\n", string(perm.Key))
+				} else {
+					printRange((*v3.Permission)(perm))
+				}
 			}
 		}
 	}
+
+	printPerms(v3.PermRead, v3.PermReadWrite)
 	fmt.Println("KV Write:")
-	for _, perm := range r.Perm {
-		if perm.PermType == v3.PermWrite || perm.PermType == v3.PermReadWrite {
-			if len(perm.RangeEnd) == 0 {
-				fmt.Printf("\t%s\n", string(perm.Key))
-			} else {
-				printRange((*v3.Permission)(perm))
+	printPerms(v3.PermWrite, v3.PermReadWrite)
+
+	// the finer permissions are only listed when granted
+	for _, p := range []struct {
+		typ  authpb.Permission_Type
+		name string
+	}{{v3.PermCreate, "Create"}, {v3.PermDelete, "Delete"}, {v3.PermWatch, "Watch"}, {v3.PermLease, "Lease"}} {
+		for _, perm := range r.Perm {
+			if perm.PermType == p.typ {
+				fmt.Printf("KV %s:\n", p.name)
+				printPerms(p.typ)
+				break
 			}
 		}
 	}
+	printLimits(r.Limits)
+}
+
+// printLimits lists the limits that are set, if any.
+func printLimits(l *authpb.Limits) {
+	if l == nil || (l.RequestRate == 0 && l.MaxWatches == 0 && l.MaxLeases == 0 && l.MaxBytes == 0) {
+		return
+	}
+	fmt.Println("Limits:")
+	if l.RequestRate != 0 {
+		fmt.Printf("\tRequest rate: %d/s\n", l.RequestRate)
+	}
+	if l.MaxWatches != 0 {
+		fmt.Printf("\tMax watches: %d\n", l.MaxWatches)
+	}
+	if l.MaxLeases != 0 {
+		fmt.Printf("\tMax leases: %d\n", l.MaxLeases)
+	}
+	if l.MaxBytes != 0 {
+		fmt.Printf("\tMax bytes: %d\n", l.MaxBytes)
+	}
 }
 
 func (s *simplePrinter) RoleList(r v3.AuthRoleListResponse) {
@@ -267,6 +344,10 @@ func (s *simplePrinter) RoleRevokePermission(role string, key string, end string
 	}
 }
 
+func (s *simplePrinter) RoleSetLimits(role string, r v3.AuthRoleSetLimitsResponse) {
+	fmt.Printf("Limits of role %s updated\n", role)
+}
+
 func (s *simplePrinter) UserAdd(name string, r v3.AuthUserAddResponse) {
 	fmt.Printf("User %s created\n", name)
 }
@@ -278,6 +359,14 @@ func (s *simplePrinter) UserGet(name string, r v3.AuthUserGetResponse) {
 		fmt.Printf(" %s", role)
 	}
 	fmt.Print("\n")
+	for _, re := range r.RoleExpiries {
+		if left := time.Until(time.Unix(re.ExpireTime, 0)); left > 0 {
+			fmt.Printf("Role %s expires in %v\n", re.Role, left.Round(time.Second))
+		} else {
+			fmt.Printf("Role %s expired, pending revocation\n", re.Role)
+		}
+	}
+	printLimits(r.Limits)
 }
 
 func (s *simplePrinter) UserChangePassword(v3.AuthUserChangePasswordResponse) {
@@ -296,12 +385,46 @@ func (s *simplePrinter) UserDelete(user string, r v3.AuthUserDeleteResponse) {
 	fmt.Printf("User %s deleted\n", user)
 }
 
+func (s *simplePrinter) UserSetLimits(user string, r v3.AuthUserSetLimitsResponse) {
+	fmt.Printf("Limits of user %s updated\n", user)
+}
+
 func (s *simplePrinter) UserList(r v3.AuthUserListResponse) {
 	for _, user := range r.Users {
 		fmt.Printf("%s\n", user)
 	}
 }
 
+func (s *simplePrinter) TokenList(r v3.AuthTokenListResponse) {
+	_, rows := makeTokenListTable(r)
+	for _, row := range rows {
+		fmt.Println(strings.Join(row, ", "))
+	}
+}
+
+func (s *simplePrinter) TokenRevoke(id uint64, r v3.AuthTokenRevokeResponse) {
+	fmt.Printf("Token %d revoked\n", id)
+}
+
+func (s *simplePrinter) AuthExplain(user string, r v3.AuthExplainResponse) {
+	if r.Permitted {
+		fmt.Printf("User %s is permitted\n", user)
+	} else {
+		fmt.Printf("User %s is not permitted\n", user)
+	}
+	for _, re := range r.Roles {
+		fmt.Printf("Role %s (permitted: %v)\n", re.Role, re.Permitted)
+		fmt.Println("Matching:")
+		for _, perm := range re.Matching {
+			fmt.Printf("\t%s %s\n", perm.PermType, formatPermRange(string(perm.Key), string(perm.RangeEnd)))
+		}
+		fmt.Println("Non-matching:")
+		for _, perm := range re.NonMatching {
+			fmt.Printf("\t%s %s\n", perm.PermType, formatPermRange(string(perm.Key), string(perm.RangeEnd)))
+		}
+	}
+}
+
 func (s *simplePrinter) AuthStatus(r v3.AuthStatusResponse) {
 	fmt.Println("Authentication Status:", r.Enabled)
 	fmt.Println("AuthRevision:", r.AuthRevision)
@@ -27,6 +27,8 @@ import (
 var (
 	rolePermPrefix  bool
 	rolePermFromKey bool
+
+	authLimits clientv3.Limits
 )
 
 // NewRoleCommand returns the cobra command for "role".
@@ -42,6 +44,7 @@ func NewRoleCommand() *cobra.Command {
 	ac.AddCommand(newRoleListCommand())
 	ac.AddCommand(newRoleGrantPermissionCommand())
 	ac.AddCommand(newRoleRevokePermissionCommand())
+	ac.AddCommand(newRoleSetLimitsCommand())
 
 	return ac
 }
@@ -104,6 +107,26 @@ func newRoleRevokePermissionCommand() *cobra.Command {
 	return cmd
 }
 
+func newRoleSetLimitsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-limits [options] <role name>",
+		Short: "Sets the limits of the users granted a role",
+		Run:   roleSetLimitsCommandFunc,
+	}
+
+	registerLimitsFlags(cmd)
+
+	return cmd
+}
+
+// registerLimitsFlags registers the flags of the limits of a user or role.
+func registerLimitsFlags(cmd *cobra.Command) {
+	cmd.Flags().Uint64Var(&authLimits.RequestRate, "request-rate", 0, "maximum number of requests per second to each member, 0 for unlimited")
+	cmd.Flags().Uint64Var(&authLimits.MaxWatches, "max-watches", 0, "maximum number of concurrent watches on each member, 0 for unlimited")
+	cmd.Flags().Uint64Var(&authLimits.MaxLeases, "max-leases", 0, "maximum number of leases granted, 0 for unlimited")
+	cmd.Flags().Uint64Var(&authLimits.MaxBytes, "max-bytes", 0, "maximum bytes of the keys and values stored under the writable keys, 0 for unlimited")
+}
+
 // roleAddCommandFunc executes the "role add" command.
 func roleAddCommandFunc(cmd *cobra.Command, args []string) {
 	if len(args) != 1 {
@@ -195,6 +218,20 @@ func roleRevokePermissionCommandFunc(cmd *cobra.Command, args []string) {
 	display.RoleRevokePermission(args[0], args[1], rangeEnd, *resp)
 }
 
+// roleSetLimitsCommandFunc executes the "role set-limits" command.
+func roleSetLimitsCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("role set-limits command requires role name as its argument"))
+	}
+
+	resp, err := mustClientFromCmd(cmd).Auth.RoleSetLimits(context.TODO(), args[0], authLimits)
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+
+	display.RoleSetLimits(args[0], *resp)
+}
+
 func permRange(args []string) (string, string) {
 	key := args[0]
 	var rangeEnd string
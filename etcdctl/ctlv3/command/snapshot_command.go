@@ -33,9 +33,15 @@ func NewSnapshotCommand() *cobra.Command {
 		Short: "Manages etcd node snapshots",
 	}
 	cmd.AddCommand(NewSnapshotSaveCommand())
+	cmd.AddCommand(NewSnapshotSaveDeltaCommand())
 	return cmd
 }
 
+var (
+	deltaStartRev int64
+	deltaEndRev   int64
+)
+
 func NewSnapshotSaveCommand() *cobra.Command {
 	return &cobra.Command{
 		Use:   "save <filename>",
@@ -73,3 +79,45 @@ func snapshotSaveCommandFunc(cmd *cobra.Command, args []string) {
 		fmt.Printf("Server version %s\n", version)
 	}
 }
+
+func NewSnapshotSaveDeltaCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "save-delta <filename> --start-revision=<rev>",
+		Short: "Stores the changes made after a revision of an etcd node to a given file",
+		Run:   snapshotSaveDeltaCommandFunc,
+	}
+	cmd.Flags().Int64Var(&deltaStartRev, "start-revision", 0, "Revision of the snapshot, or of the end of the previous delta, the delta is based on")
+	cmd.Flags().Int64Var(&deltaEndRev, "end-revision", 0, "Last revision of the delta (defaults to the current revision)")
+	return cmd
+}
+
+func snapshotSaveDeltaCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		err := fmt.Errorf("snapshot save-delta expects one argument")
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, err)
+	}
+	if deltaStartRev <= 0 {
+		err := fmt.Errorf("snapshot save-delta requires a positive --start-revision")
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, err)
+	}
+
+	lg, err := logutil.CreateDefaultZapLogger(zap.InfoLevel)
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+	cfg := mustClientCfgFromCmd(cmd)
+
+	// if user does not specify "--command-timeout" flag, there will be no timeout for snapshot save-delta command
+	ctx, cancel := context.WithCancel(context.Background())
+	if isCommandTimeoutFlagSet(cmd) {
+		ctx, cancel = commandCtx(cmd)
+	}
+	defer cancel()
+
+	path := args[0]
+	endRev, err := snapshot.SaveDelta(ctx, lg, *cfg, deltaStartRev, deltaEndRev, path)
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitInterrupted, err)
+	}
+	fmt.Printf("Snapshot delta of revisions %d to %d saved at %s\n", deltaStartRev+1, endRev, path)
+}
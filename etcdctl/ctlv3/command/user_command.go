@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/bgentry/speakeasy"
 	"github.com/spf13/cobra"
@@ -44,6 +45,7 @@ func NewUserCommand() *cobra.Command {
 	ac.AddCommand(newUserChangePasswordCommand())
 	ac.AddCommand(newUserGrantRoleCommand())
 	ac.AddCommand(newUserRevokeRoleCommand())
+	ac.AddCommand(newUserSetLimitsCommand())
 
 	return ac
 }
@@ -108,12 +110,18 @@ func newUserChangePasswordCommand() *cobra.Command {
 	return &cmd
 }
 
+var grantRoleTTL time.Duration
+
 func newUserGrantRoleCommand() *cobra.Command {
-	return &cobra.Command{
-		Use:   "grant-role <user name> <role name>",
+	cmd := cobra.Command{
+		Use:   "grant-role <user name> <role name> [options]",
 		Short: "Grants a role to a user",
 		Run:   userGrantRoleCommandFunc,
 	}
+
+	cmd.Flags().DurationVar(&grantRoleTTL, "ttl", 0, "Revoke the role once the duration elapsed (e.g. '1h'); the role does not expire if 0")
+
+	return &cmd
 }
 
 func newUserRevokeRoleCommand() *cobra.Command {
@@ -124,6 +132,18 @@ func newUserRevokeRoleCommand() *cobra.Command {
 	}
 }
 
+func newUserSetLimitsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "set-limits [options] <user name>",
+		Short: "Sets the limits of a user",
+		Run:   userSetLimitsCommandFunc,
+	}
+
+	registerLimitsFlags(cmd)
+
+	return cmd
+}
+
 // userAddCommandFunc executes the "user add" command.
 func userAddCommandFunc(cmd *cobra.Command, args []string) {
 	if len(args) != 1 {
@@ -254,7 +274,11 @@ func userGrantRoleCommandFunc(cmd *cobra.Command, args []string) {
 		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("user grant command requires user name and role name as its argument"))
 	}
 
-	resp, err := mustClientFromCmd(cmd).Auth.UserGrantRole(context.TODO(), args[0], args[1])
+	if grantRoleTTL < 0 || grantRoleTTL%time.Second != 0 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("ttl must be a non-negative whole number of seconds, got %v", grantRoleTTL))
+	}
+
+	resp, err := mustClientFromCmd(cmd).Auth.UserGrantRoleWithTTL(context.TODO(), args[0], args[1], int64(grantRoleTTL/time.Second))
 	if err != nil {
 		cobrautl.ExitWithError(cobrautl.ExitError, err)
 	}
@@ -276,6 +300,20 @@ func userRevokeRoleCommandFunc(cmd *cobra.Command, args []string) {
 	display.UserRevokeRole(args[0], args[1], *resp)
 }
 
+// userSetLimitsCommandFunc executes the "user set-limits" command.
+func userSetLimitsCommandFunc(cmd *cobra.Command, args []string) {
+	if len(args) != 1 {
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, fmt.Errorf("user set-limits command requires user name as its argument"))
+	}
+
+	resp, err := mustClientFromCmd(cmd).Auth.UserSetLimits(context.TODO(), args[0], authLimits)
+	if err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+
+	display.UserSetLimits(args[0], *resp)
+}
+
 func readPasswordInteractive(name string) string {
 	prompt1 := fmt.Sprintf("Password of %s: ", name)
 	password1, err1 := speakeasy.Ask(prompt1)
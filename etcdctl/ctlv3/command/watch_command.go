@@ -42,6 +42,12 @@ var (
 	watchInteractive bool
 	watchPrevKey     bool
 	progressNotify   bool
+
+	watchKeyGlob       string
+	watchValuePrefix   string
+	watchValueContains string
+	watchMinValueSize  int64
+	watchMaxValueSize  int64
 )
 
 // NewWatchCommand returns the cobra command for "watch".
@@ -57,6 +63,11 @@ func NewWatchCommand() *cobra.Command {
 	cmd.Flags().Int64Var(&watchRev, "rev", 0, "Revision to start watching")
 	cmd.Flags().BoolVar(&watchPrevKey, "prev-kv", false, "get the previous key-value pair before the event happens")
 	cmd.Flags().BoolVar(&progressNotify, "progress-notify", false, "get periodic watch progress notification from server")
+	cmd.Flags().StringVar(&watchKeyGlob, "key-glob", "", "Watch only the keys matching the given glob pattern (e.g. '/jobs/*/status')")
+	cmd.Flags().StringVar(&watchValuePrefix, "value-prefix", "", "Watch only the events whose value starts with the given prefix")
+	cmd.Flags().StringVar(&watchValueContains, "value-contains", "", "Watch only the events whose value contains the given substring")
+	cmd.Flags().Int64Var(&watchMinValueSize, "min-value-size", 0, "Watch only the events whose value is at least the given number of bytes")
+	cmd.Flags().Int64Var(&watchMaxValueSize, "max-value-size", 0, "Watch only the events whose value is at most the given number of bytes")
 
 	return cmd
 }
@@ -160,6 +171,21 @@ func getWatchChan(c *clientv3.Client, args []string) (clientv3.WatchChan, error)
 	if progressNotify {
 		opts = append(opts, clientv3.WithProgressNotify())
 	}
+	if watchKeyGlob != "" {
+		opts = append(opts, clientv3.WithKeyGlob(watchKeyGlob))
+	}
+	if watchValuePrefix != "" {
+		opts = append(opts, clientv3.WithValueFilterPrefix(watchValuePrefix))
+	}
+	if watchValueContains != "" {
+		opts = append(opts, clientv3.WithValueFilterContains(watchValueContains))
+	}
+	if watchMinValueSize != 0 {
+		opts = append(opts, clientv3.WithValueFilterMinSize(watchMinValueSize))
+	}
+	if watchMaxValueSize != 0 {
+		opts = append(opts, clientv3.WithValueFilterMaxSize(watchMaxValueSize))
+	}
 	return c.Watch(clientv3.WithRequireLeader(context.Background()), key, opts...), nil
 }
 
@@ -334,6 +360,26 @@ func parseWatchArgs(osArgs, commandArgs []string, envKey, envRange string, inter
 		if err != nil {
 			return nil, nil, err
 		}
+		watchKeyGlob, err = flagset.GetString("key-glob")
+		if err != nil {
+			return nil, nil, err
+		}
+		watchValuePrefix, err = flagset.GetString("value-prefix")
+		if err != nil {
+			return nil, nil, err
+		}
+		watchValueContains, err = flagset.GetString("value-contains")
+		if err != nil {
+			return nil, nil, err
+		}
+		watchMinValueSize, err = flagset.GetInt64("min-value-size")
+		if err != nil {
+			return nil, nil, err
+		}
+		watchMaxValueSize, err = flagset.GetInt64("max-value-size")
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
 	// "ETCDCTL_WATCH_KEY=foo watch -- echo hello"
@@ -77,6 +77,8 @@ func init() {
 
 	rootCmd.AddCommand(
 		command.NewGetCommand(),
+		command.NewDuCommand(),
+		command.NewMultiGetCommand(),
 		command.NewPutCommand(),
 		command.NewDelCommand(),
 		command.NewTxnCommand(),
@@ -16,6 +16,7 @@ require (
 	go.uber.org/zap v1.25.0
 	golang.org/x/time v0.3.0
 	google.golang.org/grpc v1.57.0
+	sigs.k8s.io/yaml v1.3.0
 )
 
 require (
@@ -39,6 +40,7 @@ require (
 	google.golang.org/genproto/googleapis/api v0.0.0-20230525234035-dd9d682886f9 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20230525234030-28d5490b6b19 // indirect
 	google.golang.org/protobuf v1.31.0 // indirect
+	gopkg.in/yaml.v2 v2.4.0 // indirect
 )
 
 replace (
@@ -18,6 +18,8 @@ import (
 	"fmt"
 	"strings"
 
+	"go.etcd.io/etcd/client/pkg/v3/transport"
+	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.etcd.io/etcd/etcdutl/v3/snapshot"
 	"go.etcd.io/etcd/pkg/v3/cobrautl"
 	"go.etcd.io/etcd/server/v3/storage/datadir"
@@ -40,6 +42,12 @@ var (
 	skipHashCheck       bool
 	markCompacted       bool
 	revisionBump        uint64
+	restoreDeltas       string
+
+	restoreJoinEndpoints string
+	restoreJoinCACert    string
+	restoreJoinCert      string
+	restoreJoinKey       string
 )
 
 // NewSnapshotCommand returns the cobra command for "snapshot".
@@ -80,6 +88,12 @@ func NewSnapshotRestoreCommand() *cobra.Command {
 	cmd.Flags().Uint64Var(&revisionBump, "bump-revision", 0, "How much to increase the latest revision after restore")
 	cmd.Flags().BoolVar(&markCompacted, "mark-compacted", false, "Mark the latest revision after restore as the point of scheduled compaction (required if --bump-revision > 0, disallowed otherwise)")
 
+	cmd.Flags().StringVar(&restoreDeltas, "deltas", "", "Comma separated paths of the snapshot deltas to replay, in order, onto the snapshot before restoring it")
+	cmd.Flags().StringVar(&restoreJoinEndpoints, "join-endpoints", "", "Comma separated client URLs of the running cluster the snapshot was saved from, to add the restored member to as a learner instead of bootstrapping a new cluster")
+	cmd.Flags().StringVar(&restoreJoinCACert, "join-cacert", "", "Verify certificates of the --join-endpoints using this CA bundle")
+	cmd.Flags().StringVar(&restoreJoinCert, "join-cert", "", "Identify to the --join-endpoints using this TLS certificate file")
+	cmd.Flags().StringVar(&restoreJoinKey, "join-key", "", "Identify to the --join-endpoints using this TLS key file")
+
 	cmd.MarkFlagDirname("data-dir")
 	cmd.MarkFlagDirname("wal-dir")
 
@@ -103,8 +117,12 @@ func SnapshotStatusCommandFunc(cmd *cobra.Command, args []string) {
 }
 
 func snapshotRestoreCommandFunc(_ *cobra.Command, args []string) {
+	if restoreJoinEndpoints != "" {
+		snapshotRestoreLearnerCommandFunc(args)
+		return
+	}
 	SnapshotRestoreCommandFunc(restoreCluster, restoreClusterToken, restoreDataDir, restoreWalDir,
-		restorePeerURLs, restoreName, skipHashCheck, revisionBump, markCompacted, args)
+		restorePeerURLs, restoreName, skipHashCheck, revisionBump, markCompacted, splitDeltas(restoreDeltas), args)
 }
 
 func SnapshotRestoreCommandFunc(restoreCluster string,
@@ -116,6 +134,7 @@ func SnapshotRestoreCommandFunc(restoreCluster string,
 	skipHashCheck bool,
 	revisionBump uint64,
 	markCompacted bool,
+	deltas []string,
 	args []string) {
 	if len(args) != 1 {
 		err := fmt.Errorf("snapshot restore requires exactly one argument")
@@ -151,11 +170,72 @@ func SnapshotRestoreCommandFunc(restoreCluster string,
 		SkipHashCheck:       skipHashCheck,
 		RevisionBump:        revisionBump,
 		MarkCompacted:       markCompacted,
+		Deltas:              deltas,
+	}); err != nil {
+		cobrautl.ExitWithError(cobrautl.ExitError, err)
+	}
+}
+
+// snapshotRestoreLearnerCommandFunc restores the snapshot as the data directory
+// of a learner joining the cluster at --join-endpoints.
+func snapshotRestoreLearnerCommandFunc(args []string) {
+	if len(args) != 1 {
+		err := fmt.Errorf("snapshot restore requires exactly one argument")
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, err)
+	}
+	if revisionBump > 0 || markCompacted {
+		err := fmt.Errorf("--bump-revision and --mark-compacted cannot be used with --join-endpoints")
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, err)
+	}
+	if restoreDeltas != "" {
+		err := fmt.Errorf("--deltas cannot be used with --join-endpoints")
+		cobrautl.ExitWithError(cobrautl.ExitBadArgs, err)
+	}
+
+	join := &clientv3.Config{Endpoints: strings.Split(restoreJoinEndpoints, ",")}
+	if restoreJoinCACert != "" || restoreJoinCert != "" || restoreJoinKey != "" {
+		tlsInfo := transport.TLSInfo{
+			TrustedCAFile: restoreJoinCACert,
+			CertFile:      restoreJoinCert,
+			KeyFile:       restoreJoinKey,
+		}
+		tlsCfg, err := tlsInfo.ClientConfig()
+		if err != nil {
+			cobrautl.ExitWithError(cobrautl.ExitBadArgs, err)
+		}
+		join.TLS = tlsCfg
+	}
+
+	dataDir := restoreDataDir
+	if dataDir == "" {
+		dataDir = restoreName + ".etcd"
+	}
+	walDir := restoreWalDir
+	if walDir == "" {
+		walDir = datadir.ToWalDir(dataDir)
+	}
+
+	sp := snapshot.NewV3(GetLogger())
+	if err := sp.Restore(snapshot.RestoreConfig{
+		SnapshotPath:  args[0],
+		Name:          restoreName,
+		OutputDataDir: dataDir,
+		OutputWALDir:  walDir,
+		PeerURLs:      strings.Split(restorePeerURLs, ","),
+		SkipHashCheck: skipHashCheck,
+		Join:          join,
 	}); err != nil {
 		cobrautl.ExitWithError(cobrautl.ExitError, err)
 	}
 }
 
+func splitDeltas(deltas string) []string {
+	if deltas == "" {
+		return nil
+	}
+	return strings.Split(deltas, ",")
+}
+
 func initialClusterFromName(name string) string {
 	n := name
 	if name == "" {
@@ -0,0 +1,153 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/client/v3/snapshot"
+	"go.etcd.io/etcd/pkg/v3/traceutil"
+	"go.etcd.io/etcd/server/v3/lease"
+	"go.etcd.io/etcd/server/v3/lease/leasepb"
+	"go.etcd.io/etcd/server/v3/storage/backend"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+	"go.etcd.io/etcd/server/v3/storage/schema"
+)
+
+// replayDeltas replays the snapshot deltas, in order, onto the restored database.
+func (s *v3Manager) replayDeltas(deltaPaths []string) error {
+	be := backend.NewDefaultBackend(s.lg, s.outDbPath())
+	defer be.Close()
+
+	for _, p := range deltaPaths {
+		if err := s.replayDelta(be, p); err != nil {
+			return fmt.Errorf("cannot replay snapshot delta %q: %w", p, err)
+		}
+	}
+	return nil
+}
+
+func (s *v3Manager) replayDelta(be backend.Backend, deltaPath string) error {
+	tx := be.BatchTx()
+	tx.LockOutsideApply()
+	latest, err := s.unsafeGetLatestRevision(tx)
+	compacted, _ := mvcc.UnsafeReadFinishedCompact(tx)
+	tx.Unlock()
+	if err != nil {
+		return err
+	}
+	rev := latest.main
+	if compacted > rev {
+		rev = compacted
+	}
+
+	s.lg.Info("replaying snapshot delta", zap.String("path", deltaPath), zap.Int64("revision", rev))
+	var last *etcdserverpb.SnapshotDeltaResponse
+	err = snapshot.ReadDelta(deltaPath, func(resp *etcdserverpb.SnapshotDeltaResponse) error {
+		if resp.StartRevision != rev {
+			return fmt.Errorf("delta starts at revision %d, but the snapshot is at revision %d", resp.StartRevision, rev)
+		}
+		tx.LockOutsideApply()
+		defer tx.Unlock()
+		for _, kv := range resp.Kvs {
+			if kv.Revision <= rev || kv.Revision > resp.EndRevision {
+				return fmt.Errorf("revision %d is out of the delta range (%d, %d]", kv.Revision, rev, resp.EndRevision)
+			}
+			rkv := mvcc.RevisionKeyValue{Revision: kv.Revision, SubRevision: kv.SubRevision, Tombstone: kv.Tombstone}
+			if kv.Kv != nil {
+				rkv.KV = *kv.Kv
+			}
+			if err := mvcc.UnsafePutRevision(tx, rkv); err != nil {
+				return err
+			}
+		}
+		if resp.Done {
+			last = resp
+			return s.unsafeReplaceState(be, tx, resp)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	be.ForceCommit()
+
+	return s.verifyDelta(be, last)
+}
+
+// unsafeReplaceState replaces the lease and auth state of the database with
+// those of the delta, and reindexes the key expiries.
+func (s *v3Manager) unsafeReplaceState(be backend.Backend, tx backend.BatchTx, resp *etcdserverpb.SnapshotDeltaResponse) error {
+	for _, l := range schema.MustUnsafeGetAllLeases(tx) {
+		schema.UnsafeDeleteLease(tx, l)
+	}
+	for _, l := range resp.Leases {
+		schema.MustUnsafePutLease(tx, &leasepb.Lease{ID: l.ID, TTL: l.TTL, RemainingTTL: l.RemainingTTL, Parent: l.Parent, Owner: l.Owner})
+	}
+
+	if auth := resp.Auth; auth != nil {
+		// the auth batch tx wraps tx, which is already locked
+		atx := schema.NewAuthBackend(s.lg, be).BatchTx()
+		for _, u := range atx.UnsafeGetAllUsers() {
+			atx.UnsafeDeleteUser(string(u.Name))
+		}
+		for _, r := range atx.UnsafeGetAllRoles() {
+			atx.UnsafeDeleteRole(string(r.Name))
+		}
+		for _, u := range auth.Users {
+			atx.UnsafePutUser(u)
+		}
+		for _, r := range auth.Roles {
+			atx.UnsafePutRole(r)
+		}
+		atx.UnsafeSaveAuthEnabled(auth.Enabled)
+		atx.UnsafeSaveAuthRevision(auth.Revision)
+	}
+	return mvcc.UnsafeRebuildKeyExpiry(tx)
+}
+
+// verifyDelta checks that the key space replayed from the delta hashes the same
+// as the key space it was exported from.
+func (s *v3Manager) verifyDelta(be backend.Backend, last *etcdserverpb.SnapshotDeltaResponse) error {
+	kvs := mvcc.NewStore(s.lg, be, &lease.FakeLessor{}, mvcc.StoreConfig{})
+	defer kvs.Close()
+
+	// revisions compacted in the cluster since the snapshot do not count in its hash
+	ch, err := kvs.Compact(traceutil.TODO(), last.CompactRevision)
+	switch err {
+	case nil:
+		<-ch
+	case mvcc.ErrCompacted:
+	default:
+		return err
+	}
+	h, _, err := kvs.HashStorage().HashByRev(last.EndRevision)
+	if err != nil {
+		return err
+	}
+	if h.Hash != last.Hash {
+		return fmt.Errorf("hash of the key space at revision %d is %d, expected %d", last.EndRevision, h.Hash, last.Hash)
+	}
+	s.lg.Info(
+		"replayed snapshot delta",
+		zap.Int64("start-revision", last.StartRevision),
+		zap.Int64("end-revision", last.EndRevision),
+		zap.Uint32("hash", h.Hash),
+	)
+	return nil
+}
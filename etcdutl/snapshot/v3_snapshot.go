@@ -16,15 +16,13 @@ package snapshot
 
 import (
 	"context"
-	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"hash/crc32"
-	"io"
 	"os"
 	"path/filepath"
-	"reflect"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -49,6 +47,9 @@ import (
 	"go.etcd.io/raft/v3/raftpb"
 )
 
+// joinTimeout is the timeout of the requests adding a restored learner to its cluster.
+const joinTimeout = 30 * time.Second
+
 // Manager defines snapshot methods.
 type Manager interface {
 	// Save fetches snapshot from remote etcd server, saves data
@@ -86,14 +87,6 @@ type v3Manager struct {
 	skipHashCheck bool
 }
 
-// hasChecksum returns "true" if the file size "n"
-// has appended sha256 hash digest.
-func hasChecksum(n int64) bool {
-	// 512 is chosen because it's a minimum disk sector size
-	// smaller than (and multiplies to) OS page size in most systems
-	return (n % 512) == sha256.Size
-}
-
 // Save fetches snapshot from remote etcd server and saves data to target path.
 func (s *v3Manager) Save(ctx context.Context, cfg clientv3.Config, dbPath string) (version string, err error) {
 	return snapshot.SaveWithVersion(ctx, s.lg, cfg, dbPath)
@@ -213,10 +206,25 @@ type RestoreConfig struct {
 	// MarkCompacted is "true" to mark the latest revision as compacted.
 	// (required if RevisionBump > 0)
 	MarkCompacted bool
+
+	// Deltas are the paths of the snapshot deltas, saved from the cluster with
+	// clientv3/snapshot.SaveDelta, replayed in order onto the snapshot before it
+	// is restored. Each delta must start at the revision the previous one ended.
+	Deltas []string
+
+	// Join is the client configuration of the running cluster the snapshot was saved from.
+	// If set, instead of bootstrapping a new cluster, the restored member is added to it
+	// as a learner, which resumes raft from the snapshot so that the leader only sends it
+	// the entries committed since. InitialCluster and InitialClusterToken are ignored.
+	Join *clientv3.Config
 }
 
 // Restore restores a new etcd data directory from given snapshot file.
 func (s *v3Manager) Restore(cfg RestoreConfig) error {
+	if cfg.Join != nil {
+		return s.restoreLearner(cfg)
+	}
+
 	pURLs, err := types.NewURLs(cfg.PeerURLs)
 	if err != nil {
 		return err
@@ -243,19 +251,74 @@ func (s *v3Manager) Restore(cfg RestoreConfig) error {
 		return err
 	}
 
+	dataDir, err := s.setup(cfg)
+	if err != nil {
+		return err
+	}
+
+	s.lg.Info(
+		"restoring snapshot",
+		zap.String("path", s.srcDbPath),
+		zap.String("wal-dir", s.walDir),
+		zap.String("data-dir", dataDir),
+		zap.String("snap-dir", s.snapDir),
+	)
+
+	if err = s.saveDB(); err != nil {
+		return err
+	}
+
+	if len(cfg.Deltas) > 0 {
+		if err = s.replayDeltas(cfg.Deltas); err != nil {
+			return err
+		}
+	}
+
+	if cfg.MarkCompacted && cfg.RevisionBump > 0 {
+		if err = s.modifyLatestRevision(cfg.RevisionBump); err != nil {
+			return err
+		}
+	}
+
+	hardstate, err := s.saveWALAndSnap()
+	if err != nil {
+		return err
+	}
+
+	if err := s.updateCIndex(hardstate.Commit, hardstate.Term); err != nil {
+		return err
+	}
+
+	s.lg.Info(
+		"restored snapshot",
+		zap.String("path", s.srcDbPath),
+		zap.String("wal-dir", s.walDir),
+		zap.String("data-dir", dataDir),
+		zap.String("snap-dir", s.snapDir),
+	)
+
+	return verify.VerifyIfEnabled(verify.Config{
+		ExactIndex: true,
+		Logger:     s.lg,
+		DataDir:    dataDir,
+	})
+}
+
+// setup checks the output directories of the restore and returns the data directory.
+func (s *v3Manager) setup(cfg RestoreConfig) (string, error) {
 	dataDir := cfg.OutputDataDir
 	if dataDir == "" {
 		dataDir = cfg.Name + ".etcd"
 	}
 	if fileutil.Exist(dataDir) && !fileutil.DirEmpty(dataDir) {
-		return fmt.Errorf("data-dir %q not empty or could not be read", dataDir)
+		return "", fmt.Errorf("data-dir %q not empty or could not be read", dataDir)
 	}
 
 	walDir := cfg.OutputWALDir
 	if walDir == "" {
 		walDir = filepath.Join(dataDir, "member", "wal")
 	} else if fileutil.Exist(walDir) {
-		return fmt.Errorf("wal-dir %q exists", walDir)
+		return "", fmt.Errorf("wal-dir %q exists", walDir)
 	}
 
 	s.name = cfg.Name
@@ -263,40 +326,84 @@ func (s *v3Manager) Restore(cfg RestoreConfig) error {
 	s.walDir = walDir
 	s.snapDir = filepath.Join(dataDir, "member", "snap")
 	s.skipHashCheck = cfg.SkipHashCheck
+	return dataDir, nil
+}
+
+// restoreLearner restores the data directory of a learner joining the cluster
+// the snapshot was saved from.
+func (s *v3Manager) restoreLearner(cfg RestoreConfig) error {
+	if cfg.RevisionBump > 0 || cfg.MarkCompacted {
+		return fmt.Errorf("the revision of a learner joining a running cluster cannot be bumped")
+	}
+	if len(cfg.Deltas) > 0 {
+		return fmt.Errorf("a learner joining a running cluster cannot be restored from snapshot deltas")
+	}
+	if _, err := types.NewURLs(cfg.PeerURLs); err != nil {
+		return err
+	}
+	dataDir, err := s.setup(cfg)
+	if err != nil {
+		return err
+	}
 
 	s.lg.Info(
-		"restoring snapshot",
+		"restoring snapshot for learner",
 		zap.String("path", s.srcDbPath),
 		zap.String("wal-dir", s.walDir),
 		zap.String("data-dir", dataDir),
 		zap.String("snap-dir", s.snapDir),
+		zap.Strings("join-endpoints", cfg.Join.Endpoints),
 	)
 
-	if err = s.saveDB(); err != nil {
+	if err = s.copyAndVerifyDB(); err != nil {
 		return err
 	}
+	be := backend.NewDefaultBackend(s.lg, s.outDbPath())
+	index, _ := schema.ReadConsistentIndex(be.ReadTx())
+	be.Close()
 
-	if cfg.MarkCompacted && cfg.RevisionBump > 0 {
-		if err = s.modifyLatestRevision(cfg.RevisionBump); err != nil {
-			return err
-		}
+	jcfg := *cfg.Join
+	jcfg.Logger = s.lg.Named("client")
+	cli, err := clientv3.New(jcfg)
+	if err != nil {
+		return err
 	}
+	defer cli.Close()
 
-	hardstate, err := s.saveWALAndSnap()
+	ctx, cancel := context.WithTimeout(context.Background(), joinTimeout)
+	defer cancel()
+	// a snapshot ahead of the cluster was not saved from it
+	sresp, err := cli.Status(ctx, cli.Endpoints()[0])
 	if err != nil {
 		return err
 	}
+	if index > sresp.RaftAppliedIndex {
+		return fmt.Errorf("snapshot consistent index %d is ahead of the cluster applied index %d", index, sresp.RaftAppliedIndex)
+	}
 
-	if err := s.updateCIndex(hardstate.Commit, hardstate.Term); err != nil {
+	mresp, err := cli.MemberAddAsLearner(ctx, cfg.PeerURLs)
+	if err != nil {
+		return err
+	}
+	learner := toMember(mresp.Member)
+	members := make([]*membership.Member, len(mresp.Members))
+	for i := range mresp.Members {
+		members[i] = toMember(mresp.Members[i])
+	}
+	if err = etcdserver.SeedLearner(s.lg, s.outDbPath(), s.walDir, s.snapDir, types.ID(mresp.Header.ClusterId), learner, members); err != nil {
+		// do not leave behind a learner which cannot be started
+		if _, rerr := cli.MemberRemove(ctx, mresp.Member.ID); rerr != nil {
+			s.lg.Warn("failed to remove learner", zap.String("learner-id", learner.ID.String()), zap.Error(rerr))
+		}
 		return err
 	}
 
 	s.lg.Info(
-		"restored snapshot",
+		"restored snapshot for learner, start it with --initial-cluster-state=existing",
 		zap.String("path", s.srcDbPath),
-		zap.String("wal-dir", s.walDir),
 		zap.String("data-dir", dataDir),
-		zap.String("snap-dir", s.snapDir),
+		zap.String("learner-id", learner.ID.String()),
+		zap.Uint64("consistent-index", index),
 	)
 
 	return verify.VerifyIfEnabled(verify.Config{
@@ -306,6 +413,14 @@ func (s *v3Manager) Restore(cfg RestoreConfig) error {
 	})
 }
 
+func toMember(m *etcdserverpb.Member) *membership.Member {
+	return &membership.Member{
+		ID:             types.ID(m.ID),
+		RaftAttributes: membership.RaftAttributes{PeerURLs: m.PeerURLs, IsLearner: m.IsLearner},
+		Attributes:     membership.Attributes{Name: m.Name, ClientURLs: m.ClientURLs},
+	}
+}
+
 func (s *v3Manager) outDbPath() string {
 	return filepath.Join(s.snapDir, "db")
 }
@@ -393,74 +508,11 @@ func (s *v3Manager) unsafeGetLatestRevision(tx backend.UnsafeReader) (revision,
 }
 
 func (s *v3Manager) copyAndVerifyDB() error {
-	srcf, ferr := os.Open(s.srcDbPath)
-	if ferr != nil {
-		return ferr
-	}
-	defer srcf.Close()
-
-	// get snapshot integrity hash
-	if _, err := srcf.Seek(-sha256.Size, io.SeekEnd); err != nil {
-		return err
-	}
-	sha := make([]byte, sha256.Size)
-	if _, err := srcf.Read(sha); err != nil {
-		return err
-	}
-	if _, err := srcf.Seek(0, io.SeekStart); err != nil {
-		return err
-	}
-
 	if err := fileutil.CreateDirAll(s.lg, s.snapDir); err != nil {
 		return err
 	}
-
-	outDbPath := s.outDbPath()
-
-	db, dberr := os.OpenFile(outDbPath, os.O_RDWR|os.O_CREATE, 0600)
-	if dberr != nil {
-		return dberr
-	}
-	defer db.Close()
-
-	if _, err := io.Copy(db, srcf); err != nil {
-		return err
-	}
-
-	// truncate away integrity hash, if any.
-	off, serr := db.Seek(0, io.SeekEnd)
-	if serr != nil {
-		return serr
-	}
-	hasHash := hasChecksum(off)
-	if hasHash {
-		if err := db.Truncate(off - sha256.Size); err != nil {
-			return err
-		}
-	}
-
-	if !hasHash && !s.skipHashCheck {
-		return fmt.Errorf("snapshot missing hash but --skip-hash-check=false")
-	}
-
-	if hasHash && !s.skipHashCheck {
-		// check for match
-		if _, err := db.Seek(0, io.SeekStart); err != nil {
-			return err
-		}
-		h := sha256.New()
-		if _, err := io.Copy(h, db); err != nil {
-			return err
-		}
-		dbsha := h.Sum(nil)
-		if !reflect.DeepEqual(sha, dbsha) {
-			return fmt.Errorf("expected sha256 %v, got %v", sha, dbsha)
-		}
-	}
-
-	// db hash is OK, can now modify DB so it can be part of a new cluster
-
-	return nil
+	// db hash is OK once copied, can now modify DB so it can be part of a new cluster
+	return snapshot.Copy(s.srcDbPath, s.outDbPath(), s.skipHashCheck)
 }
 
 // saveWALAndSnap creates a WAL for the initial cluster
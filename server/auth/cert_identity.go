@@ -0,0 +1,167 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"regexp"
+)
+
+const (
+	certFieldCommonName   = "cn"
+	certFieldURI          = "uri"
+	certFieldDNS          = "dns"
+	certFieldEmail        = "email"
+	certFieldOrganization = "o"
+	certFieldOrgUnit      = "ou"
+)
+
+// CertIdentity derives the user, and the roles granted to it, from the fields
+// of a client certificate, rather than only from its CommonName.
+type CertIdentity struct {
+	// User rules are tried in order, the first matching one names the user.
+	User []CertRule `json:"user"`
+	// Roles rules all grant a role to the user when matching.
+	Roles []CertRule `json:"roles,omitempty"`
+}
+
+// CertRule matches the values of a field of a certificate: "cn", "uri"
+// (URI SANs, such as SPIFFE IDs), "dns" (DNS SANs), "email", "o" or "ou".
+type CertRule struct {
+	Field string `json:"field"`
+	// Match is a regular expression the value must match, any value matches if
+	// empty, $0 then standing for the whole value.
+	Match string `json:"match,omitempty"`
+	// Replace rewrites the matching value into the user name, $1 standing for
+	// the first submatch. The whole value is used if empty.
+	Replace string `json:"replace,omitempty"`
+	// Role is the role granted by the rules of CertIdentity.Roles, which may
+	// refer to submatches like Replace.
+	Role string `json:"role,omitempty"`
+
+	re *regexp.Regexp
+}
+
+// ParseCertIdentity parses and validates the JSON encoded CertIdentity.
+func ParseCertIdentity(s string) (*CertIdentity, error) {
+	ci := &CertIdentity{}
+	if err := json.Unmarshal([]byte(s), ci); err != nil {
+		return nil, fmt.Errorf("cannot parse client certificate identity: %w", err)
+	}
+	if len(ci.User) == 0 {
+		return nil, fmt.Errorf("client certificate identity has no user rule")
+	}
+	for i := range ci.User {
+		if err := ci.User[i].compile(); err != nil {
+			return nil, err
+		}
+		if ci.User[i].Role != "" {
+			return nil, fmt.Errorf("client certificate user rule %d grants a role", i)
+		}
+	}
+	for i := range ci.Roles {
+		if err := ci.Roles[i].compile(); err != nil {
+			return nil, err
+		}
+		if ci.Roles[i].Role == "" {
+			return nil, fmt.Errorf("client certificate role rule %d has no role", i)
+		}
+	}
+	return ci, nil
+}
+
+func (r *CertRule) compile() error {
+	switch r.Field {
+	case certFieldCommonName, certFieldURI, certFieldDNS, certFieldEmail, certFieldOrganization, certFieldOrgUnit:
+	default:
+		return fmt.Errorf("unknown client certificate field %q", r.Field)
+	}
+	match := r.Match
+	if match == "" {
+		match = "^.*$"
+	}
+	var err error
+	if r.re, err = regexp.Compile(match); err != nil {
+		return fmt.Errorf("invalid client certificate rule of field %q: %w", r.Field, err)
+	}
+	return nil
+}
+
+// expand returns the template expanded with the submatches of the first value
+// of the field matching the rule.
+func (r *CertRule) expand(cert *x509.Certificate, template string) (string, bool) {
+	for _, v := range certFieldValues(cert, r.Field) {
+		if v == "" {
+			continue
+		}
+		m := r.re.FindStringSubmatchIndex(v)
+		if m == nil {
+			continue
+		}
+		if template == "" {
+			return v, true
+		}
+		if s := string(r.re.ExpandString(nil, template, v, m)); s != "" {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// identify returns the user named by the first matching user rule, and the
+// roles granted by the matching role rules.
+func (ci *CertIdentity) identify(cert *x509.Certificate) (string, []string) {
+	var user string
+	for i := range ci.User {
+		if u, ok := ci.User[i].expand(cert, ci.User[i].Replace); ok {
+			user = u
+			break
+		}
+	}
+	if user == "" {
+		return "", nil
+	}
+	var roles []string
+	for i := range ci.Roles {
+		if role, ok := ci.Roles[i].expand(cert, ci.Roles[i].Role); ok && !hasRole(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+	return user, roles
+}
+
+func certFieldValues(cert *x509.Certificate, field string) []string {
+	switch field {
+	case certFieldCommonName:
+		return []string{cert.Subject.CommonName}
+	case certFieldURI:
+		vs := make([]string, 0, len(cert.URIs))
+		for _, u := range cert.URIs {
+			vs = append(vs, u.String())
+		}
+		return vs
+	case certFieldDNS:
+		return cert.DNSNames
+	case certFieldEmail:
+		return cert.EmailAddresses
+	case certFieldOrganization:
+		return cert.Subject.Organization
+	case certFieldOrgUnit:
+		return cert.Subject.OrganizationalUnit
+	}
+	return nil
+}
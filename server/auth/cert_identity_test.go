@@ -0,0 +1,146 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+func TestParseCertIdentity(t *testing.T) {
+	tests := []struct {
+		s   string
+		err string
+	}{
+		{s: `{"user":[{"field":"cn"}]}`},
+		{s: `{"user":[{"field":"uri","match":"^spiffe://(.+)$","replace":"$1"}],"roles":[{"field":"ou","role":"ou-$0"}]}`},
+		{s: `{"user":`, err: "cannot parse"},
+		{s: `{"roles":[{"field":"ou","role":"r"}]}`, err: "no user rule"},
+		{s: `{"user":[{"field":"serial"}]}`, err: "unknown client certificate field"},
+		{s: `{"user":[{"field":"cn","match":"("}]}`, err: "invalid client certificate rule"},
+		{s: `{"user":[{"field":"cn","role":"r"}]}`, err: "grants a role"},
+		{s: `{"user":[{"field":"cn"}],"roles":[{"field":"ou"}]}`, err: "has no role"},
+	}
+	for i, tt := range tests {
+		_, err := ParseCertIdentity(tt.s)
+		if tt.err == "" && err != nil {
+			t.Errorf("#%d: unexpected error %v", i, err)
+		}
+		if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+			t.Errorf("#%d: error = %v, want %q", i, err, tt.err)
+		}
+	}
+}
+
+func TestCertIdentityIdentify(t *testing.T) {
+	ci, err := ParseCertIdentity(`{
+		"user": [
+			{"field": "uri", "match": "^spiffe://example.org/ns/([^/]+)/sa/([^/]+)$", "replace": "$1-$2"},
+			{"field": "email", "match": "^(.+)@example.org$", "replace": "$1"},
+			{"field": "cn"}
+		],
+		"roles": [
+			{"field": "ou", "match": "^platform$", "role": "platform-admin"},
+			{"field": "o", "match": "^team-(.+)$", "role": "$1"},
+			{"field": "dns", "match": "\\.ops\\.example\\.org$", "role": "platform-admin"}
+		]
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	spiffe, _ := url.Parse("spiffe://example.org/ns/prod/sa/web")
+	other, _ := url.Parse("https://example.org/web")
+
+	tests := []struct {
+		cert  *x509.Certificate
+		user  string
+		roles []string
+	}{
+		{
+			cert: &x509.Certificate{
+				Subject:  pkix.Name{CommonName: "web", OrganizationalUnit: []string{"platform"}, Organization: []string{"team-web", "acme"}},
+				URIs:     []*url.URL{other, spiffe},
+				DNSNames: []string{"web.ops.example.org"},
+			},
+			user:  "prod-web",
+			roles: []string{"platform-admin", "web"},
+		},
+		{
+			cert: &x509.Certificate{
+				Subject:        pkix.Name{CommonName: "alice"},
+				URIs:           []*url.URL{other},
+				EmailAddresses: []string{"alice@example.com", "alice@example.org"},
+			},
+			user: "alice",
+		},
+		{
+			cert: &x509.Certificate{Subject: pkix.Name{CommonName: "bob", Organization: []string{"acme"}}},
+			user: "bob",
+		},
+		{
+			cert: &x509.Certificate{Subject: pkix.Name{OrganizationalUnit: []string{"platform"}}},
+		},
+	}
+	for i, tt := range tests {
+		user, roles := ci.identify(tt.cert)
+		if user != tt.user || !reflect.DeepEqual(roles, tt.roles) {
+			t.Errorf("#%d: identify = %q %v, want %q %v", i, user, roles, tt.user, tt.roles)
+		}
+	}
+}
+
+func TestAuthInfoFromTLSCertIdentity(t *testing.T) {
+	as, tearDown := setupAuthStore(t)
+	defer tearDown(t)
+
+	spiffe, _ := url.Parse("spiffe://example.org/sa/web")
+	cert := &x509.Certificate{
+		Subject: pkix.Name{CommonName: "foo", OrganizationalUnit: []string{"ops"}},
+		URIs:    []*url.URL{spiffe},
+	}
+	ctx := peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}},
+	})
+	ctx = metadata.NewIncomingContext(ctx, metadata.New(nil))
+
+	ai := as.AuthInfoFromTLS(ctx, nil)
+	if ai == nil || ai.Username != "foo" || len(ai.Roles) != 0 {
+		t.Fatalf("expected the common name to name the user, got %+v", ai)
+	}
+
+	ci, err := ParseCertIdentity(`{"user":[{"field":"uri","match":"^spiffe://example.org/sa/(.+)$","replace":"$1"}],"roles":[{"field":"ou","role":"$0"}]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ai = as.AuthInfoFromTLS(ctx, ci)
+	if ai == nil || ai.Username != "web" || !reflect.DeepEqual(ai.Roles, []string{"ops"}) {
+		t.Fatalf("expected user web with role ops, got %+v", ai)
+	}
+
+	cert.URIs = nil
+	if ai = as.AuthInfoFromTLS(ctx, ci); ai != nil {
+		t.Fatalf("expected no user when no rule matches, got %+v", ai)
+	}
+}
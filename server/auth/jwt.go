@@ -24,6 +24,8 @@ import (
 
 	jwt "github.com/golang-jwt/jwt/v4"
 	"go.uber.org/zap"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 )
 
 type tokenJWT struct {
@@ -38,6 +40,8 @@ func (t *tokenJWT) enable()                         {}
 func (t *tokenJWT) disable()                        {}
 func (t *tokenJWT) invalidateUser(string)           {}
 func (t *tokenJWT) genTokenPrefix() (string, error) { return "", nil }
+func (t *tokenJWT) tokens(string) []*pb.AuthToken   { return nil }
+func (t *tokenJWT) revoke(uint64) bool              { return false }
 
 func (t *tokenJWT) info(ctx context.Context, token string, rev uint64) (*AuthInfo, bool) {
 	// rev isn't used in JWT, it is only used in simple token
@@ -95,6 +99,10 @@ func (t *tokenJWT) assign(ctx context.Context, username string, revision uint64)
 	if t.verifyOnly {
 		return "", ErrVerifyOnly
 	}
+	// JWT tokens cannot be revoked, so they are not issued with a narrower scope either
+	if tk, ok := ctx.Value(AuthenticateParamToken{}).(*pb.AuthToken); ok && tk.Scope != nil {
+		return "", ErrInvalidTokenScope
+	}
 
 	// Future work: let a jwt token include permission information would be useful for
 	// permission checking in proxy side.
@@ -0,0 +1,261 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	"go.etcd.io/etcd/api/v3/authpb"
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+)
+
+func (as *authStore) UserSetLimits(r *pb.AuthUserSetLimitsRequest) (*pb.AuthUserSetLimitsResponse, error) {
+	if r.Name == rootUser {
+		as.lg.Error("cannot set limits of 'root' user", zap.String("user-name", r.Name))
+		return nil, ErrInvalidAuthMgmt
+	}
+
+	tx := as.be.BatchTx()
+	tx.Lock()
+	defer tx.Unlock()
+
+	user := tx.UnsafeGetUser(r.Name)
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	user.Limits = r.Limits
+	tx.UnsafePutUser(user)
+
+	as.commitRevision(tx)
+	as.refreshRangePermCache(tx)
+
+	as.lg.Info("set limits of a user", append([]zap.Field{zap.String("user-name", r.Name)}, limitsFields(r.Limits)...)...)
+	return &pb.AuthUserSetLimitsResponse{}, nil
+}
+
+func (as *authStore) RoleSetLimits(r *pb.AuthRoleSetLimitsRequest) (*pb.AuthRoleSetLimitsResponse, error) {
+	if r.Role == rootRole {
+		as.lg.Error("cannot set limits of 'root' role", zap.String("role-name", r.Role))
+		return nil, ErrInvalidAuthMgmt
+	}
+
+	tx := as.be.BatchTx()
+	tx.Lock()
+	defer tx.Unlock()
+
+	role := tx.UnsafeGetRole(r.Role)
+	if role == nil {
+		return nil, ErrRoleNotFound
+	}
+	role.Limits = r.Limits
+	tx.UnsafePutRole(role)
+
+	as.commitRevision(tx)
+	as.refreshRangePermCache(tx)
+
+	as.lg.Info("set limits of a role", append([]zap.Field{zap.String("role-name", r.Role)}, limitsFields(r.Limits)...)...)
+	return &pb.AuthRoleSetLimitsResponse{}, nil
+}
+
+func limitsFields(l *authpb.Limits) []zap.Field {
+	if l == nil {
+		l = &authpb.Limits{}
+	}
+	return []zap.Field{
+		zap.Uint64("request-rate", l.RequestRate),
+		zap.Uint64("max-watches", l.MaxWatches),
+		zap.Uint64("max-leases", l.MaxLeases),
+		zap.Uint64("max-bytes", l.MaxBytes),
+	}
+}
+
+// Limits returns, for each resource, the lowest limit set on the user and
+// on its roles, including the roles granted by its token. Users with the root
+// role are never limited.
+func (as *authStore) Limits(authInfo *AuthInfo) *authpb.Limits {
+	if !as.IsAuthEnabled() || authInfo == nil || authInfo.Username == "" {
+		return nil
+	}
+	if hasRole(authInfo.Roles, rootRole) {
+		return nil
+	}
+
+	as.rangePermCacheMu.RLock()
+	defer as.rangePermCacheMu.RUnlock()
+
+	var limits *authpb.Limits
+	if ul, ok := as.userLimitsCache[authInfo.Username]; ok {
+		if ul.root {
+			return nil
+		}
+		limits = ul.limits
+	}
+	for _, r := range authInfo.Roles {
+		limits = mergeLimits(limits, as.roleLimitsCache[r])
+	}
+	return limits
+}
+
+// userLimits is the limits of a user merged with those of its roles.
+type userLimits struct {
+	limits *authpb.Limits
+	// root is whether the user has the root role, and so is never limited.
+	root bool
+}
+
+// refreshLimitsCache rebuilds the limits of the users and of the roles. It
+// must be called with rangePermCacheMu write locked.
+func (as *authStore) refreshLimitsCache(users []*authpb.User, roles []*authpb.Role) {
+	as.roleLimitsCache = make(map[string]*authpb.Limits, len(roles))
+	for _, role := range roles {
+		if role.Limits != nil {
+			as.roleLimitsCache[string(role.Name)] = role.Limits
+		}
+	}
+	as.userLimitsCache = make(map[string]*userLimits, len(users))
+	for _, user := range users {
+		ul := &userLimits{limits: user.Limits, root: hasRootRole(user)}
+		for _, r := range user.Roles {
+			ul.limits = mergeLimits(ul.limits, as.roleLimitsCache[r])
+		}
+		as.userLimitsCache[string(user.Name)] = ul
+	}
+}
+
+// Permissions returns the key permissions of the roles of the user, including
+// the roles granted by its token.
+func (as *authStore) Permissions(authInfo *AuthInfo) []*authpb.Permission {
+	if authInfo == nil || authInfo.Username == "" {
+		return nil
+	}
+
+	tx := as.be.ReadTx()
+	tx.RLock()
+	defer tx.RUnlock()
+
+	roles := authInfo.Roles
+	if user := tx.UnsafeGetUser(authInfo.Username); user != nil {
+		roles = append(roles[:len(roles):len(roles)], user.Roles...)
+	}
+	var perms []*authpb.Permission
+	for _, r := range roles {
+		if role := tx.UnsafeGetRole(r); role != nil {
+			perms = append(perms, role.KeyPermission...)
+		}
+	}
+	return perms
+}
+
+// mergeLimits returns the lowest of the limits a and b for each resource,
+// zero meaning unlimited.
+func mergeLimits(a, b *authpb.Limits) *authpb.Limits {
+	if b == nil {
+		return a
+	}
+	if a == nil {
+		a = &authpb.Limits{}
+	}
+	return &authpb.Limits{
+		RequestRate: minLimit(a.RequestRate, b.RequestRate),
+		MaxWatches:  minLimit(a.MaxWatches, b.MaxWatches),
+		MaxLeases:   minLimit(a.MaxLeases, b.MaxLeases),
+		MaxBytes:    minLimit(a.MaxBytes, b.MaxBytes),
+	}
+}
+
+func minLimit(a, b uint64) uint64 {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+// Limiter enforces the limits of users that are tracked by each member on its
+// own: the rate of requests and the number of concurrent watches.
+type Limiter struct {
+	as AuthStore
+
+	mu      sync.Mutex
+	rates   map[string]*rate.Limiter
+	watches map[string]int
+}
+
+// NewLimiter creates a Limiter enforcing the limits of the users of as.
+func NewLimiter(as AuthStore) *Limiter {
+	return &Limiter{
+		as:      as,
+		rates:   make(map[string]*rate.Limiter),
+		watches: make(map[string]int),
+	}
+}
+
+// AllowRequest returns ErrLimitExceeded if the user sent more requests than
+// its request rate allows.
+func (l *Limiter) AllowRequest(authInfo *AuthInfo) error {
+	limits := l.as.Limits(authInfo)
+	if limits == nil || limits.RequestRate == 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	limit := rate.Limit(limits.RequestRate)
+	r := l.rates[authInfo.Username]
+	if r == nil || r.Limit() != limit {
+		// allow a burst of one second worth of requests
+		r = rate.NewLimiter(limit, int(limits.RequestRate))
+		l.rates[authInfo.Username] = r
+	}
+	if !r.Allow() {
+		return ErrLimitExceeded
+	}
+	return nil
+}
+
+// AcquireWatch counts a new watch of the user, or returns ErrLimitExceeded if
+// the user already has as many watches as it is allowed. Every acquired watch
+// must be released with ReleaseWatch.
+func (l *Limiter) AcquireWatch(authInfo *AuthInfo) error {
+	if authInfo == nil || authInfo.Username == "" {
+		return nil
+	}
+	limits := l.as.Limits(authInfo)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if limits != nil && limits.MaxWatches != 0 && uint64(l.watches[authInfo.Username]) >= limits.MaxWatches {
+		return ErrLimitExceeded
+	}
+	l.watches[authInfo.Username]++
+	return nil
+}
+
+// ReleaseWatch uncounts a watch of the user acquired with AcquireWatch.
+func (l *Limiter) ReleaseWatch(authInfo *AuthInfo) {
+	if authInfo == nil || authInfo.Username == "" {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.watches[authInfo.Username] <= 1 {
+		delete(l.watches, authInfo.Username)
+		return
+	}
+	l.watches[authInfo.Username]--
+}
@@ -0,0 +1,167 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"reflect"
+	"testing"
+
+	"go.etcd.io/etcd/api/v3/authpb"
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+)
+
+func TestSetLimits(t *testing.T) {
+	as, tearDown := setupAuthStore(t)
+	defer tearDown(t)
+
+	if _, err := as.UserSetLimits(&pb.AuthUserSetLimitsRequest{Name: "nobody"}); err != ErrUserNotFound {
+		t.Fatalf("expected %v, got %v", ErrUserNotFound, err)
+	}
+	if _, err := as.RoleSetLimits(&pb.AuthRoleSetLimitsRequest{Role: "nobody"}); err != ErrRoleNotFound {
+		t.Fatalf("expected %v, got %v", ErrRoleNotFound, err)
+	}
+	if _, err := as.UserSetLimits(&pb.AuthUserSetLimitsRequest{Name: rootUser}); err != ErrInvalidAuthMgmt {
+		t.Fatalf("expected %v, got %v", ErrInvalidAuthMgmt, err)
+	}
+
+	limits := &authpb.Limits{RequestRate: 10, MaxBytes: 100}
+	if _, err := as.UserSetLimits(&pb.AuthUserSetLimitsRequest{Name: "foo", Limits: limits}); err != nil {
+		t.Fatal(err)
+	}
+	// the limits are kept when the user is updated
+	if _, err := as.UserGrantRole(&pb.AuthUserGrantRoleRequest{User: "foo", Role: "role-test"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := as.UserRevokeRole(&pb.AuthUserRevokeRoleRequest{Name: "foo", Role: "role-test"}); err != nil {
+		t.Fatal(err)
+	}
+	u, err := as.UserGet(&pb.AuthUserGetRequest{Name: "foo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(u.Limits, limits) {
+		t.Fatalf("expected limits %v, got %v", limits, u.Limits)
+	}
+
+	if _, err = as.RoleSetLimits(&pb.AuthRoleSetLimitsRequest{Role: "role-test", Limits: limits}); err != nil {
+		t.Fatal(err)
+	}
+	r, err := as.RoleGet(&pb.AuthRoleGetRequest{Role: "role-test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(r.Limits, limits) {
+		t.Fatalf("expected limits %v, got %v", limits, r.Limits)
+	}
+}
+
+func TestLimits(t *testing.T) {
+	as, tearDown := setupAuthStore(t)
+	defer tearDown(t)
+
+	for _, r := range []string{"role-a", "role-b"} {
+		if _, err := as.RoleAdd(&pb.AuthRoleAddRequest{Name: r}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := as.UserGrantRole(&pb.AuthUserGrantRoleRequest{User: "foo", Role: "role-a"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := as.UserSetLimits(&pb.AuthUserSetLimitsRequest{Name: "foo", Limits: &authpb.Limits{RequestRate: 10, MaxWatches: 5}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := as.RoleSetLimits(&pb.AuthRoleSetLimitsRequest{Role: "role-a", Limits: &authpb.Limits{RequestRate: 20, MaxLeases: 3}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := as.RoleSetLimits(&pb.AuthRoleSetLimitsRequest{Role: "role-b", Limits: &authpb.Limits{MaxWatches: 2}}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		ai   *AuthInfo
+		want *authpb.Limits
+	}{
+		{"no user", &AuthInfo{}, nil},
+		{"root", &AuthInfo{Username: rootUser}, nil},
+		{"token root role", &AuthInfo{Username: "foo", Roles: []string{rootRole}}, nil},
+		{"user and roles", &AuthInfo{Username: "foo"}, &authpb.Limits{RequestRate: 10, MaxWatches: 5, MaxLeases: 3}},
+		{"token roles", &AuthInfo{Username: "foo", Roles: []string{"role-b"}}, &authpb.Limits{RequestRate: 10, MaxWatches: 2, MaxLeases: 3}},
+		{"external user", &AuthInfo{Username: "bar", Roles: []string{"role-b"}}, &authpb.Limits{MaxWatches: 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := as.Limits(tt.ai); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected limits %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	// the limits follow the changes of the users and roles
+	if _, err := as.RoleSetLimits(&pb.AuthRoleSetLimitsRequest{Role: "role-a", Limits: &authpb.Limits{MaxLeases: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := as.Limits(&AuthInfo{Username: "foo"}), (&authpb.Limits{RequestRate: 10, MaxWatches: 5, MaxLeases: 1}); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected limits %v, got %v", want, got)
+	}
+	if _, err := as.UserRevokeRole(&pb.AuthUserRevokeRoleRequest{Name: "foo", Role: "role-a"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := as.Limits(&AuthInfo{Username: "foo"}), (&authpb.Limits{RequestRate: 10, MaxWatches: 5}); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected limits %v, got %v", want, got)
+	}
+
+	as.AuthDisable()
+	if got := as.Limits(&AuthInfo{Username: "foo"}); got != nil {
+		t.Errorf("expected no limits with auth disabled, got %v", got)
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	as, tearDown := setupAuthStore(t)
+	defer tearDown(t)
+
+	if _, err := as.UserSetLimits(&pb.AuthUserSetLimitsRequest{Name: "foo", Limits: &authpb.Limits{RequestRate: 2, MaxWatches: 2}}); err != nil {
+		t.Fatal(err)
+	}
+	l := NewLimiter(as)
+	ai := &AuthInfo{Username: "foo"}
+
+	// the burst is one second worth of requests
+	for i := 0; i < 2; i++ {
+		if err := l.AllowRequest(ai); err != nil {
+			t.Fatalf("#%d: expected request to be allowed, got %v", i, err)
+		}
+	}
+	if err := l.AllowRequest(ai); err != ErrLimitExceeded {
+		t.Fatalf("expected %v, got %v", ErrLimitExceeded, err)
+	}
+	if err := l.AllowRequest(&AuthInfo{Username: rootUser}); err != nil {
+		t.Fatalf("expected root to be unlimited, got %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := l.AcquireWatch(ai); err != nil {
+			t.Fatalf("#%d: expected watch to be acquired, got %v", i, err)
+		}
+	}
+	if err := l.AcquireWatch(ai); err != ErrLimitExceeded {
+		t.Fatalf("expected %v, got %v", ErrLimitExceeded, err)
+	}
+	l.ReleaseWatch(ai)
+	if err := l.AcquireWatch(ai); err != nil {
+		t.Fatalf("expected watch to be acquired once released, got %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/api/v3/authpb"
+)
+
+// Mirror is a read-only copy of the users and roles of an auth store, replaced
+// by the states synced from it, which checks the permissions of requests the
+// same way the auth store does.
+type Mirror struct {
+	// mu protects as from being checked while replaced
+	mu sync.RWMutex
+	as *authStore
+}
+
+func NewMirror(lg *zap.Logger) *Mirror {
+	if lg == nil {
+		lg = zap.NewNop()
+	}
+	return &Mirror{as: &authStore{
+		lg:                 lg,
+		be:                 newStateBackend(&State{}),
+		rangePermCache:     make(map[string]*unifiedRangePermissions),
+		roleRangePermCache: make(map[string]*unifiedRangePermissions),
+		tokenProvider:      &tokenNop{},
+	}}
+}
+
+// Update replaces the users and roles of the mirror with the given state.
+func (m *Mirror) Update(st *State) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.as.Recover(newStateBackend(st))
+}
+
+func (m *Mirror) IsAuthEnabled() bool { return m.as.IsAuthEnabled() }
+
+func (m *Mirror) Revision() uint64 { return m.as.Revision() }
+
+// IsRangePermitted checks that the user may read the range, at the revision
+// of the mirror.
+func (m *Mirror) IsRangePermitted(authInfo *AuthInfo, key, rangeEnd []byte) error {
+	return m.isOpPermitted(authInfo, key, rangeEnd, authpb.READ)
+}
+
+// IsWatchPermitted checks that the user may watch the range, at the revision
+// of the mirror.
+func (m *Mirror) IsWatchPermitted(authInfo *AuthInfo, key, rangeEnd []byte) error {
+	return m.isOpPermitted(authInfo, key, rangeEnd, authpb.WATCH)
+}
+
+func (m *Mirror) isOpPermitted(authInfo *AuthInfo, key, rangeEnd []byte, permTyp authpb.Permission_Type) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if authInfo == nil {
+		authInfo = &AuthInfo{}
+	}
+	rev := uint64(0)
+	if authInfo.Username != "" || len(authInfo.Roles) != 0 {
+		// the identity of the user is checked by the auth store it was synced from
+		rev = m.as.Revision()
+	}
+	return m.as.isOpPermitted(authInfo.Username, rev, authInfo.Roles, authInfo.Scope, authInfo.Time, key, rangeEnd, permTyp)
+}
+
+// stateBackend is an in-memory auth backend holding a state.
+type stateBackend struct {
+	st    *State
+	users map[string]*authpb.User
+	roles map[string]*authpb.Role
+}
+
+func newStateBackend(st *State) *stateBackend {
+	b := &stateBackend{
+		st:    st,
+		users: make(map[string]*authpb.User, len(st.Users)),
+		roles: make(map[string]*authpb.Role, len(st.Roles)),
+	}
+	for _, u := range st.Users {
+		b.users[string(u.Name)] = u
+	}
+	for _, r := range st.Roles {
+		b.roles[string(r.Name)] = r
+	}
+	return b
+}
+
+func (b *stateBackend) CreateAuthBuckets()             {}
+func (b *stateBackend) ForceCommit()                   {}
+func (b *stateBackend) ReadTx() AuthReadTx             { return b }
+func (b *stateBackend) BatchTx() AuthBatchTx           { return b }
+func (b *stateBackend) GetUser(s string) *authpb.User  { return b.users[s] }
+func (b *stateBackend) GetAllUsers() []*authpb.User    { return b.st.Users }
+func (b *stateBackend) GetRole(s string) *authpb.Role  { return b.roles[s] }
+func (b *stateBackend) GetAllRoles() []*authpb.Role    { return b.st.Roles }
+func (b *stateBackend) Lock()                          {}
+func (b *stateBackend) Unlock()                        {}
+func (b *stateBackend) RLock()                         {}
+func (b *stateBackend) RUnlock()                       {}
+func (b *stateBackend) UnsafeReadAuthEnabled() bool    { return b.st.Enabled }
+func (b *stateBackend) UnsafeReadAuthRevision() uint64 { return b.st.Revision }
+func (b *stateBackend) UnsafeGetUser(s string) *authpb.User {
+	return b.users[s]
+}
+func (b *stateBackend) UnsafeGetRole(s string) *authpb.Role {
+	return b.roles[s]
+}
+func (b *stateBackend) UnsafeGetAllUsers() []*authpb.User { return b.st.Users }
+func (b *stateBackend) UnsafeGetAllRoles() []*authpb.Role { return b.st.Roles }
+
+// the state is read-only
+func (b *stateBackend) UnsafeSaveAuthEnabled(bool)    {}
+func (b *stateBackend) UnsafeSaveAuthRevision(uint64) {}
+func (b *stateBackend) UnsafePutUser(*authpb.User)    {}
+func (b *stateBackend) UnsafeDeleteUser(string)       {}
+func (b *stateBackend) UnsafePutRole(*authpb.Role)    {}
+func (b *stateBackend) UnsafeDeleteRole(string)       {}
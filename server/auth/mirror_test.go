@@ -0,0 +1,80 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+
+	"go.etcd.io/etcd/api/v3/authpb"
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+)
+
+// TestMirror ensures that a mirror permits the same requests as the auth store
+// its state is synced from.
+func TestMirror(t *testing.T) {
+	as, tearDown := setupAuthStore(t)
+	defer tearDown(t)
+
+	perm := &authpb.Permission{PermType: authpb.READ, Key: []byte("/a/"), RangeEnd: []byte("/a0")}
+	if _, err := as.RoleGrantPermission(&pb.AuthRoleGrantPermissionRequest{Name: "role-test", Perm: perm}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := as.UserGrantRole(&pb.AuthUserGrantRoleRequest{User: "foo", Role: "role-test"}); err != nil {
+		t.Fatal(err)
+	}
+
+	m := NewMirror(zaptest.NewLogger(t))
+	if m.IsAuthEnabled() {
+		t.Fatal("expected auth of an empty mirror to be disabled")
+	}
+	m.Update(as.State())
+	if !m.IsAuthEnabled() || m.Revision() != as.Revision() {
+		t.Fatalf("expected enabled mirror at revision %d, got %v at %d", as.Revision(), m.IsAuthEnabled(), m.Revision())
+	}
+
+	tests := []struct {
+		user  string
+		key   string
+		watch bool
+		want  error
+	}{
+		{"foo", "/a/x", false, nil},
+		{"foo", "/b/x", false, ErrPermissionDenied},
+		{"foo", "/a/x", true, nil},
+		{"root", "/b/x", true, nil},
+		{"", "/a/x", false, ErrUserEmpty},
+		{"unknown", "/a/x", false, ErrPermissionDenied},
+	}
+	for i, tt := range tests {
+		ai := &AuthInfo{Username: tt.user}
+		isPermitted := m.IsRangePermitted
+		if tt.watch {
+			isPermitted = m.IsWatchPermitted
+		}
+		if err := isPermitted(ai, []byte(tt.key), nil); err != tt.want {
+			t.Errorf("#%d: expected %v, got %v", i, tt.want, err)
+		}
+	}
+
+	if _, err := as.UserRevokeRole(&pb.AuthUserRevokeRoleRequest{Name: "foo", Role: "role-test"}); err != nil {
+		t.Fatal(err)
+	}
+	m.Update(as.State())
+	if err := m.IsRangePermitted(&AuthInfo{Username: "foo"}, []byte("/a/x"), nil); err != ErrPermissionDenied {
+		t.Errorf("expected %v once the role is revoked, got %v", ErrPermissionDenied, err)
+	}
+}
@@ -16,6 +16,8 @@ package auth
 
 import (
 	"context"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 )
 
 type tokenNop struct{}
@@ -24,6 +26,8 @@ func (t *tokenNop) enable()                         {}
 func (t *tokenNop) disable()                        {}
 func (t *tokenNop) invalidateUser(string)           {}
 func (t *tokenNop) genTokenPrefix() (string, error) { return "", nil }
+func (t *tokenNop) tokens(string) []*pb.AuthToken   { return nil }
+func (t *tokenNop) revoke(uint64) bool              { return false }
 func (t *tokenNop) info(ctx context.Context, token string, rev uint64) (*AuthInfo, bool) {
 	return nil, false
 }
@@ -0,0 +1,428 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	"go.uber.org/zap"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+)
+
+const (
+	optJWKS           = "jwks"
+	optJWKSRefresh    = "jwks-refresh"
+	optIssuer         = "issuer"
+	optAudience       = "audience"
+	optUsernameClaim  = "username-claim"
+	optUsernamePrefix = "username-prefix"
+	optGroupsClaim    = "groups-claim"
+	optGroupsPrefix   = "groups-prefix"
+
+	defaultUsernameClaim = "sub"
+	defaultJWKSRefresh   = 5 * time.Minute
+	// minJWKSRefresh rate limits the reloads of the JWKS on tokens signed by
+	// unknown keys, which are expected when the issuer rotates its keys.
+	minJWKSRefresh = 10 * time.Second
+	jwksTimeout    = 10 * time.Second
+)
+
+// tokenOIDC verifies OpenID Connect ID tokens issued outside of etcd against
+// the JSON Web Key Set of the issuer. It does not assign tokens.
+type tokenOIDC struct {
+	lg             *zap.Logger
+	issuer         string
+	audience       string
+	usernameClaim  string
+	usernamePrefix string
+	groupsClaim    string
+	groupsPrefix   string
+	keys           *jwks
+
+	mu    sync.Mutex
+	stopc chan struct{}
+	donec chan struct{}
+}
+
+func (t *tokenOIDC) invalidateUser(string)           {}
+func (t *tokenOIDC) genTokenPrefix() (string, error) { return "", nil }
+func (t *tokenOIDC) tokens(string) []*pb.AuthToken   { return nil }
+func (t *tokenOIDC) revoke(uint64) bool              { return false }
+
+func (t *tokenOIDC) enable() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.stopc != nil { // already enabled
+		return
+	}
+	t.stopc, t.donec = make(chan struct{}), make(chan struct{})
+	go t.keys.run(t.stopc, t.donec)
+}
+
+func (t *tokenOIDC) disable() {
+	t.mu.Lock()
+	stopc, donec := t.stopc, t.donec
+	t.stopc, t.donec = nil, nil
+	t.mu.Unlock()
+	if stopc != nil {
+		close(stopc)
+		<-donec
+	}
+}
+
+func (t *tokenOIDC) info(ctx context.Context, token string, rev uint64) (*AuthInfo, bool) {
+	parsed, err := jwt.Parse(token, t.keys.keyFunc)
+	if err != nil {
+		t.lg.Warn("failed to parse an OIDC token", zap.Error(err))
+		return nil, false
+	}
+	claims, ok := parsed.Claims.(jwt.MapClaims)
+	if !parsed.Valid || !ok {
+		t.lg.Warn("failed to obtain claims from an OIDC token")
+		return nil, false
+	}
+	if _, ok = claims["exp"]; !ok {
+		t.lg.Warn("OIDC token does not expire")
+		return nil, false
+	}
+	if !claims.VerifyIssuer(t.issuer, true) {
+		t.lg.Warn("unexpected issuer of OIDC token", zap.Any("issuer", claims["iss"]))
+		return nil, false
+	}
+	if t.audience != "" && !claims.VerifyAudience(t.audience, true) {
+		t.lg.Warn("unexpected audience of OIDC token", zap.Any("audience", claims["aud"]))
+		return nil, false
+	}
+
+	username, ok := claims[t.usernameClaim].(string)
+	if !ok || username == "" {
+		t.lg.Warn("failed to obtain user claim from OIDC token", zap.String("claim", t.usernameClaim))
+		return nil, false
+	}
+	ai := &AuthInfo{Username: t.usernamePrefix + username, Revision: rev}
+	if t.groupsClaim == "" {
+		return ai, true
+	}
+	switch groups := claims[t.groupsClaim].(type) {
+	case nil:
+	case string:
+		ai.Roles = []string{t.groupsPrefix + groups}
+	case []interface{}:
+		for _, g := range groups {
+			if s, ok := g.(string); ok {
+				ai.Roles = append(ai.Roles, t.groupsPrefix+s)
+			}
+		}
+	default:
+		t.lg.Warn("failed to obtain groups claim from OIDC token", zap.String("claim", t.groupsClaim))
+		return nil, false
+	}
+	return ai, true
+}
+
+func (t *tokenOIDC) assign(ctx context.Context, username string, revision uint64) (string, error) {
+	return "", ErrVerifyOnly
+}
+
+func newTokenProviderOIDC(lg *zap.Logger, optMap map[string]string) (*tokenOIDC, error) {
+	if lg == nil {
+		lg = zap.NewNop()
+	}
+	t := &tokenOIDC{
+		lg:             lg,
+		issuer:         optMap[optIssuer],
+		audience:       optMap[optAudience],
+		usernameClaim:  optMap[optUsernameClaim],
+		usernamePrefix: optMap[optUsernamePrefix],
+		groupsClaim:    optMap[optGroupsClaim],
+		groupsPrefix:   optMap[optGroupsPrefix],
+		keys:           &jwks{lg: lg, source: optMap[optJWKS], refresh: defaultJWKSRefresh},
+	}
+	for k := range optMap {
+		switch k {
+		case optJWKS, optJWKSRefresh, optIssuer, optAudience, optUsernameClaim, optUsernamePrefix, optGroupsClaim, optGroupsPrefix:
+		default:
+			lg.Warn("unknown OIDC option", zap.String("key", k))
+		}
+	}
+	if t.issuer == "" || t.keys.source == "" {
+		lg.Error("OIDC token requires the issuer and the JWKS of its keys")
+		return nil, ErrInvalidAuthOpts
+	}
+	if t.usernameClaim == "" {
+		t.usernameClaim = defaultUsernameClaim
+	}
+	if r := optMap[optJWKSRefresh]; r != "" {
+		var err error
+		if t.keys.refresh, err = time.ParseDuration(r); err != nil || t.keys.refresh < minJWKSRefresh {
+			lg.Error("invalid JWKS refresh interval", zap.String("refresh", r), zap.Duration("minimum", minJWKSRefresh))
+			return nil, ErrInvalidAuthOpts
+		}
+	}
+
+	if err := t.keys.load(); err != nil {
+		if !t.keys.remote() {
+			return nil, err
+		}
+		// the issuer may be unreachable for now, tokens are rejected until it is not
+		lg.Warn("failed to load JWKS", zap.String("jwks", t.keys.source), zap.Error(err))
+	}
+	return t, nil
+}
+
+// jwks is a JSON Web Key Set, loaded from a file or an URL, and periodically
+// reloaded to follow the key rotations of the issuer.
+type jwks struct {
+	lg      *zap.Logger
+	source  string
+	refresh time.Duration
+
+	mu       sync.RWMutex
+	keys     map[string]jwk
+	loadedAt time.Time
+}
+
+type jwk struct {
+	alg string
+	key interface{}
+}
+
+func (s *jwks) remote() bool {
+	return strings.HasPrefix(s.source, "https://") || strings.HasPrefix(s.source, "http://")
+}
+
+func (s *jwks) run(stopc, donec chan struct{}) {
+	defer close(donec)
+	ticker := time.NewTicker(s.refresh)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.load(); err != nil {
+				s.lg.Warn("failed to reload JWKS", zap.String("jwks", s.source), zap.Error(err))
+			}
+		case <-stopc:
+			return
+		}
+	}
+}
+
+func (s *jwks) keyFunc(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA, *jwt.SigningMethodEd25519:
+	default:
+		return nil, fmt.Errorf("unsupported signing method %q", token.Method.Alg())
+	}
+	kid, _ := token.Header["kid"].(string)
+	k, ok := s.get(kid)
+	if !ok {
+		// the issuer may have rotated its keys since the last load
+		if err := s.reload(); err != nil {
+			return nil, err
+		}
+		if k, ok = s.get(kid); !ok {
+			return nil, fmt.Errorf("unknown signing key %q", kid)
+		}
+	}
+	if k.alg != "" && k.alg != token.Method.Alg() {
+		return nil, fmt.Errorf("signing key %q is not used with %q", kid, token.Method.Alg())
+	}
+	return k.key, nil
+}
+
+func (s *jwks) get(kid string) (jwk, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if kid == "" && len(s.keys) == 1 {
+		for _, k := range s.keys {
+			return k, true
+		}
+	}
+	k, ok := s.keys[kid]
+	return k, ok
+}
+
+// reload loads the keys again, unless they have just been loaded.
+func (s *jwks) reload() error {
+	s.mu.RLock()
+	recent := time.Since(s.loadedAt) < minJWKSRefresh
+	s.mu.RUnlock()
+	if recent {
+		return nil
+	}
+	return s.load()
+}
+
+func (s *jwks) load() error {
+	s.mu.Lock()
+	s.loadedAt = time.Now()
+	s.mu.Unlock()
+
+	data, err := s.fetch()
+	if err != nil {
+		return err
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.keys = keys
+	s.mu.Unlock()
+	s.lg.Info("loaded JWKS", zap.String("jwks", s.source), zap.Int("keys", len(keys)))
+	return nil
+}
+
+func (s *jwks) fetch() ([]byte, error) {
+	if !s.remote() {
+		return os.ReadFile(s.source)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), jwksTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.source, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %q fetching %s", resp.Status, s.source)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+func parseJWKS(data []byte) (map[string]jwk, error) {
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			Alg string `json:"alg"`
+			Crv string `json:"crv"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("cannot parse JWKS: %w", err)
+	}
+	keys := make(map[string]jwk)
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		var (
+			key interface{}
+			err error
+		)
+		switch k.Kty {
+		case "RSA":
+			key, err = parseRSAJWK(k.N, k.E)
+		case "EC":
+			key, err = parseECJWK(k.Crv, k.X, k.Y)
+		case "OKP":
+			key, err = parseEdJWK(k.Crv, k.X)
+		default:
+			err = fmt.Errorf("unsupported key type %q", k.Kty)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse JWK %q: %w", k.Kid, err)
+		}
+		keys[k.Kid] = jwk{alg: k.Alg, key: key}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("JWKS has no signing key")
+	}
+	return keys, nil
+}
+
+func parseRSAJWK(n, e string) (*rsa.PublicKey, error) {
+	nb, err := base64.RawURLEncoding.DecodeString(n)
+	if err != nil {
+		return nil, err
+	}
+	eb, err := base64.RawURLEncoding.DecodeString(e)
+	if err != nil {
+		return nil, err
+	}
+	exp := new(big.Int).SetBytes(eb)
+	if len(nb) == 0 || !exp.IsInt64() || exp.Int64() < 2 || exp.Int64() > 1<<31-1 {
+		return nil, errors.New("invalid RSA key")
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(nb), E: int(exp.Int64())}, nil
+}
+
+func parseECJWK(crv, x, y string) (*ecdsa.PublicKey, error) {
+	var curve elliptic.Curve
+	switch crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", crv)
+	}
+	xb, err := base64.RawURLEncoding.DecodeString(x)
+	if err != nil {
+		return nil, err
+	}
+	yb, err := base64.RawURLEncoding.DecodeString(y)
+	if err != nil {
+		return nil, err
+	}
+	key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(xb), Y: new(big.Int).SetBytes(yb)}
+	if !curve.IsOnCurve(key.X, key.Y) {
+		return nil, errors.New("invalid EC key")
+	}
+	return key, nil
+}
+
+func parseEdJWK(crv, x string) (ed25519.PublicKey, error) {
+	if crv != "Ed25519" {
+		return nil, fmt.Errorf("unsupported curve %q", crv)
+	}
+	xb, err := base64.RawURLEncoding.DecodeString(x)
+	if err != nil {
+		return nil, err
+	}
+	if len(xb) != ed25519.PublicKeySize {
+		return nil, errors.New("invalid Ed25519 key")
+	}
+	return ed25519.PublicKey(xb), nil
+}
@@ -0,0 +1,211 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	"go.etcd.io/etcd/api/v3/authpb"
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+)
+
+const testOIDCIssuer = "https://issuer.example.com"
+
+func TestOIDCInfo(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	jwksPath := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(jwksPath, testJWKS(t, map[string]interface{}{"ec": ecKey, "ed": edKey, "rsa": rsaKey}), 0600))
+
+	p, err := newTokenProviderOIDC(zaptest.NewLogger(t), map[string]string{
+		optIssuer:         testOIDCIssuer,
+		optJWKS:           jwksPath,
+		optAudience:       "etcd",
+		optUsernameClaim:  "email",
+		optUsernamePrefix: "oidc:",
+		optGroupsClaim:    "groups",
+	})
+	require.NoError(t, err)
+
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":    testOIDCIssuer,
+			"aud":    []string{"etcd", "other"},
+			"exp":    time.Now().Add(time.Hour).Unix(),
+			"email":  "alice@example.com",
+			"groups": []string{"readers", "writers"},
+		}
+	}
+	ctx := context.TODO()
+	for _, tc := range []struct {
+		name   string
+		method jwt.SigningMethod
+		kid    string
+		key    interface{}
+	}{
+		{"ECDSA", jwt.SigningMethodES256, "ec", ecKey},
+		{"Ed25519", jwt.SigningMethodEdDSA, "ed", edKey},
+		{"RSA", jwt.SigningMethodRS256, "rsa", rsaKey},
+		{"RSAPSS", jwt.SigningMethodPS256, "rsa", rsaKey},
+	} {
+		ai, ok := p.info(ctx, testOIDCToken(t, tc.method, tc.kid, tc.key, valid()), 42)
+		require.Truef(t, ok, "%s token is rejected", tc.name)
+		assert.Equal(t, &AuthInfo{Username: "oidc:alice@example.com", Revision: 42, Roles: []string{"readers", "writers"}}, ai)
+	}
+
+	_, err = p.assign(ctx, "alice", 1)
+	assert.Equal(t, ErrVerifyOnly, err)
+
+	invalid := map[string]func(jwt.MapClaims){
+		"wrong issuer":   func(c jwt.MapClaims) { c["iss"] = "https://other.example.com" },
+		"wrong audience": func(c jwt.MapClaims) { c["aud"] = "other" },
+		"expired":        func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() },
+		"no expiry":      func(c jwt.MapClaims) { delete(c, "exp") },
+		"no user":        func(c jwt.MapClaims) { delete(c, "email") },
+		"invalid groups": func(c jwt.MapClaims) { c["groups"] = 1 },
+	}
+	for name, f := range invalid {
+		claims := valid()
+		f(claims)
+		_, ok := p.info(ctx, testOIDCToken(t, jwt.SigningMethodES256, "ec", ecKey, claims), 42)
+		assert.Falsef(t, ok, "token with %s is accepted", name)
+	}
+
+	// tokens must be signed by the keys of the issuer
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	_, ok := p.info(ctx, testOIDCToken(t, jwt.SigningMethodES256, "ec", otherKey, valid()), 42)
+	assert.False(t, ok, "token signed by another key is accepted")
+	_, ok = p.info(ctx, testOIDCToken(t, jwt.SigningMethodES256, "unknown", ecKey, valid()), 42)
+	assert.False(t, ok, "token signed by an unknown key is accepted")
+	_, ok = p.info(ctx, testOIDCToken(t, jwt.SigningMethodHS256, "ec", []byte("secret"), valid()), 42)
+	assert.False(t, ok, "token signed by a shared secret is accepted")
+}
+
+// TestOIDCKeyRotation ensures that tokens signed by a key the issuer rotated to
+// are accepted once the JWKS is reloaded.
+func TestOIDCKeyRotation(t *testing.T) {
+	key1, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	key2, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	var mu sync.Mutex
+	jwks := testJWKS(t, map[string]interface{}{"key1": key1})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		w.Write(jwks)
+	}))
+	defer srv.Close()
+
+	p, err := newTokenProviderOIDC(zaptest.NewLogger(t), map[string]string{optIssuer: testOIDCIssuer, optJWKS: srv.URL})
+	require.NoError(t, err)
+	claims := jwt.MapClaims{"iss": testOIDCIssuer, "sub": "alice", "exp": time.Now().Add(time.Hour).Unix()}
+	ctx := context.TODO()
+	_, ok := p.info(ctx, testOIDCToken(t, jwt.SigningMethodES256, "key1", key1, claims), 1)
+	require.True(t, ok)
+
+	mu.Lock()
+	jwks = testJWKS(t, map[string]interface{}{"key2": key2})
+	mu.Unlock()
+	// unknown keys are not reloaded more than once every minJWKSRefresh
+	_, ok = p.info(ctx, testOIDCToken(t, jwt.SigningMethodES256, "key2", key2, claims), 1)
+	require.False(t, ok)
+
+	p.keys.mu.Lock()
+	p.keys.loadedAt = time.Time{}
+	p.keys.mu.Unlock()
+	ai, ok := p.info(ctx, testOIDCToken(t, jwt.SigningMethodES256, "key2", key2, claims), 1)
+	require.True(t, ok)
+	assert.Equal(t, "alice", ai.Username)
+	_, ok = p.info(ctx, testOIDCToken(t, jwt.SigningMethodES256, "key1", key1, claims), 1)
+	assert.False(t, ok, "token signed by a rotated out key is accepted")
+}
+
+// TestIsOpPermittedWithTokenRoles ensures that users authenticated by an
+// external issuer are granted the permissions of the roles of their token.
+func TestIsOpPermittedWithTokenRoles(t *testing.T) {
+	as, tearDown := setupAuthStore(t)
+	defer tearDown(t)
+
+	perm := &authpb.Permission{PermType: authpb.READWRITE, Key: []byte("foo"), RangeEnd: []byte("fop")}
+	_, err := as.RoleGrantPermission(&pb.AuthRoleGrantPermissionRequest{Name: "role-test", Perm: perm})
+	require.NoError(t, err)
+
+	ai := &AuthInfo{Username: "oidc:alice", Revision: as.Revision()}
+	assert.Equal(t, ErrPermissionDenied, as.IsPutPermitted(ai, []byte("foo1")))
+	ai.Roles = []string{"unknown", "role-test"}
+	assert.NoError(t, as.IsPutPermitted(ai, []byte("foo1")))
+	assert.NoError(t, as.IsRangePermitted(ai, []byte("foo"), []byte("fop")))
+	assert.Equal(t, ErrPermissionDenied, as.IsPutPermitted(ai, []byte("bar")))
+	assert.Equal(t, ErrUserNotFound, as.IsAdminPermitted(ai))
+
+	ai.Roles = []string{"root"}
+	assert.NoError(t, as.IsPutPermitted(ai, []byte("bar")))
+	assert.NoError(t, as.IsAdminPermitted(ai))
+}
+
+func testOIDCToken(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	tk := jwt.NewWithClaims(method, claims)
+	tk.Header["kid"] = kid
+	s, err := tk.SignedString(key)
+	require.NoError(t, err)
+	return s
+}
+
+func testJWKS(t *testing.T, keys map[string]interface{}) []byte {
+	enc := base64.RawURLEncoding.EncodeToString
+	var set struct {
+		Keys []map[string]string `json:"keys"`
+	}
+	for kid, key := range keys {
+		switch k := key.(type) {
+		case *ecdsa.PrivateKey:
+			set.Keys = append(set.Keys, map[string]string{"kid": kid, "kty": "EC", "crv": "P-256", "x": enc(k.X.Bytes()), "y": enc(k.Y.Bytes())})
+		case *rsa.PrivateKey:
+			set.Keys = append(set.Keys, map[string]string{"kid": kid, "kty": "RSA", "n": enc(k.N.Bytes()), "e": enc(big.NewInt(int64(k.E)).Bytes())})
+		case ed25519.PrivateKey:
+			set.Keys = append(set.Keys, map[string]string{"kid": kid, "kty": "OKP", "crv": "Ed25519", "x": enc(k.Public().(ed25519.PublicKey))})
+		}
+	}
+	b, err := json.Marshal(set)
+	require.NoError(t, err)
+	return b
+}
@@ -0,0 +1,214 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+
+	"go.etcd.io/etcd/api/v3/authpb"
+)
+
+const (
+	passwordHashBcrypt   = "bcrypt"
+	passwordHashArgon2id = "argon2id"
+
+	optArgon2idTime    = "time"
+	optArgon2idMemory  = "memory"
+	optArgon2idThreads = "threads"
+
+	argon2idSaltLen = 16
+	argon2idKeyLen  = 32
+)
+
+// DefaultArgon2idParams are the parameters recommended by golang.org/x/crypto/argon2.
+var DefaultArgon2idParams = Argon2idParams{Time: 1, Memory: 64 * 1024, Threads: 4}
+
+// PasswordHasher hashes the passwords of users. The hashes of passwords
+// hashed with another algorithm or other parameters are replaced when their
+// users authenticate.
+type PasswordHasher interface {
+	// Algorithm returns the algorithm of the hashes.
+	Algorithm() authpb.PasswordAlgorithm
+	// Hash returns the hash of the password.
+	Hash(password string) ([]byte, error)
+	// Current returns true if the hash, of the hasher's algorithm, was
+	// hashed with the hasher's parameters.
+	Current(hash []byte) bool
+}
+
+// NewPasswordHasher creates the password hasher of the given options, e.g.
+// 'bcrypt' or 'argon2id,time=1,memory=65536,threads=4'. Bcrypt hashes with
+// the given cost.
+func NewPasswordHasher(lg *zap.Logger, opts string, bcryptCost int) (PasswordHasher, error) {
+	typ, optMap, err := decomposeOpts(lg, opts)
+	if err != nil {
+		return nil, err
+	}
+	switch typ {
+	case "", passwordHashBcrypt:
+		if len(optMap) != 0 {
+			return nil, ErrInvalidAuthOpts
+		}
+		if bcryptCost < bcrypt.MinCost || bcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("invalid bcrypt cost %d", bcryptCost)
+		}
+		return NewBcryptHasher(bcryptCost), nil
+	case passwordHashArgon2id:
+		p := DefaultArgon2idParams
+		for k, v := range optMap {
+			n, err := strconv.ParseUint(v, 10, 32)
+			if err != nil || n == 0 {
+				return nil, fmt.Errorf("invalid argon2id %s %q", k, v)
+			}
+			switch k {
+			case optArgon2idTime:
+				p.Time = uint32(n)
+			case optArgon2idMemory:
+				p.Memory = uint32(n)
+			case optArgon2idThreads:
+				if n > 255 {
+					return nil, fmt.Errorf("invalid argon2id %s %q", k, v)
+				}
+				p.Threads = uint8(n)
+			default:
+				return nil, fmt.Errorf("unknown argon2id option %q", k)
+			}
+		}
+		return NewArgon2idHasher(p), nil
+	default:
+		return nil, fmt.Errorf("unknown password hash %q", typ)
+	}
+}
+
+// comparePassword returns nil if the password matches the hash, whatever the
+// parameters it was hashed with.
+func comparePassword(alg authpb.PasswordAlgorithm, hash []byte, password string) error {
+	switch alg {
+	case authpb.BCRYPT:
+		return bcrypt.CompareHashAndPassword(hash, []byte(password))
+	case authpb.ARGON2ID:
+		p, salt, key, err := decodeArgon2id(hash)
+		if err != nil {
+			return err
+		}
+		if subtle.ConstantTimeCompare(key, p.key([]byte(password), salt, uint32(len(key)))) != 1 {
+			return ErrAuthFailed
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown password algorithm %v", alg)
+	}
+}
+
+type bcryptHasher struct {
+	cost int
+}
+
+// NewBcryptHasher creates a password hasher of the bcrypt algorithm.
+func NewBcryptHasher(cost int) PasswordHasher {
+	return &bcryptHasher{cost: cost}
+}
+
+func (h *bcryptHasher) Algorithm() authpb.PasswordAlgorithm { return authpb.BCRYPT }
+
+func (h *bcryptHasher) Hash(password string) ([]byte, error) {
+	return bcrypt.GenerateFromPassword([]byte(password), h.cost)
+}
+
+func (h *bcryptHasher) Current(hash []byte) bool {
+	cost, err := bcrypt.Cost(hash)
+	return err == nil && cost == h.cost
+}
+
+// Argon2idParams are the parameters of the argon2id algorithm.
+type Argon2idParams struct {
+	// Time is the number of passes over the memory.
+	Time uint32
+	// Memory is the size of the memory in KiB.
+	Memory uint32
+	// Threads is the number of threads.
+	Threads uint8
+}
+
+func (p Argon2idParams) key(password, salt []byte, keyLen uint32) []byte {
+	return argon2.IDKey(password, salt, p.Time, p.Memory, p.Threads, keyLen)
+}
+
+type argon2idHasher struct {
+	params Argon2idParams
+}
+
+// NewArgon2idHasher creates a password hasher of the argon2id algorithm.
+func NewArgon2idHasher(p Argon2idParams) PasswordHasher {
+	return &argon2idHasher{params: p}
+}
+
+func (h *argon2idHasher) Algorithm() authpb.PasswordAlgorithm { return authpb.ARGON2ID }
+
+// Hash returns the hash of the password in the PHC string format, e.g.
+// '$argon2id$v=19$m=65536,t=1,p=4$<salt>$<key>'.
+func (h *argon2idHasher) Hash(password string) ([]byte, error) {
+	salt := make([]byte, argon2idSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	key := h.params.key([]byte(password), salt, argon2idKeyLen)
+	return []byte(fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.params.Memory, h.params.Time, h.params.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)), nil
+}
+
+func (h *argon2idHasher) Current(hash []byte) bool {
+	p, _, key, err := decodeArgon2id(hash)
+	return err == nil && p == h.params && len(key) == argon2idKeyLen
+}
+
+func decodeArgon2id(hash []byte) (p Argon2idParams, salt, key []byte, err error) {
+	fields := bytes.Split(hash, []byte("$"))
+	if len(fields) != 6 || len(fields[0]) != 0 || string(fields[1]) != passwordHashArgon2id {
+		return p, nil, nil, fmt.Errorf("invalid argon2id hash")
+	}
+	var version int
+	if _, err = fmt.Sscanf(string(fields[2]), "v=%d", &version); err != nil {
+		return p, nil, nil, fmt.Errorf("invalid argon2id hash version: %w", err)
+	}
+	if version != argon2.Version {
+		return p, nil, nil, fmt.Errorf("unsupported argon2id version %d", version)
+	}
+	if _, err = fmt.Sscanf(string(fields[3]), "m=%d,t=%d,p=%d", &p.Memory, &p.Time, &p.Threads); err != nil {
+		return p, nil, nil, fmt.Errorf("invalid argon2id hash parameters: %w", err)
+	}
+	if salt, err = base64.RawStdEncoding.DecodeString(string(fields[4])); err != nil {
+		return p, nil, nil, fmt.Errorf("invalid argon2id hash salt: %w", err)
+	}
+	if key, err = base64.RawStdEncoding.DecodeString(string(fields[5])); err != nil {
+		return p, nil, nil, fmt.Errorf("invalid argon2id hash key: %w", err)
+	}
+	if len(key) == 0 || p.Time == 0 || p.Threads == 0 {
+		return p, nil, nil, fmt.Errorf("invalid argon2id hash parameters")
+	}
+	return p, salt, key, nil
+}
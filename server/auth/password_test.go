@@ -0,0 +1,139 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+	"golang.org/x/crypto/bcrypt"
+
+	"go.etcd.io/etcd/api/v3/authpb"
+)
+
+var testArgon2idParams = Argon2idParams{Time: 1, Memory: 1024, Threads: 1}
+
+func TestPasswordHasher(t *testing.T) {
+	tests := []struct {
+		name   string
+		hasher PasswordHasher
+		other  PasswordHasher
+	}{
+		{"bcrypt", NewBcryptHasher(bcrypt.MinCost), NewBcryptHasher(bcrypt.MinCost + 1)},
+		{"argon2id", NewArgon2idHasher(testArgon2idParams), NewArgon2idHasher(Argon2idParams{Time: 2, Memory: 1024, Threads: 1})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := tt.hasher.Hash("pass")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = comparePassword(tt.hasher.Algorithm(), hash, "pass"); err != nil {
+				t.Fatalf("expected password to match, got %v", err)
+			}
+			if err = comparePassword(tt.hasher.Algorithm(), hash, "wrong"); err == nil {
+				t.Fatal("expected wrong password not to match")
+			}
+			if !tt.hasher.Current(hash) {
+				t.Fatal("expected hash to be current")
+			}
+			// hashes of other parameters still match, but are not current
+			if tt.other.Current(hash) {
+				t.Fatal("expected hash of other parameters not to be current")
+			}
+			if err = comparePassword(tt.other.Algorithm(), hash, "pass"); err != nil {
+				t.Fatalf("expected password to match, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNewPasswordHasher(t *testing.T) {
+	tests := []struct {
+		opts    string
+		alg     authpb.PasswordAlgorithm
+		params  Argon2idParams
+		wantErr bool
+	}{
+		{opts: "", alg: authpb.BCRYPT},
+		{opts: "bcrypt", alg: authpb.BCRYPT},
+		{opts: "argon2id", alg: authpb.ARGON2ID, params: DefaultArgon2idParams},
+		{opts: "argon2id,time=3,memory=1024,threads=2", alg: authpb.ARGON2ID, params: Argon2idParams{Time: 3, Memory: 1024, Threads: 2}},
+		{opts: "bcrypt,cost=10", wantErr: true},
+		{opts: "argon2id,time=0", wantErr: true},
+		{opts: "argon2id,threads=256", wantErr: true},
+		{opts: "argon2id,salt=8", wantErr: true},
+		{opts: "argon2id,time", wantErr: true},
+		{opts: "scrypt", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.opts, func(t *testing.T) {
+			h, err := NewPasswordHasher(zaptest.NewLogger(t), tt.opts, bcrypt.MinCost)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if h.Algorithm() != tt.alg {
+				t.Fatalf("expected algorithm %v, got %v", tt.alg, h.Algorithm())
+			}
+			if a, ok := h.(*argon2idHasher); ok && a.params != tt.params {
+				t.Fatalf("expected parameters %+v, got %+v", tt.params, a.params)
+			}
+		})
+	}
+}
+
+func TestRehashPassword(t *testing.T) {
+	as, tearDown := setupAuthStore(t)
+	defer tearDown(t)
+
+	if as.PasswordNeedsRehash("foo") {
+		t.Fatal("expected bcrypt password not to need rehash")
+	}
+	as.SetPasswordHasher(NewArgon2idHasher(testArgon2idParams))
+	if !as.PasswordNeedsRehash("foo") {
+		t.Fatal("expected bcrypt password to need rehash")
+	}
+
+	rev, err := as.CheckPassword("foo", "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, alg, err := as.HashPassword("bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the password checked at an old revision may have changed since
+	as.RehashPassword("foo", hash, alg, rev-1)
+	if !as.PasswordNeedsRehash("foo") {
+		t.Fatal("expected password checked at an old revision not to be rehashed")
+	}
+
+	as.RehashPassword("foo", hash, alg, rev)
+	if as.PasswordNeedsRehash("foo") {
+		t.Fatal("expected password to be rehashed")
+	}
+	if as.Revision() != rev {
+		t.Fatalf("expected revision %d, got %d", rev, as.Revision())
+	}
+	if _, err = as.CheckPassword("foo", "bar"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = as.CheckPassword("foo", "baz"); err != ErrAuthFailed {
+		t.Fatalf("expected %v, got %v", ErrAuthFailed, err)
+	}
+}
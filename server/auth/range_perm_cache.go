@@ -15,9 +15,12 @@
 package auth
 
 import (
+	"sort"
+
 	"go.uber.org/zap"
 
 	"go.etcd.io/etcd/api/v3/authpb"
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/pkg/v3/adt"
 )
 
@@ -26,48 +29,84 @@ func getMergedPerms(tx UnsafeAuthReader, userName string) *unifiedRangePermissio
 	if user == nil {
 		return nil
 	}
+	// the roles granted with a ttl are checked on their own, see isOpPermitted
+	var roles []string
+	for _, role := range user.Roles {
+		if roleExpiry(user.RoleExpiries, role) == 0 {
+			roles = append(roles, role)
+		}
+	}
+	return getRolesPerms(tx, roles)
+}
 
-	readPerms := adt.NewIntervalTree()
-	writePerms := adt.NewIntervalTree()
+func getRolesPerms(tx UnsafeAuthReader, roleNames []string) *unifiedRangePermissions {
+	perms := &unifiedRangePermissions{
+		readPerms:   adt.NewIntervalTree(),
+		writePerms:  adt.NewIntervalTree(),
+		createPerms: adt.NewIntervalTree(),
+		deletePerms: adt.NewIntervalTree(),
+		watchPerms:  adt.NewIntervalTree(),
+		leasePerms:  adt.NewIntervalTree(),
+	}
 
-	for _, roleName := range user.Roles {
+	for _, roleName := range roleNames {
 		role := tx.UnsafeGetRole(roleName)
 		if role == nil {
 			continue
 		}
 
 		for _, perm := range role.KeyPermission {
-			var ivl adt.Interval
-			var rangeEnd []byte
-
-			if len(perm.RangeEnd) != 1 || perm.RangeEnd[0] != 0 {
-				rangeEnd = perm.RangeEnd
-			}
-
-			if len(perm.RangeEnd) != 0 {
-				ivl = adt.NewBytesAffineInterval(perm.Key, rangeEnd)
-			} else {
-				ivl = adt.NewBytesAffinePoint(perm.Key)
+			ivl := permInterval(perm)
+			for _, typ := range impliedPermTypes(perm.PermType) {
+				perms.tree(typ).Insert(ivl, struct{}{})
 			}
+		}
+	}
 
-			switch perm.PermType {
-			case authpb.READWRITE:
-				readPerms.Insert(ivl, struct{}{})
-				writePerms.Insert(ivl, struct{}{})
+	return perms
+}
 
-			case authpb.READ:
-				readPerms.Insert(ivl, struct{}{})
+// permInterval returns the interval of the keys of a permission.
+func permInterval(perm *authpb.Permission) adt.Interval {
+	if len(perm.RangeEnd) == 0 {
+		return adt.NewBytesAffinePoint(perm.Key)
+	}
+	var rangeEnd []byte
+	if len(perm.RangeEnd) != 1 || perm.RangeEnd[0] != 0 {
+		rangeEnd = perm.RangeEnd
+	}
+	return adt.NewBytesAffineInterval(perm.Key, rangeEnd)
+}
 
-			case authpb.WRITE:
-				writePerms.Insert(ivl, struct{}{})
-			}
-		}
+// permMatches returns whether the permission grants the permission type on a
+// part of the range.
+func permMatches(perm *authpb.Permission, typ authpb.Permission_Type, key, rangeEnd []byte) bool {
+	implied := false
+	for _, t := range impliedPermTypes(perm.PermType) {
+		implied = implied || t == typ
+	}
+	if !implied {
+		return false
 	}
+	tree := adt.NewIntervalTree()
+	tree.Insert(permInterval(perm), struct{}{})
+	return tree.Intersects(permInterval(&authpb.Permission{Key: key, RangeEnd: rangeEnd}))
+}
 
-	return &unifiedRangePermissions{
-		readPerms:  readPerms,
-		writePerms: writePerms,
+// impliedPermTypes returns the permission types granted by a permission: READ
+// implies WATCH, and WRITE implies CREATE, DELETE and LEASE.
+func impliedPermTypes(typ authpb.Permission_Type) []authpb.Permission_Type {
+	switch typ {
+	case authpb.READWRITE:
+		return []authpb.Permission_Type{authpb.READ, authpb.WATCH, authpb.WRITE, authpb.CREATE, authpb.DELETE, authpb.LEASE}
+	case authpb.READ:
+		return []authpb.Permission_Type{authpb.READ, authpb.WATCH}
+	case authpb.WRITE:
+		return []authpb.Permission_Type{authpb.WRITE, authpb.CREATE, authpb.DELETE, authpb.LEASE}
+	case authpb.CREATE, authpb.DELETE, authpb.WATCH, authpb.LEASE:
+		return []authpb.Permission_Type{typ}
 	}
+	return nil
 }
 
 func checkKeyInterval(
@@ -82,28 +121,20 @@ func checkKeyInterval(
 	}
 
 	ivl := adt.NewBytesAffineInterval(key, rangeEnd)
-	switch permtyp {
-	case authpb.READ:
-		return cachedPerms.readPerms.Contains(ivl)
-	case authpb.WRITE:
-		return cachedPerms.writePerms.Contains(ivl)
-	default:
+	perms := cachedPerms.tree(permtyp)
+	if perms == nil {
 		lg.Panic("unknown auth type", zap.String("auth-type", permtyp.String()))
 	}
-	return false
+	return perms.Contains(ivl)
 }
 
 func checkKeyPoint(lg *zap.Logger, cachedPerms *unifiedRangePermissions, key []byte, permtyp authpb.Permission_Type) bool {
 	pt := adt.NewBytesAffinePoint(key)
-	switch permtyp {
-	case authpb.READ:
-		return cachedPerms.readPerms.Intersects(pt)
-	case authpb.WRITE:
-		return cachedPerms.writePerms.Intersects(pt)
-	default:
+	perms := cachedPerms.tree(permtyp)
+	if perms == nil {
 		lg.Panic("unknown auth type", zap.String("auth-type", permtyp.String()))
 	}
-	return false
+	return perms.Intersects(pt)
 }
 
 func (as *authStore) isRangeOpPermitted(userName string, key, rangeEnd []byte, permtyp authpb.Permission_Type) bool {
@@ -120,11 +151,30 @@ func (as *authStore) isRangeOpPermitted(userName string, key, rangeEnd []byte, p
 		return false
 	}
 
+	return checkRangePerm(as.lg, rangePerm, key, rangeEnd, permtyp)
+}
+
+// isRoleRangeOpPermitted returns whether any of the roles, granted to a user
+// by the issuer of its token, permits the operation.
+func (as *authStore) isRoleRangeOpPermitted(roles []string, key, rangeEnd []byte, permtyp authpb.Permission_Type) bool {
+	as.rangePermCacheMu.RLock()
+	defer as.rangePermCacheMu.RUnlock()
+
+	for _, role := range roles {
+		rangePerm, ok := as.roleRangePermCache[role]
+		if ok && checkRangePerm(as.lg, rangePerm, key, rangeEnd, permtyp) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkRangePerm(lg *zap.Logger, rangePerm *unifiedRangePermissions, key, rangeEnd []byte, permtyp authpb.Permission_Type) bool {
 	if len(rangeEnd) == 0 {
-		return checkKeyPoint(as.lg, rangePerm, key, permtyp)
+		return checkKeyPoint(lg, rangePerm, key, permtyp)
 	}
 
-	return checkKeyInterval(as.lg, rangePerm, key, rangeEnd, permtyp)
+	return checkKeyInterval(lg, rangePerm, key, rangeEnd, permtyp)
 }
 
 func (as *authStore) refreshRangePermCache(tx UnsafeAuthReader) {
@@ -137,10 +187,14 @@ func (as *authStore) refreshRangePermCache(tx UnsafeAuthReader) {
 	as.lg.Debug("Refreshing rangePermCache")
 
 	as.rangePermCache = make(map[string]*unifiedRangePermissions)
+	as.roleGrantExpiries = nil
 
 	users := tx.UnsafeGetAllUsers()
 	for _, user := range users {
 		userName := string(user.Name)
+		for _, re := range user.RoleExpiries {
+			as.roleGrantExpiries = append(as.roleGrantExpiries, &pb.ExpiredRoleGrant{User: userName, Role: re.Role, ExpireTime: re.ExpireTime})
+		}
 		perms := getMergedPerms(tx, userName)
 		if perms == nil {
 			as.lg.Error(
@@ -151,11 +205,45 @@ func (as *authStore) refreshRangePermCache(tx UnsafeAuthReader) {
 		}
 		as.rangePermCache[userName] = perms
 	}
+
+	as.roleRangePermCache = make(map[string]*unifiedRangePermissions)
+	roles := tx.UnsafeGetAllRoles()
+	for _, role := range roles {
+		roleName := string(role.Name)
+		as.roleRangePermCache[roleName] = getRolesPerms(tx, []string{roleName})
+	}
+	as.refreshLimitsCache(users, roles)
+	sort.Slice(as.roleGrantExpiries, func(i, j int) bool {
+		return as.roleGrantExpiries[i].ExpireTime < as.roleGrantExpiries[j].ExpireTime
+	})
 }
 
 type unifiedRangePermissions struct {
-	readPerms  adt.IntervalTree
-	writePerms adt.IntervalTree
+	readPerms   adt.IntervalTree
+	writePerms  adt.IntervalTree
+	createPerms adt.IntervalTree
+	deletePerms adt.IntervalTree
+	watchPerms  adt.IntervalTree
+	leasePerms  adt.IntervalTree
+}
+
+// tree returns the ranges permitted for the operations of the permission type.
+func (p *unifiedRangePermissions) tree(typ authpb.Permission_Type) adt.IntervalTree {
+	switch typ {
+	case authpb.READ:
+		return p.readPerms
+	case authpb.WRITE:
+		return p.writePerms
+	case authpb.CREATE:
+		return p.createPerms
+	case authpb.DELETE:
+		return p.deletePerms
+	case authpb.WATCH:
+		return p.watchPerms
+	case authpb.LEASE:
+		return p.leasePerms
+	}
+	return nil
 }
 
 // Constraints related to key range
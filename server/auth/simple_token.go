@@ -23,12 +23,15 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"go.uber.org/zap"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 )
 
 const (
@@ -103,7 +106,7 @@ type tokenSimple struct {
 	indexWaiter       func(uint64) <-chan struct{}
 	simpleTokenKeeper *simpleTokenTTLKeeper
 	simpleTokensMu    sync.Mutex
-	simpleTokens      map[string]string // token -> username
+	simpleTokens      map[string]*pb.AuthToken
 	simpleTokenTTL    time.Duration
 }
 
@@ -122,7 +125,7 @@ func (t *tokenSimple) genTokenPrefix() (string, error) {
 	return string(ret), nil
 }
 
-func (t *tokenSimple) assignSimpleTokenToUser(username, token string) {
+func (t *tokenSimple) assignSimpleTokenToUser(tk *pb.AuthToken, token string) {
 	t.simpleTokensMu.Lock()
 	defer t.simpleTokensMu.Unlock()
 	if t.simpleTokenKeeper == nil {
@@ -133,12 +136,12 @@ func (t *tokenSimple) assignSimpleTokenToUser(username, token string) {
 	if ok {
 		t.lg.Panic(
 			"failed to assign already-used simple token to a user",
-			zap.String("user-name", username),
+			zap.String("user-name", tk.Name),
 			zap.String("token", token),
 		)
 	}
 
-	t.simpleTokens[token] = username
+	t.simpleTokens[token] = tk
 	t.simpleTokenKeeper.addSimpleToken(token)
 }
 
@@ -147,8 +150,8 @@ func (t *tokenSimple) invalidateUser(username string) {
 		return
 	}
 	t.simpleTokensMu.Lock()
-	for token, name := range t.simpleTokens {
-		if name == username {
+	for token, tk := range t.simpleTokens {
+		if tk.Name == username {
 			delete(t.simpleTokens, token)
 			t.simpleTokenKeeper.deleteSimpleToken(token)
 		}
@@ -156,6 +159,35 @@ func (t *tokenSimple) invalidateUser(username string) {
 	t.simpleTokensMu.Unlock()
 }
 
+func (t *tokenSimple) tokens(username string) []*pb.AuthToken {
+	t.simpleTokensMu.Lock()
+	defer t.simpleTokensMu.Unlock()
+	var tks []*pb.AuthToken
+	for _, tk := range t.simpleTokens {
+		if username == "" || tk.Name == username {
+			tks = append(tks, tk)
+		}
+	}
+	sort.Slice(tks, func(i, j int) bool { return tks[i].ID < tks[j].ID })
+	return tks
+}
+
+func (t *tokenSimple) revoke(id uint64) bool {
+	t.simpleTokensMu.Lock()
+	defer t.simpleTokensMu.Unlock()
+	if t.simpleTokenKeeper == nil {
+		return false
+	}
+	for token, tk := range t.simpleTokens {
+		if tk.ID == id {
+			delete(t.simpleTokens, token)
+			t.simpleTokenKeeper.deleteSimpleToken(token)
+			return true
+		}
+	}
+	return false
+}
+
 func (t *tokenSimple) enable() {
 	t.simpleTokensMu.Lock()
 	defer t.simpleTokensMu.Unlock()
@@ -167,10 +199,10 @@ func (t *tokenSimple) enable() {
 	}
 
 	delf := func(tk string) {
-		if username, ok := t.simpleTokens[tk]; ok {
+		if stk, ok := t.simpleTokens[tk]; ok {
 			t.lg.Info(
 				"deleted a simple token",
-				zap.String("user-name", username),
+				zap.String("user-name", stk.Name),
 				zap.String("token", tk),
 			)
 			delete(t.simpleTokens, tk)
@@ -191,7 +223,7 @@ func (t *tokenSimple) disable() {
 	t.simpleTokensMu.Lock()
 	tk := t.simpleTokenKeeper
 	t.simpleTokenKeeper = nil
-	t.simpleTokens = make(map[string]string) // invalidate all tokens
+	t.simpleTokens = make(map[string]*pb.AuthToken) // invalidate all tokens
 	t.simpleTokensMu.Unlock()
 	if tk != nil {
 		tk.stop()
@@ -203,12 +235,15 @@ func (t *tokenSimple) info(ctx context.Context, token string, revision uint64) (
 		return nil, false
 	}
 	t.simpleTokensMu.Lock()
-	username, ok := t.simpleTokens[token]
+	tk, ok := t.simpleTokens[token]
 	if ok && t.simpleTokenKeeper != nil {
 		t.simpleTokenKeeper.resetSimpleToken(token)
 	}
 	t.simpleTokensMu.Unlock()
-	return &AuthInfo{Username: username, Revision: revision}, ok
+	if !ok {
+		return &AuthInfo{Revision: revision}, false
+	}
+	return &AuthInfo{Username: tk.Name, Revision: revision, Scope: tk.Scope}, true
 }
 
 func (t *tokenSimple) assign(ctx context.Context, username string, rev uint64) (string, error) {
@@ -220,7 +255,12 @@ func (t *tokenSimple) assign(ctx context.Context, username string, rev uint64) (
 	}
 	simpleTokenPrefix := ctx.Value(AuthenticateParamSimpleTokenPrefix{}).(string)
 	token := fmt.Sprintf("%s.%d", simpleTokenPrefix, index)
-	t.assignSimpleTokenToUser(username, token)
+	// the index the token is assigned at identifies it without revealing it
+	tk := &pb.AuthToken{ID: index, Name: username}
+	if p, ok := ctx.Value(AuthenticateParamToken{}).(*pb.AuthToken); ok {
+		tk.Created, tk.Address, tk.Scope = p.Created, p.Address, p.Scope
+	}
+	t.assignSimpleTokenToUser(tk, token)
 
 	return token, nil
 }
@@ -250,7 +290,7 @@ func newTokenProviderSimple(lg *zap.Logger, indexWaiter func(uint64) <-chan stru
 	}
 	return &tokenSimple{
 		lg:             lg,
-		simpleTokens:   make(map[string]string),
+		simpleTokens:   make(map[string]*pb.AuthToken),
 		indexWaiter:    indexWaiter,
 		simpleTokenTTL: TokenTTL,
 	}
@@ -19,6 +19,8 @@ import (
 	"testing"
 
 	"go.uber.org/zap/zaptest"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 )
 
 // TestSimpleTokenDisabled ensures that TokenProviderSimple behaves correctly when
@@ -68,3 +70,47 @@ func TestSimpleTokenAssign(t *testing.T) {
 		t.Errorf("expected ok == false after user is invalidated")
 	}
 }
+
+// TestSimpleTokenListRevoke ensures that TokenProviderSimple lists the tokens
+// of a user along with their details, and revokes a single token.
+func TestSimpleTokenListRevoke(t *testing.T) {
+	tp := newTokenProviderSimple(zaptest.NewLogger(t), dummyIndexWaiter, simpleTokenTTLDefault)
+	tp.enable()
+	defer tp.disable()
+
+	var tokens []string
+	for i, name := range []string{"user1", "user2", "user1"} {
+		ctx := context.WithValue(context.TODO(), AuthenticateParamIndex{}, uint64(i+1))
+		ctx = context.WithValue(ctx, AuthenticateParamSimpleTokenPrefix{}, "dummy")
+		ctx = context.WithValue(ctx, AuthenticateParamToken{}, &pb.AuthToken{Created: int64(i), Address: "127.0.0.1:2379"})
+		token, err := tp.assign(ctx, name, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tokens = append(tokens, token)
+	}
+
+	tks := tp.tokens("user1")
+	if len(tks) != 2 || tks[0].ID != 1 || tks[1].ID != 3 {
+		t.Fatalf("expected tokens 1 and 3 of user1, got %+v", tks)
+	}
+	if tks[1].Name != "user1" || tks[1].Created != 2 || tks[1].Address != "127.0.0.1:2379" {
+		t.Errorf("unexpected token details %+v", tks[1])
+	}
+	if tks = tp.tokens(""); len(tks) != 3 {
+		t.Errorf("expected 3 tokens, got %+v", tks)
+	}
+
+	if !tp.revoke(3) {
+		t.Fatal("expected token 3 to be revoked")
+	}
+	if tp.revoke(3) {
+		t.Error("expected revoked token 3 not to be found")
+	}
+	if _, ok := tp.info(context.TODO(), tokens[2], 0); ok {
+		t.Error("expected revoked token to be invalid")
+	}
+	if _, ok := tp.info(context.TODO(), tokens[0], 0); !ok {
+		t.Error("expected other tokens of the user to stay valid")
+	}
+}
@@ -65,6 +65,9 @@ var (
 	ErrMissingKey           = errors.New("auth: missing key data")
 	ErrKeyMismatch          = errors.New("auth: public and private keys don't match")
 	ErrVerifyOnly           = errors.New("auth: token signing attempted with verify-only key")
+	ErrLimitExceeded        = errors.New("auth: user limit exceeded")
+	ErrTokenNotFound        = errors.New("auth: token not found")
+	ErrInvalidTokenScope    = errors.New("auth: invalid token scope")
 )
 
 const (
@@ -73,11 +76,34 @@ const (
 
 	tokenTypeSimple = "simple"
 	tokenTypeJWT    = "jwt"
+	tokenTypeOIDC   = "oidc"
 )
 
 type AuthInfo struct {
 	Username string
 	Revision uint64
+	// Roles are the roles granted to the user by the issuer of its token, in
+	// addition to the roles of the user in etcd.
+	Roles []string
+	// Scope restricts the roles and keys the token of the user gives access to.
+	Scope *authpb.TokenScope
+	// Time is the unix time, in seconds, at which the roles granted with a ttl
+	// are checked. Zero is the current time, and TimeIgnoreExpiry keeps them
+	// granted until they are revoked.
+	Time int64
+}
+
+// TimeIgnoreExpiry is the Time of an AuthInfo whose request has no time of its
+// own, and must not be checked against the clock of the member, such as when
+// applying requests proposed by members not recording it.
+const TimeIgnoreExpiry int64 = -1
+
+// State is a consistent view of the users and roles of an auth store.
+type State struct {
+	Enabled  bool
+	Revision uint64
+	Users    []*authpb.User
+	Roles    []*authpb.Role
 }
 
 // AuthenticateParamIndex is used for a key of context in the parameters of Authenticate()
@@ -86,6 +112,20 @@ type AuthenticateParamIndex struct{}
 // AuthenticateParamSimpleTokenPrefix is used for a key of context in the parameters of Authenticate()
 type AuthenticateParamSimpleTokenPrefix struct{}
 
+// authInfoKey is the key of context of the AuthInfo resolved for a request.
+type authInfoKey struct{}
+
+// WithAuthInfo returns a copy of ctx carrying the AuthInfo already resolved
+// from the credentials of its request, so that AuthInfoFromCtx returns it
+// without resolving them again.
+func WithAuthInfo(ctx context.Context, authInfo *AuthInfo) context.Context {
+	return context.WithValue(ctx, authInfoKey{}, authInfo)
+}
+
+// AuthenticateParamToken is used for a key of context in the parameters of Authenticate().
+// Its value is a *pb.AuthToken carrying the creation time, source address and scope of the token.
+type AuthenticateParamToken struct{}
+
 // AuthStore defines auth storage interface.
 type AuthStore interface {
 	// AuthEnable turns on the authentication feature
@@ -115,6 +155,19 @@ type AuthStore interface {
 	// UserGrantRole grants a role to the user
 	UserGrantRole(r *pb.AuthUserGrantRoleRequest) (*pb.AuthUserGrantRoleResponse, error)
 
+	// UserGrantRoleUntil grants a role to the user until the unix expire time,
+	// or without expiry if it is zero
+	UserGrantRoleUntil(r *pb.AuthUserGrantRoleRequest, expireTime int64) (*pb.AuthUserGrantRoleResponse, error)
+
+	// ExpiredRoleGrants returns at most limit role grants expired at the unix time
+	ExpiredRoleGrants(now int64, limit int) []*pb.ExpiredRoleGrant
+
+	// Explain explains which roles of a user permit a hypothetical request
+	Explain(r *pb.AuthExplainRequest) (*pb.AuthExplainResponse, error)
+
+	// RevokeExpiredRoleGrants revokes the expired role grants
+	RevokeExpiredRoleGrants(r *pb.AuthRoleGrantExpireRequest) (*pb.AuthUserRevokeRoleResponse, error)
+
 	// UserGet gets the detailed information of a users
 	UserGet(r *pb.AuthUserGetRequest) (*pb.AuthUserGetResponse, error)
 
@@ -142,9 +195,30 @@ type AuthStore interface {
 	// RoleList gets a list of all roles
 	RoleList(r *pb.AuthRoleListRequest) (*pb.AuthRoleListResponse, error)
 
+	// UserSetLimits sets the limits of a user
+	UserSetLimits(r *pb.AuthUserSetLimitsRequest) (*pb.AuthUserSetLimitsResponse, error)
+
+	// RoleSetLimits sets the limits of the users granted a role
+	RoleSetLimits(r *pb.AuthRoleSetLimitsRequest) (*pb.AuthRoleSetLimitsResponse, error)
+
+	// Limits returns the limits applying to the user, or nil if there are none
+	Limits(authInfo *AuthInfo) *authpb.Limits
+
+	// Permissions returns the key permissions granted to the user by its roles
+	Permissions(authInfo *AuthInfo) []*authpb.Permission
+
 	// IsPutPermitted checks put permission of the user
 	IsPutPermitted(authInfo *AuthInfo, key []byte) error
 
+	// IsCreatePermitted checks permission of the user to put a key not existing yet
+	IsCreatePermitted(authInfo *AuthInfo, key []byte) error
+
+	// IsWatchPermitted checks watch permission of the user
+	IsWatchPermitted(authInfo *AuthInfo, key, rangeEnd []byte) error
+
+	// IsLeasePermitted checks permission of the user to attach a key to a lease
+	IsLeasePermitted(authInfo *AuthInfo, key []byte) error
+
 	// IsRangePermitted checks range permission of the user
 	IsRangePermitted(authInfo *AuthInfo, key, rangeEnd []byte) error
 
@@ -170,8 +244,9 @@ type AuthStore interface {
 	// AuthInfoFromCtx gets AuthInfo from gRPC's context
 	AuthInfoFromCtx(ctx context.Context) (*AuthInfo, error)
 
-	// AuthInfoFromTLS gets AuthInfo from TLS info of gRPC's context
-	AuthInfoFromTLS(ctx context.Context) *AuthInfo
+	// AuthInfoFromTLS gets AuthInfo from TLS info of gRPC's context. The user
+	// is the CommonName of the client certificate, unless identified by ci.
+	AuthInfoFromTLS(ctx context.Context, ci *CertIdentity) *AuthInfo
 
 	// WithRoot generates and installs a token that can be used as a root credential
 	WithRoot(ctx context.Context) context.Context
@@ -181,6 +256,29 @@ type AuthStore interface {
 
 	// BcryptCost gets strength of hashing bcrypted auth password
 	BcryptCost() int
+
+	// HashPassword hashes a password with the current algorithm and parameters
+	HashPassword(password string) ([]byte, authpb.PasswordAlgorithm, error)
+
+	// PasswordNeedsRehash returns true if the password of the user was hashed
+	// with another algorithm or other parameters than the current ones
+	PasswordNeedsRehash(username string) bool
+
+	// RehashPassword replaces the hash of the password of the user, if the auth
+	// revision is still the one the password was checked at
+	RehashPassword(username string, hash []byte, alg authpb.PasswordAlgorithm, revision uint64)
+
+	// TokenList lists the active tokens of a user, or of all users if no user is given
+	TokenList(r *pb.AuthTokenListRequest) (*pb.AuthTokenListResponse, error)
+
+	// TokenRevoke revokes an active token
+	TokenRevoke(r *pb.AuthTokenRevokeRequest) (*pb.AuthTokenRevokeResponse, error)
+
+	// State returns the users and roles of the auth store at its current revision
+	State() *State
+
+	// RevisionNotify returns a channel closed once the auth revision changes
+	RevisionNotify() <-chan struct{}
 }
 
 type TokenProvider interface {
@@ -191,6 +289,12 @@ type TokenProvider interface {
 
 	invalidateUser(string)
 	genTokenPrefix() (string, error)
+
+	// tokens returns the active tokens of the user, or of all users if username is empty.
+	// Token providers which do not keep track of their tokens return none.
+	tokens(username string) []*pb.AuthToken
+	// revoke invalidates the token with the given ID, and returns false if there is none.
+	revoke(id uint64) bool
 }
 
 type AuthBackend interface {
@@ -255,11 +359,25 @@ type authStore struct {
 	//
 	// Note that BatchTx and ReadTx cannot be a mutex for rangePermCache because they are independent resources
 	// see also: https://github.com/etcd-io/etcd/pull/13920#discussion_r849114855
-	rangePermCache   map[string]*unifiedRangePermissions // username -> unifiedRangePermissions
+	rangePermCache     map[string]*unifiedRangePermissions // username -> unifiedRangePermissions
+	roleRangePermCache map[string]*unifiedRangePermissions // role name -> unifiedRangePermissions
+	// roleGrantExpiries is the grants of roles with a ttl, the earliest
+	// expiring first. It is rebuilt with rangePermCache.
+	roleGrantExpiries []*pb.ExpiredRoleGrant
+	// userLimitsCache and roleLimitsCache are the limits of the users, merged
+	// with those of their roles, and of the roles. They are rebuilt with
+	// rangePermCache.
+	userLimitsCache  map[string]*userLimits
+	roleLimitsCache  map[string]*authpb.Limits
 	rangePermCacheMu sync.RWMutex
 
 	tokenProvider TokenProvider
 	bcryptCost    int // the algorithm cost / strength for hashing auth passwords
+	hasher        PasswordHasher
+
+	// revisionNotifyC is closed and replaced when the auth revision changes
+	revisionNotifyC  chan struct{}
+	revisionNotifyMu sync.Mutex
 }
 
 func (as *authStore) AuthEnable() error {
@@ -345,6 +463,14 @@ func (as *authStore) Authenticate(ctx context.Context, username, password string
 	// Password checking is already performed in the API layer, so we don't need to check for now.
 	// Staleness of password can be detected with OCC in the API layer, too.
 
+	if tk, ok := ctx.Value(AuthenticateParamToken{}).(*pb.AuthToken); ok && tk.Scope != nil {
+		for _, role := range tk.Scope.Roles {
+			if !hasRole(user.Roles, role) {
+				return nil, ErrRoleNotGranted
+			}
+		}
+	}
+
 	token, err := as.tokenProvider.assign(ctx, username, as.Revision())
 	if err != nil {
 		return nil, err
@@ -386,7 +512,7 @@ func (as *authStore) CheckPassword(username, password string) (uint64, error) {
 		return 0, err
 	}
 
-	if bcrypt.CompareHashAndPassword(user.Password, []byte(password)) != nil {
+	if comparePassword(user.PasswordAlgorithm, user.Password, password) != nil {
 		as.lg.Info("invalid password", zap.String("user-name", username))
 		return 0, ErrAuthFailed
 	}
@@ -412,12 +538,57 @@ func (as *authStore) Recover(be AuthBackend) {
 	as.enabledMu.Unlock()
 }
 
-func (as *authStore) selectPassword(password string, hashedPassword string) ([]byte, error) {
+func (as *authStore) selectPassword(password string, hashedPassword string, alg authpb.PasswordAlgorithm) ([]byte, authpb.PasswordAlgorithm, error) {
 	if password != "" && hashedPassword == "" {
 		// This path is for processing log entries created by etcd whose version is older than 3.5
-		return bcrypt.GenerateFromPassword([]byte(password), as.bcryptCost)
+		return as.HashPassword(password)
+	}
+	hash, err := base64.StdEncoding.DecodeString(hashedPassword)
+	return hash, alg, err
+}
+
+func (as *authStore) HashPassword(password string) ([]byte, authpb.PasswordAlgorithm, error) {
+	hash, err := as.hasher.Hash(password)
+	return hash, as.hasher.Algorithm(), err
+}
+
+func (as *authStore) PasswordNeedsRehash(username string) bool {
+	user := as.be.GetUser(username)
+	if user == nil || (user.Options != nil && user.Options.NoPassword) {
+		return false
+	}
+	return user.PasswordAlgorithm != as.hasher.Algorithm() || !as.hasher.Current(user.Password)
+}
+
+func (as *authStore) RehashPassword(username string, hash []byte, alg authpb.PasswordAlgorithm, revision uint64) {
+	tx := as.be.BatchTx()
+	tx.Lock()
+	defer tx.Unlock()
+
+	// the password may have changed since it was checked
+	if tx.UnsafeReadAuthRevision() != revision {
+		return
+	}
+	user := tx.UnsafeGetUser(username)
+	if user == nil {
+		return
 	}
-	return base64.StdEncoding.DecodeString(hashedPassword)
+	user.Password = hash
+	user.PasswordAlgorithm = alg
+	// the password is unchanged, so the revision is not bumped and the tokens
+	// of the user remain valid.
+	tx.UnsafePutUser(user)
+
+	as.lg.Info(
+		"rehashed a password of a user",
+		zap.String("user-name", username),
+		zap.Stringer("password-algorithm", alg),
+	)
+}
+
+// SetPasswordHasher replaces the bcrypt hasher of the passwords.
+func (as *authStore) SetPasswordHasher(h PasswordHasher) {
+	as.hasher = h
 }
 
 func (as *authStore) UserAdd(r *pb.AuthUserAddRequest) (*pb.AuthUserAddResponse, error) {
@@ -442,19 +613,21 @@ func (as *authStore) UserAdd(r *pb.AuthUserAddRequest) (*pb.AuthUserAddResponse,
 	}
 
 	var password []byte
+	var alg authpb.PasswordAlgorithm
 	var err error
 
 	if !options.NoPassword {
-		password, err = as.selectPassword(r.Password, r.HashedPassword)
+		password, alg, err = as.selectPassword(r.Password, r.HashedPassword, r.HashedPasswordAlgorithm)
 		if err != nil {
 			return nil, ErrNoPasswordUser
 		}
 	}
 
 	newUser := &authpb.User{
-		Name:     []byte(r.Name),
-		Password: password,
-		Options:  options,
+		Name:              []byte(r.Name),
+		Password:          password,
+		PasswordAlgorithm: alg,
+		Options:           options,
 	}
 	tx.UnsafePutUser(newUser)
 
@@ -505,21 +678,25 @@ func (as *authStore) UserChangePassword(r *pb.AuthUserChangePasswordRequest) (*p
 	}
 
 	var password []byte
+	var alg authpb.PasswordAlgorithm
 	var err error
 
 	// Backward compatible with old versions of etcd, user options is nil
 	if user.Options == nil || !user.Options.NoPassword {
-		password, err = as.selectPassword(r.Password, r.HashedPassword)
+		password, alg, err = as.selectPassword(r.Password, r.HashedPassword, r.HashedPasswordAlgorithm)
 		if err != nil {
 			return nil, ErrNoPasswordUser
 		}
 	}
 
 	updatedUser := &authpb.User{
-		Name:     []byte(r.Name),
-		Roles:    user.Roles,
-		Password: password,
-		Options:  user.Options,
+		Name:              []byte(r.Name),
+		Roles:             user.Roles,
+		Password:          password,
+		PasswordAlgorithm: alg,
+		Options:           user.Options,
+		Limits:            user.Limits,
+		RoleExpiries:      user.RoleExpiries,
 	}
 	tx.UnsafePutUser(updatedUser)
 
@@ -537,6 +714,10 @@ func (as *authStore) UserChangePassword(r *pb.AuthUserChangePasswordRequest) (*p
 }
 
 func (as *authStore) UserGrantRole(r *pb.AuthUserGrantRoleRequest) (*pb.AuthUserGrantRoleResponse, error) {
+	return as.UserGrantRoleUntil(r, 0)
+}
+
+func (as *authStore) UserGrantRoleUntil(r *pb.AuthUserGrantRoleRequest, expireTime int64) (*pb.AuthUserGrantRoleResponse, error) {
 	tx := as.be.BatchTx()
 	tx.Lock()
 	defer tx.Unlock()
@@ -546,6 +727,11 @@ func (as *authStore) UserGrantRole(r *pb.AuthUserGrantRoleRequest) (*pb.AuthUser
 		return nil, ErrUserNotFound
 	}
 
+	if expireTime != 0 && r.User == rootUser && r.Role == rootRole {
+		as.lg.Error("'root' role cannot be granted to 'root' user with a ttl")
+		return nil, ErrInvalidAuthMgmt
+	}
+
 	if r.Role != rootRole {
 		role := tx.UnsafeGetRole(r.Role)
 		if role == nil {
@@ -554,7 +740,8 @@ func (as *authStore) UserGrantRole(r *pb.AuthUserGrantRoleRequest) (*pb.AuthUser
 	}
 
 	idx := sort.SearchStrings(user.Roles, r.Role)
-	if idx < len(user.Roles) && user.Roles[idx] == r.Role {
+	granted := idx < len(user.Roles) && user.Roles[idx] == r.Role
+	if granted && roleExpiry(user.RoleExpiries, r.Role) == expireTime {
 		as.lg.Warn(
 			"ignored grant role request to a user",
 			zap.String("user-name", r.User),
@@ -564,8 +751,15 @@ func (as *authStore) UserGrantRole(r *pb.AuthUserGrantRoleRequest) (*pb.AuthUser
 		return &pb.AuthUserGrantRoleResponse{}, nil
 	}
 
-	user.Roles = append(user.Roles, r.Role)
-	sort.Strings(user.Roles)
+	if !granted {
+		user.Roles = append(user.Roles, r.Role)
+		sort.Strings(user.Roles)
+	}
+	user.RoleExpiries = roleExpiriesWithout(user.RoleExpiries, r.Role)
+	if expireTime != 0 {
+		user.RoleExpiries = append(user.RoleExpiries, &authpb.RoleExpiry{Role: r.Role, ExpireTime: expireTime})
+		sort.Slice(user.RoleExpiries, func(i, j int) bool { return user.RoleExpiries[i].Role < user.RoleExpiries[j].Role })
+	}
 
 	tx.UnsafePutUser(user)
 
@@ -577,10 +771,85 @@ func (as *authStore) UserGrantRole(r *pb.AuthUserGrantRoleRequest) (*pb.AuthUser
 		zap.String("user-name", r.User),
 		zap.Strings("user-roles", user.Roles),
 		zap.String("added-role-name", r.Role),
+		zap.Int64("expire-time", expireTime),
 	)
 	return &pb.AuthUserGrantRoleResponse{}, nil
 }
 
+// ExpiredRoleGrants returns at most limit grants of roles expired at the
+// given unix time.
+func (as *authStore) ExpiredRoleGrants(now int64, limit int) []*pb.ExpiredRoleGrant {
+	as.rangePermCacheMu.RLock()
+	defer as.rangePermCacheMu.RUnlock()
+
+	var expired []*pb.ExpiredRoleGrant
+	for _, g := range as.roleGrantExpiries {
+		if len(expired) >= limit || g.ExpireTime > now {
+			break
+		}
+		expired = append(expired, g)
+	}
+	return expired
+}
+
+func (as *authStore) RevokeExpiredRoleGrants(r *pb.AuthRoleGrantExpireRequest) (*pb.AuthUserRevokeRoleResponse, error) {
+	tx := as.be.BatchTx()
+	tx.Lock()
+	defer tx.Unlock()
+
+	revoked := 0
+	for _, g := range r.Grants {
+		user := tx.UnsafeGetUser(g.User)
+		if user == nil || roleExpiry(user.RoleExpiries, g.Role) != g.ExpireTime {
+			// the user was deleted, or the role revoked or granted again since
+			continue
+		}
+		var roles []string
+		for _, role := range user.Roles {
+			if role != g.Role {
+				roles = append(roles, role)
+			}
+		}
+		user.Roles = roles
+		user.RoleExpiries = roleExpiriesWithout(user.RoleExpiries, g.Role)
+		tx.UnsafePutUser(user)
+		revoked++
+
+		as.lg.Info(
+			"revoked an expired role from a user",
+			zap.String("user-name", g.User),
+			zap.Strings("new-user-roles", user.Roles),
+			zap.String("revoked-role-name", g.Role),
+			zap.Int64("expire-time", g.ExpireTime),
+		)
+	}
+	if revoked != 0 {
+		as.commitRevision(tx)
+		as.refreshRangePermCache(tx)
+	}
+	return &pb.AuthUserRevokeRoleResponse{}, nil
+}
+
+// roleExpiry returns the expire time of the role, or zero if it does not expire.
+func roleExpiry(res []*authpb.RoleExpiry, role string) int64 {
+	for _, re := range res {
+		if re.Role == role {
+			return re.ExpireTime
+		}
+	}
+	return 0
+}
+
+func roleExpiriesWithout(res []*authpb.RoleExpiry, role string) []*authpb.RoleExpiry {
+	var without []*authpb.RoleExpiry
+	for _, re := range res {
+		if re.Role != role {
+			without = append(without, re)
+		}
+	}
+	return without
+}
+
 func (as *authStore) UserGet(r *pb.AuthUserGetRequest) (*pb.AuthUserGetResponse, error) {
 	user := as.be.GetUser(r.Name)
 
@@ -590,6 +859,8 @@ func (as *authStore) UserGet(r *pb.AuthUserGetRequest) (*pb.AuthUserGetResponse,
 
 	var resp pb.AuthUserGetResponse
 	resp.Roles = append(resp.Roles, user.Roles...)
+	resp.Limits = user.Limits
+	resp.RoleExpiries = user.RoleExpiries
 	return &resp, nil
 }
 
@@ -603,6 +874,64 @@ func (as *authStore) UserList(r *pb.AuthUserListRequest) (*pb.AuthUserListRespon
 	return resp, nil
 }
 
+func (as *authStore) TokenList(r *pb.AuthTokenListRequest) (*pb.AuthTokenListResponse, error) {
+	if r.Name != "" && as.be.GetUser(r.Name) == nil {
+		return nil, ErrUserNotFound
+	}
+	return &pb.AuthTokenListResponse{Tokens: as.tokenProvider.tokens(r.Name)}, nil
+}
+
+func (as *authStore) Explain(r *pb.AuthExplainRequest) (*pb.AuthExplainResponse, error) {
+	if _, ok := authpb.Permission_Type_name[int32(r.PermType)]; !ok || r.PermType == authpb.READWRITE {
+		return nil, ErrInvalidAuthMgmt
+	}
+	tx := as.be.ReadTx()
+	tx.RLock()
+	user := tx.UnsafeGetUser(r.User)
+	if user == nil {
+		tx.RUnlock()
+		return nil, ErrUserNotFound
+	}
+	resp := &pb.AuthExplainResponse{Roles: make([]*pb.AuthRoleExplanation, len(user.Roles))}
+	for i, name := range user.Roles {
+		re := &pb.AuthRoleExplanation{Role: name}
+		if name == rootRole {
+			re.Permitted = true
+			re.Matching = []*authpb.Permission{&rootPerm}
+		} else if role := tx.UnsafeGetRole(name); role != nil {
+			for _, perm := range role.KeyPermission {
+				if permMatches(perm, r.PermType, r.Key, r.RangeEnd) {
+					re.Matching = append(re.Matching, perm)
+				} else {
+					re.NonMatching = append(re.NonMatching, perm)
+				}
+			}
+			re.Permitted = as.isRoleRangeOpPermitted([]string{name}, r.Key, r.RangeEnd, r.PermType)
+		}
+		resp.Roles[i] = re
+	}
+	tx.RUnlock()
+
+	// the range may only be permitted by several roles together
+	resp.Permitted = as.isOpPermitted(r.User, as.Revision(), nil, nil, 0, r.Key, r.RangeEnd, r.PermType) == nil
+	return resp, nil
+}
+
+func (as *authStore) TokenRevoke(r *pb.AuthTokenRevokeRequest) (*pb.AuthTokenRevokeResponse, error) {
+	tx := as.be.BatchTx()
+	tx.Lock()
+	defer tx.Unlock()
+
+	if !as.tokenProvider.revoke(r.ID) {
+		return nil, ErrTokenNotFound
+	}
+	// the watchers of the auth store, such as proxies, forget the token
+	as.commitRevision(tx)
+
+	as.lg.Info("revoked a token", zap.Uint64("token-id", r.ID))
+	return &pb.AuthTokenRevokeResponse{}, nil
+}
+
 func (as *authStore) UserRevokeRole(r *pb.AuthUserRevokeRoleRequest) (*pb.AuthUserRevokeRoleResponse, error) {
 	if as.enabled && r.Name == rootUser && r.Role == rootRole {
 		as.lg.Error(
@@ -623,9 +952,12 @@ func (as *authStore) UserRevokeRole(r *pb.AuthUserRevokeRoleRequest) (*pb.AuthUs
 	}
 
 	updatedUser := &authpb.User{
-		Name:     user.Name,
-		Password: user.Password,
-		Options:  user.Options,
+		Name:              user.Name,
+		Password:          user.Password,
+		PasswordAlgorithm: user.PasswordAlgorithm,
+		Options:           user.Options,
+		Limits:            user.Limits,
+		RoleExpiries:      roleExpiriesWithout(user.RoleExpiries, r.Role),
 	}
 
 	for _, role := range user.Roles {
@@ -665,6 +997,7 @@ func (as *authStore) RoleGet(r *pb.AuthRoleGetRequest) (*pb.AuthRoleGetResponse,
 	} else {
 		resp.Perm = append(resp.Perm, role.KeyPermission...)
 	}
+	resp.Limits = role.Limits
 	return &resp, nil
 }
 
@@ -689,7 +1022,8 @@ func (as *authStore) RoleRevokePermission(r *pb.AuthRoleRevokePermissionRequest)
 	}
 
 	updatedRole := &authpb.Role{
-		Name: role.Name,
+		Name:   role.Name,
+		Limits: role.Limits,
 	}
 
 	for _, perm := range role.KeyPermission {
@@ -736,9 +1070,12 @@ func (as *authStore) RoleDelete(r *pb.AuthRoleDeleteRequest) (*pb.AuthRoleDelete
 	users := tx.UnsafeGetAllUsers()
 	for _, user := range users {
 		updatedUser := &authpb.User{
-			Name:     user.Name,
-			Password: user.Password,
-			Options:  user.Options,
+			Name:              user.Name,
+			Password:          user.Password,
+			PasswordAlgorithm: user.PasswordAlgorithm,
+			Options:           user.Options,
+			Limits:            user.Limits,
+			RoleExpiries:      roleExpiriesWithout(user.RoleExpiries, r.Role),
 		}
 
 		for _, role := range user.Roles {
@@ -806,6 +1143,16 @@ func (perms permSlice) Swap(i, j int) {
 	perms[i], perms[j] = perms[j], perms[i]
 }
 
+// permKind returns the permission type which the permission type replaces
+// when granted on the same range.
+func permKind(typ authpb.Permission_Type) authpb.Permission_Type {
+	switch typ {
+	case authpb.READ, authpb.WRITE, authpb.READWRITE:
+		return authpb.READWRITE
+	}
+	return typ
+}
+
 func (as *authStore) RoleGrantPermission(r *pb.AuthRoleGrantPermissionRequest) (*pb.AuthRoleGrantPermissionResponse, error) {
 	if r.Perm == nil {
 		return nil, ErrPermissionNotGiven
@@ -813,6 +1160,9 @@ func (as *authStore) RoleGrantPermission(r *pb.AuthRoleGrantPermissionRequest) (
 	if !isValidPermissionRange(r.Perm.Key, r.Perm.RangeEnd) {
 		return nil, ErrInvalidAuthMgmt
 	}
+	if _, ok := authpb.Permission_Type_name[int32(r.Perm.PermType)]; !ok {
+		return nil, ErrInvalidAuthMgmt
+	}
 
 	tx := as.be.BatchTx()
 	tx.Lock()
@@ -826,8 +1176,15 @@ func (as *authStore) RoleGrantPermission(r *pb.AuthRoleGrantPermissionRequest) (
 	idx := sort.Search(len(role.KeyPermission), func(i int) bool {
 		return bytes.Compare(role.KeyPermission[i].Key, r.Perm.Key) >= 0
 	})
+	// READ, WRITE and READWRITE replace each other on a range, while the finer
+	// permission types are granted alongside them
+	for ; idx < len(role.KeyPermission) && bytes.Equal(role.KeyPermission[idx].Key, r.Perm.Key); idx++ {
+		if bytes.Equal(role.KeyPermission[idx].RangeEnd, r.Perm.RangeEnd) && permKind(role.KeyPermission[idx].PermType) == permKind(r.Perm.PermType) {
+			break
+		}
+	}
 
-	if idx < len(role.KeyPermission) && bytes.Equal(role.KeyPermission[idx].Key, r.Perm.Key) && bytes.Equal(role.KeyPermission[idx].RangeEnd, r.Perm.RangeEnd) {
+	if idx < len(role.KeyPermission) && bytes.Equal(role.KeyPermission[idx].Key, r.Perm.Key) {
 		// update existing permission
 		role.KeyPermission[idx].PermType = r.Perm.PermType
 	} else {
@@ -857,7 +1214,7 @@ func (as *authStore) RoleGrantPermission(r *pb.AuthRoleGrantPermissionRequest) (
 	return &pb.AuthRoleGrantPermissionResponse{}, nil
 }
 
-func (as *authStore) isOpPermitted(userName string, revision uint64, key, rangeEnd []byte, permTyp authpb.Permission_Type) error {
+func (as *authStore) isOpPermitted(userName string, revision uint64, roles []string, scope *authpb.TokenScope, now int64, key, rangeEnd []byte, permTyp authpb.Permission_Type) error {
 	// TODO(mitake): this function would be costly so we need a caching mechanism
 	if !as.IsAuthEnabled() {
 		return nil
@@ -881,34 +1238,124 @@ func (as *authStore) isOpPermitted(userName string, revision uint64, key, rangeE
 	tx.RLock()
 	defer tx.RUnlock()
 
+	// users authenticated by an external issuer may only hold the roles of their token
 	user := tx.UnsafeGetUser(userName)
-	if user == nil {
+	if user == nil && len(roles) == 0 {
 		as.lg.Error("cannot find a user for permission check", zap.String("user-name", userName))
 		return ErrPermissionDenied
 	}
 
+	if now == 0 {
+		now = time.Now().Unix()
+	}
+	var userRoles []string
+	if user != nil {
+		userRoles = activeRoles(user, now)
+	}
+
+	if scope != nil {
+		if !isInScope(scope.Prefix, key, rangeEnd) {
+			return ErrPermissionDenied
+		}
+		if len(scope.Roles) != 0 {
+			// the token only gives access to the roles of its scope the user still holds
+			roles = roles[:len(roles):len(roles)]
+			for _, role := range scope.Roles {
+				if hasRole(userRoles, role) {
+					roles = append(roles, role)
+				}
+			}
+			user = nil
+			if hasRole(roles, rootRole) {
+				return nil
+			}
+			if as.isRoleRangeOpPermitted(roles, key, rangeEnd, permTyp) {
+				return nil
+			}
+			return ErrPermissionDenied
+		}
+	}
+
 	// root role should have permission on all ranges
-	if hasRootRole(user) {
+	if hasRole(userRoles, rootRole) || hasRole(roles, rootRole) {
 		return nil
 	}
 
-	if as.isRangeOpPermitted(userName, key, rangeEnd, permTyp) {
+	if user != nil && as.isRangeOpPermitted(userName, key, rangeEnd, permTyp) {
+		return nil
+	}
+	if user != nil {
+		// the roles granted with a ttl are not merged into the permissions of
+		// the user, so that they stop giving access as soon as they expire
+		roles = roles[:len(roles):len(roles)]
+		for _, re := range user.RoleExpiries {
+			if re.ExpireTime > now {
+				roles = append(roles, re.Role)
+			}
+		}
+	}
+	if as.isRoleRangeOpPermitted(roles, key, rangeEnd, permTyp) {
 		return nil
 	}
 
 	return ErrPermissionDenied
 }
 
+// isInScope returns true if the range [key, rangeEnd) is within the keys with the given prefix.
+func isInScope(prefix, key, rangeEnd []byte) bool {
+	if len(prefix) == 0 {
+		return true
+	}
+	if !bytes.HasPrefix(key, prefix) {
+		return false
+	}
+	if len(rangeEnd) == 0 {
+		return true
+	}
+	end := prefixEnd(prefix)
+	if end == nil {
+		// every key after the prefix starts with it
+		return true
+	}
+	return !(len(rangeEnd) == 1 && rangeEnd[0] == 0) && bytes.Compare(rangeEnd, end) <= 0
+}
+
+// prefixEnd returns the end of the range of the keys with the given prefix, or
+// nil if the range has no end.
+func prefixEnd(prefix []byte) []byte {
+	end := make([]byte, len(prefix))
+	copy(end, prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
 func (as *authStore) IsPutPermitted(authInfo *AuthInfo, key []byte) error {
-	return as.isOpPermitted(authInfo.Username, authInfo.Revision, key, nil, authpb.WRITE)
+	return as.isOpPermitted(authInfo.Username, authInfo.Revision, authInfo.Roles, authInfo.Scope, authInfo.Time, key, nil, authpb.WRITE)
+}
+
+func (as *authStore) IsCreatePermitted(authInfo *AuthInfo, key []byte) error {
+	return as.isOpPermitted(authInfo.Username, authInfo.Revision, authInfo.Roles, authInfo.Scope, authInfo.Time, key, nil, authpb.CREATE)
+}
+
+func (as *authStore) IsWatchPermitted(authInfo *AuthInfo, key, rangeEnd []byte) error {
+	return as.isOpPermitted(authInfo.Username, authInfo.Revision, authInfo.Roles, authInfo.Scope, authInfo.Time, key, rangeEnd, authpb.WATCH)
+}
+
+func (as *authStore) IsLeasePermitted(authInfo *AuthInfo, key []byte) error {
+	return as.isOpPermitted(authInfo.Username, authInfo.Revision, authInfo.Roles, authInfo.Scope, authInfo.Time, key, nil, authpb.LEASE)
 }
 
 func (as *authStore) IsRangePermitted(authInfo *AuthInfo, key, rangeEnd []byte) error {
-	return as.isOpPermitted(authInfo.Username, authInfo.Revision, key, rangeEnd, authpb.READ)
+	return as.isOpPermitted(authInfo.Username, authInfo.Revision, authInfo.Roles, authInfo.Scope, authInfo.Time, key, rangeEnd, authpb.READ)
 }
 
 func (as *authStore) IsDeleteRangePermitted(authInfo *AuthInfo, key, rangeEnd []byte) error {
-	return as.isOpPermitted(authInfo.Username, authInfo.Revision, key, rangeEnd, authpb.WRITE)
+	return as.isOpPermitted(authInfo.Username, authInfo.Revision, authInfo.Roles, authInfo.Scope, authInfo.Time, key, rangeEnd, authpb.DELETE)
 }
 
 func (as *authStore) IsAdminPermitted(authInfo *AuthInfo) error {
@@ -918,6 +1365,15 @@ func (as *authStore) IsAdminPermitted(authInfo *AuthInfo) error {
 	if authInfo == nil || authInfo.Username == "" {
 		return ErrUserEmpty
 	}
+	if scope := authInfo.Scope; scope != nil {
+		// tokens scoped to a prefix or to roles other than root cannot administer the cluster
+		if len(scope.Prefix) != 0 || (len(scope.Roles) != 0 && !hasRole(scope.Roles, rootRole)) {
+			return ErrPermissionDenied
+		}
+	}
+	if hasRole(authInfo.Roles, rootRole) {
+		return nil
+	}
 
 	tx := as.be.ReadTx()
 	tx.RLock()
@@ -928,7 +1384,7 @@ func (as *authStore) IsAdminPermitted(authInfo *AuthInfo) error {
 		return ErrUserNotFound
 	}
 
-	if !hasRootRole(u) {
+	if !hasRole(activeRoles(u, authInfo.Time), rootRole) {
 		return ErrPermissionDenied
 	}
 
@@ -965,13 +1421,15 @@ func NewAuthStore(lg *zap.Logger, be AuthBackend, tp TokenProvider, bcryptCost i
 	tx.Lock()
 	enabled := tx.UnsafeReadAuthEnabled()
 	as := &authStore{
-		revision:       tx.UnsafeReadAuthRevision(),
-		lg:             lg,
-		be:             be,
-		enabled:        enabled,
-		rangePermCache: make(map[string]*unifiedRangePermissions),
-		tokenProvider:  tp,
-		bcryptCost:     bcryptCost,
+		revision:           tx.UnsafeReadAuthRevision(),
+		lg:                 lg,
+		be:                 be,
+		enabled:            enabled,
+		rangePermCache:     make(map[string]*unifiedRangePermissions),
+		roleRangePermCache: make(map[string]*unifiedRangePermissions),
+		tokenProvider:      tp,
+		bcryptCost:         bcryptCost,
+		hasher:             NewBcryptHasher(bcryptCost),
 	}
 
 	if enabled {
@@ -992,6 +1450,33 @@ func NewAuthStore(lg *zap.Logger, be AuthBackend, tp TokenProvider, bcryptCost i
 	return as
 }
 
+func hasRole(roles []string, role string) bool {
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// activeRoles returns the roles of the user whose grant has not expired at the
+// unix time now, zero being the current time.
+func activeRoles(u *authpb.User, now int64) []string {
+	if len(u.RoleExpiries) == 0 {
+		return u.Roles
+	}
+	if now == 0 {
+		now = time.Now().Unix()
+	}
+	var roles []string
+	for _, role := range u.Roles {
+		if exp := roleExpiry(u.RoleExpiries, role); exp == 0 || exp > now {
+			roles = append(roles, role)
+		}
+	}
+	return roles
+}
+
 func hasRootRole(u *authpb.User) bool {
 	// u.Roles is sorted in UserGrantRole(), so we can use binary search.
 	idx := sort.SearchStrings(u.Roles, rootRole)
@@ -1001,17 +1486,49 @@ func hasRootRole(u *authpb.User) bool {
 func (as *authStore) commitRevision(tx UnsafeAuthWriter) {
 	atomic.AddUint64(&as.revision, 1)
 	tx.UnsafeSaveAuthRevision(as.Revision())
+	as.notifyRevision()
 }
 
 func (as *authStore) setRevision(rev uint64) {
 	atomic.StoreUint64(&as.revision, rev)
+	as.notifyRevision()
+}
+
+func (as *authStore) RevisionNotify() <-chan struct{} {
+	as.revisionNotifyMu.Lock()
+	defer as.revisionNotifyMu.Unlock()
+	if as.revisionNotifyC == nil {
+		as.revisionNotifyC = make(chan struct{})
+	}
+	return as.revisionNotifyC
+}
+
+func (as *authStore) notifyRevision() {
+	as.revisionNotifyMu.Lock()
+	defer as.revisionNotifyMu.Unlock()
+	if as.revisionNotifyC != nil {
+		close(as.revisionNotifyC)
+		as.revisionNotifyC = nil
+	}
+}
+
+func (as *authStore) State() *State {
+	tx := as.be.ReadTx()
+	tx.RLock()
+	defer tx.RUnlock()
+	return &State{
+		Enabled:  tx.UnsafeReadAuthEnabled(),
+		Revision: tx.UnsafeReadAuthRevision(),
+		Users:    tx.UnsafeGetAllUsers(),
+		Roles:    tx.UnsafeGetAllRoles(),
+	}
 }
 
 func (as *authStore) Revision() uint64 {
 	return atomic.LoadUint64(&as.revision)
 }
 
-func (as *authStore) AuthInfoFromTLS(ctx context.Context) (ai *AuthInfo) {
+func (as *authStore) AuthInfoFromTLS(ctx context.Context, ci *CertIdentity) (ai *AuthInfo) {
 	peer, ok := peer.FromContext(ctx)
 	if !ok || peer == nil || peer.AuthInfo == nil {
 		return nil
@@ -1026,6 +1543,16 @@ func (as *authStore) AuthInfoFromTLS(ctx context.Context) (ai *AuthInfo) {
 			Username: chains[0].Subject.CommonName,
 			Revision: as.Revision(),
 		}
+		if ci != nil {
+			ai.Username, ai.Roles = ci.identify(chains[0])
+			if ai.Username == "" {
+				as.lg.Warn(
+					"no client certificate identity rule matched",
+					zap.String("common-name", chains[0].Subject.CommonName),
+				)
+				return nil
+			}
+		}
 		md, ok := metadata.FromIncomingContext(ctx)
 		if !ok {
 			return nil
@@ -1037,7 +1564,7 @@ func (as *authStore) AuthInfoFromTLS(ctx context.Context) (ai *AuthInfo) {
 		if gw := md["grpcgateway-accept"]; len(gw) > 0 {
 			as.lg.Warn(
 				"ignoring common name in gRPC-gateway proxy request",
-				zap.String("common-name", ai.Username),
+				zap.String("common-name", chains[0].Subject.CommonName),
 				zap.String("user-name", ai.Username),
 				zap.Uint64("revision", ai.Revision),
 			)
@@ -1045,8 +1572,9 @@ func (as *authStore) AuthInfoFromTLS(ctx context.Context) (ai *AuthInfo) {
 		}
 		as.lg.Debug(
 			"found command name",
-			zap.String("common-name", ai.Username),
+			zap.String("common-name", chains[0].Subject.CommonName),
 			zap.String("user-name", ai.Username),
+			zap.Strings("roles", ai.Roles),
 			zap.Uint64("revision", ai.Revision),
 		)
 		break
@@ -1058,6 +1586,9 @@ func (as *authStore) AuthInfoFromCtx(ctx context.Context) (*AuthInfo, error) {
 	if !as.IsAuthEnabled() {
 		return nil, nil
 	}
+	if authInfo, ok := ctx.Value(authInfoKey{}).(*AuthInfo); ok {
+		return authInfo, nil
+	}
 
 	md, ok := metadata.FromIncomingContext(ctx)
 	if !ok {
@@ -1093,7 +1624,7 @@ func decomposeOpts(lg *zap.Logger, optstr string) (string, map[string]string, er
 
 	typeSpecificOpts := make(map[string]string)
 	for i := 1; i < len(opts); i++ {
-		pair := strings.Split(opts[i], "=")
+		pair := strings.SplitN(opts[i], "=", 2)
 
 		if len(pair) != 2 {
 			if lg != nil {
@@ -1141,6 +1672,9 @@ func NewTokenProvider(
 	case tokenTypeJWT:
 		return newTokenProviderJWT(lg, typeSpecificOpts)
 
+	case tokenTypeOIDC:
+		return newTokenProviderOIDC(lg, typeSpecificOpts)
+
 	case "":
 		return newTokenProviderNop()
 
@@ -19,6 +19,8 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"reflect"
+	"sort"
 	"strings"
 	"sync"
 	"testing"
@@ -446,7 +448,7 @@ func TestIsOpPermitted(t *testing.T) {
 
 	// check permission reflected to user
 
-	err = as.isOpPermitted("foo", as.Revision(), perm.Key, perm.RangeEnd, perm.PermType)
+	err = as.isOpPermitted("foo", as.Revision(), nil, nil, 0, perm.Key, perm.RangeEnd, perm.PermType)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -456,12 +458,268 @@ func TestIsOpPermitted(t *testing.T) {
 	as.rangePermCacheMu.Lock()
 	delete(as.rangePermCache, "foo")
 	as.rangePermCacheMu.Unlock()
-	if err := as.isOpPermitted("foo", as.Revision(), perm.Key, perm.RangeEnd, perm.PermType); err != ErrPermissionDenied {
+	if err := as.isOpPermitted("foo", as.Revision(), nil, nil, 0, perm.Key, perm.RangeEnd, perm.PermType); err != ErrPermissionDenied {
 		t.Fatal(err)
 	}
 
 }
 
+func TestScopedTokenPermitted(t *testing.T) {
+	as, tearDown := setupAuthStore(t)
+	defer tearDown(t)
+
+	for _, p := range []struct{ role, key string }{{"role-a", "/a/"}, {"role-b", "/b/"}} {
+		if _, err := as.RoleAdd(&pb.AuthRoleAddRequest{Name: p.role}); err != nil {
+			t.Fatal(err)
+		}
+		perm := &authpb.Permission{PermType: authpb.READ, Key: []byte(p.key), RangeEnd: []byte(p.key[:len(p.key)-1] + "0")}
+		if _, err := as.RoleGrantPermission(&pb.AuthRoleGrantPermissionRequest{Name: p.role, Perm: perm}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := as.UserGrantRole(&pb.AuthUserGrantRoleRequest{User: "foo", Role: p.role}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		scope *authpb.TokenScope
+		key   string
+		want  error
+	}{
+		{nil, "/b/x", nil},
+		{&authpb.TokenScope{Roles: []string{"role-a"}}, "/a/x", nil},
+		{&authpb.TokenScope{Roles: []string{"role-a"}}, "/b/x", ErrPermissionDenied},
+		{&authpb.TokenScope{Prefix: []byte("/a/x")}, "/a/xy", nil},
+		{&authpb.TokenScope{Prefix: []byte("/a/x")}, "/a/y", ErrPermissionDenied},
+		{&authpb.TokenScope{Roles: []string{"role-b"}, Prefix: []byte("/a/")}, "/a/x", ErrPermissionDenied},
+	}
+	for i, tt := range tests {
+		ai := &AuthInfo{Username: "foo", Revision: as.Revision(), Scope: tt.scope}
+		if err := as.IsRangePermitted(ai, []byte(tt.key), nil); err != tt.want {
+			t.Errorf("#%d: expected %v, got %v", i, tt.want, err)
+		}
+	}
+
+	// the roles revoked from the user are no longer granted by its scoped tokens
+	if _, err := as.UserRevokeRole(&pb.AuthUserRevokeRoleRequest{Name: "foo", Role: "role-a"}); err != nil {
+		t.Fatal(err)
+	}
+	ai := &AuthInfo{Username: "foo", Revision: as.Revision(), Scope: &authpb.TokenScope{Roles: []string{"role-a"}}}
+	if err := as.IsRangePermitted(ai, []byte("/a/x"), nil); err != ErrPermissionDenied {
+		t.Errorf("expected %v, got %v", ErrPermissionDenied, err)
+	}
+
+	// scoped tokens only administer the cluster if they keep the root role
+	for _, tt := range []struct {
+		scope *authpb.TokenScope
+		want  error
+	}{
+		{&authpb.TokenScope{Roles: []string{"root"}}, nil},
+		{&authpb.TokenScope{Roles: []string{"role-test"}}, ErrPermissionDenied},
+		{&authpb.TokenScope{Prefix: []byte("/a/")}, ErrPermissionDenied},
+	} {
+		if err := as.IsAdminPermitted(&AuthInfo{Username: "root", Revision: as.Revision(), Scope: tt.scope}); err != tt.want {
+			t.Errorf("scope %v: expected %v, got %v", tt.scope, tt.want, err)
+		}
+	}
+}
+
+func TestIsInScope(t *testing.T) {
+	tests := []struct {
+		prefix, key, end string
+		want             bool
+	}{
+		{"", "a", "\x00", true},
+		{"/a/", "/a/b", "", true},
+		{"/a/", "/b", "", false},
+		{"/a/", "/a/b", "/a/c", true},
+		{"/a/", "/a/", "/a0", true},
+		{"/a/", "/a/", "/b", false},
+		{"/a/", "/a/", "\x00", false},
+		{"\xff", "\xff", "\x00", true},
+	}
+	for i, tt := range tests {
+		if got := isInScope([]byte(tt.prefix), []byte(tt.key), []byte(tt.end)); got != tt.want {
+			t.Errorf("#%d: expected %v, got %v", i, tt.want, got)
+		}
+	}
+}
+
+func TestAuthenticateWithScope(t *testing.T) {
+	as, tearDown := setupAuthStore(t)
+	defer tearDown(t)
+
+	if _, err := as.UserGrantRole(&pb.AuthUserGrantRoleRequest{User: "foo", Role: "role-test"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		roles []string
+		want  error
+	}{
+		{[]string{"role-test"}, nil},
+		{[]string{"root"}, ErrRoleNotGranted},
+	} {
+		ctx := context.WithValue(context.WithValue(context.TODO(), AuthenticateParamIndex{}, uint64(1)), AuthenticateParamSimpleTokenPrefix{}, "dummy")
+		ctx = context.WithValue(ctx, AuthenticateParamToken{}, &pb.AuthToken{Scope: &authpb.TokenScope{Roles: tt.roles}})
+		if _, err := as.Authenticate(ctx, "foo", "bar"); err != tt.want {
+			t.Errorf("roles %v: expected %v, got %v", tt.roles, tt.want, err)
+		}
+	}
+
+	resp, err := as.TokenList(&pb.AuthTokenListRequest{Name: "foo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Tokens) != 1 || resp.Tokens[0].Scope == nil || resp.Tokens[0].Scope.Roles[0] != "role-test" {
+		t.Fatalf("expected the scoped token to be listed, got %+v", resp.Tokens)
+	}
+	rev := as.Revision()
+	if _, err = as.TokenRevoke(&pb.AuthTokenRevokeRequest{ID: resp.Tokens[0].ID}); err != nil {
+		t.Fatal(err)
+	}
+	if as.Revision() <= rev {
+		t.Errorf("expected the revocation to advance the auth revision %d, got %d", rev, as.Revision())
+	}
+	if _, err = as.TokenRevoke(&pb.AuthTokenRevokeRequest{ID: resp.Tokens[0].ID}); err != ErrTokenNotFound {
+		t.Errorf("expected %v, got %v", ErrTokenNotFound, err)
+	}
+	if _, err = as.TokenList(&pb.AuthTokenListRequest{Name: "bar"}); err != ErrUserNotFound {
+		t.Errorf("expected %v, got %v", ErrUserNotFound, err)
+	}
+}
+
+func TestUserGrantRoleUntil(t *testing.T) {
+	as, tearDown := setupAuthStore(t)
+	defer tearDown(t)
+
+	if _, err := as.UserGrantRoleUntil(&pb.AuthUserGrantRoleRequest{User: "root", Role: "root"}, 100); err != ErrInvalidAuthMgmt {
+		t.Fatalf("expected %v granting root a ttl, got %v", ErrInvalidAuthMgmt, err)
+	}
+	if _, err := as.UserGrantRoleUntil(&pb.AuthUserGrantRoleRequest{User: "foo", Role: "role-test"}, 100); err != nil {
+		t.Fatal(err)
+	}
+	perm := &authpb.Permission{PermType: authpb.READ, Key: []byte("foo"), RangeEnd: []byte("fop")}
+	if _, err := as.RoleGrantPermission(&pb.AuthRoleGrantPermissionRequest{Name: "role-test", Perm: perm}); err != nil {
+		t.Fatal(err)
+	}
+	// the role stops giving access as soon as it expires, before it is revoked
+	ai := &AuthInfo{Username: "foo", Revision: as.Revision(), Time: 99}
+	if err := as.IsRangePermitted(ai, []byte("foo1"), nil); err != nil {
+		t.Fatalf("expected the role to give access before it expires, got %v", err)
+	}
+	ai.Time = 100
+	if err := as.IsRangePermitted(ai, []byte("foo1"), nil); err != ErrPermissionDenied {
+		t.Fatalf("expected %v once the role expired, got %v", ErrPermissionDenied, err)
+	}
+	ai.Time = TimeIgnoreExpiry
+	if err := as.IsRangePermitted(ai, []byte("foo1"), nil); err != nil {
+		t.Fatalf("expected the role to give access until revoked when ignoring its expiry, got %v", err)
+	}
+
+	if got := as.ExpiredRoleGrants(99, 10); len(got) != 0 {
+		t.Fatalf("expected no expired grant, got %+v", got)
+	}
+	expired := as.ExpiredRoleGrants(100, 10)
+	want := []*pb.ExpiredRoleGrant{{User: "foo", Role: "role-test", ExpireTime: 100}}
+	if !reflect.DeepEqual(expired, want) {
+		t.Fatalf("expected expired grants %+v, got %+v", want, expired)
+	}
+
+	// the grants renewed since they were found expired are not revoked
+	if _, err := as.UserGrantRoleUntil(&pb.AuthUserGrantRoleRequest{User: "foo", Role: "role-test"}, 200); err != nil {
+		t.Fatal(err)
+	}
+	rev := as.Revision()
+	if _, err := as.RevokeExpiredRoleGrants(&pb.AuthRoleGrantExpireRequest{Grants: expired}); err != nil {
+		t.Fatal(err)
+	}
+	if as.Revision() != rev {
+		t.Fatalf("expected revision %d, got %d", rev, as.Revision())
+	}
+	u, err := as.UserGet(&pb.AuthUserGetRequest{Name: "foo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(u.Roles, []string{"role-test"}) || len(u.RoleExpiries) != 1 || u.RoleExpiries[0].ExpireTime != 200 {
+		t.Fatalf("expected role-test to expire at 200, got %v %+v", u.Roles, u.RoleExpiries)
+	}
+
+	if _, err = as.RevokeExpiredRoleGrants(&pb.AuthRoleGrantExpireRequest{Grants: as.ExpiredRoleGrants(200, 10)}); err != nil {
+		t.Fatal(err)
+	}
+	if u, err = as.UserGet(&pb.AuthUserGetRequest{Name: "foo"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(u.Roles) != 0 || len(u.RoleExpiries) != 0 {
+		t.Fatalf("expected role-test to be revoked, got %v %+v", u.Roles, u.RoleExpiries)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	as, tearDown := setupAuthStore(t)
+	defer tearDown(t)
+
+	if _, err := as.RoleAdd(&pb.AuthRoleAddRequest{Name: "role-test-2"}); err != nil {
+		t.Fatal(err)
+	}
+	grants := []struct {
+		role string
+		perm *authpb.Permission
+	}{
+		{"role-test", &authpb.Permission{PermType: authpb.WRITE, Key: []byte("foo"), RangeEnd: []byte("fop")}},
+		{"role-test", &authpb.Permission{PermType: authpb.READ, Key: []byte("bar")}},
+		{"role-test-2", &authpb.Permission{PermType: authpb.WRITE, Key: []byte("fop"), RangeEnd: []byte("foq")}},
+	}
+	for _, g := range grants {
+		if _, err := as.RoleGrantPermission(&pb.AuthRoleGrantPermissionRequest{Name: g.role, Perm: g.perm}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, role := range []string{"role-test", "role-test-2"} {
+		if _, err := as.UserGrantRole(&pb.AuthUserGrantRoleRequest{User: "foo", Role: role}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resp, err := as.Explain(&pb.AuthExplainRequest{User: "foo", PermType: authpb.WRITE, Key: []byte("foo1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &pb.AuthExplainResponse{
+		Permitted: true,
+		Roles: []*pb.AuthRoleExplanation{
+			{Role: "role-test", Permitted: true, Matching: []*authpb.Permission{grants[0].perm}, NonMatching: []*authpb.Permission{grants[1].perm}},
+			{Role: "role-test-2", NonMatching: []*authpb.Permission{grants[2].perm}},
+		},
+	}
+	if !reflect.DeepEqual(resp, want) {
+		t.Fatalf("expected %+v, got %+v", want, resp)
+	}
+
+	// the range is only permitted by both roles together
+	resp, err = as.Explain(&pb.AuthExplainRequest{User: "foo", PermType: authpb.WRITE, Key: []byte("foo"), RangeEnd: []byte("foq")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Permitted || resp.Roles[0].Permitted || resp.Roles[1].Permitted || len(resp.Roles[1].Matching) != 1 {
+		t.Fatalf("expected the range to be permitted by both roles together, got %+v", resp)
+	}
+
+	if resp, err = as.Explain(&pb.AuthExplainRequest{User: "foo", PermType: authpb.DELETE, Key: []byte("bar")}); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Permitted || len(resp.Roles[0].Matching) != 0 {
+		t.Fatalf("expected delete of bar not to be permitted, got %+v", resp)
+	}
+
+	if _, err = as.Explain(&pb.AuthExplainRequest{User: "nobody", PermType: authpb.READ, Key: []byte("foo")}); err != ErrUserNotFound {
+		t.Fatalf("expected %v, got %v", ErrUserNotFound, err)
+	}
+	if _, err = as.Explain(&pb.AuthExplainRequest{User: "foo", PermType: authpb.READWRITE, Key: []byte("foo")}); err != ErrInvalidAuthMgmt {
+		t.Fatalf("expected %v, got %v", ErrInvalidAuthMgmt, err)
+	}
+}
+
 func TestGetUser(t *testing.T) {
 	as, tearDown := setupAuthStore(t)
 	defer tearDown(t)
@@ -558,6 +816,77 @@ func TestRoleGrantPermission(t *testing.T) {
 	assert.Equal(t, perm, r.Perm[0])
 }
 
+func TestRoleGrantFinerPermission(t *testing.T) {
+	as, tearDown := setupAuthStore(t)
+	defer tearDown(t)
+
+	_, err := as.RoleAdd(&pb.AuthRoleAddRequest{Name: "role-test-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, typ := range []authpb.Permission_Type{authpb.READ, authpb.CREATE, authpb.WRITE, authpb.WATCH, authpb.CREATE} {
+		_, err = as.RoleGrantPermission(&pb.AuthRoleGrantPermissionRequest{
+			Name: "role-test-1",
+			Perm: &authpb.Permission{PermType: typ, Key: []byte("a"), RangeEnd: []byte("b")},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err = as.RoleGrantPermission(&pb.AuthRoleGrantPermissionRequest{
+		Name: "role-test-1",
+		Perm: &authpb.Permission{PermType: authpb.Permission_Type(100), Key: []byte("a")},
+	})
+	if err != ErrInvalidAuthMgmt {
+		t.Fatalf("expected %v granting an unknown permission type, got %v", ErrInvalidAuthMgmt, err)
+	}
+
+	// WRITE replaces READ, while CREATE and WATCH are granted alongside
+	r, err := as.RoleGet(&pb.AuthRoleGetRequest{Role: "role-test-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var types []authpb.Permission_Type
+	for _, perm := range r.Perm {
+		types = append(types, perm.PermType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	if !reflect.DeepEqual(types, []authpb.Permission_Type{authpb.WRITE, authpb.CREATE, authpb.WATCH}) {
+		t.Fatalf("expected WRITE, CREATE and WATCH permissions, got %v", types)
+	}
+
+	_, err = as.UserGrantRole(&pb.AuthUserGrantRoleRequest{User: "foo", Role: "role-test-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ai := &AuthInfo{Username: "foo", Revision: as.Revision()}
+	if err = as.IsRangePermitted(ai, []byte("a"), nil); err != ErrPermissionDenied {
+		t.Errorf("expected range to be denied, got %v", err)
+	}
+	if err = as.IsWatchPermitted(ai, []byte("a"), []byte("b")); err != nil {
+		t.Errorf("expected watch to be permitted, got %v", err)
+	}
+	// WRITE implies the permissions to create, delete and lease
+	for _, check := range []func(*AuthInfo, []byte) error{as.IsPutPermitted, as.IsCreatePermitted, as.IsLeasePermitted} {
+		if err = check(ai, []byte("a")); err != nil {
+			t.Errorf("expected write to be permitted, got %v", err)
+		}
+	}
+	if err = as.IsDeleteRangePermitted(ai, []byte("a"), []byte("b")); err != nil {
+		t.Errorf("expected delete to be permitted, got %v", err)
+	}
+
+	// revoking the range revokes all its permissions
+	_, err = as.RoleRevokePermission(&pb.AuthRoleRevokePermissionRequest{Role: "role-test-1", Key: []byte("a"), RangeEnd: []byte("b")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ai.Revision = as.Revision()
+	if err = as.IsWatchPermitted(ai, []byte("a"), []byte("b")); err != ErrPermissionDenied {
+		t.Errorf("expected watch to be denied, got %v", err)
+	}
+}
+
 func TestRoleGrantInvalidPermission(t *testing.T) {
 	as, tearDown := setupAuthStore(t)
 	defer tearDown(t)
@@ -1181,6 +1510,12 @@ func testAuthInfoFromCtxWithRoot(t *testing.T, opts string) {
 	if ai.Username != "root" {
 		t.Errorf("expected user name 'root', got %+v", ai)
 	}
+
+	// an already resolved AuthInfo is returned as is
+	want := &AuthInfo{Username: "foo", Revision: 1}
+	if ai, aerr = as.AuthInfoFromCtx(WithAuthInfo(ctx, want)); aerr != nil || ai != want {
+		t.Errorf("expected %+v, got %+v (%v)", want, ai, aerr)
+	}
 }
 
 func TestUserNoPasswordAdd(t *testing.T) {
@@ -28,8 +28,12 @@ import (
 	"go.etcd.io/etcd/client/pkg/v3/transport"
 	"go.etcd.io/etcd/client/pkg/v3/types"
 	"go.etcd.io/etcd/pkg/v3/netutil"
+	"go.etcd.io/etcd/server/v3/auth"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3audit"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3compactor"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v3discovery"
 	"go.etcd.io/etcd/server/v3/storage/datadir"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
 
 	bolt "go.etcd.io/bbolt"
 )
@@ -126,6 +130,13 @@ type ServerConfig struct {
 	QuotaBackendBytes       int64
 	MaxTxnOps               uint
 
+	// CompactionPrefixRetention keeps the history of key prefixes for longer
+	// than AutoCompactionRetention.
+	CompactionPrefixRetention []v3compactor.PrefixRetention
+
+	// Audit configures the audit log of the mutations and admin operations.
+	Audit v3audit.Config
+
 	// MaxRequestBytes is the maximum request size to send over raft.
 	MaxRequestBytes uint
 
@@ -140,10 +151,16 @@ type ServerConfig struct {
 
 	// ClientCertAuthEnabled is true when cert has been signed by the client CA.
 	ClientCertAuthEnabled bool
+	// ClientCertIdentity derives the users and their roles from the fields of
+	// client certificates. The user is the CommonName if nil.
+	ClientCertIdentity *auth.CertIdentity
 
 	AuthToken  string
 	BcryptCost uint
-	TokenTTL   uint
+	// PasswordHash is the algorithm, and its options, auth passwords are
+	// hashed with. Passwords are hashed with bcrypt if empty.
+	PasswordHash string
+	TokenTTL     uint
 
 	// InitialCorruptCheck is true to check data corruption on boot
 	// before serving any peer/client traffic.
@@ -178,6 +195,8 @@ type ServerConfig struct {
 	ExperimentalTracerOptions []otelgrpc.Option
 
 	WatchProgressNotifyInterval time.Duration
+	// WatchBacklog limits the events held in memory for slow watchers.
+	WatchBacklog mvcc.WatchBacklogConfig
 
 	// UnsafeNoFsync disables all uses of fsync.
 	// Setting this is unsafe and will cause data loss.
@@ -204,6 +223,10 @@ type ServerConfig struct {
 	// ExperimentalMaxLearners sets a limit to the number of learner members that can exist in the cluster membership.
 	ExperimentalMaxLearners int `json:"experimental-max-learners"`
 
+	// ExperimentalLearnerSnapshot is the path of a snapshot seeding the empty data directory
+	// of a member joining an existing cluster, which it was added to as a learner.
+	ExperimentalLearnerSnapshot string `json:"experimental-learner-snapshot"`
+
 	// V2Deprecation defines a phase of v2store deprecation process.
 	V2Deprecation V2DeprecationEnum `json:"v2-deprecation"`
 }
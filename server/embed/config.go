@@ -69,6 +69,10 @@ const (
 	DefaultDowngradeCheckTime          = 5 * time.Second
 	DefaultWaitClusterReadyTimeout     = 5 * time.Second
 	DefaultAutoCompactionMode          = "periodic"
+	DefaultAuditLevel                  = "metadata"
+	DefaultAuditMaxSize                = 100
+	DefaultAuditMaxBackups             = 10
+	DefaultSlowWatcherPolicy           = "block"
 
 	DefaultDiscoveryDialTimeout      = 2 * time.Second
 	DefaultDiscoveryRequestTimeOut   = 5 * time.Second
@@ -342,6 +346,26 @@ type Config struct {
 	// ExperimentalCompactionSleepInterval is the sleep interval between every etcd compaction loop.
 	ExperimentalCompactionSleepInterval     time.Duration `json:"experimental-compaction-sleep-interval"`
 	ExperimentalWatchProgressNotifyInterval time.Duration `json:"experimental-watch-progress-notify-interval"`
+	// ExperimentalWatchStreamMaxPendingEvents and ExperimentalWatchStreamMaxPendingBytes
+	// limit the events held in memory for the watchers of a watch stream that are
+	// blocked on sending their events to the client. Zero means no limit.
+	ExperimentalWatchStreamMaxPendingEvents int `json:"experimental-watch-stream-max-pending-events"`
+	ExperimentalWatchStreamMaxPendingBytes  int `json:"experimental-watch-stream-max-pending-bytes"`
+	// ExperimentalWatchMaxPendingEvents and ExperimentalWatchMaxPendingBytes limit the
+	// events held in memory for all the blocked watchers. Zero means no limit.
+	ExperimentalWatchMaxPendingEvents int `json:"experimental-watch-max-pending-events"`
+	ExperimentalWatchMaxPendingBytes  int `json:"experimental-watch-max-pending-bytes"`
+	// ExperimentalSlowWatcherPolicy is what happens to the blocked watchers whose
+	// events exceed the pending limits: 'block' reads their events again from the
+	// backend once they catch up, 'drop' cancels them so that their clients resync,
+	// and 'coalesce' only keeps the latest event of each key.
+	ExperimentalSlowWatcherPolicy string `json:"experimental-slow-watcher-policy"`
+	// ExperimentalCompactionPrefixRetention is a comma separated list of
+	// '<prefix>=<retention>' pairs that keep the history of key prefixes for
+	// longer than AutoCompactionRetention. A retention with a time unit
+	// (e.g. '72h') keeps that duration of history, and a plain number
+	// (e.g. '10000') keeps that many revisions. Prefixes must not overlap.
+	ExperimentalCompactionPrefixRetention string `json:"experimental-compaction-prefix-retention"`
 	// ExperimentalWarningApplyDuration is the time duration after which a warning is generated if applying request
 	// takes more time than this value.
 	ExperimentalWarningApplyDuration time.Duration `json:"experimental-warning-apply-duration"`
@@ -355,6 +379,46 @@ type Config struct {
 	ExperimentalWarningUnaryRequestDuration time.Duration `json:"experimental-warning-unary-request-duration"`
 	// ExperimentalMaxLearners sets a limit to the number of learner members that can exist in the cluster membership.
 	ExperimentalMaxLearners int `json:"experimental-max-learners"`
+	// ExperimentalLearnerSnapshot is the path of a snapshot, saved from the cluster the member
+	// joins, seeding its data directory if empty. The member must have been added to the
+	// cluster as a learner, and resumes raft from the snapshot so that the leader only sends
+	// it the entries committed since, rather than a snapshot of its own.
+	ExperimentalLearnerSnapshot string `json:"experimental-learner-snapshot"`
+
+	// ExperimentalAuditSink is where the audit log of the mutations and admin
+	// operations is written, either 'file' or 'syslog'. Auditing is disabled if empty.
+	ExperimentalAuditSink string `json:"experimental-audit-sink"`
+	// ExperimentalAuditFile is the path of the JSON lines audit log of the 'file' sink.
+	ExperimentalAuditFile string `json:"experimental-audit-file"`
+	// ExperimentalAuditMaxSize is the size in megabytes at which the audit log file is rotated.
+	ExperimentalAuditMaxSize int `json:"experimental-audit-max-size"`
+	// ExperimentalAuditMaxBackups is the number of rotated audit log files to retain.
+	ExperimentalAuditMaxBackups int `json:"experimental-audit-max-backups"`
+	// ExperimentalAuditLevel is the verbosity of the audit records of the RPCs
+	// changing the cluster: 'none', 'metadata' or 'request'. Read-only RPCs are
+	// not audited unless enabled by ExperimentalAuditRPCLevels or
+	// ExperimentalAuditPrefixLevels.
+	ExperimentalAuditLevel string `json:"experimental-audit-level"`
+	// ExperimentalAuditRPCLevels is a comma separated list of '<rpc>=<level>'
+	// pairs overriding the audit level of RPCs by name (e.g. 'Range=request').
+	ExperimentalAuditRPCLevels string `json:"experimental-audit-rpc-levels"`
+	// ExperimentalAuditPrefixLevels is a comma separated list of
+	// '<prefix>=<level>' pairs overriding the audit level of the requests
+	// accessing keys under a prefix (e.g. '/secrets/=request').
+	ExperimentalAuditPrefixLevels string `json:"experimental-audit-prefix-levels"`
+	// ExperimentalClientCertIdentity is a JSON document of the rules deriving
+	// the users, and the roles granted to them, from the fields of the client
+	// certificates rather than from their CommonName, such as
+	// '{"user":[{"field":"uri","match":"^spiffe://example.org/(.+)$","replace":"$1"}],
+	// "roles":[{"field":"ou","match":"^(.+)$","role":"ou-$1"}]}'. It requires
+	// client certificate authentication.
+	ExperimentalClientCertIdentity string `json:"experimental-client-cert-identity"`
+	// ExperimentalPasswordHash is the algorithm auth passwords are hashed
+	// with, 'bcrypt' or 'argon2id' followed by its options, such as
+	// 'argon2id,time=1,memory=65536,threads=4'. The passwords hashed with
+	// another algorithm or other options are rehashed when their users
+	// authenticate.
+	ExperimentalPasswordHash string `json:"experimental-password-hash"`
 
 	// ForceNewCluster starts a new cluster even if previously started; unsafe.
 	ForceNewCluster bool `json:"force-new-cluster"`
@@ -483,6 +547,12 @@ func NewConfig() *Config {
 		MaxConcurrentStreams:             DefaultMaxConcurrentStreams,
 		ExperimentalWarningApplyDuration: DefaultWarningApplyDuration,
 
+		ExperimentalAuditLevel:      DefaultAuditLevel,
+		ExperimentalAuditMaxSize:    DefaultAuditMaxSize,
+		ExperimentalAuditMaxBackups: DefaultAuditMaxBackups,
+
+		ExperimentalSlowWatcherPolicy: DefaultSlowWatcherPolicy,
+
 		GRPCKeepAliveMinTime:  DefaultGRPCKeepAliveMinTime,
 		GRPCKeepAliveInterval: DefaultGRPCKeepAliveInterval,
 		GRPCKeepAliveTimeout:  DefaultGRPCKeepAliveTimeout,
@@ -729,6 +799,9 @@ func (cfg *Config) Validate() error {
 	if cfg.ClusterState != ClusterStateFlagNew && cfg.ClusterState != ClusterStateFlagExisting {
 		return fmt.Errorf("unexpected clusterState %q", cfg.ClusterState)
 	}
+	if cfg.ExperimentalLearnerSnapshot != "" && cfg.ClusterState != ClusterStateFlagExisting {
+		return fmt.Errorf("learner snapshot requires clusterState %q", ClusterStateFlagExisting)
+	}
 
 	if nSet > 1 {
 		return ErrConflictBootstrapFlags
@@ -30,6 +30,8 @@ import (
 	"go.etcd.io/etcd/client/pkg/v3/srv"
 	"go.etcd.io/etcd/client/pkg/v3/transport"
 	"go.etcd.io/etcd/client/pkg/v3/types"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3audit"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3compactor"
 
 	"sigs.k8s.io/yaml"
 )
@@ -225,6 +227,73 @@ func TestAutoCompactionModeParse(t *testing.T) {
 	}
 }
 
+func TestCompactionPrefixRetentionParse(t *testing.T) {
+	tests := []struct {
+		retention string
+		werr      bool
+		wret      []v3compactor.PrefixRetention
+	}{
+		{"", false, nil},
+		{"/events/=1h", false, []v3compactor.PrefixRetention{{Prefix: "/events/", Period: time.Hour}}},
+		{
+			"/events/=1h,/config/=1000",
+			false,
+			[]v3compactor.PrefixRetention{{Prefix: "/events/", Period: time.Hour}, {Prefix: "/config/", Revisions: 1000}},
+		},
+		{"a=b=10", false, []v3compactor.PrefixRetention{{Prefix: "a=b", Revisions: 10}}},
+		{"/events/", true, nil},
+		{"=1h", true, nil},
+		{"/events/=a", true, nil},
+		{"/events/=0", true, nil},
+		{"/events/=-1h", true, nil},
+		{"/a=1h,/a/b=1h", true, nil},
+	}
+
+	for i, tt := range tests {
+		ret, err := parseCompactionPrefixRetention(tt.retention)
+		assert.Equalf(t, tt.werr, err != nil, "#%d: err = %v", i, err)
+		assert.Equalf(t, tt.wret, ret, "#%d", i)
+	}
+}
+
+func TestAuditConfigParse(t *testing.T) {
+	tests := []struct {
+		sink, level, rpcLevels, prefixLevels string
+		werr                                 bool
+		wcfg                                 v3audit.Config
+	}{
+		{"", "bogus", "", "", false, v3audit.Config{}},
+		{"file", "", "", "", false, v3audit.Config{Sink: "file", Level: v3audit.LevelMetadata}},
+		{
+			"syslog", "request", "Range=metadata,Put=none", "/secrets/=request,/a=b=none",
+			false,
+			v3audit.Config{
+				Sink:         "syslog",
+				Level:        v3audit.LevelRequest,
+				RPCLevels:    map[string]v3audit.Level{"Range": v3audit.LevelMetadata, "Put": v3audit.LevelNone},
+				PrefixLevels: map[string]v3audit.Level{"/secrets/": v3audit.LevelRequest, "/a=b": v3audit.LevelNone},
+			},
+		},
+		{"file", "bogus", "", "", true, v3audit.Config{}},
+		{"file", "", "Range", "", true, v3audit.Config{}},
+		{"file", "", "", "/secrets/=all", true, v3audit.Config{}},
+	}
+
+	for i, tt := range tests {
+		cfg := Config{
+			ExperimentalAuditSink:         tt.sink,
+			ExperimentalAuditLevel:        tt.level,
+			ExperimentalAuditRPCLevels:    tt.rpcLevels,
+			ExperimentalAuditPrefixLevels: tt.prefixLevels,
+		}
+		ac, err := cfg.auditConfig()
+		assert.Equalf(t, tt.werr, err != nil, "#%d: err = %v", i, err)
+		if err == nil {
+			assert.Equalf(t, tt.wcfg, ac, "#%d", i)
+		}
+	}
+}
+
 func TestPeerURLsMapAndTokenFromSRV(t *testing.T) {
 	defer func() { getCluster = srv.GetCluster }()
 
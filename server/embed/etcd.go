@@ -37,11 +37,15 @@ import (
 	"go.etcd.io/etcd/client/v3/credentials"
 	"go.etcd.io/etcd/pkg/v3/debugutil"
 	runtimeutil "go.etcd.io/etcd/pkg/v3/runtime"
+	"go.etcd.io/etcd/server/v3/auth"
 	"go.etcd.io/etcd/server/v3/config"
 	"go.etcd.io/etcd/server/v3/etcdserver"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/etcdhttp"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/rafthttp"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3audit"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3compactor"
 	"go.etcd.io/etcd/server/v3/storage"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
 	"go.etcd.io/etcd/server/v3/verify"
 
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
@@ -163,6 +167,22 @@ func StartEtcd(inCfg *Config) (e *Etcd, err error) {
 	if err != nil {
 		return e, err
 	}
+	compactionPrefixRetention, err := parseCompactionPrefixRetention(cfg.ExperimentalCompactionPrefixRetention)
+	if err != nil {
+		return e, err
+	}
+	audit, err := cfg.auditConfig()
+	if err != nil {
+		return e, err
+	}
+	watchBacklog, err := cfg.watchBacklogConfig()
+	if err != nil {
+		return e, err
+	}
+	clientCertIdentity, err := cfg.clientCertIdentity()
+	if err != nil {
+		return e, err
+	}
 
 	backendFreelistType := parseBackendFreelistType(cfg.BackendFreelistType)
 
@@ -189,6 +209,8 @@ func StartEtcd(inCfg *Config) (e *Etcd, err error) {
 		InitialElectionTickAdvance:               cfg.InitialElectionTickAdvance,
 		AutoCompactionRetention:                  autoCompactionRetention,
 		AutoCompactionMode:                       cfg.AutoCompactionMode,
+		CompactionPrefixRetention:                compactionPrefixRetention,
+		Audit:                                    audit,
 		QuotaBackendBytes:                        cfg.QuotaBackendBytes,
 		BackendBatchLimit:                        cfg.BackendBatchLimit,
 		BackendFreelistType:                      backendFreelistType,
@@ -199,8 +221,10 @@ func StartEtcd(inCfg *Config) (e *Etcd, err error) {
 		SocketOpts:                               cfg.SocketOpts,
 		StrictReconfigCheck:                      cfg.StrictReconfigCheck,
 		ClientCertAuthEnabled:                    cfg.ClientTLSInfo.ClientCertAuth,
+		ClientCertIdentity:                       clientCertIdentity,
 		AuthToken:                                cfg.AuthToken,
 		BcryptCost:                               cfg.BcryptCost,
+		PasswordHash:                             cfg.ExperimentalPasswordHash,
 		TokenTTL:                                 cfg.AuthTokenTTL,
 		CORS:                                     cfg.CORS,
 		HostWhitelist:                            cfg.HostWhitelist,
@@ -219,6 +243,7 @@ func StartEtcd(inCfg *Config) (e *Etcd, err error) {
 		CompactionBatchLimit:                     cfg.ExperimentalCompactionBatchLimit,
 		CompactionSleepInterval:                  cfg.ExperimentalCompactionSleepInterval,
 		WatchProgressNotifyInterval:              cfg.ExperimentalWatchProgressNotifyInterval,
+		WatchBacklog:                             watchBacklog,
 		DowngradeCheckTime:                       cfg.ExperimentalDowngradeCheckTime,
 		WarningApplyDuration:                     cfg.ExperimentalWarningApplyDuration,
 		WarningUnaryRequestDuration:              cfg.WarningUnaryRequestDuration,
@@ -226,6 +251,7 @@ func StartEtcd(inCfg *Config) (e *Etcd, err error) {
 		ExperimentalTxnModeWriteWithSharedBuffer: cfg.ExperimentalTxnModeWriteWithSharedBuffer,
 		ExperimentalBootstrapDefragThresholdMegabytes: cfg.ExperimentalBootstrapDefragThresholdMegabytes,
 		ExperimentalMaxLearners:                       cfg.ExperimentalMaxLearners,
+		ExperimentalLearnerSnapshot:                   cfg.ExperimentalLearnerSnapshot,
 		V2Deprecation:                                 cfg.V2DeprecationEffective(),
 	}
 
@@ -356,6 +382,19 @@ func print(lg *zap.Logger, ec Config, sc config.ServerConfig, memberInitialized
 		zap.String("auto-compaction-mode", sc.AutoCompactionMode),
 		zap.Duration("auto-compaction-retention", sc.AutoCompactionRetention),
 		zap.String("auto-compaction-interval", sc.AutoCompactionRetention.String()),
+		zap.String("compaction-prefix-retention", ec.ExperimentalCompactionPrefixRetention),
+		zap.String("audit-sink", ec.ExperimentalAuditSink),
+		zap.String("audit-file", ec.ExperimentalAuditFile),
+		zap.String("audit-level", ec.ExperimentalAuditLevel),
+		zap.String("audit-rpc-levels", ec.ExperimentalAuditRPCLevels),
+		zap.String("audit-prefix-levels", ec.ExperimentalAuditPrefixLevels),
+		zap.String("client-cert-identity", ec.ExperimentalClientCertIdentity),
+		zap.String("password-hash", ec.ExperimentalPasswordHash),
+		zap.Int("watch-stream-max-pending-events", sc.WatchBacklog.MaxStreamEvents),
+		zap.Int("watch-stream-max-pending-bytes", sc.WatchBacklog.MaxStreamBytes),
+		zap.Int("watch-max-pending-events", sc.WatchBacklog.MaxEvents),
+		zap.Int("watch-max-pending-bytes", sc.WatchBacklog.MaxBytes),
+		zap.String("slow-watcher-policy", string(sc.WatchBacklog.Policy)),
 		zap.String("discovery-url", sc.DiscoveryURL),
 		zap.String("discovery-proxy", sc.DiscoveryProxy),
 
@@ -374,6 +413,7 @@ func print(lg *zap.Logger, ec Config, sc config.ServerConfig, memberInitialized
 
 		zap.String("downgrade-check-interval", sc.DowngradeCheckTime.String()),
 		zap.Int("max-learners", sc.ExperimentalMaxLearners),
+		zap.String("learner-snapshot", sc.ExperimentalLearnerSnapshot),
 	)
 }
 
@@ -879,6 +919,118 @@ func (e *Etcd) GetLogger() *zap.Logger {
 	return l
 }
 
+// parseCompactionPrefixRetention parses a comma separated list of
+// '<prefix>=<retention>' pairs.
+func parseCompactionPrefixRetention(s string) ([]v3compactor.PrefixRetention, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var ret []v3compactor.PrefixRetention
+	for _, pair := range strings.Split(s, ",") {
+		i := strings.LastIndex(pair, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid compaction prefix retention %q", pair)
+		}
+		pr := v3compactor.PrefixRetention{Prefix: pair[:i]}
+		v := pair[i+1:]
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			pr.Revisions = n
+		} else if pr.Period, err = time.ParseDuration(v); err != nil {
+			return nil, fmt.Errorf("error parsing compaction prefix retention %q: %v", pair, err)
+		}
+		if pr.Revisions <= 0 && pr.Period <= 0 {
+			return nil, fmt.Errorf("compaction prefix retention %q must be positive", pair)
+		}
+		for _, p := range ret {
+			if strings.HasPrefix(p.Prefix, pr.Prefix) || strings.HasPrefix(pr.Prefix, p.Prefix) {
+				return nil, fmt.Errorf("compaction prefix retention %q overlaps with prefix %q", pair, p.Prefix)
+			}
+		}
+		ret = append(ret, pr)
+	}
+	return ret, nil
+}
+
+// auditConfig parses the audit log configuration.
+func (cfg *Config) auditConfig() (ac v3audit.Config, err error) {
+	ac = v3audit.Config{
+		Sink:       cfg.ExperimentalAuditSink,
+		File:       cfg.ExperimentalAuditFile,
+		MaxSize:    cfg.ExperimentalAuditMaxSize,
+		MaxBackups: cfg.ExperimentalAuditMaxBackups,
+	}
+	if ac.Sink == "" {
+		return ac, nil
+	}
+	level := cfg.ExperimentalAuditLevel
+	if level == "" {
+		level = DefaultAuditLevel
+	}
+	if ac.Level, err = v3audit.ParseLevel(level); err != nil {
+		return ac, err
+	}
+	if ac.RPCLevels, err = parseAuditLevels(cfg.ExperimentalAuditRPCLevels); err != nil {
+		return ac, err
+	}
+	if ac.PrefixLevels, err = parseAuditLevels(cfg.ExperimentalAuditPrefixLevels); err != nil {
+		return ac, err
+	}
+	return ac, nil
+}
+
+// watchBacklogConfig returns the limits of the events held for slow watchers.
+func (cfg *Config) watchBacklogConfig() (mvcc.WatchBacklogConfig, error) {
+	wc := mvcc.WatchBacklogConfig{
+		MaxStreamEvents: cfg.ExperimentalWatchStreamMaxPendingEvents,
+		MaxStreamBytes:  cfg.ExperimentalWatchStreamMaxPendingBytes,
+		MaxEvents:       cfg.ExperimentalWatchMaxPendingEvents,
+		MaxBytes:        cfg.ExperimentalWatchMaxPendingBytes,
+		Policy:          mvcc.SlowWatcherPolicy(cfg.ExperimentalSlowWatcherPolicy),
+	}
+	if wc.MaxStreamEvents < 0 || wc.MaxStreamBytes < 0 || wc.MaxEvents < 0 || wc.MaxBytes < 0 {
+		return wc, fmt.Errorf("watch pending limits must be >= 0")
+	}
+	switch wc.Policy {
+	case "":
+		wc.Policy = mvcc.SlowWatcherBlock
+	case mvcc.SlowWatcherBlock, mvcc.SlowWatcherDrop, mvcc.SlowWatcherCoalesce:
+	default:
+		return wc, fmt.Errorf("unknown slow watcher policy %q", wc.Policy)
+	}
+	return wc, nil
+}
+
+// clientCertIdentity parses the rules identifying the users of client certificates.
+func (cfg *Config) clientCertIdentity() (*auth.CertIdentity, error) {
+	if cfg.ExperimentalClientCertIdentity == "" {
+		return nil, nil
+	}
+	if !cfg.ClientTLSInfo.ClientCertAuth {
+		return nil, fmt.Errorf("client certificate identity requires client certificate authentication")
+	}
+	return auth.ParseCertIdentity(cfg.ExperimentalClientCertIdentity)
+}
+
+// parseAuditLevels parses a comma separated list of '<name>=<level>' pairs.
+func parseAuditLevels(s string) (map[string]v3audit.Level, error) {
+	if s == "" {
+		return nil, nil
+	}
+	ret := make(map[string]v3audit.Level)
+	for _, pair := range strings.Split(s, ",") {
+		i := strings.LastIndex(pair, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("invalid audit level %q", pair)
+		}
+		l, err := v3audit.ParseLevel(pair[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("error parsing audit level %q: %v", pair, err)
+		}
+		ret[pair[:i]] = l
+	}
+	return ret, nil
+}
+
 func parseCompactionRetention(mode, retention string) (ret time.Duration, err error) {
 	h, err := strconv.Atoi(retention)
 	if err == nil && h >= 0 {
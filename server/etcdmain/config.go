@@ -202,6 +202,7 @@ func newConfig() *config {
 	fs.StringVar(&cfg.ec.ClientTLSInfo.ClientCertFile, "client-cert-file", "", "Path to an explicit peer client TLS cert file otherwise cert file will be used when client auth is required.")
 	fs.StringVar(&cfg.ec.ClientTLSInfo.ClientKeyFile, "client-key-file", "", "Path to an explicit peer client TLS key file otherwise key file will be used when client auth is required.")
 	fs.BoolVar(&cfg.ec.ClientTLSInfo.ClientCertAuth, "client-cert-auth", false, "Enable client cert authentication.")
+	fs.StringVar(&cfg.ec.ExperimentalClientCertIdentity, "experimental-client-cert-identity", "", "JSON rules deriving the users, and the roles granted to them, from the fields of client certificates rather than from their CommonName.")
 	fs.StringVar(&cfg.ec.ClientTLSInfo.CRLFile, "client-crl-file", "", "Path to the client certificate revocation list file.")
 	fs.StringVar(&cfg.ec.ClientTLSInfo.AllowedHostname, "client-cert-allowed-hostname", "", "Allowed TLS hostname for client cert authentication.")
 	fs.StringVar(&cfg.ec.ClientTLSInfo.TrustedCAFile, "trusted-ca-file", "", "Path to the client server TLS trusted CA cert file.")
@@ -259,6 +260,7 @@ func newConfig() *config {
 	// auth
 	fs.StringVar(&cfg.ec.AuthToken, "auth-token", cfg.ec.AuthToken, "Specify auth token specific options.")
 	fs.UintVar(&cfg.ec.BcryptCost, "bcrypt-cost", cfg.ec.BcryptCost, "Specify bcrypt algorithm cost factor for auth password hashing.")
+	fs.StringVar(&cfg.ec.ExperimentalPasswordHash, "experimental-password-hash", "", "Algorithm of auth password hashing, 'bcrypt' or 'argon2id' followed by its options (e.g. 'argon2id,time=1,memory=65536,threads=4').")
 	fs.UintVar(&cfg.ec.AuthTokenTTL, "auth-token-ttl", cfg.ec.AuthTokenTTL, "The lifetime in seconds of the auth token.")
 
 	// gateway
@@ -275,7 +277,13 @@ func newConfig() *config {
 	fs.BoolVar(&cfg.ec.ExperimentalEnableLeaseCheckpointPersist, "experimental-enable-lease-checkpoint-persist", false, "Enable persisting remainingTTL to prevent indefinite auto-renewal of long lived leases. Always enabled in v3.6. Should be used to ensure smooth upgrade from v3.5 clusters with this feature enabled. Requires experimental-enable-lease-checkpoint to be enabled.")
 	fs.IntVar(&cfg.ec.ExperimentalCompactionBatchLimit, "experimental-compaction-batch-limit", cfg.ec.ExperimentalCompactionBatchLimit, "Sets the maximum revisions deleted in each compaction batch.")
 	fs.DurationVar(&cfg.ec.ExperimentalCompactionSleepInterval, "experimental-compaction-sleep-interval", cfg.ec.ExperimentalCompactionSleepInterval, "Sets the sleep interval between each compaction batch.")
+	fs.StringVar(&cfg.ec.ExperimentalCompactionPrefixRetention, "experimental-compaction-prefix-retention", "", "Comma separated '<prefix>=<retention>' pairs that keep the history of key prefixes for longer than 'auto-compaction-retention'. A retention with a time unit (e.g. '72h') keeps that duration of history, a plain number (e.g. '10000') keeps that many revisions.")
 	fs.DurationVar(&cfg.ec.ExperimentalWatchProgressNotifyInterval, "experimental-watch-progress-notify-interval", cfg.ec.ExperimentalWatchProgressNotifyInterval, "Duration of periodic watch progress notifications.")
+	fs.IntVar(&cfg.ec.ExperimentalWatchStreamMaxPendingEvents, "experimental-watch-stream-max-pending-events", 0, "Maximum number of events held in memory for the blocked watchers of a watch stream, 0 for no limit.")
+	fs.IntVar(&cfg.ec.ExperimentalWatchStreamMaxPendingBytes, "experimental-watch-stream-max-pending-bytes", 0, "Maximum size in bytes of the events held in memory for the blocked watchers of a watch stream, 0 for no limit.")
+	fs.IntVar(&cfg.ec.ExperimentalWatchMaxPendingEvents, "experimental-watch-max-pending-events", 0, "Maximum number of events held in memory for all the blocked watchers, 0 for no limit.")
+	fs.IntVar(&cfg.ec.ExperimentalWatchMaxPendingBytes, "experimental-watch-max-pending-bytes", 0, "Maximum size in bytes of the events held in memory for all the blocked watchers, 0 for no limit.")
+	fs.StringVar(&cfg.ec.ExperimentalSlowWatcherPolicy, "experimental-slow-watcher-policy", cfg.ec.ExperimentalSlowWatcherPolicy, "Policy of the blocked watchers whose events exceed the pending limits, 'block', 'drop' or 'coalesce'.")
 	fs.DurationVar(&cfg.ec.ExperimentalDowngradeCheckTime, "experimental-downgrade-check-time", cfg.ec.ExperimentalDowngradeCheckTime, "Duration of time between two downgrade status check.")
 	fs.DurationVar(&cfg.ec.ExperimentalWarningApplyDuration, "experimental-warning-apply-duration", cfg.ec.ExperimentalWarningApplyDuration, "Time duration after which a warning is generated if request takes more time.")
 	fs.DurationVar(&cfg.ec.WarningUnaryRequestDuration, "warning-unary-request-duration", cfg.ec.WarningUnaryRequestDuration, "Time duration after which a warning is generated if a unary request takes more time.")
@@ -284,6 +292,13 @@ func newConfig() *config {
 	fs.BoolVar(&cfg.ec.ExperimentalTxnModeWriteWithSharedBuffer, "experimental-txn-mode-write-with-shared-buffer", true, "Enable the write transaction to use a shared buffer in its readonly check operations.")
 	fs.UintVar(&cfg.ec.ExperimentalBootstrapDefragThresholdMegabytes, "experimental-bootstrap-defrag-threshold-megabytes", 0, "Enable the defrag during etcd server bootstrap on condition that it will free at least the provided threshold of disk space. Needs to be set to non-zero value to take effect.")
 	fs.IntVar(&cfg.ec.ExperimentalMaxLearners, "experimental-max-learners", membership.DefaultMaxLearners, "Sets the maximum number of learners that can be available in the cluster membership.")
+	fs.StringVar(&cfg.ec.ExperimentalAuditSink, "experimental-audit-sink", "", "Sink of the audit log of the mutations and admin operations, 'file' or 'syslog'. Auditing is disabled if empty.")
+	fs.StringVar(&cfg.ec.ExperimentalAuditFile, "experimental-audit-file", "", "Path of the JSON lines audit log of the 'file' sink.")
+	fs.IntVar(&cfg.ec.ExperimentalAuditMaxSize, "experimental-audit-max-size", cfg.ec.ExperimentalAuditMaxSize, "Size in megabytes at which the audit log file is rotated.")
+	fs.IntVar(&cfg.ec.ExperimentalAuditMaxBackups, "experimental-audit-max-backups", cfg.ec.ExperimentalAuditMaxBackups, "Number of rotated audit log files to retain.")
+	fs.StringVar(&cfg.ec.ExperimentalAuditLevel, "experimental-audit-level", cfg.ec.ExperimentalAuditLevel, "Audit level of the RPCs changing the cluster, 'none', 'metadata' or 'request'. Read-only RPCs are not audited unless enabled per RPC or per prefix.")
+	fs.StringVar(&cfg.ec.ExperimentalAuditRPCLevels, "experimental-audit-rpc-levels", "", "Comma separated '<rpc>=<level>' pairs overriding the audit level of RPCs by name (e.g. 'Range=request').")
+	fs.StringVar(&cfg.ec.ExperimentalAuditPrefixLevels, "experimental-audit-prefix-levels", "", "Comma separated '<prefix>=<level>' pairs overriding the audit level of the requests accessing keys under a prefix (e.g. '/secrets/=request').")
 	fs.DurationVar(&cfg.ec.ExperimentalWaitClusterReadyTimeout, "experimental-wait-cluster-ready-timeout", cfg.ec.ExperimentalWaitClusterReadyTimeout, "Maximum duration to wait for the cluster to be ready.")
 	fs.Uint64Var(&cfg.ec.SnapshotCatchUpEntries, "experimental-snapshot-catchup-entries", cfg.ec.SnapshotCatchUpEntries, "Number of entries for a slow follower to catch up after compacting the raft storage entries.")
 
@@ -27,6 +27,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
@@ -97,6 +98,16 @@ var (
 	grpcProxyEnableOrdering bool
 	grpcProxyEnableLogging  bool
 
+	grpcProxyAuthMirror            bool
+	grpcProxyAuthMirrorIdentityTTL time.Duration
+
+	grpcProxyReplica        bool
+	grpcProxyReplicaPrefix  string
+	grpcProxyReplicaHistory int64
+
+	grpcProxyFederationBackends []string
+	grpcProxyFederationRoutes   []string
+
 	grpcProxyDebug bool
 
 	// GRPC keep alive related options.
@@ -109,6 +120,10 @@ var (
 
 const defaultGRPCMaxCallSendMsgSize = 1.5 * 1024 * 1024
 
+// defaultFederationBackend is the name of the cluster of --endpoints in the
+// federation, which serves the keys not routed to another backend.
+const defaultFederationBackend = "default"
+
 func init() {
 	rootCmd.AddCommand(newGRPCProxyCommand())
 }
@@ -169,6 +184,13 @@ func newGRPCProxyStartCommand() *cobra.Command {
 	cmd.Flags().BoolVar(&grpcProxyEnableOrdering, "experimental-serializable-ordering", false, "Ensure serializable reads have monotonically increasing store revisions across endpoints.")
 	cmd.Flags().StringVar(&grpcProxyLeasing, "experimental-leasing-prefix", "", "leasing metadata prefix for disconnected linearized reads.")
 	cmd.Flags().BoolVar(&grpcProxyEnableLogging, "experimental-enable-grpc-logging", false, "logging all grpc requests and responses")
+	cmd.Flags().BoolVar(&grpcProxyAuthMirror, "experimental-auth-mirror", false, "Sync the auth store of the cluster to check the permissions of cached reads and watches locally. Requires the proxy client to be root.")
+	cmd.Flags().DurationVar(&grpcProxyAuthMirrorIdentityTTL, "experimental-auth-mirror-identity-ttl", 5*time.Second, "Time to cache the users of the auth tokens resolved by the auth mirror.")
+	cmd.Flags().BoolVar(&grpcProxyReplica, "experimental-replica", false, "Keep an in-memory replica of the keys under the replica prefix, to serve linearizable and historic reads of them without forwarding. Requires --experimental-auth-mirror.")
+	cmd.Flags().StringVar(&grpcProxyReplicaPrefix, "experimental-replica-prefix", "", "Prefix of the keys replicated by the proxy, all the keys if empty.")
+	cmd.Flags().Int64Var(&grpcProxyReplicaHistory, "experimental-replica-history", 1000, "Number of revisions of history the replica keeps to serve historic reads.")
+	cmd.Flags().StringArrayVar(&grpcProxyFederationBackends, "experimental-federation-backend", nil, "Backend cluster of the federation, as '<name>=<endpoint>,<endpoint>'. Can be repeated.")
+	cmd.Flags().StringArrayVar(&grpcProxyFederationRoutes, "experimental-federation-route", nil, "Route of the keys under a prefix to a backend cluster of the federation, as '<prefix>=<name>'. Can be repeated. The other keys are routed to the cluster of --endpoints, which alone serves lease and auth requests: its leases and auth tokens are rejected for the keys of other backends.")
 
 	cmd.Flags().BoolVar(&grpcProxyDebug, "debug", false, "Enable debug-level logging for grpc-proxy.")
 
@@ -233,7 +255,12 @@ func startGRPCProxy(cmd *cobra.Command, args []string) {
 	}
 	httpClient := mustNewHTTPClient(lg)
 
-	srvhttp, httpl := mustHTTPListener(lg, m, tlsInfo, client, proxyClient)
+	var rt *grpcproxy.Router
+	if len(grpcProxyFederationRoutes) > 0 {
+		rt = mustNewRouter(lg, client)
+	}
+
+	srvhttp, httpl := mustHTTPListener(lg, m, tlsInfo, client, proxyClient, rt)
 
 	if err := http2.ConfigureServer(srvhttp, &http2.Server{
 		MaxConcurrentStreams: maxConcurrentStreams,
@@ -242,7 +269,7 @@ func startGRPCProxy(cmd *cobra.Command, args []string) {
 	}
 
 	errc := make(chan error, 3)
-	go func() { errc <- newGRPCProxyServer(lg, client).Serve(grpcl) }()
+	go func() { errc <- newGRPCProxyServer(lg, client, rt).Serve(grpcl) }()
 	go func() { errc <- srvhttp.Serve(httpl) }()
 	go func() { errc <- m.Serve() }()
 	if len(grpcProxyMetricsListenAddr) > 0 {
@@ -253,6 +280,9 @@ func startGRPCProxy(cmd *cobra.Command, args []string) {
 			grpcproxy.HandleHealth(lg, mux, client)
 			grpcproxy.HandleProxyMetrics(mux)
 			grpcproxy.HandleProxyHealth(lg, mux, proxyClient)
+			if rt != nil {
+				grpcproxy.HandleFederationHealth(lg, mux, rt)
+			}
 			lg.Info("gRPC proxy server metrics URL serving")
 			herr := http.Serve(mhttpl, mux)
 			if herr != nil {
@@ -285,6 +315,29 @@ func checkArgs() {
 		fmt.Fprintln(os.Stderr, fmt.Errorf("invalid advertise-client-url %q", grpcProxyAdvertiseClientURL))
 		os.Exit(1)
 	}
+	if grpcProxyAuthMirror && grpcProxyNamespace != "" {
+		fmt.Fprintln(os.Stderr, fmt.Errorf("experimental-auth-mirror cannot be used with namespace"))
+		os.Exit(1)
+	}
+	if grpcProxyReplica && !grpcProxyAuthMirror {
+		// the replica serves linearizable and historic reads, which are only checked by the auth mirror
+		fmt.Fprintln(os.Stderr, fmt.Errorf("experimental-replica requires experimental-auth-mirror"))
+		os.Exit(1)
+	}
+	if grpcProxyReplica && grpcProxyReplicaHistory < 1 {
+		fmt.Fprintln(os.Stderr, fmt.Errorf("invalid experimental-replica-history %d", grpcProxyReplicaHistory))
+		os.Exit(1)
+	}
+	if len(grpcProxyFederationBackends) > 0 || len(grpcProxyFederationRoutes) > 0 {
+		if _, _, err := parseFederation(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		if grpcProxyNamespace != "" || grpcProxyLeasing != "" || grpcProxyAuthMirror || grpcProxyReplica {
+			fmt.Fprintln(os.Stderr, fmt.Errorf("experimental-federation-route cannot be used with namespace, experimental-leasing-prefix, experimental-auth-mirror or experimental-replica"))
+			os.Exit(1)
+		}
+	}
 	if grpcProxyListenAutoTLS && selfSignedCertValidity == 0 {
 		fmt.Fprintln(os.Stderr, fmt.Errorf("selfSignedCertValidity is invalid,it should be greater than 0"))
 		os.Exit(1)
@@ -297,6 +350,10 @@ func mustNewClient(lg *zap.Logger) *clientv3.Client {
 	if len(eps) == 0 {
 		eps = grpcProxyEndpoints
 	}
+	return mustNewClientWithEndpoints(lg, eps)
+}
+
+func mustNewClientWithEndpoints(lg *zap.Logger, eps []string) *clientv3.Client {
 	cfg, err := newClientCfg(lg, eps)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -315,6 +372,60 @@ func mustNewClient(lg *zap.Logger) *clientv3.Client {
 	return client
 }
 
+// parseFederation returns the endpoints of the backends of the federation by
+// name, and the backend names of the routed prefixes.
+func parseFederation() (map[string][]string, map[string]string, error) {
+	backends := make(map[string][]string)
+	for _, v := range grpcProxyFederationBackends {
+		name, eps, ok := strings.Cut(v, "=")
+		if !ok || name == "" || eps == "" {
+			return nil, nil, fmt.Errorf("invalid experimental-federation-backend %q", v)
+		}
+		if name == defaultFederationBackend {
+			return nil, nil, fmt.Errorf("experimental-federation-backend name %q is reserved for the cluster of --endpoints", name)
+		}
+		if _, ok := backends[name]; ok {
+			return nil, nil, fmt.Errorf("duplicate experimental-federation-backend %q", name)
+		}
+		backends[name] = strings.Split(eps, ",")
+	}
+	routes := make(map[string]string)
+	for _, v := range grpcProxyFederationRoutes {
+		prefix, name, ok := strings.Cut(v, "=")
+		if !ok || prefix == "" {
+			return nil, nil, fmt.Errorf("invalid experimental-federation-route %q", v)
+		}
+		if _, ok := backends[name]; !ok && name != defaultFederationBackend {
+			return nil, nil, fmt.Errorf("experimental-federation-route %q routes to unknown backend %q", v, name)
+		}
+		if _, ok := routes[prefix]; ok {
+			return nil, nil, fmt.Errorf("duplicate experimental-federation-route prefix %q", prefix)
+		}
+		routes[prefix] = name
+	}
+	return backends, routes, nil
+}
+
+func mustNewRouter(lg *zap.Logger, client *clientv3.Client) *grpcproxy.Router {
+	backends, routes, err := parseFederation()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	def := &grpcproxy.Backend{Name: defaultFederationBackend, Client: client}
+	bs := map[string]*grpcproxy.Backend{defaultFederationBackend: def}
+	for name, eps := range backends {
+		bs[name] = &grpcproxy.Backend{Name: name, Client: mustNewClientWithEndpoints(lg, eps)}
+		lg.Info("federation backend", zap.String("name", name), zap.Strings("endpoints", eps))
+	}
+	rs := make(map[string]*grpcproxy.Backend)
+	for prefix, name := range routes {
+		rs[prefix] = bs[name]
+		lg.Info("federation route", zap.String("prefix", prefix), zap.String("backend", name))
+	}
+	return grpcproxy.NewRouter(client.Ctx(), lg, def, rs)
+}
+
 func mustNewProxyClient(lg *zap.Logger, tls *transport.TLSInfo) *clientv3.Client {
 	eps := []string{grpcProxyAdvertiseClientURL}
 	cfg, err := newProxyClientCfg(lg.Named("client"), eps, tls)
@@ -410,7 +521,7 @@ func mustListenCMux(lg *zap.Logger, tlsinfo *transport.TLSInfo) cmux.CMux {
 	return cmux.New(l)
 }
 
-func newGRPCProxyServer(lg *zap.Logger, client *clientv3.Client) *grpc.Server {
+func newGRPCProxyServer(lg *zap.Logger, client *clientv3.Client, rt *grpcproxy.Router) *grpc.Server {
 	if grpcProxyEnableOrdering {
 		vf := ordering.NewOrderViolationSwitchEndpointClosure(client)
 		client.KV = ordering.NewKV(client.KV, vf)
@@ -435,8 +546,21 @@ func newGRPCProxyServer(lg *zap.Logger, client *clientv3.Client) *grpc.Server {
 		client.KV, _, _ = leasing.NewKV(client, grpcProxyLeasing)
 	}
 
-	kvp, _ := grpcproxy.NewKvProxy(client)
-	watchp, _ := grpcproxy.NewWatchProxy(client.Ctx(), lg, client)
+	var am *grpcproxy.AuthMirror
+	if grpcProxyAuthMirror {
+		am = grpcproxy.NewAuthMirror(client.Ctx(), lg, client, grpcProxyAuthMirrorIdentityTTL)
+	}
+	var replica *grpcproxy.KVReplica
+	if grpcProxyReplica {
+		replica = grpcproxy.NewKVReplica(client.Ctx(), lg, client, grpcProxyReplicaPrefix, grpcProxyReplicaHistory)
+	}
+	kvp, _ := grpcproxy.NewKvProxyWithReplica(client, am, replica)
+	watchp, _ := grpcproxy.NewWatchProxyWithAuth(client.Ctx(), lg, client, am)
+	if rt != nil {
+		// the other services are served by the cluster of --endpoints
+		kvp, _ = grpcproxy.NewFederatedKvProxy(rt)
+		watchp, _ = grpcproxy.NewFederatedWatchProxy(client.Ctx(), lg, rt)
+	}
 	if grpcProxyResolverPrefix != "" {
 		grpcproxy.Register(lg, client, grpcProxyResolverPrefix, grpcProxyAdvertiseClientURL, grpcProxyResolverTTL)
 	}
@@ -504,7 +628,7 @@ func newGRPCProxyServer(lg *zap.Logger, client *clientv3.Client) *grpc.Server {
 	return server
 }
 
-func mustHTTPListener(lg *zap.Logger, m cmux.CMux, tlsinfo *transport.TLSInfo, c *clientv3.Client, proxy *clientv3.Client) (*http.Server, net.Listener) {
+func mustHTTPListener(lg *zap.Logger, m cmux.CMux, tlsinfo *transport.TLSInfo, c *clientv3.Client, proxy *clientv3.Client, rt *grpcproxy.Router) (*http.Server, net.Listener) {
 	httpClient := mustNewHTTPClient(lg)
 	httpmux := http.NewServeMux()
 	httpmux.HandleFunc("/", http.NotFound)
@@ -512,6 +636,9 @@ func mustHTTPListener(lg *zap.Logger, m cmux.CMux, tlsinfo *transport.TLSInfo, c
 	grpcproxy.HandleHealth(lg, httpmux, c)
 	grpcproxy.HandleProxyMetrics(httpmux)
 	grpcproxy.HandleProxyHealth(lg, httpmux, proxy)
+	if rt != nil {
+		grpcproxy.HandleFederationHealth(lg, httpmux, rt)
+	}
 	if grpcProxyEnablePprof {
 		for p, h := range debugutil.PProfHandlers() {
 			httpmux.Handle(p, h)
@@ -209,7 +209,8 @@ Security:
 
 Auth:
   --auth-token 'simple'
-    Specify a v3 authentication token type and its options ('simple' or 'jwt').
+    Specify a v3 authentication token type and its options ('simple', 'jwt' or 'oidc').
+    'oidc' verifies tokens issued outside of etcd, e.g. 'oidc,issuer=<url>,jwks=<file or url>,audience=etcd,username-claim=sub,groups-claim=groups'.
   --bcrypt-cost ` + fmt.Sprintf("%d", bcrypt.DefaultCost) + `
     Specify the cost / strength of the bcrypt algorithm for hashing auth passwords. Valid values are between ` + fmt.Sprintf("%d", bcrypt.MinCost) + ` and ` + fmt.Sprintf("%d", bcrypt.MaxCost) + `.
   --auth-token-ttl 300
@@ -264,10 +265,22 @@ Experimental feature:
     ExperimentalEnableLeaseCheckpoint enables primary lessor to persist lease remainingTTL to prevent indefinite auto-renewal of long lived leases.
   --experimental-compaction-batch-limit 1000
     ExperimentalCompactionBatchLimit sets the maximum revisions deleted in each compaction batch.
+  --experimental-compaction-prefix-retention ''
+    Comma separated '<prefix>=<retention>' pairs that keep the history of key prefixes for longer than 'auto-compaction-retention'. A retention with a time unit (e.g. '72h') keeps that duration of history, a plain number (e.g. '10000') keeps that many revisions.
   --experimental-peer-skip-client-san-verification 'false'
     Skip verification of SAN field in client certificate for peer connections.
   --experimental-watch-progress-notify-interval '10m'
     Duration of periodical watch progress notification.
+  --experimental-watch-stream-max-pending-events '0'
+    Maximum number of events held in memory for the blocked watchers of a watch stream, 0 for no limit.
+  --experimental-watch-stream-max-pending-bytes '0'
+    Maximum size in bytes of the events held in memory for the blocked watchers of a watch stream, 0 for no limit.
+  --experimental-watch-max-pending-events '0'
+    Maximum number of events held in memory for all the blocked watchers, 0 for no limit.
+  --experimental-watch-max-pending-bytes '0'
+    Maximum size in bytes of the events held in memory for all the blocked watchers, 0 for no limit.
+  --experimental-slow-watcher-policy 'block'
+    Policy of the blocked watchers whose events exceed the pending limits: 'block' reads their events again from the backend once they catch up, 'drop' cancels them with a dedicated cancel reason so that their clients resync, 'coalesce' only keeps the latest event of each key.
   --experimental-warning-apply-duration '100ms'
     Warning is generated if requests take more than this duration.
   --experimental-txn-mode-write-with-shared-buffer 'true'
@@ -282,6 +295,24 @@ Experimental feature:
     Set the maximum time duration to wait for the cluster to be ready.
   --experimental-snapshot-catch-up-entries '5000'
     Number of entries for a slow follower to catch up after compacting the raft storage entries.
+  --experimental-audit-sink ''
+    Sink of the audit log of the mutations and admin operations, 'file' or 'syslog'. Auditing is disabled if empty.
+  --experimental-audit-file ''
+    Path of the JSON lines audit log of the 'file' sink.
+  --experimental-audit-max-size '100'
+    Size in megabytes at which the audit log file is rotated.
+  --experimental-audit-max-backups '10'
+    Number of rotated audit log files to retain.
+  --experimental-audit-level 'metadata'
+    Audit level of the RPCs changing the cluster, 'none', 'metadata' or 'request'. Read-only RPCs are not audited unless enabled per RPC or per prefix.
+  --experimental-audit-rpc-levels ''
+    Comma separated '<rpc>=<level>' pairs overriding the audit level of RPCs by name (e.g. 'Range=request').
+  --experimental-audit-prefix-levels ''
+    Comma separated '<prefix>=<level>' pairs overriding the audit level of the requests accessing keys under a prefix (e.g. '/secrets/=request').
+  --experimental-client-cert-identity ''
+    JSON rules deriving the users, and the roles granted to them, from the URI, DNS, email, O or OU fields of client certificates rather than from their CommonName. Requires --client-cert-auth.
+  --experimental-password-hash 'bcrypt'
+    Algorithm of auth password hashing, 'bcrypt' or 'argon2id' followed by its options 'time', 'memory' (in KiB) and 'threads' (e.g. 'argon2id,time=1,memory=65536,threads=4'). Passwords hashed otherwise are rehashed when their users authenticate.
 
 Unsafe feature:
   --force-new-cluster 'false'
@@ -0,0 +1,360 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+const (
+	// SinkFile writes the audit records to a rotating JSON lines file.
+	SinkFile = "file"
+	// SinkSyslog writes the audit records to the local syslog daemon.
+	SinkSyslog = "syslog"
+
+	// StageRPC records a request as received and answered by the gRPC server.
+	StageRPC = "rpc"
+	// StageApply records a request as applied by the raft state machine.
+	StageApply = "apply"
+
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+
+	// maxPendingEvents is the number of events waiting to be written to the
+	// sink beyond which events are dropped, rather than blocking their callers.
+	maxPendingEvents = 4096
+)
+
+// Level is the verbosity of the audit records of a request.
+type Level int
+
+const (
+	// LevelNone does not record the request.
+	LevelNone Level = iota
+	// LevelMetadata records who made the request, when, and its outcome.
+	LevelMetadata
+	// LevelRequest also records the key ranges, revision and arguments of the request.
+	LevelRequest
+)
+
+// ParseLevel parses "none", "metadata" or "request" into a Level.
+func ParseLevel(s string) (Level, error) {
+	switch s {
+	case "none":
+		return LevelNone, nil
+	case "metadata":
+		return LevelMetadata, nil
+	case "request":
+		return LevelRequest, nil
+	}
+	return LevelNone, fmt.Errorf("unknown audit level %q (expected 'none', 'metadata' or 'request')", s)
+}
+
+func (l Level) String() string {
+	switch l {
+	case LevelNone:
+		return "none"
+	case LevelMetadata:
+		return "metadata"
+	case LevelRequest:
+		return "request"
+	}
+	return fmt.Sprintf("Level(%d)", int(l))
+}
+
+// readOnlyRPCs are not audited unless enabled by Config.RPCLevels or
+// Config.PrefixLevels.
+var readOnlyRPCs = map[string]struct{}{
+	"Range":           {},
+	"RangeStats":      {},
+	"MultiRange":      {},
+	"Watch":           {},
+	"LeaseKeepAlive":  {},
+	"LeaseTimeToLive": {},
+	"LeaseLeases":     {},
+	"MemberList":      {},
+	"Status":          {},
+	"Hash":            {},
+	"HashKV":          {},
+	"AuthStatus":      {},
+	"AuthExplain":     {},
+	"UserGet":         {},
+	"UserList":        {},
+	"RoleGet":         {},
+	"RoleList":        {},
+}
+
+// Config configures the audit log.
+type Config struct {
+	// Sink is either SinkFile or SinkSyslog. The audit log is disabled if empty.
+	Sink string
+	// File is the path of the audit log of SinkFile.
+	File string
+	// MaxSize is the size in megabytes at which the file is rotated.
+	MaxSize int
+	// MaxBackups is the number of rotated files to retain.
+	MaxBackups int
+
+	// Level is the verbosity of the RPCs changing the cluster. Read-only RPCs
+	// are not audited by default.
+	Level Level
+	// RPCLevels overrides the verbosity of RPCs by name, such as "Range" or
+	// "UserGrantRole".
+	RPCLevels map[string]Level
+	// PrefixLevels overrides the verbosity of the requests accessing keys under
+	// a prefix. The longest prefix matching a key wins, and the most verbose
+	// level wins across the keys of a request.
+	PrefixLevels map[string]Level
+}
+
+// KeyRange is a key, or a range of keys, accessed by a request.
+type KeyRange struct {
+	Key      string `json:"key"`
+	RangeEnd string `json:"range-end,omitempty"`
+}
+
+// Event is an audit record.
+type Event struct {
+	Time       time.Time         `json:"time"`
+	Stage      string            `json:"stage"`
+	RPC        string            `json:"rpc"`
+	User       string            `json:"user,omitempty"`
+	CommonName string            `json:"common-name,omitempty"`
+	RemoteAddr string            `json:"remote-addr,omitempty"`
+	Ranges     []KeyRange        `json:"ranges,omitempty"`
+	Revision   int64             `json:"revision,omitempty"`
+	Details    map[string]string `json:"details,omitempty"`
+	Outcome    string            `json:"outcome"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// Auditor writes audit records to the configured sink. A nil Auditor records
+// nothing.
+type Auditor struct {
+	lg  *zap.Logger
+	cfg Config
+
+	// prefixes may raise the level of any RPC accessing keys
+	prefixes bool
+
+	w io.WriteCloser
+	// eventc is drained by the goroutine writing the events to w, so that a
+	// slow sink does not block the callers of Log, such as the apply loop.
+	eventc    chan Event
+	stopc     chan struct{}
+	donec     chan struct{}
+	closeOnce sync.Once
+}
+
+// New returns the Auditor of the configuration, or nil if auditing is disabled.
+func New(lg *zap.Logger, cfg Config) (*Auditor, error) {
+	if lg == nil {
+		lg = zap.NewNop()
+	}
+	var w io.WriteCloser
+	switch cfg.Sink {
+	case "":
+		return nil, nil
+	case SinkFile:
+		if cfg.File == "" {
+			return nil, fmt.Errorf("audit log file is not specified")
+		}
+		w = &lumberjack.Logger{Filename: cfg.File, MaxSize: cfg.MaxSize, MaxBackups: cfg.MaxBackups}
+	case SinkSyslog:
+		var err error
+		if w, err = newSyslogWriter(); err != nil {
+			return nil, fmt.Errorf("cannot open audit syslog: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unknown audit sink %q (expected %q or %q)", cfg.Sink, SinkFile, SinkSyslog)
+	}
+	return newAuditor(lg, cfg, w), nil
+}
+
+func newAuditor(lg *zap.Logger, cfg Config, w io.WriteCloser) *Auditor {
+	a := &Auditor{
+		lg:     lg,
+		cfg:    cfg,
+		w:      w,
+		eventc: make(chan Event, maxPendingEvents),
+		stopc:  make(chan struct{}),
+		donec:  make(chan struct{}),
+	}
+	for _, l := range cfg.PrefixLevels {
+		if l > LevelNone {
+			a.prefixes = true
+		}
+	}
+	go a.run()
+	return a
+}
+
+// run writes the logged events to the sink until the Auditor is closed.
+func (a *Auditor) run() {
+	defer close(a.donec)
+	for {
+		select {
+		case ev := <-a.eventc:
+			a.write(ev)
+		case <-a.stopc:
+			for {
+				select {
+				case ev := <-a.eventc:
+					a.write(ev)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (a *Auditor) write(ev Event) {
+	b, err := json.Marshal(ev)
+	if err != nil {
+		a.lg.Warn("failed to marshal audit event", zap.String("rpc", ev.RPC), zap.Error(err))
+		return
+	}
+	if _, err = a.w.Write(append(b, '\n')); err != nil {
+		a.lg.Warn("failed to write audit event", zap.String("rpc", ev.RPC), zap.Error(err))
+	}
+}
+
+// Enabled returns whether requests of the RPC may be recorded, to spare
+// building events that would be dropped anyway.
+func (a *Auditor) Enabled(rpc string) bool {
+	if a == nil {
+		return false
+	}
+	return a.prefixes || a.rpcLevel(rpc) > LevelNone
+}
+
+func (a *Auditor) rpcLevel(rpc string) Level {
+	if l, ok := a.cfg.RPCLevels[rpc]; ok {
+		return l
+	}
+	if _, ok := readOnlyRPCs[rpc]; ok {
+		return LevelNone
+	}
+	return a.cfg.Level
+}
+
+func (a *Auditor) level(ev *Event) Level {
+	lvl := a.rpcLevel(ev.RPC)
+	if len(a.cfg.PrefixLevels) == 0 || len(ev.Ranges) == 0 {
+		return lvl
+	}
+	matched := false
+	var plvl Level
+	for _, r := range ev.Ranges {
+		if l, ok := a.rangeLevel(r); ok {
+			if !matched || l > plvl {
+				plvl = l
+			}
+			matched = true
+		}
+	}
+	if matched {
+		return plvl
+	}
+	return lvl
+}
+
+// rangeLevel returns the level of the longest prefix holding the whole range,
+// raised to the level of any prefix only overlapping it.
+func (a *Auditor) rangeLevel(r KeyRange) (Level, bool) {
+	longest, matched := -1, false
+	var lvl, overlap Level
+	for p, l := range a.cfg.PrefixLevels {
+		pEnd := prefixEnd(p)
+		switch {
+		case strings.HasPrefix(r.Key, p) && (r.RangeEnd == "" || (r.RangeEnd != "\x00" && (pEnd == "" || r.RangeEnd <= pEnd))):
+			if len(p) > longest {
+				longest, lvl = len(p), l
+			}
+		case r.RangeEnd != "" && (pEnd == "" || r.Key < pEnd) && (r.RangeEnd == "\x00" || r.RangeEnd > p):
+			if !matched || l > overlap {
+				overlap = l
+			}
+			matched = true
+		}
+	}
+	if longest >= 0 && (!matched || lvl > overlap) {
+		return lvl, true
+	}
+	return overlap, matched
+}
+
+// prefixEnd returns the end of the range of keys with the prefix, or "" if the
+// range has no end.
+func prefixEnd(p string) string {
+	end := []byte(p)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return string(end[:i+1])
+		}
+	}
+	return ""
+}
+
+// Log records the event at the verbosity configured for its RPC and keys. The
+// event is written to the sink in the background, and dropped if too many
+// events are waiting to be written.
+func (a *Auditor) Log(ev Event) {
+	if a == nil {
+		return
+	}
+	lvl := a.level(&ev)
+	if lvl == LevelNone {
+		return
+	}
+	if lvl < LevelRequest {
+		ev.Ranges, ev.Revision, ev.Details = nil, 0, nil
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	if ev.Outcome == "" {
+		ev.Outcome = OutcomeSuccess
+		if ev.Error != "" {
+			ev.Outcome = OutcomeFailure
+		}
+	}
+	select {
+	case a.eventc <- ev:
+	default:
+		droppedEvents.Inc()
+	}
+}
+
+// Close writes the pending events and closes the sink of the audit log. The
+// events logged afterwards are not written.
+func (a *Auditor) Close() error {
+	if a == nil {
+		return nil
+	}
+	a.closeOnce.Do(func() { close(a.stopc) })
+	<-a.donec
+	return a.w.Close()
+}
@@ -0,0 +1,160 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+)
+
+func TestAuditorLevel(t *testing.T) {
+	a := &Auditor{cfg: Config{
+		Level:        LevelMetadata,
+		RPCLevels:    map[string]Level{"Put": LevelRequest, "Compact": LevelNone},
+		PrefixLevels: map[string]Level{"/secrets/": LevelRequest, "/secrets/public/": LevelNone},
+	}}
+	tests := []struct {
+		rpc    string
+		ranges []KeyRange
+		want   Level
+	}{
+		{"DeleteRange", []KeyRange{{Key: "foo"}}, LevelMetadata},
+		{"Put", []KeyRange{{Key: "foo"}}, LevelRequest},
+		{"Compact", nil, LevelNone},
+		{"UserGrantRole", nil, LevelMetadata},
+		{"Range", []KeyRange{{Key: "foo"}}, LevelNone},
+		{"Range", []KeyRange{{Key: "/secrets/a"}}, LevelRequest},
+		// the longest prefix wins
+		{"Put", []KeyRange{{Key: "/secrets/public/a"}}, LevelNone},
+		{"Range", []KeyRange{{Key: "/secrets/public/a", RangeEnd: "/secrets/public/b"}}, LevelNone},
+		// the most verbose prefix wins across keys
+		{"Txn", []KeyRange{{Key: "/secrets/public/a"}, {Key: "/secrets/a"}}, LevelRequest},
+		// ranges spanning a prefix are audited at its level
+		{"Range", []KeyRange{{Key: "/", RangeEnd: "0"}}, LevelRequest},
+		{"Range", []KeyRange{{Key: "/secrets/", RangeEnd: "/secrets0"}}, LevelRequest},
+		{"Range", []KeyRange{{Key: "\x00", RangeEnd: "\x00"}}, LevelRequest},
+		{"Range", []KeyRange{{Key: "/a", RangeEnd: "/b"}}, LevelNone},
+	}
+	for i, tt := range tests {
+		ev := Event{RPC: tt.rpc, Ranges: tt.ranges}
+		assert.Equalf(t, tt.want, a.level(&ev), "#%d: %s %v", i, tt.rpc, tt.ranges)
+	}
+}
+
+func TestAuditorFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	a, err := New(zaptest.NewLogger(t), Config{
+		Sink:         SinkFile,
+		File:         path,
+		MaxSize:      1,
+		Level:        LevelMetadata,
+		PrefixLevels: map[string]Level{"/secrets/": LevelRequest},
+	})
+	require.NoError(t, err)
+	// reads may access the audited prefix
+	assert.True(t, a.Enabled("Range"))
+
+	put := &pb.PutRequest{Key: []byte("/secrets/a"), Lease: 1}
+	ranges, details := RequestInfo(put)
+	a.Log(Event{Stage: StageRPC, RPC: "Put", User: "alice", Ranges: ranges, Details: details, Revision: 2})
+	ranges, details = RequestInfo(&pb.DeleteRangeRequest{Key: []byte("foo")})
+	a.Log(Event{Stage: StageApply, RPC: "DeleteRange", User: "bob", Ranges: ranges, Details: details, Error: "permission denied"})
+	a.Log(Event{Stage: StageRPC, RPC: "Range", Ranges: []KeyRange{{Key: "foo"}}})
+	require.NoError(t, a.Close())
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	var evs []Event
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var ev Event
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &ev))
+		evs = append(evs, ev)
+	}
+	require.Len(t, evs, 2)
+
+	assert.Equal(t, "alice", evs[0].User)
+	assert.Equal(t, OutcomeSuccess, evs[0].Outcome)
+	assert.Equal(t, []KeyRange{{Key: "/secrets/a"}}, evs[0].Ranges)
+	assert.Equal(t, int64(2), evs[0].Revision)
+	assert.Equal(t, map[string]string{"lease": "0000000000000001"}, evs[0].Details)
+	assert.False(t, evs[0].Time.IsZero())
+
+	// metadata only records who made the request and its outcome
+	assert.Equal(t, Event{Time: evs[1].Time, Stage: StageApply, RPC: "DeleteRange", User: "bob", Outcome: OutcomeFailure, Error: "permission denied"}, evs[1])
+}
+
+func TestRequestInfo(t *testing.T) {
+	txn := &pb.TxnRequest{
+		Compare: []*pb.Compare{{Key: []byte("a")}},
+		Success: []*pb.RequestOp{{Request: &pb.RequestOp_RequestPut{RequestPut: &pb.PutRequest{Key: []byte("b")}}}},
+		Failure: []*pb.RequestOp{{Request: &pb.RequestOp_RequestTxn{RequestTxn: &pb.TxnRequest{
+			Success: []*pb.RequestOp{{Request: &pb.RequestOp_RequestDeleteRange{RequestDeleteRange: &pb.DeleteRangeRequest{Key: []byte("c"), RangeEnd: []byte("d")}}}},
+		}}}},
+	}
+	ranges, _ := RequestInfo(txn)
+	assert.Equal(t, []KeyRange{{Key: "a"}, {Key: "b"}, {Key: "c", RangeEnd: "d"}}, ranges)
+
+	// passwords are never recorded
+	_, details := RequestInfo(&pb.AuthUserAddRequest{Name: "alice", Password: "secret"})
+	assert.Equal(t, map[string]string{"user": "alice"}, details)
+
+	rpc, req := InternalRequest(&pb.InternalRaftRequest{AuthUserGrantRole: &pb.AuthUserGrantRoleRequest{User: "alice", Role: "admin"}})
+	assert.Equal(t, "UserGrantRole", rpc)
+	_, details = RequestInfo(req)
+	assert.Equal(t, map[string]string{"user": "alice", "role": "admin"}, details)
+
+	rpc, _ = InternalRequest(&pb.InternalRaftRequest{LeaseCheckpoint: &pb.LeaseCheckpointRequest{}})
+	assert.Empty(t, rpc)
+}
+
+// blockingWriter blocks writes until unblockc is closed.
+type blockingWriter struct {
+	unblockc chan struct{}
+	writes   int
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.unblockc
+	w.writes++
+	return len(p), nil
+}
+
+func (w *blockingWriter) Close() error { return nil }
+
+func TestAuditorSlowSink(t *testing.T) {
+	w := &blockingWriter{unblockc: make(chan struct{})}
+	a := newAuditor(zaptest.NewLogger(t), Config{Level: LevelMetadata}, w)
+
+	// logging does not wait for the sink, and drops the events it cannot keep
+	n := maxPendingEvents + 10
+	for i := 0; i < n; i++ {
+		a.Log(Event{Stage: StageApply, RPC: "Put"})
+	}
+	close(w.unblockc)
+	require.NoError(t, a.Close())
+	assert.Less(t, w.writes, n)
+	assert.GreaterOrEqual(t, w.writes, maxPendingEvents)
+}
@@ -0,0 +1,267 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3audit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.etcd.io/etcd/api/v3/authpb"
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+)
+
+// RequestInfo returns the key ranges accessed by a request of the etcd API,
+// along with the arguments worth recording. Secrets such as passwords are
+// never returned.
+func RequestInfo(req interface{}) ([]KeyRange, map[string]string) {
+	switch r := req.(type) {
+	case *pb.RangeRequest:
+		return keyRange(r.Key, r.RangeEnd), nil
+	case *pb.PutRequest:
+		d := map[string]string{}
+		if r.Lease != 0 {
+			d["lease"] = leaseID(r.Lease)
+		}
+		if r.Ttl != 0 {
+			d["ttl"] = strconv.FormatInt(r.Ttl, 10)
+		}
+		return keyRange(r.Key, nil), nilIfEmpty(d)
+	case *pb.DeleteRangeRequest:
+		return keyRange(r.Key, r.RangeEnd), nil
+	case *pb.TxnRequest:
+		return txnRanges(r), nil
+	case *pb.MultiRangeRequest:
+		var krs []KeyRange
+		for _, sr := range r.Ranges {
+			krs = append(krs, keyRange(sr.Key, sr.RangeEnd)...)
+		}
+		return krs, nil
+	case *pb.RangeStatsRequest:
+		return keyRange(r.Key, r.RangeEnd), nil
+	case *pb.WatchRequest:
+		if cr := r.GetCreateRequest(); cr != nil {
+			return keyRange(cr.Key, cr.RangeEnd), nil
+		}
+	case *pb.CompactionRequest:
+		return nil, map[string]string{"revision": strconv.FormatInt(r.Revision, 10)}
+	case *pb.LeaseGrantRequest:
+		d := map[string]string{"ttl": strconv.FormatInt(r.TTL, 10)}
+		if r.ID != 0 {
+			d["lease"] = leaseID(r.ID)
+		}
+		if r.Parent != 0 {
+			d["parent"] = leaseID(r.Parent)
+		}
+		return nil, d
+	case *pb.LeaseRevokeRequest:
+		return nil, map[string]string{"lease": leaseID(r.ID)}
+	case *pb.AlarmRequest:
+		return nil, map[string]string{"action": r.Action.String(), "alarm": r.Alarm.String(), "member": memberID(r.MemberID)}
+	case *pb.MemberAddRequest:
+		return nil, map[string]string{"peer-urls": strings.Join(r.PeerURLs, ","), "learner": strconv.FormatBool(r.IsLearner)}
+	case *pb.MemberRemoveRequest:
+		return nil, map[string]string{"member": memberID(r.ID)}
+	case *pb.MemberUpdateRequest:
+		return nil, map[string]string{"member": memberID(r.ID), "peer-urls": strings.Join(r.PeerURLs, ",")}
+	case *pb.MemberPromoteRequest:
+		return nil, map[string]string{"member": memberID(r.ID)}
+	case *pb.MoveLeaderRequest:
+		return nil, map[string]string{"member": memberID(r.TargetID)}
+	case *pb.DowngradeRequest:
+		return nil, map[string]string{"action": r.Action.String(), "version": r.Version}
+	case *pb.SnapshotDeltaRequest:
+		return nil, map[string]string{"start-revision": strconv.FormatInt(r.StartRevision, 10), "end-revision": strconv.FormatInt(r.EndRevision, 10)}
+	case *pb.AuthenticateRequest:
+		return nil, map[string]string{"user": r.Name}
+	case *pb.AuthUserAddRequest:
+		return nil, map[string]string{"user": r.Name}
+	case *pb.AuthUserDeleteRequest:
+		return nil, map[string]string{"user": r.Name}
+	case *pb.AuthUserChangePasswordRequest:
+		return nil, map[string]string{"user": r.Name}
+	case *pb.AuthUserGetRequest:
+		return nil, map[string]string{"user": r.Name}
+	case *pb.AuthUserGrantRoleRequest:
+		d := map[string]string{"user": r.User, "role": r.Role}
+		if r.Ttl != 0 {
+			d["ttl"] = strconv.FormatInt(r.Ttl, 10)
+		}
+		return nil, d
+	case *pb.AuthUserRevokeRoleRequest:
+		return nil, map[string]string{"user": r.Name, "role": r.Role}
+	case *pb.AuthRoleAddRequest:
+		return nil, map[string]string{"role": r.Name}
+	case *pb.AuthRoleDeleteRequest:
+		return nil, map[string]string{"role": r.Role}
+	case *pb.AuthRoleGetRequest:
+		return nil, map[string]string{"role": r.Role}
+	case *pb.AuthRoleGrantPermissionRequest:
+		d := map[string]string{"role": r.Name}
+		if r.Perm == nil {
+			return nil, d
+		}
+		d["permission"] = r.Perm.PermType.String()
+		return keyRange(r.Perm.Key, r.Perm.RangeEnd), d
+	case *pb.AuthRoleRevokePermissionRequest:
+		return keyRange(r.Key, r.RangeEnd), map[string]string{"role": r.Role}
+	case *pb.AuthUserSetLimitsRequest:
+		return nil, limitsDetails(map[string]string{"user": r.Name}, r.Limits)
+	case *pb.AuthRoleSetLimitsRequest:
+		return nil, limitsDetails(map[string]string{"role": r.Role}, r.Limits)
+	case *pb.AuthTokenListRequest:
+		return nil, map[string]string{"user": r.Name}
+	case *pb.AuthTokenRevokeRequest:
+		return nil, map[string]string{"token-id": strconv.FormatUint(r.ID, 10)}
+	case *pb.AuthExplainRequest:
+		return keyRange(r.Key, r.RangeEnd), map[string]string{"user": r.User, "permission": r.PermType.String()}
+	case *pb.AuthRoleGrantExpireRequest:
+		grants := make([]string, len(r.Grants))
+		for i, g := range r.Grants {
+			grants[i] = g.User + "=" + g.Role
+		}
+		return nil, map[string]string{"grants": strings.Join(grants, ",")}
+	}
+	return nil, nil
+}
+
+// InternalRequest returns the RPC name and request of a raft request applied
+// on behalf of a client, or of the revocations of expired role grants. It
+// returns an empty name for the other requests etcd proposes by itself, such
+// as lease checkpoints or key expiries.
+func InternalRequest(r *pb.InternalRaftRequest) (string, interface{}) {
+	switch {
+	case r.Range != nil:
+		return "Range", r.Range
+	case r.Put != nil:
+		return "Put", r.Put
+	case r.DeleteRange != nil:
+		return "DeleteRange", r.DeleteRange
+	case r.Txn != nil:
+		return "Txn", r.Txn
+	case r.Compaction != nil:
+		return "Compact", r.Compaction
+	case r.LeaseGrant != nil:
+		return "LeaseGrant", r.LeaseGrant
+	case r.LeaseRevoke != nil:
+		return "LeaseRevoke", r.LeaseRevoke
+	case r.Alarm != nil:
+		return "Alarm", r.Alarm
+	case r.AuthEnable != nil:
+		return "AuthEnable", r.AuthEnable
+	case r.AuthDisable != nil:
+		return "AuthDisable", r.AuthDisable
+	case r.AuthStatus != nil:
+		return "AuthStatus", r.AuthStatus
+	case r.AuthUserAdd != nil:
+		return "UserAdd", r.AuthUserAdd
+	case r.AuthUserDelete != nil:
+		return "UserDelete", r.AuthUserDelete
+	case r.AuthUserChangePassword != nil:
+		return "UserChangePassword", r.AuthUserChangePassword
+	case r.AuthUserGrantRole != nil:
+		return "UserGrantRole", r.AuthUserGrantRole
+	case r.AuthUserGet != nil:
+		return "UserGet", r.AuthUserGet
+	case r.AuthUserRevokeRole != nil:
+		return "UserRevokeRole", r.AuthUserRevokeRole
+	case r.AuthUserList != nil:
+		return "UserList", r.AuthUserList
+	case r.AuthRoleAdd != nil:
+		return "RoleAdd", r.AuthRoleAdd
+	case r.AuthRoleGrantPermission != nil:
+		return "RoleGrantPermission", r.AuthRoleGrantPermission
+	case r.AuthRoleGet != nil:
+		return "RoleGet", r.AuthRoleGet
+	case r.AuthRoleRevokePermission != nil:
+		return "RoleRevokePermission", r.AuthRoleRevokePermission
+	case r.AuthRoleDelete != nil:
+		return "RoleDelete", r.AuthRoleDelete
+	case r.AuthRoleList != nil:
+		return "RoleList", r.AuthRoleList
+	case r.AuthUserSetLimits != nil:
+		return "UserSetLimits", r.AuthUserSetLimits
+	case r.AuthRoleSetLimits != nil:
+		return "RoleSetLimits", r.AuthRoleSetLimits
+	case r.AuthTokenList != nil:
+		return "TokenList", r.AuthTokenList
+	case r.AuthTokenRevoke != nil:
+		return "TokenRevoke", r.AuthTokenRevoke
+	case r.AuthRoleGrantExpire != nil:
+		return "RoleGrantExpire", r.AuthRoleGrantExpire
+	}
+	return "", nil
+}
+
+// Revision returns the revision of the response header of an etcd API
+// response, if any.
+func Revision(resp interface{}) int64 {
+	if h, ok := resp.(interface{ GetHeader() *pb.ResponseHeader }); ok {
+		return h.GetHeader().GetRevision()
+	}
+	return 0
+}
+
+func txnRanges(r *pb.TxnRequest) []KeyRange {
+	var krs []KeyRange
+	for _, c := range r.Compare {
+		krs = append(krs, keyRange(c.Key, c.RangeEnd)...)
+	}
+	for _, ops := range [][]*pb.RequestOp{r.Success, r.Failure} {
+		for _, op := range ops {
+			var req interface{}
+			switch tv := op.Request.(type) {
+			case *pb.RequestOp_RequestRange:
+				req = tv.RequestRange
+			case *pb.RequestOp_RequestPut:
+				req = tv.RequestPut
+			case *pb.RequestOp_RequestDeleteRange:
+				req = tv.RequestDeleteRange
+			case *pb.RequestOp_RequestTxn:
+				req = tv.RequestTxn
+			}
+			opRanges, _ := RequestInfo(req)
+			krs = append(krs, opRanges...)
+		}
+	}
+	return krs
+}
+
+func keyRange(key, end []byte) []KeyRange {
+	return []KeyRange{{Key: string(key), RangeEnd: string(end)}}
+}
+
+// limitsDetails adds the limits set by a request to d, zero meaning unlimited.
+func limitsDetails(d map[string]string, l *authpb.Limits) map[string]string {
+	if l == nil {
+		l = &authpb.Limits{}
+	}
+	d["request-rate"] = strconv.FormatUint(l.RequestRate, 10)
+	d["max-watches"] = strconv.FormatUint(l.MaxWatches, 10)
+	d["max-leases"] = strconv.FormatUint(l.MaxLeases, 10)
+	d["max-bytes"] = strconv.FormatUint(l.MaxBytes, 10)
+	return d
+}
+
+func leaseID(id int64) string { return fmt.Sprintf("%016x", id) }
+
+func memberID(id uint64) string { return fmt.Sprintf("%x", id) }
+
+func nilIfEmpty(d map[string]string) map[string]string {
+	if len(d) == 0 {
+		return nil
+	}
+	return d
+}
@@ -52,21 +52,29 @@ type RevGetter interface {
 }
 
 // New returns a new Compactor based on given "mode".
+// The history of the given prefixes is kept according to their own retention
+// when it is longer than the retention of the mode.
 func New(
 	lg *zap.Logger,
 	mode string,
 	retention time.Duration,
+	prefixes []PrefixRetention,
 	rg RevGetter,
 	c Compactable,
 ) (Compactor, error) {
 	if lg == nil {
 		lg = zap.NewNop()
 	}
+	clock := clockwork.NewRealClock()
 	switch mode {
 	case ModePeriodic:
-		return newPeriodic(lg, clockwork.NewRealClock(), retention, rg, c), nil
+		pc := newPeriodic(lg, clock, retention, rg, c)
+		pc.prefixes = newPrefixRetention(clock, prefixes)
+		return pc, nil
 	case ModeRevision:
-		return newRevision(lg, clockwork.NewRealClock(), int64(retention), rg, c), nil
+		rc := newRevision(lg, clock, int64(retention), rg, c)
+		rc.prefixes = newPrefixRetention(clock, prefixes)
+		return rc, nil
 	default:
 		return nil, fmt.Errorf("unsupported compaction mode %s", mode)
 	}
@@ -36,6 +36,8 @@ type Periodic struct {
 	rg RevGetter
 	c  Compactable
 
+	prefixes *prefixRetention
+
 	revs   []int64
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -106,7 +108,9 @@ func (pc *Periodic) Run() {
 		lastSuccess := pc.clock.Now()
 		baseInterval := pc.period
 		for {
-			pc.revs = append(pc.revs, pc.rg.Rev())
+			curRev := pc.rg.Rev()
+			pc.revs = append(pc.revs, curRev)
+			pc.prefixes.record(curRev)
 			if len(pc.revs) > retentions {
 				pc.revs = pc.revs[1:] // pc.revs[0] is always the rev at pc.period ago
 			}
@@ -138,7 +142,7 @@ func (pc *Periodic) Run() {
 				zap.Duration("compact-period", pc.period),
 			)
 			startTime := pc.clock.Now()
-			_, err := pc.c.Compact(pc.ctx, &pb.CompactionRequest{Revision: rev})
+			_, err := pc.c.Compact(pc.ctx, &pb.CompactionRequest{Revision: rev, Retention: pc.prefixes.retention(rev)})
 			if err == nil || err == mvcc.ErrCompacted {
 				pc.lg.Info(
 					"completed auto periodic compaction",
@@ -0,0 +1,129 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3compactor
+
+import (
+	"time"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+
+	"github.com/jonboulle/clockwork"
+)
+
+// PrefixRetention keeps the history of the keys under Prefix for longer than
+// the retention of the compaction mode. Either Revisions or Period is set.
+type PrefixRetention struct {
+	Prefix string
+	// Revisions is the number of most recent revisions whose history is kept.
+	Revisions int64
+	// Period is the duration of the most recent history that is kept.
+	Period time.Duration
+}
+
+type revSample struct {
+	time time.Time
+	rev  int64
+}
+
+// prefixRetention tracks the revisions seen by a compactor over time so that
+// its compaction requests can carry the retention of every configured prefix.
+// A nil *prefixRetention adds no retention.
+type prefixRetention struct {
+	clock     clockwork.Clock
+	prefixes  []PrefixRetention
+	maxPeriod time.Duration
+
+	// samples are ordered by time; samples[0] is the newest sample
+	// at least maxPeriod old, if any.
+	samples []revSample
+}
+
+func newPrefixRetention(clock clockwork.Clock, prefixes []PrefixRetention) *prefixRetention {
+	if len(prefixes) == 0 {
+		return nil
+	}
+	pr := &prefixRetention{clock: clock, prefixes: prefixes}
+	for _, p := range prefixes {
+		if p.Period > pr.maxPeriod {
+			pr.maxPeriod = p.Period
+		}
+	}
+	return pr
+}
+
+// record records the current revision of the store.
+func (pr *prefixRetention) record(rev int64) {
+	if pr == nil {
+		return
+	}
+	now := pr.clock.Now()
+	pr.samples = append(pr.samples, revSample{time: now, rev: rev})
+	for len(pr.samples) > 1 && now.Sub(pr.samples[1].time) >= pr.maxPeriod {
+		pr.samples = pr.samples[1:]
+	}
+}
+
+// retention returns the retention for a compaction at the given revision.
+// Prefixes whose kept history starts after rev need no retention.
+func (pr *prefixRetention) retention(rev int64) []*pb.CompactionRetention {
+	if pr == nil || len(pr.samples) == 0 {
+		return nil
+	}
+	now := pr.clock.Now()
+	curRev := pr.samples[len(pr.samples)-1].rev
+
+	var ret []*pb.CompactionRetention
+	for _, p := range pr.prefixes {
+		var keep int64
+		switch {
+		case p.Revisions > 0:
+			keep = curRev - p.Revisions
+		case p.Period > 0:
+			// keep everything until the period has been observed
+			for _, s := range pr.samples {
+				if now.Sub(s.time) < p.Period {
+					break
+				}
+				keep = s.rev
+			}
+		}
+		if keep >= rev {
+			continue
+		}
+		if keep < 0 {
+			keep = 0
+		}
+		ret = append(ret, &pb.CompactionRetention{
+			Key:      []byte(p.Prefix),
+			RangeEnd: prefixRangeEnd(p.Prefix),
+			Revision: keep,
+		})
+	}
+	return ret
+}
+
+// prefixRangeEnd returns the end of the range of the keys with the given prefix.
+func prefixRangeEnd(prefix string) []byte {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	// next prefix does not exist (e.g., 0xffff);
+	// default to all keys greater than or equal to the prefix
+	return []byte{0}
+}
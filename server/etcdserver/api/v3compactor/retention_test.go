@@ -0,0 +1,106 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3compactor
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/client/pkg/v3/testutil"
+
+	"github.com/jonboulle/clockwork"
+)
+
+func TestPrefixRetention(t *testing.T) {
+	fc := clockwork.NewFakeClock()
+	pr := newPrefixRetention(fc, []PrefixRetention{
+		{Prefix: "/config/", Revisions: 50},
+		{Prefix: "/events/", Period: time.Hour},
+	})
+
+	// no period observed yet, so all history of /events/ is kept
+	pr.record(100)
+	wreq := []*pb.CompactionRetention{
+		{Key: []byte("/config/"), RangeEnd: []byte("/config0"), Revision: 50},
+		{Key: []byte("/events/"), RangeEnd: []byte("/events0"), Revision: 0},
+	}
+	if req := pr.retention(90); !reflect.DeepEqual(req, wreq) {
+		t.Errorf("retention = %v, want %v", req, wreq)
+	}
+
+	fc.Advance(30 * time.Minute)
+	pr.record(200)
+	fc.Advance(30 * time.Minute)
+	pr.record(300)
+	fc.Advance(30 * time.Minute)
+	pr.record(400)
+
+	// /config/ keeps history after revision 350, which is not compacted
+	wreq = []*pb.CompactionRetention{
+		{Key: []byte("/events/"), RangeEnd: []byte("/events0"), Revision: 200},
+	}
+	if req := pr.retention(300); !reflect.DeepEqual(req, wreq) {
+		t.Errorf("retention = %v, want %v", req, wreq)
+	}
+	if len(pr.samples) != 3 {
+		t.Errorf("len(samples) = %d, want 3", len(pr.samples))
+	}
+}
+
+func TestPrefixRangeEnd(t *testing.T) {
+	tests := []struct {
+		prefix string
+		wend   []byte
+	}{
+		{"/a", []byte("/b")},
+		{"a\xff", []byte("b")},
+		{"\xff\xff", []byte{0}},
+	}
+	for i, tt := range tests {
+		if end := prefixRangeEnd(tt.prefix); !reflect.DeepEqual(end, tt.wend) {
+			t.Errorf("#%d: prefixRangeEnd(%q) = %q, want %q", i, tt.prefix, end, tt.wend)
+		}
+	}
+}
+
+func TestRevisionWithPrefixRetention(t *testing.T) {
+	fc := clockwork.NewFakeClock()
+	rg := &fakeRevGetter{testutil.NewRecorderStream(), 99} // will be 100
+	compactable := &fakeCompactable{testutil.NewRecorderStream()}
+	tb := newRevision(zaptest.NewLogger(t), fc, 10, rg, compactable)
+	tb.prefixes = newPrefixRetention(fc, []PrefixRetention{{Prefix: "/config/", Revisions: 50}})
+
+	tb.Run()
+	defer tb.Stop()
+
+	fc.BlockUntil(1)
+	fc.Advance(revInterval)
+	rg.Wait(1)
+	a, err := compactable.Wait(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wreq := &pb.CompactionRequest{
+		Revision:  90,
+		Retention: []*pb.CompactionRetention{{Key: []byte("/config/"), RangeEnd: []byte("/config0"), Revision: 50}},
+	}
+	if !reflect.DeepEqual(a[0].Params[0], wreq) {
+		t.Errorf("compact request = %v, want %v", a[0].Params[0], wreq)
+	}
+}
@@ -37,6 +37,8 @@ type Revision struct {
 	rg RevGetter
 	c  Compactable
 
+	prefixes *prefixRetention
+
 	ctx    context.Context
 	cancel context.CancelFunc
 
@@ -77,7 +79,9 @@ func (rc *Revision) Run() {
 				}
 			}
 
-			rev := rc.rg.Rev() - rc.retention
+			curRev := rc.rg.Rev()
+			rc.prefixes.record(curRev)
+			rev := curRev - rc.retention
 			if rev <= 0 || rev == prev {
 				continue
 			}
@@ -88,7 +92,7 @@ func (rc *Revision) Run() {
 				zap.Int64("revision", rev),
 				zap.Int64("revision-compaction-retention", rc.retention),
 			)
-			_, err := rc.c.Compact(rc.ctx, &pb.CompactionRequest{Revision: rev})
+			_, err := rc.c.Compact(rc.ctx, &pb.CompactionRequest{Revision: rev, Retention: rc.prefixes.retention(rev)})
 			if err == nil || err == mvcc.ErrCompacted {
 				prev = rev
 				rc.lg.Info(
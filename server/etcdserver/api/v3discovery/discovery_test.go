@@ -757,6 +757,14 @@ func (fkv *fakeBaseKV) Compact(ctx context.Context, rev int64, opts ...clientv3.
 	return nil, nil
 }
 
+func (fkv *fakeBaseKV) RangeStats(ctx context.Context, key string, opts ...clientv3.OpOption) (*clientv3.RangeStatsResponse, error) {
+	return nil, nil
+}
+
+func (fkv *fakeBaseKV) MultiRange(ctx context.Context, ops []clientv3.Op, opts ...clientv3.OpOption) (*clientv3.MultiRangeResponse, error) {
+	return nil, nil
+}
+
 func (fkv *fakeBaseKV) Do(ctx context.Context, op clientv3.Op) (clientv3.OpResponse, error) {
 	return clientv3.OpResponse{}, nil
 }
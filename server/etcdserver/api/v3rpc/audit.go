@@ -0,0 +1,113 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3rpc
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+
+	"go.etcd.io/etcd/server/v3/etcdserver"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3audit"
+)
+
+const auditServicePrefix = "/etcdserverpb."
+
+func newAuditUnaryInterceptor(s *etcdserver.EtcdServer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		rpc, ok := auditRPC(s, info.FullMethod)
+		if !ok {
+			return handler(ctx, req)
+		}
+		resp, err := handler(ctx, req)
+		ev := newAuditEvent(ctx, s, rpc, err)
+		ev.Ranges, ev.Details = v3audit.RequestInfo(req)
+		if err == nil {
+			ev.Revision = v3audit.Revision(resp)
+		}
+		s.Auditor().Log(ev)
+		return resp, err
+	}
+}
+
+func newAuditStreamInterceptor(s *etcdserver.EtcdServer) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		rpc, ok := auditRPC(s, info.FullMethod)
+		if !ok {
+			return handler(srv, ss)
+		}
+		as := &auditServerStream{ServerStream: ss}
+		err := handler(srv, as)
+		ev := newAuditEvent(ss.Context(), s, rpc, err)
+		ev.Ranges, ev.Details = as.ranges, as.details
+		s.Auditor().Log(ev)
+		return err
+	}
+}
+
+// auditRPC returns the name of the etcd API method, if it may be audited.
+func auditRPC(s *etcdserver.EtcdServer, fullMethod string) (string, bool) {
+	if !strings.HasPrefix(fullMethod, auditServicePrefix) {
+		return "", false
+	}
+	rpc := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	return rpc, s.Auditor().Enabled(rpc)
+}
+
+func newAuditEvent(ctx context.Context, s *etcdserver.EtcdServer, rpc string, err error) v3audit.Event {
+	ev := v3audit.Event{Stage: v3audit.StageRPC, RPC: rpc}
+	if ai, aerr := s.AuthInfoFromCtx(ctx); aerr == nil && ai != nil {
+		ev.User = ai.Username
+	}
+	if p, ok := peer.FromContext(ctx); ok && p != nil {
+		if p.Addr != nil {
+			ev.RemoteAddr = p.Addr.String()
+		}
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(tlsInfo.State.PeerCertificates) > 0 {
+			ev.CommonName = tlsInfo.State.PeerCertificates[0].Subject.CommonName
+		}
+	}
+	if err != nil {
+		ev.Error = err.Error()
+	}
+	return ev
+}
+
+// auditServerStream collects the key ranges and arguments of the requests
+// received over a stream.
+type auditServerStream struct {
+	grpc.ServerStream
+
+	ranges  []v3audit.KeyRange
+	details map[string]string
+}
+
+func (ss *auditServerStream) RecvMsg(m interface{}) error {
+	if err := ss.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	ranges, details := v3audit.RequestInfo(m)
+	ss.ranges = append(ss.ranges, ranges...)
+	for k, v := range details {
+		if ss.details == nil {
+			ss.details = make(map[string]string)
+		}
+		ss.details[k] = v
+	}
+	return nil
+}
@@ -16,18 +16,28 @@ package v3rpc
 
 import (
 	"context"
+	"time"
 
+	"go.etcd.io/etcd/api/v3/authpb"
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	"go.etcd.io/etcd/server/v3/auth"
 	"go.etcd.io/etcd/server/v3/etcdserver"
+	"go.etcd.io/etcd/server/v3/lease"
 )
 
+// authWatchRetryInterval is the interval to read the auth state again at, if
+// it was read before the change of the auth revision was written.
+const authWatchRetryInterval = 10 * time.Millisecond
+
 type AuthServer struct {
 	authenticator etcdserver.Authenticator
+	ag            AuthGetter
+	hdr           header
 }
 
 func NewAuthServer(s *etcdserver.EtcdServer) *AuthServer {
-	return &AuthServer{authenticator: s}
+	return &AuthServer{authenticator: s, ag: s, hdr: newHeader(s)}
 }
 
 func (as *AuthServer) AuthEnable(ctx context.Context, r *pb.AuthEnableRequest) (*pb.AuthEnableResponse, error) {
@@ -110,6 +120,14 @@ func (as *AuthServer) RoleGrantPermission(ctx context.Context, r *pb.AuthRoleGra
 	return resp, nil
 }
 
+func (as *AuthServer) RoleSetLimits(ctx context.Context, r *pb.AuthRoleSetLimitsRequest) (*pb.AuthRoleSetLimitsResponse, error) {
+	resp, err := as.authenticator.RoleSetLimits(ctx, r)
+	if err != nil {
+		return nil, togRPCError(err)
+	}
+	return resp, nil
+}
+
 func (as *AuthServer) UserAdd(ctx context.Context, r *pb.AuthUserAddRequest) (*pb.AuthUserAddResponse, error) {
 	resp, err := as.authenticator.UserAdd(ctx, r)
 	if err != nil {
@@ -143,6 +161,9 @@ func (as *AuthServer) UserList(ctx context.Context, r *pb.AuthUserListRequest) (
 }
 
 func (as *AuthServer) UserGrantRole(ctx context.Context, r *pb.AuthUserGrantRoleRequest) (*pb.AuthUserGrantRoleResponse, error) {
+	if r.Ttl < 0 || r.Ttl > lease.MaxLeaseTTL {
+		return nil, rpctypes.ErrGRPCInvalidGrantTTL
+	}
 	resp, err := as.authenticator.UserGrantRole(ctx, r)
 	if err != nil {
 		return nil, togRPCError(err)
@@ -166,6 +187,106 @@ func (as *AuthServer) UserChangePassword(ctx context.Context, r *pb.AuthUserChan
 	return resp, nil
 }
 
+func (as *AuthServer) UserSetLimits(ctx context.Context, r *pb.AuthUserSetLimitsRequest) (*pb.AuthUserSetLimitsResponse, error) {
+	resp, err := as.authenticator.UserSetLimits(ctx, r)
+	if err != nil {
+		return nil, togRPCError(err)
+	}
+	return resp, nil
+}
+
+func (as *AuthServer) TokenList(ctx context.Context, r *pb.AuthTokenListRequest) (*pb.AuthTokenListResponse, error) {
+	resp, err := as.authenticator.TokenList(ctx, r)
+	if err != nil {
+		return nil, togRPCError(err)
+	}
+	return resp, nil
+}
+
+func (as *AuthServer) TokenRevoke(ctx context.Context, r *pb.AuthTokenRevokeRequest) (*pb.AuthTokenRevokeResponse, error) {
+	resp, err := as.authenticator.TokenRevoke(ctx, r)
+	if err != nil {
+		return nil, togRPCError(err)
+	}
+	return resp, nil
+}
+
+func (as *AuthServer) AuthExplain(ctx context.Context, r *pb.AuthExplainRequest) (*pb.AuthExplainResponse, error) {
+	if !isExplainablePermType(r.PermType) {
+		return nil, rpctypes.ErrGRPCInvalidAuthMgmt
+	}
+	resp, err := as.authenticator.AuthExplain(ctx, r)
+	if err != nil {
+		return nil, togRPCError(err)
+	}
+	resp.Header = &pb.ResponseHeader{}
+	as.hdr.fill(resp.Header)
+	return resp, nil
+}
+
+// isExplainablePermType returns whether a request of the permission type can
+// be explained. READWRITE is only a type of granted permissions.
+func isExplainablePermType(typ authpb.Permission_Type) bool {
+	switch typ {
+	case authpb.READ, authpb.WRITE, authpb.CREATE, authpb.DELETE, authpb.WATCH, authpb.LEASE:
+		return true
+	}
+	return false
+}
+
+func (as *AuthServer) AuthWatch(r *pb.AuthWatchRequest, stream pb.Auth_AuthWatchServer) error {
+	ctx := stream.Context()
+	ai, err := as.ag.AuthInfoFromCtx(ctx)
+	if err != nil {
+		return togRPCError(err)
+	}
+	st := as.ag.AuthStore()
+	if err = st.IsAdminPermitted(ai); err != nil {
+		return togRPCError(err)
+	}
+	rev := r.Revision
+	for {
+		notifyc := st.RevisionNotify()
+		if s := st.State(); s.Revision > rev {
+			// the users and roles may have changed the permissions of the watcher
+			if err = st.IsAdminPermitted(ai); err != nil {
+				return togRPCError(err)
+			}
+			resp := &pb.AuthWatchResponse{
+				Header:   &pb.ResponseHeader{},
+				Enabled:  s.Enabled,
+				Revision: s.Revision,
+				Users:    make([]*authpb.User, len(s.Users)),
+				Roles:    s.Roles,
+			}
+			for i, u := range s.Users {
+				user := *u
+				user.Password = nil
+				resp.Users[i] = &user
+			}
+			as.hdr.fill(resp.Header)
+			if err = stream.Send(resp); err != nil {
+				return togRPCError(err)
+			}
+			rev = s.Revision
+		}
+		if rev < st.Revision() {
+			// the change of the revision is not written yet
+			select {
+			case <-time.After(authWatchRetryInterval):
+				continue
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		select {
+		case <-notifyc:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 type AuthGetter interface {
 	AuthInfoFromCtx(ctx context.Context) (*auth.AuthInfo, error)
 	AuthStore() auth.AuthStore
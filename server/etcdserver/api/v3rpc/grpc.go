@@ -43,7 +43,9 @@ func Server(s *etcdserver.EtcdServer, tls *tls.Config, interceptor grpc.UnarySer
 	}
 	chainUnaryInterceptors := []grpc.UnaryServerInterceptor{
 		newLogUnaryInterceptor(s),
+		newAuditUnaryInterceptor(s),
 		newUnaryInterceptor(s),
+		newLimitUnaryInterceptor(s),
 		grpc_prometheus.UnaryServerInterceptor,
 	}
 	if interceptor != nil {
@@ -51,6 +53,7 @@ func Server(s *etcdserver.EtcdServer, tls *tls.Config, interceptor grpc.UnarySer
 	}
 
 	chainStreamInterceptors := []grpc.StreamServerInterceptor{
+		newAuditStreamInterceptor(s),
 		newStreamInterceptor(s),
 		grpc_prometheus.StreamServerInterceptor,
 	}
@@ -22,6 +22,7 @@ import (
 
 	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	"go.etcd.io/etcd/client/pkg/v3/types"
+	"go.etcd.io/etcd/server/v3/auth"
 	"go.etcd.io/etcd/server/v3/etcdserver"
 	"go.etcd.io/etcd/server/v3/etcdserver/api"
 	"go.etcd.io/raft/v3"
@@ -76,6 +77,22 @@ func newUnaryInterceptor(s *etcdserver.EtcdServer) grpc.UnaryServerInterceptor {
 	}
 }
 
+// newLimitUnaryInterceptor rejects the requests of the users exceeding their
+// request rate.
+func newLimitUnaryInterceptor(s *etcdserver.EtcdServer) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		// requests with invalid credentials are rejected by their handler
+		if ai, err := s.AuthInfoFromCtx(ctx); err == nil && ai != nil {
+			if err = s.Limiter().AllowRequest(ai); err != nil {
+				return nil, togRPCError(err)
+			}
+			// the handler does not need to resolve the credentials again
+			ctx = auth.WithAuthInfo(ctx, ai)
+		}
+		return handler(ctx, req)
+	}
+}
+
 func newLogUnaryInterceptor(s *etcdserver.EtcdServer) grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		startTime := time.Now()
@@ -16,12 +16,15 @@
 package v3rpc
 
 import (
+	"bytes"
 	"context"
+	"sort"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	"go.etcd.io/etcd/pkg/v3/adt"
 	"go.etcd.io/etcd/server/v3/etcdserver"
+	"go.etcd.io/etcd/server/v3/lease"
 )
 
 type kvServer struct {
@@ -52,6 +55,34 @@ func (s *kvServer) Range(ctx context.Context, r *pb.RangeRequest) (*pb.RangeResp
 	return resp, nil
 }
 
+func (s *kvServer) RangeStats(ctx context.Context, r *pb.RangeStatsRequest) (*pb.RangeStatsResponse, error) {
+	if err := checkRangeStatsRequest(r); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.kv.RangeStats(ctx, r)
+	if err != nil {
+		return nil, togRPCError(err)
+	}
+
+	s.hdr.fill(resp.Header)
+	return resp, nil
+}
+
+func (s *kvServer) MultiRange(ctx context.Context, r *pb.MultiRangeRequest) (*pb.MultiRangeResponse, error) {
+	if err := checkMultiRangeRequest(r); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.kv.MultiRange(ctx, r)
+	if err != nil {
+		return nil, togRPCError(err)
+	}
+
+	s.hdr.fill(resp.Header)
+	return resp, nil
+}
+
 func (s *kvServer) Put(ctx context.Context, r *pb.PutRequest) (*pb.PutResponse, error) {
 	if err := checkPutRequest(r); err != nil {
 		return nil, err
@@ -102,6 +133,10 @@ func (s *kvServer) Txn(ctx context.Context, r *pb.TxnRequest) (*pb.TxnResponse,
 }
 
 func (s *kvServer) Compact(ctx context.Context, r *pb.CompactionRequest) (*pb.CompactionResponse, error) {
+	if err := checkCompactionRequest(r); err != nil {
+		return nil, err
+	}
+
 	resp, err := s.kv.Compact(ctx, r)
 	if err != nil {
 		return nil, togRPCError(err)
@@ -124,6 +159,62 @@ func checkRangeRequest(r *pb.RangeRequest) error {
 		return rpctypes.ErrGRPCInvalidSortOption
 	}
 
+	return checkValueFilter(r.ValueFilter)
+}
+
+func checkRangeStatsRequest(r *pb.RangeStatsRequest) error {
+	if len(r.Key) == 0 {
+		return rpctypes.ErrGRPCEmptyKey
+	}
+	return nil
+}
+
+func checkMultiRangeRequest(r *pb.MultiRangeRequest) error {
+	for _, sr := range r.Ranges {
+		if len(sr.Key) == 0 {
+			return rpctypes.ErrGRPCEmptyKey
+		}
+	}
+	return nil
+}
+
+func checkValueFilter(f *pb.ValueFilter) error {
+	if f == nil {
+		return nil
+	}
+	if f.MinSize < 0 || f.MaxSize < 0 || (f.MaxSize != 0 && f.MaxSize < f.MinSize) {
+		return rpctypes.ErrGRPCInvalidValueFilter
+	}
+	return nil
+}
+
+// checkCompactionRequest ensures that the retention ranges of the compaction
+// are well formed and do not overlap.
+func checkCompactionRequest(r *pb.CompactionRequest) error {
+	rs := make([]*pb.CompactionRetention, len(r.Retention))
+	copy(rs, r.Retention)
+	sort.Slice(rs, func(i, j int) bool { return bytes.Compare(rs[i].Key, rs[j].Key) < 0 })
+	for i, cr := range rs {
+		if len(cr.Key) == 0 {
+			return rpctypes.ErrGRPCEmptyKey
+		}
+		fromKey := len(cr.RangeEnd) == 1 && cr.RangeEnd[0] == 0
+		if len(cr.RangeEnd) != 0 && !fromKey && bytes.Compare(cr.Key, cr.RangeEnd) >= 0 {
+			return rpctypes.ErrGRPCInvalidRetention
+		}
+		if i == len(rs)-1 {
+			break
+		}
+		next := rs[i+1].Key
+		switch {
+		case fromKey:
+			return rpctypes.ErrGRPCInvalidRetention
+		case len(cr.RangeEnd) == 0 && bytes.Equal(cr.Key, next):
+			return rpctypes.ErrGRPCInvalidRetention
+		case len(cr.RangeEnd) != 0 && bytes.Compare(next, cr.RangeEnd) < 0:
+			return rpctypes.ErrGRPCInvalidRetention
+		}
+	}
 	return nil
 }
 
@@ -137,6 +228,9 @@ func checkPutRequest(r *pb.PutRequest) error {
 	if r.IgnoreLease && r.Lease != 0 {
 		return rpctypes.ErrGRPCLeaseProvided
 	}
+	if r.Ttl < 0 || r.Ttl > lease.MaxLeaseTTL {
+		return rpctypes.ErrGRPCInvalidKeyTTL
+	}
 	return nil
 }
 
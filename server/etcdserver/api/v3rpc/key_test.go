@@ -59,6 +59,49 @@ func TestCheckRangeRequest(t *testing.T) {
 	}
 }
 
+func TestCheckCompactionRequest(t *testing.T) {
+	tests := []struct {
+		retention     []*pb.CompactionRetention
+		expectedError error
+	}{
+		{
+			retention: []*pb.CompactionRetention{
+				{Key: []byte("c"), RangeEnd: []byte("d")},
+				{Key: []byte("a"), RangeEnd: []byte("c")},
+				{Key: []byte("d"), RangeEnd: []byte{0}},
+			},
+			expectedError: nil,
+		},
+		{
+			retention:     []*pb.CompactionRetention{{RangeEnd: []byte("a")}},
+			expectedError: rpctypes.ErrGRPCEmptyKey,
+		},
+		{
+			retention:     []*pb.CompactionRetention{{Key: []byte("b"), RangeEnd: []byte("a")}},
+			expectedError: rpctypes.ErrGRPCInvalidRetention,
+		},
+		{
+			retention:     []*pb.CompactionRetention{{Key: []byte("a"), RangeEnd: []byte("c")}, {Key: []byte("b")}},
+			expectedError: rpctypes.ErrGRPCInvalidRetention,
+		},
+		{
+			retention:     []*pb.CompactionRetention{{Key: []byte("a")}, {Key: []byte("a"), RangeEnd: []byte("b")}},
+			expectedError: rpctypes.ErrGRPCInvalidRetention,
+		},
+		{
+			retention:     []*pb.CompactionRetention{{Key: []byte("a"), RangeEnd: []byte{0}}, {Key: []byte("z")}},
+			expectedError: rpctypes.ErrGRPCInvalidRetention,
+		},
+	}
+
+	for i, tt := range tests {
+		actualRet := checkCompactionRequest(&pb.CompactionRequest{Revision: 10, Retention: tt.retention})
+		if getError(actualRet) != getError(tt.expectedError) {
+			t.Errorf("#%d: expected %q, but got %q", i, getError(tt.expectedError), getError(actualRet))
+		}
+	}
+}
+
 func getError(err error) string {
 	if err == nil {
 		return ""
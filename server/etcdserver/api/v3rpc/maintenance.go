@@ -187,6 +187,101 @@ func (ms *maintenanceServer) Snapshot(sr *pb.SnapshotRequest, srv pb.Maintenance
 	return nil
 }
 
+// number of key-values sent in a message of the snapshot delta stream
+const snapshotDeltaBatchKeys = 1000
+
+func (ms *maintenanceServer) SnapshotDelta(sr *pb.SnapshotDeltaRequest, srv pb.Maintenance_SnapshotDeltaServer) error {
+	// the hash fixes the end revision of the delta, and the compaction it is checked against
+	h, _, err := ms.hasher.HashByRev(sr.EndRevision)
+	if err != nil {
+		return togRPCError(err)
+	}
+	if sr.StartRevision < h.CompactRevision {
+		return togRPCError(mvcc.ErrCompacted)
+	}
+	if sr.StartRevision > h.Revision {
+		return togRPCError(mvcc.ErrFutureRev)
+	}
+
+	be := ms.bg.Backend()
+	tx := be.ConcurrentReadTx()
+	tx.RLock()
+	defer tx.RUnlock()
+
+	start := time.Now()
+	ms.lg.Info("sending snapshot delta to client",
+		zap.Int64("start-revision", sr.StartRevision),
+		zap.Int64("end-revision", h.Revision),
+	)
+	sent := 0
+	rev, sub := sr.StartRevision+1, int64(0)
+	for {
+		rkvs, err := mvcc.UnsafeRangeRevisions(tx, rev, sub, h.Revision, snapshotDeltaBatchKeys)
+		if err != nil {
+			return togRPCError(err)
+		}
+		if len(rkvs) == 0 {
+			break
+		}
+		resp := &pb.SnapshotDeltaResponse{
+			Header:        &pb.ResponseHeader{},
+			Kvs:           make([]*pb.SnapshotDeltaKeyValue, len(rkvs)),
+			StartRevision: sr.StartRevision,
+			EndRevision:   h.Revision,
+		}
+		for i := range rkvs {
+			resp.Kvs[i] = &pb.SnapshotDeltaKeyValue{
+				Revision:    rkvs[i].Revision,
+				SubRevision: rkvs[i].SubRevision,
+				Tombstone:   rkvs[i].Tombstone,
+				Kv:          &rkvs[i].KV,
+			}
+		}
+		ms.hdr.fill(resp.Header)
+		if err = srv.Send(resp); err != nil {
+			return togRPCError(err)
+		}
+		sent += len(rkvs)
+		if len(rkvs) < snapshotDeltaBatchKeys {
+			break
+		}
+		last := rkvs[len(rkvs)-1]
+		rev, sub = last.Revision, last.SubRevision+1
+	}
+
+	resp := &pb.SnapshotDeltaResponse{
+		Header:          &pb.ResponseHeader{},
+		StartRevision:   sr.StartRevision,
+		EndRevision:     h.Revision,
+		Hash:            h.Hash,
+		CompactRevision: h.CompactRevision,
+		Auth:            &pb.SnapshotDeltaAuth{},
+		Done:            true,
+	}
+	for _, l := range schema.MustUnsafeGetAllLeases(tx) {
+		resp.Leases = append(resp.Leases, &pb.SnapshotDeltaLease{ID: l.ID, TTL: l.TTL, RemainingTTL: l.RemainingTTL, Parent: l.Parent, Owner: l.Owner})
+	}
+	atx := schema.NewAuthBackend(ms.lg, be).ReadTx()
+	atx.RLock()
+	resp.Auth.Enabled = atx.UnsafeReadAuthEnabled()
+	resp.Auth.Revision = atx.UnsafeReadAuthRevision()
+	resp.Auth.Users = atx.UnsafeGetAllUsers()
+	resp.Auth.Roles = atx.UnsafeGetAllRoles()
+	atx.RUnlock()
+	ms.hdr.fill(resp.Header)
+	if err := srv.Send(resp); err != nil {
+		return togRPCError(err)
+	}
+
+	ms.lg.Info("successfully sent snapshot delta to client",
+		zap.Int64("start-revision", sr.StartRevision),
+		zap.Int64("end-revision", h.Revision),
+		zap.Int("key-values", sent),
+		zap.Duration("took", time.Since(start)),
+	)
+	return nil
+}
+
 func (ms *maintenanceServer) Hash(ctx context.Context, r *pb.HashRequest) (*pb.HashResponse, error) {
 	h, rev, err := ms.hasher.Hash()
 	if err != nil {
@@ -293,6 +388,14 @@ func (ams *authMaintenanceServer) Snapshot(sr *pb.SnapshotRequest, srv pb.Mainte
 	return ams.maintenanceServer.Snapshot(sr, srv)
 }
 
+func (ams *authMaintenanceServer) SnapshotDelta(sr *pb.SnapshotDeltaRequest, srv pb.Maintenance_SnapshotDeltaServer) error {
+	if err := ams.isPermitted(srv.Context()); err != nil {
+		return togRPCError(err)
+	}
+
+	return ams.maintenanceServer.SnapshotDelta(sr, srv)
+}
+
 func (ams *authMaintenanceServer) Hash(ctx context.Context, r *pb.HashRequest) (*pb.HashResponse, error) {
 	if err := ams.isPermitted(ctx); err != nil {
 		return nil, togRPCError(err)
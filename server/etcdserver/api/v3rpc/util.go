@@ -59,6 +59,8 @@ var toGRPCErrorMap = map[error]error{
 	errors.ErrKeyNotFound:                rpctypes.ErrGRPCKeyNotFound,
 	errors.ErrCorrupt:                    rpctypes.ErrGRPCCorrupt,
 	errors.ErrBadLeaderTransferee:        rpctypes.ErrGRPCBadLeaderTransferee,
+	errors.ErrInvalidContinuation:        rpctypes.ErrGRPCInvalidContinuation,
+	errors.ErrStaleRead:                  rpctypes.ErrGRPCStaleRead,
 
 	errors.ErrClusterVersionUnavailable:      rpctypes.ErrGRPCClusterVersionUnavailable,
 	errors.ErrWrongDowngradeVersionFormat:    rpctypes.ErrGRPCWrongDowngradeVersionFormat,
@@ -87,6 +89,9 @@ var toGRPCErrorMap = map[error]error{
 	auth.ErrInvalidAuthToken:     rpctypes.ErrGRPCInvalidAuthToken,
 	auth.ErrInvalidAuthMgmt:      rpctypes.ErrGRPCInvalidAuthMgmt,
 	auth.ErrAuthOldRevision:      rpctypes.ErrGRPCAuthOldRevision,
+	auth.ErrLimitExceeded:        rpctypes.ErrGRPCLimitExceeded,
+	auth.ErrTokenNotFound:        rpctypes.ErrGRPCTokenNotFound,
+	auth.ErrInvalidTokenScope:    rpctypes.ErrGRPCInvalidTokenScope,
 
 	// In sync with status.FromContextError
 	context.Canceled:         rpctypes.ErrGRPCCanceled,
@@ -144,6 +149,10 @@ func isRPCSupportedForLearner(req interface{}) bool {
 		return true
 	case *pb.RangeRequest:
 		return r.Serializable
+	case *pb.RangeStatsRequest:
+		return r.Serializable
+	case *pb.MultiRangeRequest:
+		return r.Serializable
 	default:
 		return false
 	}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"io"
 	"math/rand"
+	"path"
 	"sync"
 	"time"
 
@@ -29,6 +30,7 @@ import (
 	"go.etcd.io/etcd/server/v3/auth"
 	"go.etcd.io/etcd/server/v3/etcdserver"
 	"go.etcd.io/etcd/server/v3/etcdserver/apply"
+	"go.etcd.io/etcd/server/v3/etcdserver/txn"
 	"go.etcd.io/etcd/server/v3/storage/mvcc"
 
 	"go.uber.org/zap"
@@ -47,6 +49,7 @@ type watchServer struct {
 	sg        apply.RaftStatusGetter
 	watchable mvcc.WatchableKV
 	ag        AuthGetter
+	limiter   *auth.Limiter
 }
 
 // NewWatchServer returns a new watch server.
@@ -62,6 +65,7 @@ func NewWatchServer(s *etcdserver.EtcdServer) pb.WatchServer {
 		sg:        s,
 		watchable: s.Watchable(),
 		ag:        s,
+		limiter:   s.Limiter(),
 	}
 	if srv.lg == nil {
 		srv.lg = zap.NewNop()
@@ -130,12 +134,13 @@ type serverWatchStream struct {
 	sg        apply.RaftStatusGetter
 	watchable mvcc.WatchableKV
 	ag        AuthGetter
+	limiter   *auth.Limiter
 
 	gRPCStream  pb.Watch_WatchServer
 	watchStream mvcc.WatchStream
 	ctrlStream  chan *pb.WatchResponse
 
-	// mu protects progress, prevKV, fragment
+	// mu protects progress, prevKV, fragment, users, resume
 	mu sync.RWMutex
 	// tracks the watchID that stream might need to send progress to
 	// TODO: combine progress and prevKV into a single struct?
@@ -144,6 +149,10 @@ type serverWatchStream struct {
 	prevKV map[mvcc.WatchID]bool
 	// records fragmented watch IDs
 	fragment map[mvcc.WatchID]bool
+	// records the users of the watch IDs counted by the limiter
+	users map[mvcc.WatchID]*auth.AuthInfo
+	// records the resume state of the watch IDs
+	resume map[mvcc.WatchID]*watchResume
 
 	// indicates whether we have an outstanding global progress
 	// notification to send
@@ -168,6 +177,7 @@ func (ws *watchServer) Watch(stream pb.Watch_WatchServer) (err error) {
 		sg:        ws.sg,
 		watchable: ws.watchable,
 		ag:        ws.ag,
+		limiter:   ws.limiter,
 
 		gRPCStream:  stream,
 		watchStream: ws.watchable.NewWatchStream(),
@@ -177,6 +187,8 @@ func (ws *watchServer) Watch(stream pb.Watch_WatchServer) (err error) {
 		progress: make(map[mvcc.WatchID]bool),
 		prevKV:   make(map[mvcc.WatchID]bool),
 		fragment: make(map[mvcc.WatchID]bool),
+		users:    make(map[mvcc.WatchID]*auth.AuthInfo),
+		resume:   make(map[mvcc.WatchID]*watchResume),
 
 		deferredProgress: false,
 
@@ -232,16 +244,28 @@ func (ws *watchServer) Watch(stream pb.Watch_WatchServer) (err error) {
 	return err
 }
 
-func (sws *serverWatchStream) isWatchPermitted(wcr *pb.WatchCreateRequest) error {
+func (sws *serverWatchStream) isWatchPermitted(wcr *pb.WatchCreateRequest) (*auth.AuthInfo, error) {
 	authInfo, err := sws.ag.AuthInfoFromCtx(sws.gRPCStream.Context())
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if authInfo == nil {
-		// if auth is enabled, IsRangePermitted() can cause an error
+		// if auth is enabled, IsWatchPermitted() can cause an error
 		authInfo = &auth.AuthInfo{}
 	}
-	return sws.ag.AuthStore().IsRangePermitted(authInfo, wcr.Key, wcr.RangeEnd)
+	return authInfo, sws.ag.AuthStore().IsWatchPermitted(authInfo, wcr.Key, wcr.RangeEnd)
+}
+
+// releaseWatch uncounts the watch from the watches of its user.
+func (sws *serverWatchStream) releaseWatch(id mvcc.WatchID) {
+	sws.mu.Lock()
+	ai, ok := sws.users[id]
+	delete(sws.users, id)
+	delete(sws.resume, id)
+	sws.mu.Unlock()
+	if ok {
+		sws.limiter.ReleaseWatch(ai)
+	}
 }
 
 func (sws *serverWatchStream) recvLoop() error {
@@ -261,6 +285,8 @@ func (sws *serverWatchStream) recvLoop() error {
 			}
 
 			creq := uv.CreateRequest
+			// the tokens identify the watch as requested by the client
+			tokens := NewResumeTokens(creq)
 			if len(creq.Key) == 0 {
 				// \x00 is the smallest key
 				creq.Key = []byte{0}
@@ -275,7 +301,7 @@ func (sws *serverWatchStream) recvLoop() error {
 				creq.RangeEnd = []byte{}
 			}
 
-			err := sws.isWatchPermitted(creq)
+			authInfo, err := sws.isWatchPermitted(creq)
 			if err != nil {
 				var cancelReason string
 				switch err {
@@ -308,10 +334,52 @@ func (sws *serverWatchStream) recvLoop() error {
 				}
 			}
 
+			var resumeRev, skip int64
+			err = CheckWatchFilters(creq)
+			if err == nil && len(creq.ResumeToken) != 0 {
+				resumeRev, skip, err = tokens.Decode(creq.ResumeToken)
+			}
+			if err != nil {
+				wr := &pb.WatchResponse{
+					Header:       sws.newResponseHeader(sws.watchStream.Rev()),
+					WatchId:      clientv3.InvalidWatchID,
+					Canceled:     true,
+					Created:      true,
+					CancelReason: err.Error(),
+				}
+
+				select {
+				case sws.ctrlStream <- wr:
+					continue
+				case <-sws.closec:
+					return nil
+				}
+			}
+
+			if err := sws.limiter.AcquireWatch(authInfo); err != nil {
+				wr := &pb.WatchResponse{
+					Header:       sws.newResponseHeader(sws.watchStream.Rev()),
+					WatchId:      clientv3.InvalidWatchID,
+					Canceled:     true,
+					Created:      true,
+					CancelReason: rpctypes.ErrGRPCLimitExceeded.Error(),
+				}
+
+				select {
+				case sws.ctrlStream <- wr:
+					continue
+				case <-sws.closec:
+					return nil
+				}
+			}
+
 			filters := FiltersFromRequest(creq)
 
 			wsrev := sws.watchStream.Rev()
 			rev := creq.StartRevision
+			if resumeRev != 0 {
+				rev = resumeRev
+			}
 			if rev == 0 {
 				rev = wsrev + 1
 			}
@@ -327,8 +395,11 @@ func (sws *serverWatchStream) recvLoop() error {
 				if creq.Fragment {
 					sws.fragment[id] = true
 				}
+				sws.users[id] = authInfo
+				sws.resume[id] = &watchResume{tokens: tokens, skipRev: rev, skip: skip}
 				sws.mu.Unlock()
 			} else {
+				sws.limiter.ReleaseWatch(authInfo)
 				id = clientv3.InvalidWatchID
 			}
 
@@ -340,6 +411,8 @@ func (sws *serverWatchStream) recvLoop() error {
 			}
 			if err != nil {
 				wr.CancelReason = err.Error()
+			} else {
+				wr.ResumeToken = tokens.Encode(rev, skip)
 			}
 			select {
 			case sws.ctrlStream <- wr:
@@ -361,7 +434,9 @@ func (sws *serverWatchStream) recvLoop() error {
 					delete(sws.progress, mvcc.WatchID(id))
 					delete(sws.prevKV, mvcc.WatchID(id))
 					delete(sws.fragment, mvcc.WatchID(id))
+					delete(sws.resume, mvcc.WatchID(id))
 					sws.mu.Unlock()
+					sws.releaseWatch(mvcc.WatchID(id))
 				}
 			}
 		case *pb.WatchRequest_ProgressRequest:
@@ -435,7 +510,7 @@ func (sws *serverWatchStream) sendLoop() {
 				}
 			}
 
-			canceled := wresp.CompactRevision != 0
+			canceled := wresp.CompactRevision != 0 || wresp.Dropped
 			wr := &pb.WatchResponse{
 				Header:          sws.newResponseHeader(wresp.Revision),
 				WatchId:         int64(wresp.WatchID),
@@ -443,6 +518,13 @@ func (sws *serverWatchStream) sendLoop() {
 				CompactRevision: wresp.CompactRevision,
 				Canceled:        canceled,
 			}
+			if wresp.Dropped {
+				wr.CancelReason = rpctypes.ErrorDesc(rpctypes.ErrGRPCWatchTooSlow)
+			}
+			if canceled {
+				// the watcher is canceled by compaction or for being too slow
+				sws.releaseWatch(wresp.WatchID)
+			}
 
 			// Progress notifications can have WatchID -1
 			// if they announce on behalf of multiple watchers
@@ -462,10 +544,18 @@ func (sws *serverWatchStream) sendLoop() {
 			sws.mu.RUnlock()
 
 			var serr error
-			if !fragmented && !ok {
+			if token, send := sws.resumeAfter(wr); !send {
+				continue
+			} else if !fragmented && !ok {
+				wr.ResumeToken = token(len(wr.Events))
 				serr = sws.gRPCStream.Send(wr)
 			} else {
-				serr = sendFragments(wr, sws.maxRequestBytes, sws.gRPCStream.Send)
+				sent := 0
+				serr = sendFragments(wr, sws.maxRequestBytes, func(fr *pb.WatchResponse) error {
+					sent += len(fr.Events)
+					fr.ResumeToken = token(sent)
+					return sws.gRPCStream.Send(fr)
+				})
 			}
 
 			if serr != nil {
@@ -519,6 +609,11 @@ func (sws *serverWatchStream) sendLoop() {
 				ids[wid] = struct{}{}
 				for _, v := range pending[wid] {
 					mvcc.ReportEventReceived(len(v.Events))
+					token, send := sws.resumeAfter(v)
+					if !send {
+						continue
+					}
+					v.ResumeToken = token(len(v.Events))
 					if err := sws.gRPCStream.Send(v); err != nil {
 						if isClientCtxErr(sws.gRPCStream.Context().Err(), err) {
 							sws.lg.Debug("failed to send pending watch response to gRPC stream", zap.Error(err))
@@ -548,6 +643,63 @@ func (sws *serverWatchStream) sendLoop() {
 	}
 }
 
+// watchResume is the resume state of a watch.
+type watchResume struct {
+	tokens ResumeTokens
+	// skip is the number of events of revision skipRev the watch has sent
+	// before resuming, which it must not send again.
+	skipRev int64
+	skip    int64
+}
+
+// resumeAfter drops the events of the response the watch has sent before
+// resuming, and returns the resume token of the watch after the given number
+// of events of the response. send is false if there is no event left to send.
+func (sws *serverWatchStream) resumeAfter(wr *pb.WatchResponse) (token func(n int) []byte, send bool) {
+	sws.mu.Lock()
+	r := sws.resume[mvcc.WatchID(wr.WatchId)]
+	if r == nil || wr.Canceled {
+		sws.mu.Unlock()
+		return func(int) []byte { return nil }, true
+	}
+	skipRev, skip := r.skipRev, r.skip
+	if len(wr.Events) > 0 && r.skip > 0 {
+		i := 0
+		for i < len(wr.Events) && int64(i) < skip && wr.Events[i].Kv.ModRevision == skipRev {
+			i++
+		}
+		wr.Events = wr.Events[i:]
+		// responses hold whole revisions
+		r.skip = 0
+		if len(wr.Events) == 0 {
+			sws.mu.Unlock()
+			return nil, false
+		}
+	}
+	sws.mu.Unlock()
+
+	return func(n int) []byte {
+		if n == 0 {
+			// progress notification, every event up to its revision is sent
+			return r.tokens.Encode(wr.Header.Revision+1, 0)
+		}
+		last := wr.Events[n-1].Kv.ModRevision
+		if n == len(wr.Events) || wr.Events[n].Kv.ModRevision != last {
+			return r.tokens.Encode(last+1, 0)
+		}
+		var sent int64
+		for _, ev := range wr.Events[:n] {
+			if ev.Kv.ModRevision == last {
+				sent++
+			}
+		}
+		if last == skipRev {
+			sent += skip
+		}
+		return r.tokens.Encode(last, sent)
+	}, true
+}
+
 func IsCreateEvent(e mvccpb.Event) bool {
 	return e.Type == mvccpb.PUT && e.Kv.CreateRevision == e.Kv.ModRevision
 }
@@ -595,6 +747,13 @@ func (sws *serverWatchStream) close() {
 	sws.watchStream.Close()
 	close(sws.closec)
 	sws.wg.Wait()
+
+	sws.mu.Lock()
+	for _, ai := range sws.users {
+		sws.limiter.ReleaseWatch(ai)
+	}
+	sws.users = nil
+	sws.mu.Unlock()
 }
 
 func (sws *serverWatchStream) newResponseHeader(rev int64) *pb.ResponseHeader {
@@ -614,9 +773,38 @@ func filterNoPut(e mvccpb.Event) bool {
 	return e.Type == mvccpb.PUT
 }
 
+func filterValue(f *pb.ValueFilter) mvcc.FilterFunc {
+	match := txn.ValueFilter(f)
+	return func(e mvccpb.Event) bool {
+		return !match(e.Kv)
+	}
+}
+
+func filterKeyGlob(pattern string) mvcc.FilterFunc {
+	return func(e mvccpb.Event) bool {
+		// the pattern is validated when the watcher is created
+		matched, _ := path.Match(pattern, string(e.Kv.Key))
+		return !matched
+	}
+}
+
+// CheckWatchFilters ensures that the value filter and the key glob
+// of a given watch create request are well formed.
+func CheckWatchFilters(creq *pb.WatchCreateRequest) error {
+	if err := checkValueFilter(creq.ValueFilter); err != nil {
+		return err
+	}
+	if creq.KeyGlob != "" {
+		if _, err := path.Match(creq.KeyGlob, ""); err != nil {
+			return rpctypes.ErrGRPCInvalidKeyGlob
+		}
+	}
+	return nil
+}
+
 // FiltersFromRequest returns "mvcc.FilterFunc" from a given watch create request.
 func FiltersFromRequest(creq *pb.WatchCreateRequest) []mvcc.FilterFunc {
-	filters := make([]mvcc.FilterFunc, 0, len(creq.Filters))
+	filters := make([]mvcc.FilterFunc, 0, len(creq.Filters)+2)
 	for _, ft := range creq.Filters {
 		switch ft {
 		case pb.WatchCreateRequest_NOPUT:
@@ -626,5 +814,11 @@ func FiltersFromRequest(creq *pb.WatchCreateRequest) []mvcc.FilterFunc {
 		default:
 		}
 	}
+	if creq.ValueFilter != nil {
+		filters = append(filters, filterValue(creq.ValueFilter))
+	}
+	if creq.KeyGlob != "" {
+		filters = append(filters, filterKeyGlob(creq.KeyGlob))
+	}
 	return filters
 }
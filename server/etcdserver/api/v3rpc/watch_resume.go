@@ -0,0 +1,106 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v3rpc
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+)
+
+const resumeTokenVersion = 1
+
+// ResumeTokens issues and checks the resume tokens of a watch. A token is the
+// position of the watch in its history: the revision of the next event to
+// send, and the number of events of that revision already sent.
+type ResumeTokens struct {
+	// digest identifies the events the watch receives, so that a token only
+	// resumes the same watch.
+	digest uint32
+}
+
+// NewResumeTokens returns the resume tokens of the watch created by the
+// request, as sent by the client.
+func NewResumeTokens(creq *pb.WatchCreateRequest) ResumeTokens {
+	h := crc32.NewIEEE()
+	write := func(b []byte) {
+		h.Write(binary.AppendUvarint(nil, uint64(len(b))))
+		h.Write(b)
+	}
+	write(creq.Key)
+	write(creq.RangeEnd)
+	for _, ft := range creq.Filters {
+		h.Write(binary.AppendUvarint(nil, uint64(ft)))
+	}
+	var vf []byte
+	if creq.ValueFilter != nil {
+		vf, _ = creq.ValueFilter.Marshal()
+	}
+	write(vf)
+	write([]byte(creq.KeyGlob))
+	return ResumeTokens{digest: h.Sum32()}
+}
+
+// Encode returns the token resuming the watch at the revision, after the first
+// skip events of that revision. Its layout is the version, the revision and
+// skip as uvarints, followed by the digest of the watch.
+func (rt ResumeTokens) Encode(rev, skip int64) []byte {
+	buf := make([]byte, 0, 3*binary.MaxVarintLen64+4)
+	buf = binary.AppendUvarint(buf, resumeTokenVersion)
+	buf = binary.AppendUvarint(buf, uint64(rev))
+	buf = binary.AppendUvarint(buf, uint64(skip))
+	return binary.BigEndian.AppendUint32(buf, rt.digest)
+}
+
+// Decode returns the revision and the number of events of that revision to
+// skip to resume the watch at the token returned by Encode.
+func (rt ResumeTokens) Decode(token []byte) (rev, skip int64, err error) {
+	rev, skip, digest, err := parseResumeToken(token)
+	if err != nil || digest != rt.digest {
+		return 0, 0, rpctypes.ErrGRPCInvalidResumeToken
+	}
+	return rev, skip, nil
+}
+
+// ResumeTokenPosition returns the revision and the number of events of that
+// revision to skip the token resumes a watch at, whichever watch it is of.
+func ResumeTokenPosition(token []byte) (rev, skip int64, err error) {
+	rev, skip, _, err = parseResumeToken(token)
+	return rev, skip, err
+}
+
+func parseResumeToken(token []byte) (rev, skip int64, digest uint32, err error) {
+	ver, n := binary.Uvarint(token)
+	if n <= 0 || ver != resumeTokenVersion {
+		return 0, 0, 0, rpctypes.ErrGRPCInvalidResumeToken
+	}
+	token = token[n:]
+	urev, n := binary.Uvarint(token)
+	if n <= 0 || urev == 0 || int64(urev) < 0 {
+		return 0, 0, 0, rpctypes.ErrGRPCInvalidResumeToken
+	}
+	token = token[n:]
+	uskip, n := binary.Uvarint(token)
+	if n <= 0 || int64(uskip) < 0 {
+		return 0, 0, 0, rpctypes.ErrGRPCInvalidResumeToken
+	}
+	token = token[n:]
+	if len(token) != 4 {
+		return 0, 0, 0, rpctypes.ErrGRPCInvalidResumeToken
+	}
+	return int64(urev), int64(uskip), binary.BigEndian.Uint32(token), nil
+}
@@ -21,6 +21,8 @@ import (
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
 )
 
 func TestSendFragment(t *testing.T) {
@@ -82,6 +84,85 @@ func TestSendFragment(t *testing.T) {
 	}
 }
 
+func TestResumeTokens(t *testing.T) {
+	creq := &pb.WatchCreateRequest{Key: []byte("foo"), RangeEnd: []byte("fop")}
+	rt := NewResumeTokens(creq)
+	rev, skip, err := rt.Decode(rt.Encode(10, 2))
+	if err != nil || rev != 10 || skip != 2 {
+		t.Fatalf("Decode(Encode(10, 2)) = %d, %d, %v", rev, skip, err)
+	}
+
+	token := rt.Encode(10, 0)
+	for i, creq := range []*pb.WatchCreateRequest{
+		{Key: []byte("foo")},
+		{Key: []byte("foo"), RangeEnd: []byte("fop"), Filters: []pb.WatchCreateRequest_FilterType{pb.WatchCreateRequest_NOPUT}},
+		{Key: []byte("foo"), RangeEnd: []byte("fop"), ValueFilter: &pb.ValueFilter{Prefix: []byte("bar")}},
+		{Key: []byte("foo"), RangeEnd: []byte("fop"), KeyGlob: "f*"},
+	} {
+		if _, _, err = NewResumeTokens(creq).Decode(token); err != rpctypes.ErrGRPCInvalidResumeToken {
+			t.Errorf("#%d: expected %v resuming another watch, got %v", i, rpctypes.ErrGRPCInvalidResumeToken, err)
+		}
+	}
+	for i, token := range [][]byte{nil, token[:len(token)-1], append(token, 0), rt.Encode(0, 0)} {
+		if _, _, err = rt.Decode(token); err != rpctypes.ErrGRPCInvalidResumeToken {
+			t.Errorf("#%d: expected %v, got %v", i, rpctypes.ErrGRPCInvalidResumeToken, err)
+		}
+	}
+}
+
+func TestResumeAfter(t *testing.T) {
+	rt := NewResumeTokens(&pb.WatchCreateRequest{Key: []byte("foo")})
+	sws := &serverWatchStream{resume: map[mvcc.WatchID]*watchResume{
+		1: {tokens: rt, skipRev: 5, skip: 2},
+	}}
+	ev := func(rev int64) *mvccpb.Event {
+		return &mvccpb.Event{Kv: &mvccpb.KeyValue{Key: []byte("foo"), ModRevision: rev}}
+	}
+
+	// the events sent before resuming are dropped
+	wr := &pb.WatchResponse{WatchId: 1, Header: &pb.ResponseHeader{Revision: 6}, Events: []*mvccpb.Event{ev(5), ev(5), ev(5), ev(6)}}
+	token, send := sws.resumeAfter(wr)
+	if !send || len(wr.Events) != 2 {
+		t.Fatalf("expected 2 events to send, got %d (send %v)", len(wr.Events), send)
+	}
+	for i, tt := range []struct{ n, rev, skip int64 }{
+		{0, 7, 0},
+		{1, 6, 0},
+		{2, 7, 0},
+	} {
+		rev, skip, err := rt.Decode(token(int(tt.n)))
+		if err != nil || rev != tt.rev || skip != tt.skip {
+			t.Errorf("#%d: expected token at %d after %d, got %d after %d (%v)", i, tt.rev, tt.skip, rev, skip, err)
+		}
+	}
+
+	// the token of a fragment counts the events of its revision already sent
+	wr = &pb.WatchResponse{WatchId: 1, Header: &pb.ResponseHeader{Revision: 7}, Events: []*mvccpb.Event{ev(7), ev(7), ev(7)}}
+	if token, send = sws.resumeAfter(wr); !send || len(wr.Events) != 3 {
+		t.Fatalf("expected 3 events to send, got %d (send %v)", len(wr.Events), send)
+	}
+	if rev, skip, err := rt.Decode(token(2)); err != nil || rev != 7 || skip != 2 {
+		t.Errorf("expected token at 7 after 2, got %d after %d (%v)", rev, skip, err)
+	}
+
+	// responses holding only events sent before resuming are not sent
+	sws.resume[1].skip = 1
+	wr = &pb.WatchResponse{WatchId: 1, Header: &pb.ResponseHeader{Revision: 5}, Events: []*mvccpb.Event{ev(5)}}
+	if _, send = sws.resumeAfter(wr); send {
+		t.Errorf("expected response of skipped events not to be sent")
+	}
+
+	// unknown and canceled watches have no token
+	for i, wr := range []*pb.WatchResponse{
+		{WatchId: -1, Header: &pb.ResponseHeader{Revision: 5}},
+		{WatchId: 1, Header: &pb.ResponseHeader{Revision: 5}, Canceled: true, CompactRevision: 3},
+	} {
+		if token, send = sws.resumeAfter(wr); !send || token(0) != nil {
+			t.Errorf("#%d: expected response to be sent without token", i)
+		}
+	}
+}
+
 func createResponse(dataSize, events int) (resp *pb.WatchResponse) {
 	resp = &pb.WatchResponse{Events: make([]*mvccpb.Event, events)}
 	for i := range resp.Events {
@@ -82,6 +82,10 @@ type applierV3 interface {
 
 	LeaseCheckpoint(lc *pb.LeaseCheckpointRequest) (*pb.LeaseCheckpointResponse, error)
 
+	KeyExpire(ctx context.Context, kr *pb.KeyExpireRequest) (*pb.DeleteRangeResponse, *traceutil.Trace, error)
+
+	RoleGrantExpire(rr *pb.AuthRoleGrantExpireRequest) (*pb.AuthUserRevokeRoleResponse, error)
+
 	Alarm(*pb.AlarmRequest) (*pb.AlarmResponse, error)
 
 	Authenticate(r *pb.InternalAuthenticateRequest) (*pb.AuthenticateResponse, error)
@@ -93,7 +97,7 @@ type applierV3 interface {
 	UserAdd(ua *pb.AuthUserAddRequest) (*pb.AuthUserAddResponse, error)
 	UserDelete(ua *pb.AuthUserDeleteRequest) (*pb.AuthUserDeleteResponse, error)
 	UserChangePassword(ua *pb.AuthUserChangePasswordRequest) (*pb.AuthUserChangePasswordResponse, error)
-	UserGrantRole(ua *pb.AuthUserGrantRoleRequest) (*pb.AuthUserGrantRoleResponse, error)
+	UserGrantRole(ctx context.Context, ua *pb.AuthUserGrantRoleRequest) (*pb.AuthUserGrantRoleResponse, error)
 	UserGet(ua *pb.AuthUserGetRequest) (*pb.AuthUserGetResponse, error)
 	UserRevokeRole(ua *pb.AuthUserRevokeRoleRequest) (*pb.AuthUserRevokeRoleResponse, error)
 	RoleAdd(ua *pb.AuthRoleAddRequest) (*pb.AuthRoleAddResponse, error)
@@ -103,6 +107,10 @@ type applierV3 interface {
 	RoleDelete(ua *pb.AuthRoleDeleteRequest) (*pb.AuthRoleDeleteResponse, error)
 	UserList(ua *pb.AuthUserListRequest) (*pb.AuthUserListResponse, error)
 	RoleList(ua *pb.AuthRoleListRequest) (*pb.AuthRoleListResponse, error)
+	UserSetLimits(ua *pb.AuthUserSetLimitsRequest) (*pb.AuthUserSetLimitsResponse, error)
+	RoleSetLimits(ua *pb.AuthRoleSetLimitsRequest) (*pb.AuthRoleSetLimitsResponse, error)
+	TokenList(ua *pb.AuthTokenListRequest) (*pb.AuthTokenListResponse, error)
+	TokenRevoke(ua *pb.AuthTokenRevokeRequest) (*pb.AuthTokenRevokeResponse, error)
 
 	// processing internal V3 raft request
 
@@ -181,7 +189,7 @@ func (a *applierV3backend) Compaction(compaction *pb.CompactionRequest) (*pb.Com
 		traceutil.Field{Key: "revision", Value: compaction.Revision},
 	)
 
-	ch, err := a.kv.Compact(trace, compaction.Revision)
+	ch, err := a.kv.CompactWithRetention(trace, compaction.Revision, keyRetention(compaction.Retention))
 	if err != nil {
 		return nil, ch, nil, err
 	}
@@ -191,8 +199,35 @@ func (a *applierV3backend) Compaction(compaction *pb.CompactionRequest) (*pb.Com
 	return resp, ch, trace, err
 }
 
+func keyRetention(retention []*pb.CompactionRetention) []mvcc.KeyRetention {
+	if len(retention) == 0 {
+		return nil
+	}
+	krs := make([]mvcc.KeyRetention, 0, len(retention))
+	for _, r := range retention {
+		end := r.RangeEnd
+		switch {
+		case len(end) == 0:
+			end = nil
+		case len(end) == 1 && end[0] == 0:
+			// all keys greater than or equal to the key
+			end = []byte{}
+		}
+		krs = append(krs, mvcc.KeyRetention{Key: r.Key, End: end, Revision: r.Revision})
+	}
+	return krs
+}
+
 func (a *applierV3backend) LeaseGrant(lc *pb.LeaseGrantRequest) (*pb.LeaseGrantResponse, error) {
-	l, err := a.lessor.Grant(lease.LeaseID(lc.ID), lc.TTL)
+	var (
+		l   *lease.Lease
+		err error
+	)
+	if lc.Parent != int64(lease.NoLease) {
+		l, err = a.lessor.GrantChild(lease.LeaseID(lc.ID), lease.LeaseID(lc.Parent))
+	} else {
+		l, err = a.lessor.Grant(lease.LeaseID(lc.ID), lc.TTL)
+	}
 	resp := &pb.LeaseGrantResponse{}
 	if err == nil {
 		resp.ID = int64(l.ID)
@@ -217,6 +252,18 @@ func (a *applierV3backend) LeaseCheckpoint(lc *pb.LeaseCheckpointRequest) (*pb.L
 	return &pb.LeaseCheckpointResponse{Header: a.newHeader()}, nil
 }
 
+func (a *applierV3backend) KeyExpire(ctx context.Context, kr *pb.KeyExpireRequest) (*pb.DeleteRangeResponse, *traceutil.Trace, error) {
+	return mvcctxn.ExpireKeys(ctx, a.lg, a.kv, kr)
+}
+
+func (a *applierV3backend) RoleGrantExpire(rr *pb.AuthRoleGrantExpireRequest) (*pb.AuthUserRevokeRoleResponse, error) {
+	resp, err := a.authStore.RevokeExpiredRoleGrants(rr)
+	if resp != nil {
+		resp.Header = a.newHeader()
+	}
+	return resp, err
+}
+
 func (a *applierV3backend) Alarm(ar *pb.AlarmRequest) (*pb.AlarmResponse, error) {
 	resp := &pb.AlarmResponse{}
 
@@ -291,7 +338,11 @@ func (a *applierV3backend) AuthStatus() (*pb.AuthStatusResponse, error) {
 
 func (a *applierV3backend) Authenticate(r *pb.InternalAuthenticateRequest) (*pb.AuthenticateResponse, error) {
 	ctx := context.WithValue(context.WithValue(context.Background(), auth.AuthenticateParamIndex{}, a.consistentIndex.ConsistentIndex()), auth.AuthenticateParamSimpleTokenPrefix{}, r.SimpleToken)
+	ctx = context.WithValue(ctx, auth.AuthenticateParamToken{}, &pb.AuthToken{Created: r.Created, Address: r.Address, Scope: r.Scope})
 	resp, err := a.authStore.Authenticate(ctx, r.Name, r.Password)
+	if err == nil && len(r.HashedPassword) != 0 {
+		a.authStore.RehashPassword(r.Name, r.HashedPassword, r.HashedPasswordAlgorithm, r.AuthRevision)
+	}
 	if resp != nil {
 		resp.Header = a.newHeader()
 	}
@@ -322,8 +373,12 @@ func (a *applierV3backend) UserChangePassword(r *pb.AuthUserChangePasswordReques
 	return resp, err
 }
 
-func (a *applierV3backend) UserGrantRole(r *pb.AuthUserGrantRoleRequest) (*pb.AuthUserGrantRoleResponse, error) {
-	resp, err := a.authStore.UserGrantRole(r)
+func (a *applierV3backend) UserGrantRole(ctx context.Context, r *pb.AuthUserGrantRoleRequest) (*pb.AuthUserGrantRoleResponse, error) {
+	var expireTime int64
+	if r.Ttl != 0 {
+		expireTime = mvcctxn.RequestTime(ctx) + r.Ttl
+	}
+	resp, err := a.authStore.UserGrantRoleUntil(r, expireTime)
 	if resp != nil {
 		resp.Header = a.newHeader()
 	}
@@ -378,6 +433,38 @@ func (a *applierV3backend) RoleRevokePermission(r *pb.AuthRoleRevokePermissionRe
 	return resp, err
 }
 
+func (a *applierV3backend) UserSetLimits(r *pb.AuthUserSetLimitsRequest) (*pb.AuthUserSetLimitsResponse, error) {
+	resp, err := a.authStore.UserSetLimits(r)
+	if resp != nil {
+		resp.Header = a.newHeader()
+	}
+	return resp, err
+}
+
+func (a *applierV3backend) RoleSetLimits(r *pb.AuthRoleSetLimitsRequest) (*pb.AuthRoleSetLimitsResponse, error) {
+	resp, err := a.authStore.RoleSetLimits(r)
+	if resp != nil {
+		resp.Header = a.newHeader()
+	}
+	return resp, err
+}
+
+func (a *applierV3backend) TokenList(r *pb.AuthTokenListRequest) (*pb.AuthTokenListResponse, error) {
+	resp, err := a.authStore.TokenList(r)
+	if resp != nil {
+		resp.Header = a.newHeader()
+	}
+	return resp, err
+}
+
+func (a *applierV3backend) TokenRevoke(r *pb.AuthTokenRevokeRequest) (*pb.AuthTokenRevokeResponse, error) {
+	resp, err := a.authStore.TokenRevoke(r)
+	if resp != nil {
+		resp.Header = a.newHeader()
+	}
+	return resp, err
+}
+
 func (a *applierV3backend) RoleDelete(r *pb.AuthRoleDeleteRequest) (*pb.AuthRoleDeleteResponse, error) {
 	resp, err := a.authStore.RoleDelete(r)
 	if resp != nil {
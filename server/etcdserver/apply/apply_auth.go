@@ -18,18 +18,24 @@ import (
 	"context"
 	"sync"
 
+	"go.etcd.io/etcd/api/v3/authpb"
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/pkg/v3/traceutil"
 	"go.etcd.io/etcd/server/v3/auth"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/membership"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3audit"
 	"go.etcd.io/etcd/server/v3/etcdserver/txn"
 	"go.etcd.io/etcd/server/v3/lease"
+	serverstorage "go.etcd.io/etcd/server/v3/storage"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
 )
 
 type authApplierV3 struct {
 	applierV3
-	as     auth.AuthStore
-	lessor lease.Lessor
+	as      auth.AuthStore
+	lessor  lease.Lessor
+	kv      mvcc.KV
+	auditor *v3audit.Auditor
 
 	// mu serializes Apply so that user isn't corrupted and so that
 	// serialized requests don't leak data from TOCTOU errors
@@ -38,8 +44,8 @@ type authApplierV3 struct {
 	authInfo auth.AuthInfo
 }
 
-func newAuthApplierV3(as auth.AuthStore, base applierV3, lessor lease.Lessor) *authApplierV3 {
-	return &authApplierV3{applierV3: base, as: as, lessor: lessor}
+func newAuthApplierV3(as auth.AuthStore, base applierV3, lessor lease.Lessor, kv mvcc.KV, auditor *v3audit.Auditor) *authApplierV3 {
+	return &authApplierV3{applierV3: base, as: as, lessor: lessor, kv: kv, auditor: auditor}
 }
 
 func (aa *authApplierV3) Apply(ctx context.Context, r *pb.InternalRaftRequest, shouldApplyV3 membership.ShouldApplyV3, applyFunc applyFunc) *Result {
@@ -50,28 +56,62 @@ func (aa *authApplierV3) Apply(ctx context.Context, r *pb.InternalRaftRequest, s
 		// does not have header field
 		aa.authInfo.Username = r.Header.Username
 		aa.authInfo.Revision = r.Header.AuthRevision
+		aa.authInfo.Roles = r.Header.Roles
+		aa.authInfo.Scope = r.Header.Scope
+		aa.authInfo.Time = r.Header.Timestamp
+	}
+	if aa.authInfo.Time == 0 {
+		// every member must apply the request the same way, whatever its clock
+		aa.authInfo.Time = auth.TimeIgnoreExpiry
 	}
 	if needAdminPermission(r) {
 		if err := aa.as.IsAdminPermitted(&aa.authInfo); err != nil {
+			aa.audit(r, &Result{Err: err})
 			aa.authInfo.Username = ""
 			aa.authInfo.Revision = 0
+			aa.authInfo.Roles = nil
+			aa.authInfo.Scope = nil
+			aa.authInfo.Time = 0
 			return &Result{Err: err}
 		}
 	}
 	ret := aa.applierV3.Apply(ctx, r, shouldApplyV3, applyFunc)
+	aa.audit(r, ret)
 	aa.authInfo.Username = ""
 	aa.authInfo.Revision = 0
+	aa.authInfo.Roles = nil
+	aa.authInfo.Scope = nil
+	aa.authInfo.Time = 0
 	return ret
 }
 
+// audit records the outcome of the request applied on behalf of the user.
+func (aa *authApplierV3) audit(r *pb.InternalRaftRequest, ret *Result) {
+	rpc, req := v3audit.InternalRequest(r)
+	if rpc == "" || !aa.auditor.Enabled(rpc) {
+		return
+	}
+	ev := v3audit.Event{Stage: v3audit.StageApply, RPC: rpc, User: aa.authInfo.Username}
+	ev.Ranges, ev.Details = v3audit.RequestInfo(req)
+	if ret != nil {
+		if ret.Resp != nil {
+			ev.Revision = v3audit.Revision(ret.Resp)
+		}
+		if ret.Err != nil {
+			ev.Error = ret.Err.Error()
+		}
+	}
+	aa.auditor.Log(ev)
+}
+
 func (aa *authApplierV3) Put(ctx context.Context, r *pb.PutRequest) (*pb.PutResponse, *traceutil.Trace, error) {
-	if err := aa.as.IsPutPermitted(&aa.authInfo, r.Key); err != nil {
+	if err := txn.CheckPutAuth(aa.as, &aa.authInfo, aa.kv, r); err != nil {
 		return nil, nil, err
 	}
 
 	if err := aa.checkLeasePuts(lease.LeaseID(r.Lease)); err != nil {
 		// The specified lease is already attached with a key that cannot
-		// be written by this user. It means the user cannot revoke the
+		// be deleted by this user. It means the user cannot revoke the
 		// lease so attaching the lease to the newly written key should
 		// be forbidden.
 		return nil, nil, err
@@ -83,6 +123,10 @@ func (aa *authApplierV3) Put(ctx context.Context, r *pb.PutRequest) (*pb.PutResp
 			return nil, nil, err
 		}
 	}
+
+	if err := aa.checkBytesLimit(r); err != nil {
+		return nil, nil, err
+	}
 	return aa.applierV3.Put(ctx, r)
 }
 
@@ -108,12 +152,48 @@ func (aa *authApplierV3) DeleteRange(ctx context.Context, r *pb.DeleteRangeReque
 }
 
 func (aa *authApplierV3) Txn(ctx context.Context, rt *pb.TxnRequest) (*pb.TxnResponse, *traceutil.Trace, error) {
-	if err := txn.CheckTxnAuth(aa.as, &aa.authInfo, rt); err != nil {
+	if err := txn.CheckTxnAuth(aa.as, &aa.authInfo, aa.kv, rt); err != nil {
+		return nil, nil, err
+	}
+	if err := aa.checkBytesLimit(rt); err != nil {
 		return nil, nil, err
 	}
 	return aa.applierV3.Txn(ctx, rt)
 }
 
+// checkBytesLimit checks that the keys and values stored by the request do
+// not take the ranges the user may write beyond its limit.
+func (aa *authApplierV3) checkBytesLimit(r interface{}) error {
+	limits := aa.as.Limits(&aa.authInfo)
+	if limits == nil || limits.MaxBytes == 0 {
+		return nil
+	}
+	var ranges []serverstorage.KeyRange
+	for _, perm := range aa.as.Permissions(&aa.authInfo) {
+		switch perm.PermType {
+		case authpb.WRITE, authpb.READWRITE, authpb.CREATE:
+			ranges = append(ranges, serverstorage.KeyRange{Key: perm.Key, RangeEnd: perm.RangeEnd})
+		}
+	}
+	if !serverstorage.NewRangesQuota(aa.kv, ranges, int64(limits.MaxBytes)).Available(r) {
+		return auth.ErrLimitExceeded
+	}
+	return nil
+}
+
+func (aa *authApplierV3) LeaseGrant(lc *pb.LeaseGrantRequest) (*pb.LeaseGrantResponse, error) {
+	limits := aa.as.Limits(&aa.authInfo)
+	if limits != nil && limits.MaxLeases != 0 && uint64(aa.lessor.Owned(aa.authInfo.Username)) >= limits.MaxLeases {
+		return nil, auth.ErrLimitExceeded
+	}
+	resp, err := aa.applierV3.LeaseGrant(lc)
+	if err == nil && aa.authInfo.Username != "" {
+		// leases are counted against the limits of the user granting them
+		err = aa.lessor.SetOwner(lease.LeaseID(resp.ID), aa.authInfo.Username)
+	}
+	return resp, err
+}
+
 func (aa *authApplierV3) LeaseRevoke(lc *pb.LeaseRevokeRequest) (*pb.LeaseRevokeResponse, error) {
 	if err := aa.checkLeasePuts(lease.LeaseID(lc.ID)); err != nil {
 		return nil, err
@@ -138,7 +218,7 @@ func (aa *authApplierV3) checkLeasePutsKeys(l *lease.Lease) error {
 	}
 
 	for _, key := range l.Keys() {
-		if err := aa.as.IsPutPermitted(&aa.authInfo, []byte(key)); err != nil {
+		if err := aa.as.IsDeleteRangePermitted(&aa.authInfo, []byte(key), nil); err != nil {
 			return err
 		}
 	}
@@ -158,7 +238,7 @@ func (aa *authApplierV3) UserGet(r *pb.AuthUserGetRequest) (*pb.AuthUserGetRespo
 
 func (aa *authApplierV3) RoleGet(r *pb.AuthRoleGetRequest) (*pb.AuthRoleGetResponse, error) {
 	err := aa.as.IsAdminPermitted(&aa.authInfo)
-	if err != nil && !aa.as.HasRole(aa.authInfo.Username, r.Role) {
+	if err != nil && !hasTokenRole(&aa.authInfo, r.Role) && !aa.as.HasRole(aa.authInfo.Username, r.Role) {
 		aa.authInfo.Username = ""
 		aa.authInfo.Revision = 0
 		return &pb.AuthRoleGetResponse{}, err
@@ -167,6 +247,16 @@ func (aa *authApplierV3) RoleGet(r *pb.AuthRoleGetRequest) (*pb.AuthRoleGetRespo
 	return aa.applierV3.RoleGet(r)
 }
 
+// hasTokenRole returns whether the role is granted to the user by its token.
+func hasTokenRole(ai *auth.AuthInfo, role string) bool {
+	for _, r := range ai.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
 func needAdminPermission(r *pb.InternalRaftRequest) bool {
 	switch {
 	case r.AuthEnable != nil:
@@ -197,6 +287,14 @@ func needAdminPermission(r *pb.InternalRaftRequest) bool {
 		return true
 	case r.AuthRoleList != nil:
 		return true
+	case r.AuthUserSetLimits != nil:
+		return true
+	case r.AuthRoleSetLimits != nil:
+		return true
+	case r.AuthTokenList != nil:
+		return true
+	case r.AuthTokenRevoke != nil:
+		return true
 	default:
 		return false
 	}
@@ -106,7 +106,9 @@ func defaultAuthApplierV3(t *testing.T) *authApplierV3 {
 			consistentIndex,
 			false,
 		),
-		lessor)
+		lessor,
+		kv,
+		nil)
 }
 
 const (
@@ -129,14 +131,14 @@ func mustCreateRolesAndEnableAuth(t *testing.T, authApplier *authApplierV3) {
 	require.NoError(t, err)
 	_, err = authApplier.RoleAdd(&pb.AuthRoleAddRequest{Name: roleRoot})
 	require.NoError(t, err)
-	_, err = authApplier.UserGrantRole(&pb.AuthUserGrantRoleRequest{User: userRoot, Role: roleRoot})
+	_, err = authApplier.UserGrantRole(context.TODO(), &pb.AuthUserGrantRoleRequest{User: userRoot, Role: roleRoot})
 	require.NoError(t, err)
 
 	_, err = authApplier.UserAdd(&pb.AuthUserAddRequest{Name: userReadOnly, Options: &authpb.UserAddOptions{NoPassword: true}})
 	require.NoError(t, err)
 	_, err = authApplier.RoleAdd(&pb.AuthRoleAddRequest{Name: roleReadOnly})
 	require.NoError(t, err)
-	_, err = authApplier.UserGrantRole(&pb.AuthUserGrantRoleRequest{User: userReadOnly, Role: roleReadOnly})
+	_, err = authApplier.UserGrantRole(context.TODO(), &pb.AuthUserGrantRoleRequest{User: userReadOnly, Role: roleReadOnly})
 	require.NoError(t, err)
 	_, err = authApplier.RoleGrantPermission(&pb.AuthRoleGrantPermissionRequest{Name: roleReadOnly, Perm: &authpb.Permission{
 		PermType: authpb.READ,
@@ -149,7 +151,7 @@ func mustCreateRolesAndEnableAuth(t *testing.T, authApplier *authApplierV3) {
 	require.NoError(t, err)
 	_, err = authApplier.RoleAdd(&pb.AuthRoleAddRequest{Name: roleWriteOnly})
 	require.NoError(t, err)
-	_, err = authApplier.UserGrantRole(&pb.AuthUserGrantRoleRequest{User: userWriteOnly, Role: roleWriteOnly})
+	_, err = authApplier.UserGrantRole(context.TODO(), &pb.AuthUserGrantRoleRequest{User: userWriteOnly, Role: roleWriteOnly})
 	require.NoError(t, err)
 	_, err = authApplier.RoleGrantPermission(&pb.AuthRoleGrantPermissionRequest{Name: roleWriteOnly, Perm: &authpb.Permission{
 		PermType: authpb.WRITE,
@@ -222,6 +224,34 @@ func TestAuthApplierV3_Apply(t *testing.T) {
 
 }
 
+// TestAuthApplierV3_ApplyTime ensures requests are applied at the time they
+// were proposed at, and without the expiry of roles if it is unknown.
+func TestAuthApplierV3_ApplyTime(t *testing.T) {
+	tcs := []struct {
+		name       string
+		timestamp  int64
+		expectTime int64
+	}{
+		{name: "request with timestamp", timestamp: 100, expectTime: 100},
+		{name: "request without timestamp", timestamp: 0, expectTime: auth.TimeIgnoreExpiry},
+	}
+
+	authApplier := defaultAuthApplierV3(t)
+	mustCreateRolesAndEnableAuth(t, authApplier)
+	var appliedTime int64
+	applyFunc := func(_ context.Context, _ *pb.InternalRaftRequest, _ membership.ShouldApplyV3) *Result {
+		appliedTime = authApplier.authInfo.Time
+		return &Result{}
+	}
+	for _, tc := range tcs {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &pb.InternalRaftRequest{Header: &pb.RequestHeader{Username: userReadOnly, Timestamp: tc.timestamp}}
+			authApplier.Apply(context.TODO(), r, false, applyFunc)
+			require.Equal(t, tc.expectTime, appliedTime)
+		})
+	}
+}
+
 // TestAuthApplierV3_AdminPermission ensures the admin permission is checked for certain
 // operations
 func TestAuthApplierV3_AdminPermission(t *testing.T) {
@@ -276,6 +306,16 @@ func TestAuthApplierV3_AdminPermission(t *testing.T) {
 				request:               &pb.InternalRaftRequest{LeaseCheckpoint: &pb.LeaseCheckpointRequest{}},
 				adminPermissionNeeded: false,
 			},
+			{
+				name:                  "KeyExpire does not need admin permission",
+				request:               &pb.InternalRaftRequest{KeyExpire: &pb.KeyExpireRequest{}},
+				adminPermissionNeeded: false,
+			},
+			{
+				name:                  "AuthRoleGrantExpire does not need admin permission",
+				request:               &pb.InternalRaftRequest{AuthRoleGrantExpire: &pb.AuthRoleGrantExpireRequest{}},
+				adminPermissionNeeded: false,
+			},
 			{
 				name:                  "Authenticate does not need admin permission",
 				request:               &pb.InternalRaftRequest{Authenticate: &pb.InternalAuthenticateRequest{}},
@@ -356,6 +396,16 @@ func TestAuthApplierV3_AdminPermission(t *testing.T) {
 				request:               &pb.InternalRaftRequest{AuthRoleList: &pb.AuthRoleListRequest{}},
 				adminPermissionNeeded: true,
 			},
+			{
+				name:                  "AuthTokenList needs admin permission",
+				request:               &pb.InternalRaftRequest{AuthTokenList: &pb.AuthTokenListRequest{}},
+				adminPermissionNeeded: true,
+			},
+			{
+				name:                  "AuthTokenRevoke needs admin permission",
+				request:               &pb.InternalRaftRequest{AuthTokenRevoke: &pb.AuthTokenRevokeRequest{}},
+				adminPermissionNeeded: true,
+			},
 			{
 				name:                  "AuthRoleAdd needs admin permission",
 				request:               &pb.InternalRaftRequest{AuthRoleAdd: &pb.AuthRoleAddRequest{}},
@@ -44,6 +44,10 @@ func (a *applierV3Corrupt) Txn(_ context.Context, _ *pb.TxnRequest) (*pb.TxnResp
 	return nil, nil, errors.ErrCorrupt
 }
 
+func (a *applierV3Corrupt) KeyExpire(_ context.Context, _ *pb.KeyExpireRequest) (*pb.DeleteRangeResponse, *traceutil.Trace, error) {
+	return nil, nil, errors.ErrCorrupt
+}
+
 func (a *applierV3Corrupt) Compaction(_ *pb.CompactionRequest) (*pb.CompactionResponse, <-chan struct{}, *traceutil.Trace, error) {
 	return nil, nil, nil, errors.ErrCorrupt
 }
@@ -24,6 +24,7 @@ import (
 	"go.etcd.io/etcd/server/v3/auth"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/membership"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v3alarm"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3audit"
 	"go.etcd.io/etcd/server/v3/etcdserver/cindex"
 	"go.etcd.io/etcd/server/v3/etcdserver/txn"
 	"go.etcd.io/etcd/server/v3/lease"
@@ -61,8 +62,9 @@ func NewUberApplier(
 	consistentIndex cindex.ConsistentIndexer,
 	warningApplyDuration time.Duration,
 	txnModeWriteWithSharedBuffer bool,
+	auditor *v3audit.Auditor,
 	quotaBackendBytesCfg int64) UberApplier {
-	applyV3base_ := newApplierV3(lg, be, kv, alarmStore, authStore, lessor, cluster, raftStatus, snapshotServer, consistentIndex, txnModeWriteWithSharedBuffer, quotaBackendBytesCfg)
+	applyV3base_ := newApplierV3(lg, be, kv, alarmStore, authStore, lessor, cluster, raftStatus, snapshotServer, consistentIndex, txnModeWriteWithSharedBuffer, auditor, quotaBackendBytesCfg)
 
 	ua := &uberApplier{
 		lg:                   lg,
@@ -87,12 +89,15 @@ func newApplierV3(
 	snapshotServer SnapshotServer,
 	consistentIndex cindex.ConsistentIndexer,
 	txnModeWriteWithSharedBuffer bool,
+	auditor *v3audit.Auditor,
 	quotaBackendBytesCfg int64) applierV3 {
 	applierBackend := newApplierV3Backend(lg, kv, alarmStore, authStore, lessor, cluster, raftStatus, snapshotServer, consistentIndex, txnModeWriteWithSharedBuffer)
 	return newAuthApplierV3(
 		authStore,
 		newQuotaApplierV3(lg, quotaBackendBytesCfg, be, applierBackend),
 		lessor,
+		kv,
+		auditor,
 	)
 }
 
@@ -150,6 +155,10 @@ func (a *uberApplier) dispatch(ctx context.Context, r *pb.InternalRaftRequest, s
 		return nil
 	}
 
+	if r.Header != nil && r.Header.Timestamp != 0 {
+		ctx = txn.WithRequestTime(ctx, r.Header.Timestamp)
+	}
+
 	switch {
 	case r.Range != nil:
 		op = "Range"
@@ -175,6 +184,12 @@ func (a *uberApplier) dispatch(ctx context.Context, r *pb.InternalRaftRequest, s
 	case r.LeaseCheckpoint != nil:
 		op = "LeaseCheckpoint"
 		ar.Resp, ar.Err = a.applyV3.LeaseCheckpoint(r.LeaseCheckpoint)
+	case r.KeyExpire != nil:
+		op = "KeyExpire"
+		ar.Resp, ar.Trace, ar.Err = a.applyV3.KeyExpire(ctx, r.KeyExpire)
+	case r.AuthRoleGrantExpire != nil:
+		op = "AuthRoleGrantExpire"
+		ar.Resp, ar.Err = a.applyV3.RoleGrantExpire(r.AuthRoleGrantExpire)
 	case r.Alarm != nil:
 		op = "Alarm"
 		ar.Resp, ar.Err = a.Alarm(r.Alarm)
@@ -200,7 +215,7 @@ func (a *uberApplier) dispatch(ctx context.Context, r *pb.InternalRaftRequest, s
 		ar.Resp, ar.Err = a.applyV3.UserChangePassword(r.AuthUserChangePassword)
 	case r.AuthUserGrantRole != nil:
 		op = "AuthUserGrantRole"
-		ar.Resp, ar.Err = a.applyV3.UserGrantRole(r.AuthUserGrantRole)
+		ar.Resp, ar.Err = a.applyV3.UserGrantRole(ctx, r.AuthUserGrantRole)
 	case r.AuthUserGet != nil:
 		op = "AuthUserGet"
 		ar.Resp, ar.Err = a.applyV3.UserGet(r.AuthUserGet)
@@ -222,6 +237,18 @@ func (a *uberApplier) dispatch(ctx context.Context, r *pb.InternalRaftRequest, s
 	case r.AuthRoleDelete != nil:
 		op = "AuthRoleDelete"
 		ar.Resp, ar.Err = a.applyV3.RoleDelete(r.AuthRoleDelete)
+	case r.AuthUserSetLimits != nil:
+		op = "AuthUserSetLimits"
+		ar.Resp, ar.Err = a.applyV3.UserSetLimits(r.AuthUserSetLimits)
+	case r.AuthRoleSetLimits != nil:
+		op = "AuthRoleSetLimits"
+		ar.Resp, ar.Err = a.applyV3.RoleSetLimits(r.AuthRoleSetLimits)
+	case r.AuthTokenList != nil:
+		op = "AuthTokenList"
+		ar.Resp, ar.Err = a.applyV3.TokenList(r.AuthTokenList)
+	case r.AuthTokenRevoke != nil:
+		op = "AuthTokenRevoke"
+		ar.Resp, ar.Err = a.applyV3.TokenRevoke(r.AuthTokenRevoke)
 	case r.AuthUserList != nil:
 		op = "AuthUserList"
 		ar.Resp, ar.Err = a.applyV3.UserList(r.AuthUserList)
@@ -72,6 +72,7 @@ func defaultUberApplier(t *testing.T) UberApplier {
 		consistentIndex,
 		1*time.Hour,
 		false,
+		nil,
 		16*1024*1024, //16MB
 	)
 }
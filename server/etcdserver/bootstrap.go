@@ -76,6 +76,12 @@ func bootstrap(cfg config.ServerConfig) (b *bootstrappedServer, err error) {
 	}
 
 	haveWAL := wal.Exist(cfg.WALDir())
+	if !haveWAL && cfg.ExperimentalLearnerSnapshot != "" {
+		if err = bootstrapLearnerFromSnapshot(cfg, prt); err != nil {
+			return nil, err
+		}
+		haveWAL = true
+	}
 	st := v2store.New(StoreClusterPrefix, StoreKeysPrefix)
 	backend, err := bootstrapBackend(cfg, haveWAL, st, ss)
 	if err != nil {
@@ -42,6 +42,8 @@ var (
 	ErrClusterVersionUnavailable   = errors.New("etcdserver: cluster version not found during downgrade")
 	ErrWrongDowngradeVersionFormat = errors.New("etcdserver: wrong downgrade target version format")
 	ErrKeyNotFound                 = errors.New("etcdserver: key not found")
+	ErrInvalidContinuation         = errors.New("etcdserver: invalid continuation token")
+	ErrStaleRead                   = errors.New("etcdserver: member is too stale to serve the read")
 )
 
 type DiscoveryError struct {
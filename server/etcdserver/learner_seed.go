@@ -0,0 +1,160 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/coreos/go-semver/semver"
+	"go.uber.org/zap"
+
+	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/client/pkg/v3/fileutil"
+	"go.etcd.io/etcd/client/pkg/v3/types"
+	"go.etcd.io/etcd/client/v3/snapshot"
+	"go.etcd.io/etcd/pkg/v3/pbutil"
+	"go.etcd.io/etcd/server/v3/config"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/membership"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/snap"
+	"go.etcd.io/etcd/server/v3/storage/backend"
+	"go.etcd.io/etcd/server/v3/storage/schema"
+	"go.etcd.io/etcd/server/v3/storage/wal"
+	"go.etcd.io/etcd/server/v3/storage/wal/walpb"
+	"go.etcd.io/raft/v3/raftpb"
+)
+
+// SeedLearner turns the backend at dbPath, restored from a snapshot of a running
+// cluster, into the data of the given learner of that cluster. Instead of being
+// sent a raft snapshot by the leader once started, the learner resumes raft at the
+// consistent index of the backend, so that the leader only sends it the log entries
+// committed after the snapshot was taken.
+//
+// The members of the cluster are used to check that the snapshot was taken from it.
+func SeedLearner(lg *zap.Logger, dbPath, walDir, snapDir string, cid types.ID, learner *membership.Member, members []*membership.Member) error {
+	be := backend.NewDefaultBackend(lg, dbPath)
+	defer be.Close()
+
+	index, term := schema.ReadConsistentIndex(be.ReadTx())
+	if index == 0 || term == 0 {
+		return fmt.Errorf("snapshot %q has no consistent index", dbPath)
+	}
+
+	cl := membership.NewCluster(lg)
+	cl.SetBackend(schema.NewMembershipBackend(lg, be))
+	cl.Recover(func(*zap.Logger, *semver.Version) {})
+	if !hasAnyMember(cl, members) {
+		return fmt.Errorf("snapshot %q was not taken from cluster %s", dbPath, cid)
+	}
+	// The learner is usually added after the snapshot was taken. Its member is
+	// added to the snapshot anyway, as the learner needs to know about itself
+	// before applying the entry adding it, which it will then reject as a
+	// duplicate without affecting the resulting membership.
+	if cl.Member(learner.ID) == nil {
+		cl.AddMember(learner, membership.ApplyBoth)
+	}
+
+	var confState raftpb.ConfState
+	for _, m := range cl.Members() {
+		if m.IsLearner {
+			confState.Learners = append(confState.Learners, uint64(m.ID))
+		} else {
+			confState.Voters = append(confState.Voters, uint64(m.ID))
+		}
+	}
+
+	if err := fileutil.CreateDirAll(lg, walDir); err != nil {
+		return err
+	}
+	metadata := pbutil.MustMarshal(&etcdserverpb.Metadata{NodeID: uint64(learner.ID), ClusterID: uint64(cid)})
+	w, err := wal.Create(lg, walDir, metadata)
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	raftSnap := raftpb.Snapshot{
+		Data: GetMembershipInfoInV2Format(lg, cl),
+		Metadata: raftpb.SnapshotMetadata{
+			Index:     index,
+			Term:      term,
+			ConfState: confState,
+		},
+	}
+	if err = snap.New(lg, snapDir).SaveSnap(raftSnap); err != nil {
+		return err
+	}
+	if err = w.SaveSnapshot(walpb.Snapshot{Index: index, Term: term, ConfState: &confState}); err != nil {
+		return err
+	}
+	if err = w.Save(raftpb.HardState{Term: term, Commit: index}, nil); err != nil {
+		return err
+	}
+
+	lg.Info(
+		"seeded learner from snapshot",
+		zap.String("cluster-id", cid.String()),
+		zap.String("learner-id", learner.ID.String()),
+		zap.Uint64("consistent-index", index),
+		zap.Uint64("term", term),
+	)
+	return nil
+}
+
+func hasAnyMember(cl *membership.RaftCluster, members []*membership.Member) bool {
+	for _, m := range members {
+		if cl.Member(m.ID) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// bootstrapLearnerFromSnapshot seeds the empty data directory of a member joining an
+// existing cluster, which it must have been added to as a learner, from the
+// snapshot configured by ExperimentalLearnerSnapshot.
+func bootstrapLearnerFromSnapshot(cfg config.ServerConfig, prt http.RoundTripper) error {
+	if cfg.NewCluster {
+		return fmt.Errorf("a learner seeded from a snapshot must join an existing cluster")
+	}
+	if err := cfg.VerifyJoinExisting(); err != nil {
+		return err
+	}
+	cl, err := membership.NewClusterFromURLsMap(cfg.Logger, cfg.InitialClusterToken, cfg.InitialPeerURLsMap)
+	if err != nil {
+		return err
+	}
+	existingCluster, gerr := GetClusterFromRemotePeers(cfg.Logger, getRemotePeerURLs(cl, cfg.Name), prt)
+	if gerr != nil {
+		return fmt.Errorf("cannot fetch cluster info from peer urls: %v", gerr)
+	}
+	if err = membership.ValidateClusterAndAssignIDs(cfg.Logger, cl, existingCluster); err != nil {
+		return fmt.Errorf("error validating peerURLs %s: %v", existingCluster, err)
+	}
+	learner := existingCluster.Member(cl.MemberByName(cfg.Name).ID)
+	if learner == nil || !learner.IsLearner {
+		return fmt.Errorf("member %q must be added to the cluster as a learner to be seeded from a snapshot", cfg.Name)
+	}
+
+	cfg.Logger.Info(
+		"seeding learner from snapshot",
+		zap.String("path", cfg.ExperimentalLearnerSnapshot),
+		zap.String("data-dir", cfg.DataDir),
+	)
+	if err = snapshot.Copy(cfg.ExperimentalLearnerSnapshot, cfg.BackendPath(), false); err != nil {
+		return err
+	}
+	return SeedLearner(cfg.Logger, cfg.BackendPath(), cfg.WALDir(), cfg.SnapDir(), existingCluster.ID(), learner, existingCluster.Members())
+}
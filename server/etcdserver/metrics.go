@@ -112,12 +112,32 @@ var (
 		Name:      "read_indexes_failed_total",
 		Help:      "The total number of failed read indexes seen.",
 	})
+	boundedStalenessReads = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "etcd",
+		Subsystem: "server",
+		Name:      "bounded_staleness_reads_total",
+		Help:      "The total number of bounded staleness reads, by whether they were served locally, served linearizably or rejected.",
+	},
+		[]string{"result"},
+	)
 	leaseExpired = prometheus.NewCounter(prometheus.CounterOpts{
 		Namespace: "etcd_debugging",
 		Subsystem: "server",
 		Name:      "lease_expired_total",
 		Help:      "The total number of expired leases.",
 	})
+	keysExpired = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "etcd_debugging",
+		Subsystem: "server",
+		Name:      "key_expired_total",
+		Help:      "The total number of keys deleted because their TTL expired.",
+	})
+	roleGrantsExpired = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "etcd_debugging",
+		Subsystem: "server",
+		Name:      "role_grant_expired_total",
+		Help:      "The total number of role grants proposed to be revoked because their TTL expired.",
+	})
 
 	currentVersion = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: "etcd",
@@ -167,7 +187,10 @@ func init() {
 	prometheus.MustRegister(proposalsFailed)
 	prometheus.MustRegister(slowReadIndex)
 	prometheus.MustRegister(readIndexFailed)
+	prometheus.MustRegister(boundedStalenessReads)
 	prometheus.MustRegister(leaseExpired)
+	prometheus.MustRegister(keysExpired)
+	prometheus.MustRegister(roleGrantsExpired)
 	prometheus.MustRegister(currentVersion)
 	prometheus.MustRegister(currentGoVersion)
 	prometheus.MustRegister(serverID)
@@ -62,6 +62,7 @@ import (
 	stats "go.etcd.io/etcd/server/v3/etcdserver/api/v2stats"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v2store"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v3alarm"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3audit"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v3compactor"
 	"go.etcd.io/etcd/server/v3/etcdserver/cindex"
 	serverversion "go.etcd.io/etcd/server/v3/etcdserver/version"
@@ -101,6 +102,19 @@ const (
 	// maxPendingRevokes is the maximum number of outstanding expired lease revocations.
 	maxPendingRevokes = 16
 
+	// keyExpiryInterval is the interval at which the leader deletes the expired keys.
+	keyExpiryInterval = 500 * time.Millisecond
+	// maxExpiredKeysPerRequest is the maximum number of expired keys deleted by a
+	// single raft request.
+	maxExpiredKeysPerRequest = 1000
+
+	// roleGrantExpiryInterval is the interval at which the leader revokes the
+	// expired role grants.
+	roleGrantExpiryInterval = time.Second
+	// maxExpiredRoleGrantsPerRequest is the maximum number of expired role grants
+	// revoked by a single raft request.
+	maxExpiredRoleGrantsPerRequest = 100
+
 	recommendedMaxRequestBytes = 10 * 1024 * 1024
 
 	readyPercent = 0.9
@@ -239,6 +253,10 @@ type EtcdServer struct {
 	done chan struct{}
 	// leaderChanged is used to notify the linearizable read loop to drop the old read requests.
 	leaderChanged *notify.Notifier
+	// leaderContacts bounds the staleness of the reads served locally, and
+	// quorumAcks the staleness of the ones served by the leader.
+	leaderContacts leaderContacts
+	quorumAcks     quorumAcks
 
 	errorc     chan error
 	memberId   types.ID
@@ -262,6 +280,8 @@ type EtcdServer struct {
 	beHooks    *serverstorage.BackendHooks
 	authStore  auth.AuthStore
 	alarmStore *v3alarm.AlarmStore
+	// limiter enforces the request rate and watch limits of the users.
+	limiter *auth.Limiter
 
 	stats  *stats.ServerStats
 	lstats *stats.LeaderStats
@@ -269,6 +289,8 @@ type EtcdServer struct {
 	SyncTicker *time.Ticker
 	// compactor is used to auto-compact the KV.
 	compactor v3compactor.Compactor
+	// auditor records the mutations and admin operations, if enabled.
+	auditor *v3audit.Auditor
 
 	// peerRt used to send requests (version, lease) to peers.
 	peerRt   http.RoundTripper
@@ -365,15 +387,29 @@ func NewServer(cfg config.ServerConfig) (srv *EtcdServer, err error) {
 		cfg.Logger.Warn("failed to create token provider", zap.Error(err))
 		return nil, err
 	}
+	var hasher auth.PasswordHasher
+	if cfg.PasswordHash != "" {
+		hasher, err = auth.NewPasswordHasher(cfg.Logger, cfg.PasswordHash, int(cfg.BcryptCost))
+		if err != nil {
+			cfg.Logger.Warn("failed to create password hasher", zap.Error(err))
+			return nil, err
+		}
+	}
 
 	mvccStoreConfig := mvcc.StoreConfig{
 		CompactionBatchLimit:    cfg.CompactionBatchLimit,
 		CompactionSleepInterval: cfg.CompactionSleepInterval,
+		WatchBacklog:            cfg.WatchBacklog,
 	}
 	srv.kv = mvcc.New(srv.Logger(), srv.be, srv.lessor, mvccStoreConfig)
 	srv.corruptionChecker = newCorruptionChecker(cfg.Logger, srv, srv.kv.HashStorage())
 
-	srv.authStore = auth.NewAuthStore(srv.Logger(), schema.NewAuthBackend(srv.Logger(), srv.be), tp, int(cfg.BcryptCost))
+	as := auth.NewAuthStore(srv.Logger(), schema.NewAuthBackend(srv.Logger(), srv.be), tp, int(cfg.BcryptCost))
+	if hasher != nil {
+		as.SetPasswordHasher(hasher)
+	}
+	srv.authStore = as
+	srv.limiter = auth.NewLimiter(srv.authStore)
 
 	newSrv := srv // since srv == nil in defer if srv is returned as nil
 	defer func() {
@@ -384,13 +420,17 @@ func NewServer(cfg config.ServerConfig) (srv *EtcdServer, err error) {
 		}
 	}()
 	if num := cfg.AutoCompactionRetention; num != 0 {
-		srv.compactor, err = v3compactor.New(cfg.Logger, cfg.AutoCompactionMode, num, srv.kv, srv)
+		srv.compactor, err = v3compactor.New(cfg.Logger, cfg.AutoCompactionMode, num, cfg.CompactionPrefixRetention, srv.kv, srv)
 		if err != nil {
 			return nil, err
 		}
 		srv.compactor.Run()
 	}
 
+	if srv.auditor, err = v3audit.New(cfg.Logger, cfg.Audit); err != nil {
+		return nil, err
+	}
+
 	if err = srv.restoreAlarms(); err != nil {
 		return nil, err
 	}
@@ -533,6 +573,8 @@ func (s *EtcdServer) Start() {
 	s.GoAttach(s.monitorKVHash)
 	s.GoAttach(s.monitorCompactHash)
 	s.GoAttach(s.monitorDowngrade)
+	s.GoAttach(s.expireKeys)
+	s.GoAttach(s.expireRoleGrants)
 }
 
 // start prepares and starts server in a new goroutine. It is no longer safe to
@@ -707,6 +749,7 @@ func (s *EtcdServer) Process(ctx context.Context, m raftpb.Message) error {
 	if m.Type == raftpb.MsgApp {
 		s.stats.RecvAppendReq(types.ID(m.From).String(), m.Size())
 	}
+	s.observeLeaderContact(m)
 	return s.r.Step(ctx, m)
 }
 
@@ -894,6 +937,71 @@ func (s *EtcdServer) revokeExpiredLeases(leases []*lease.Lease) {
 	})
 }
 
+// expireKeys deletes the keys whose TTL expired. As for leases, expiry is
+// driven by the leader and the keys are deleted through raft.
+func (s *EtcdServer) expireKeys() {
+	lg := s.Logger()
+	for {
+		select {
+		case <-time.After(keyExpiryInterval):
+		case <-s.stopping:
+			return
+		}
+		if !s.isLeader() {
+			continue
+		}
+
+		expired := s.KV().ExpiredKeys(time.Now().Unix(), maxExpiredKeysPerRequest)
+		if len(expired) == 0 {
+			continue
+		}
+		req := &pb.KeyExpireRequest{Keys: make([]*pb.ExpiredKey, len(expired))}
+		for i, k := range expired {
+			req.Keys[i] = &pb.ExpiredKey{Key: k.Key, ExpireTime: k.ExpireTime}
+		}
+		ctx, cancel := context.WithTimeout(s.ctx, s.Cfg.ReqTimeout())
+		result, err := s.raftRequestOnce(ctx, pb.InternalRaftRequest{KeyExpire: req})
+		cancel()
+		if err != nil {
+			lg.Warn("failed to expire keys", zap.Int("keys", len(expired)), zap.Error(err))
+			continue
+		}
+		if resp, ok := result.(*pb.DeleteRangeResponse); ok {
+			keysExpired.Add(float64(resp.Deleted))
+		}
+	}
+}
+
+// expireRoleGrants revokes the roles granted for a limited time once they
+// expire. As for keys, expiry is driven by the leader and the roles are revoked
+// through raft.
+func (s *EtcdServer) expireRoleGrants() {
+	lg := s.Logger()
+	for {
+		select {
+		case <-time.After(roleGrantExpiryInterval):
+		case <-s.stopping:
+			return
+		}
+		if !s.isLeader() {
+			continue
+		}
+
+		expired := s.authStore.ExpiredRoleGrants(time.Now().Unix(), maxExpiredRoleGrantsPerRequest)
+		if len(expired) == 0 {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(s.ctx, s.Cfg.ReqTimeout())
+		_, err := s.raftRequestOnce(ctx, pb.InternalRaftRequest{AuthRoleGrantExpire: &pb.AuthRoleGrantExpireRequest{Grants: expired}})
+		cancel()
+		if err != nil {
+			lg.Warn("failed to revoke expired role grants", zap.Int("grants", len(expired)), zap.Error(err))
+			continue
+		}
+		roleGrantsExpired.Add(float64(len(expired)))
+	}
+}
+
 // Cleanup removes allocated objects by EtcdServer.NewServer in
 // situation that EtcdServer::Start was not called (that takes care of cleanup).
 func (s *EtcdServer) Cleanup() {
@@ -914,6 +1022,9 @@ func (s *EtcdServer) Cleanup() {
 	if s.compactor != nil {
 		s.compactor.Stop()
 	}
+	if err := s.auditor.Close(); err != nil {
+		s.lg.Warn("failed to close audit log", zap.Error(err))
+	}
 }
 
 func (s *EtcdServer) applyAll(ep *etcdProgress, apply *toApply) {
@@ -1090,7 +1201,7 @@ func (s *EtcdServer) applySnapshot(ep *etcdProgress, toApply *toApply) {
 
 func (s *EtcdServer) NewUberApplier() apply.UberApplier {
 	return apply.NewUberApplier(s.lg, s.be, s.KV(), s.alarmStore, s.authStore, s.lessor, s.cluster, s, s, s.consistIndex,
-		s.Cfg.WarningApplyDuration, s.Cfg.ExperimentalTxnModeWriteWithSharedBuffer, s.Cfg.QuotaBackendBytes)
+		s.Cfg.WarningApplyDuration, s.Cfg.ExperimentalTxnModeWriteWithSharedBuffer, s.auditor, s.Cfg.QuotaBackendBytes)
 }
 
 func verifySnapshotIndex(snapshot raftpb.Snapshot, cindex uint64) {
@@ -2405,6 +2516,12 @@ func (s *EtcdServer) Backend() backend.Backend {
 
 func (s *EtcdServer) AuthStore() auth.AuthStore { return s.authStore }
 
+// Limiter returns the limiter of the request rate and watches of the users.
+func (s *EtcdServer) Limiter() *auth.Limiter { return s.limiter }
+
+// Auditor returns the audit log of the server, which is nil if disabled.
+func (s *EtcdServer) Auditor() *v3audit.Auditor { return s.auditor }
+
 func (s *EtcdServer) restoreAlarms() error {
 	as, err := v3alarm.NewAlarmStore(s.lg, schema.NewAlarmBackend(s.lg, s.be))
 	if err != nil {
@@ -0,0 +1,177 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/raft/v3/raftpb"
+)
+
+// maxLeaderContacts is the maximum number of leader contacts kept to bound the
+// staleness of the reads. When the member lags further behind, the oldest
+// contacts are dropped, and the reads are no longer served locally.
+const maxLeaderContacts = 1024
+
+// leaderContact is a commit index the leader sent the member, and when. No
+// entry after the commit index was committed before that time.
+type leaderContact struct {
+	commit uint64
+	time   time.Time
+}
+
+// leaderContacts tracks the commit indexes the member has not applied yet, with
+// the time the leader sent them, to bound the staleness of the reads the member
+// serves locally.
+type leaderContacts struct {
+	mu sync.Mutex
+	// contacts are in commit index order. The first contact has the latest
+	// commit index the member has applied, the others are not applied yet.
+	contacts []leaderContact
+}
+
+// observe records that the leader has sent the commit index at time t.
+func (lc *leaderContacts) observe(commit uint64, t time.Time, applied uint64) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	if n := len(lc.contacts); n > 0 && lc.contacts[n-1].commit >= commit {
+		// a later contact with the same commit index only makes it fresher
+		if lc.contacts[n-1].commit == commit && t.After(lc.contacts[n-1].time) {
+			lc.contacts[n-1].time = t
+		}
+		return
+	}
+	lc.contacts = append(lc.contacts, leaderContact{commit: commit, time: t})
+	lc.compact(applied)
+	if len(lc.contacts) > maxLeaderContacts {
+		lc.contacts = append([]leaderContact(nil), lc.contacts[len(lc.contacts)-maxLeaderContacts:]...)
+	}
+}
+
+// compact drops the contacts older than the latest one applied.
+func (lc *leaderContacts) compact(applied uint64) {
+	i := sort.Search(len(lc.contacts), func(i int) bool { return lc.contacts[i].commit > applied })
+	if i > 1 {
+		lc.contacts = lc.contacts[i-1:]
+	}
+}
+
+// staleness returns the number of entries the leader last sent as committed
+// that are not applied yet, and how long ago the leader sent the latest commit
+// index that is applied. ok is false if no such commit index is known.
+func (lc *leaderContacts) staleness(now time.Time, applied uint64) (entries uint64, age time.Duration, ok bool) {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+	lc.compact(applied)
+	if len(lc.contacts) == 0 || lc.contacts[0].commit > applied {
+		return 0, 0, false
+	}
+	if latest := lc.contacts[len(lc.contacts)-1].commit; latest > applied {
+		entries = latest - applied
+	}
+	return entries, now.Sub(lc.contacts[0].time), true
+}
+
+// quorumAcks tracks when the voters last acknowledged the leadership of the
+// member, to bound the staleness of the reads it serves locally as the leader:
+// no other leader can commit entries before a quorum stops acknowledging it.
+type quorumAcks struct {
+	mu   sync.Mutex
+	term uint64
+	acks map[uint64]time.Time
+}
+
+// observe records that the member acknowledged the leader of the term at time t.
+func (qa *quorumAcks) observe(term, id uint64, t time.Time) {
+	qa.mu.Lock()
+	defer qa.mu.Unlock()
+	if term != qa.term {
+		if term < qa.term {
+			return
+		}
+		qa.term, qa.acks = term, make(map[uint64]time.Time)
+	}
+	qa.acks[id] = t
+}
+
+// since returns the latest time at which a quorum of the voters acknowledged
+// the leader of the term, the leader itself acknowledging it at time now. ok is
+// false if no quorum did.
+func (qa *quorumAcks) since(term, self uint64, voters []uint64, now time.Time) (t time.Time, ok bool) {
+	qa.mu.Lock()
+	defer qa.mu.Unlock()
+	var times []time.Time
+	for _, id := range voters {
+		if id == self {
+			times = append(times, now)
+		} else if at, ok := qa.acks[id]; ok && qa.term == term {
+			times = append(times, at)
+		}
+	}
+	quorum := len(voters)/2 + 1
+	if len(times) < quorum {
+		return time.Time{}, false
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i].After(times[j]) })
+	return times[quorum-1], true
+}
+
+// observeLeaderContact records the commit index of the appends the leader
+// sends to keep the member in sync, and as the leader when the followers
+// acknowledge them. The commit index of heartbeats is not the one of the
+// leader, but at most the last index the member has, so it is not recorded.
+func (s *EtcdServer) observeLeaderContact(m raftpb.Message) {
+	switch m.Type {
+	case raftpb.MsgApp:
+		if m.From == s.Lead() {
+			s.leaderContacts.observe(m.Commit, time.Now(), s.getAppliedIndex())
+		}
+	case raftpb.MsgAppResp, raftpb.MsgHeartbeatResp:
+		if s.isLeader() && m.Term == s.Term() {
+			s.quorumAcks.observe(m.Term, m.From, time.Now())
+		}
+	}
+}
+
+// isReadFresh returns whether the member is fresh enough to serve the bounded
+// staleness read locally. Every entry applies at most one revision, so the
+// staleness in revisions is bounded by the number of entries not applied yet.
+func (s *EtcdServer) isReadFresh(r *pb.RangeRequest) bool {
+	now, applied := time.Now(), s.getAppliedIndex()
+	if s.isLeader() {
+		// the leader may have been deposed since a quorum last acknowledged it
+		var voters []uint64
+		for _, id := range s.cluster.VotingMemberIDs() {
+			voters = append(voters, uint64(id))
+		}
+		if t, ok := s.quorumAcks.since(s.Term(), uint64(s.MemberId()), voters, now); ok {
+			s.leaderContacts.observe(s.getCommittedIndex(), t, applied)
+		}
+	}
+	entries, age, ok := s.leaderContacts.staleness(now, applied)
+	if !ok {
+		return false
+	}
+	if r.MaxStalenessRevisions > 0 && entries > uint64(r.MaxStalenessRevisions) {
+		return false
+	}
+	if r.MaxStalenessMs > 0 && age > time.Duration(r.MaxStalenessMs)*time.Millisecond {
+		return false
+	}
+	return true
+}
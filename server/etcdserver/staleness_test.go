@@ -0,0 +1,117 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package etcdserver
+
+import (
+	"testing"
+	"time"
+
+	"go.etcd.io/raft/v3/raftpb"
+)
+
+func TestLeaderContactsStaleness(t *testing.T) {
+	var lc leaderContacts
+	t0 := time.Unix(0, 0)
+	if _, _, ok := lc.staleness(t0, 10); ok {
+		t.Fatalf("expected the staleness to be unknown without leader contact")
+	}
+
+	lc.observe(10, t0, 5)
+	lc.observe(12, t0.Add(time.Second), 5)
+	lc.observe(12, t0.Add(2*time.Second), 5)
+	// an older contact does not make the commit index staler
+	lc.observe(12, t0.Add(time.Second), 5)
+	// a read index may be older than the commit index of the latest append
+	lc.observe(11, t0.Add(3*time.Second), 5)
+
+	tests := []struct {
+		applied uint64
+
+		entries uint64
+		age     time.Duration
+		ok      bool
+	}{
+		{applied: 5, ok: false},
+		{applied: 10, entries: 2, age: 10 * time.Second, ok: true},
+		{applied: 11, entries: 1, age: 10 * time.Second, ok: true},
+		{applied: 12, entries: 0, age: 8 * time.Second, ok: true},
+	}
+	for i, tt := range tests {
+		entries, age, ok := lc.staleness(t0.Add(10*time.Second), tt.applied)
+		if entries != tt.entries || age != tt.age || ok != tt.ok {
+			t.Errorf("#%d: staleness = (%d, %v, %v), want (%d, %v, %v)", i, entries, age, ok, tt.entries, tt.age, tt.ok)
+		}
+	}
+	if len(lc.contacts) != 1 {
+		t.Errorf("expected the applied contacts to be compacted, got %+v", lc.contacts)
+	}
+}
+
+func TestObserveLeaderContact(t *testing.T) {
+	s := &EtcdServer{}
+	s.setLead(2)
+	// the commit index of a heartbeat is at most the last index of the member
+	s.observeLeaderContact(raftpb.Message{Type: raftpb.MsgHeartbeat, From: 2, Commit: 5})
+	if _, _, ok := s.leaderContacts.staleness(time.Now(), 5); ok {
+		t.Fatalf("expected the commit index of a heartbeat not to be recorded")
+	}
+	s.observeLeaderContact(raftpb.Message{Type: raftpb.MsgApp, From: 3, Commit: 5})
+	if _, _, ok := s.leaderContacts.staleness(time.Now(), 5); ok {
+		t.Fatalf("expected the commit index of another member not to be recorded")
+	}
+	s.observeLeaderContact(raftpb.Message{Type: raftpb.MsgApp, From: 2, Commit: 5})
+	if entries, _, ok := s.leaderContacts.staleness(time.Now(), 5); !ok || entries != 0 {
+		t.Fatalf("expected the commit index of an append to be recorded, got (%d, %v)", entries, ok)
+	}
+}
+
+func TestQuorumAcksSince(t *testing.T) {
+	var qa quorumAcks
+	t0 := time.Unix(0, 0)
+	now := t0.Add(10 * time.Second)
+	voters := []uint64{1, 2, 3, 4, 5}
+	if _, ok := qa.since(2, 1, voters, now); ok {
+		t.Fatalf("expected no quorum without acknowledgement")
+	}
+
+	qa.observe(2, 2, t0.Add(time.Second))
+	if _, ok := qa.since(2, 1, voters, now); ok {
+		t.Fatalf("expected no quorum with a single acknowledgement")
+	}
+	qa.observe(2, 3, t0.Add(3*time.Second))
+	qa.observe(2, 4, t0.Add(2*time.Second))
+	if got, ok := qa.since(2, 1, voters, now); !ok || !got.Equal(t0.Add(2*time.Second)) {
+		t.Fatalf("since = (%v, %v), want (%v, true)", got, ok, t0.Add(2*time.Second))
+	}
+	qa.observe(2, 2, t0.Add(5*time.Second))
+	if got, ok := qa.since(2, 1, voters, now); !ok || !got.Equal(t0.Add(3*time.Second)) {
+		t.Fatalf("since = (%v, %v), want (%v, true)", got, ok, t0.Add(3*time.Second))
+	}
+
+	// the acknowledgements of an earlier term do not count
+	if _, ok := qa.since(3, 1, voters, now); ok {
+		t.Fatalf("expected no quorum in a later term")
+	}
+	qa.observe(3, 2, t0.Add(6*time.Second))
+	qa.observe(2, 3, t0.Add(7*time.Second))
+	if _, ok := qa.since(3, 1, voters, now); ok {
+		t.Fatalf("expected no quorum with the acknowledgements of an earlier term")
+	}
+
+	// a single member is its own quorum
+	if got, ok := qa.since(3, 1, []uint64{1}, now); !ok || !got.Equal(now) {
+		t.Fatalf("since = (%v, %v), want (%v, true)", got, ok, now)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txn
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"go.etcd.io/etcd/server/v3/etcdserver/errors"
+)
+
+const continuationVersion = 1
+
+// continuation is the position of a paginated range: the next key to read,
+// the end of the range and the revision the range is read at.
+type continuation struct {
+	key []byte
+	end []byte
+	rev int64
+}
+
+// encode returns the opaque token of the continuation. Its layout is the
+// version, the revision and the length of the key as uvarints, followed by
+// the key and the range end.
+func (c continuation) encode() []byte {
+	buf := make([]byte, 0, 3*binary.MaxVarintLen64+len(c.key)+len(c.end))
+	buf = binary.AppendUvarint(buf, continuationVersion)
+	buf = binary.AppendUvarint(buf, uint64(c.rev))
+	buf = binary.AppendUvarint(buf, uint64(len(c.key)))
+	buf = append(buf, c.key...)
+	return append(buf, c.end...)
+}
+
+// decodeContinuation decodes a token returned by encode and checks that
+// it continues the range [key, end).
+func decodeContinuation(token, key, end []byte) (continuation, error) {
+	var c continuation
+	ver, n := binary.Uvarint(token)
+	if n <= 0 || ver != continuationVersion {
+		return c, errors.ErrInvalidContinuation
+	}
+	token = token[n:]
+	rev, n := binary.Uvarint(token)
+	if n <= 0 || rev == 0 || int64(rev) < 0 {
+		return c, errors.ErrInvalidContinuation
+	}
+	token = token[n:]
+	klen, n := binary.Uvarint(token)
+	if n <= 0 || klen > uint64(len(token)-n) {
+		return c, errors.ErrInvalidContinuation
+	}
+	token = token[n:]
+	c = continuation{key: token[:klen], end: token[klen:], rev: int64(rev)}
+
+	// the continuation must resume the very same range
+	if !bytes.Equal(c.end, end) || bytes.Compare(c.key, key) < 0 ||
+		(len(end) > 0 && !bytes.Equal(end, []byte{0}) && bytes.Compare(c.key, end) >= 0) ||
+		(len(end) == 0 && !bytes.Equal(c.key, key)) {
+		return continuation{}, errors.ErrInvalidContinuation
+	}
+	return c, nil
+}
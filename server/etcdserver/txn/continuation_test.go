@@ -0,0 +1,87 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txn
+
+import (
+	"reflect"
+	"testing"
+
+	"go.etcd.io/etcd/server/v3/etcdserver/errors"
+)
+
+func TestContinuation(t *testing.T) {
+	tests := []struct {
+		name     string
+		c        continuation
+		key, end []byte
+		werr     error
+	}{
+		{
+			name: "range",
+			c:    continuation{key: []byte("foo2"), end: []byte("fop"), rev: 10},
+			key:  []byte("foo"), end: []byte("fop"),
+		},
+		{
+			name: "from key",
+			c:    continuation{key: []byte("foo2"), end: []byte{0}, rev: 10},
+			key:  []byte("foo"), end: []byte{0},
+		},
+		{
+			name: "other range end",
+			c:    continuation{key: []byte("foo2"), end: []byte("fop"), rev: 10},
+			key:  []byte("foo"), end: []byte("foo3"),
+			werr: errors.ErrInvalidContinuation,
+		},
+		{
+			name: "key before range",
+			c:    continuation{key: []byte("foo2"), end: []byte("fop"), rev: 10},
+			key:  []byte("foo3"), end: []byte("fop"),
+			werr: errors.ErrInvalidContinuation,
+		},
+		{
+			name: "key after range",
+			c:    continuation{key: []byte("fop"), end: []byte("fop"), rev: 10},
+			key:  []byte("foo"), end: []byte("fop"),
+			werr: errors.ErrInvalidContinuation,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := decodeContinuation(tt.c.encode(), tt.key, tt.end)
+			if err != tt.werr {
+				t.Fatalf("err = %v, want %v", err, tt.werr)
+			}
+			if err == nil && !reflect.DeepEqual(c, tt.c) {
+				t.Errorf("continuation = %+v, want %+v", c, tt.c)
+			}
+		})
+	}
+}
+
+func TestContinuationMalformed(t *testing.T) {
+	token := continuation{key: []byte("foo2"), end: []byte("fop"), rev: 10}.encode()
+	for i, tok := range [][]byte{
+		nil,
+		{2, 10, 4},     // unknown version
+		{1, 0, 0},      // no revision
+		token[:3],      // truncated key
+		{1, 10, 0xff},  // truncated length
+		{1, 10, 10, 1}, // key longer than the token
+	} {
+		if _, err := decodeContinuation(tok, []byte("foo"), []byte("fop")); err != errors.ErrInvalidContinuation {
+			t.Errorf("#%d: err = %v, want %v", i, err, errors.ErrInvalidContinuation)
+		}
+	}
+}
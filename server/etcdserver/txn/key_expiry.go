@@ -0,0 +1,98 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txn
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/pkg/v3/traceutil"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+)
+
+type requestTimeKey struct{}
+
+// WithRequestTime returns a context carrying the unix time, in seconds, at
+// which the applied request was proposed. The TTL of the keys it puts, or of
+// the role it grants, is counted from it, so that every member computes the
+// same expire time.
+func WithRequestTime(ctx context.Context, unix int64) context.Context {
+	return context.WithValue(ctx, requestTimeKey{}, unix)
+}
+
+// RequestTime returns the unix time carried by the context, or zero.
+func RequestTime(ctx context.Context) int64 {
+	unix, _ := ctx.Value(requestTimeKey{}).(int64)
+	return unix
+}
+
+// HasKeyTTL returns true if the request puts any key with a TTL.
+func HasKeyTTL(r *pb.InternalRaftRequest) bool {
+	if r.Put != nil {
+		return r.Put.Ttl != 0
+	}
+	return r.Txn != nil && txnHasKeyTTL(r.Txn)
+}
+
+func txnHasKeyTTL(rt *pb.TxnRequest) bool {
+	for _, reqs := range [][]*pb.RequestOp{rt.Success, rt.Failure} {
+		for _, req := range reqs {
+			switch tv := req.Request.(type) {
+			case *pb.RequestOp_RequestPut:
+				if tv.RequestPut.Ttl != 0 {
+					return true
+				}
+			case *pb.RequestOp_RequestTxn:
+				if txnHasKeyTTL(tv.RequestTxn) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// ExpireKeys deletes the expired keys of the request, unless they were put
+// again with another expire time since they were found expired.
+func ExpireKeys(ctx context.Context, lg *zap.Logger, kv mvcc.KV, r *pb.KeyExpireRequest) (resp *pb.DeleteRangeResponse, trace *traceutil.Trace, err error) {
+	trace = traceutil.Get(ctx)
+	if trace.IsEmpty() {
+		trace = traceutil.New("key_expire", lg, traceutil.Field{Key: "keys", Value: len(r.Keys)})
+		ctx = context.WithValue(ctx, traceutil.TraceKey, trace)
+	}
+	txnWrite := kv.Write(trace)
+	defer txnWrite.End()
+
+	resp = &pb.DeleteRangeResponse{Header: &pb.ResponseHeader{}}
+	for _, k := range r.Keys {
+		rr, err := txnWrite.Range(ctx, k.Key, nil, mvcc.RangeOptions{})
+		if err != nil {
+			return nil, trace, err
+		}
+		if len(rr.KVs) == 0 || rr.KVs[0].ExpireTime != k.ExpireTime {
+			continue
+		}
+		n, _ := txnWrite.DeleteRange(k.Key, nil)
+		resp.Deleted += n
+	}
+	resp.Header.Revision = txnWrite.Rev()
+	if resp.Deleted != 0 {
+		resp.Header.Revision++
+	}
+	trace.Step("delete expired keys")
+	return resp, trace, nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txn
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/pkg/v3/traceutil"
+	"go.etcd.io/etcd/server/v3/etcdserver/errors"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+)
+
+// MultiRange reads all the ranges of the given request from a single read txn,
+// so that they are consistent with each other.
+func MultiRange(ctx context.Context, lg *zap.Logger, kv mvcc.KV, r *pb.MultiRangeRequest) (resp *pb.MultiRangeResponse, trace *traceutil.Trace, err error) {
+	trace = traceutil.Get(ctx)
+	if trace.IsEmpty() {
+		trace = traceutil.New("multi_range", lg)
+		ctx = context.WithValue(ctx, traceutil.TraceKey, trace)
+	}
+	txnRead := kv.Read(mvcc.ConcurrentReadTxMode, trace)
+	defer txnRead.End()
+
+	// continued ranges are read at the revision of their continuation
+	rev := r.Revision
+	starts := make([][]byte, len(r.Ranges))
+	for i, sr := range r.Ranges {
+		starts[i] = sr.Key
+		if len(sr.Continuation) == 0 {
+			continue
+		}
+		c, err := decodeContinuation(sr.Continuation, sr.Key, sr.RangeEnd)
+		if err != nil {
+			return nil, trace, err
+		}
+		if rev > 0 && c.rev != rev {
+			return nil, trace, errors.ErrInvalidContinuation
+		}
+		rev = c.rev
+		starts[i] = c.key
+	}
+	if rev <= 0 {
+		rev = txnRead.Rev()
+	}
+
+	resp = &pb.MultiRangeResponse{
+		Header:    &pb.ResponseHeader{Revision: txnRead.Rev()},
+		Responses: make([]*pb.SubRangeResponse, len(r.Ranges)),
+		Revision:  rev,
+	}
+	for i, sr := range r.Ranges {
+		resp.Responses[i], err = executeSubRange(ctx, txnRead, starts[i], sr, rev)
+		if err != nil {
+			return nil, trace, err
+		}
+	}
+	trace.Step("range keys of all the ranges")
+	return resp, trace, nil
+}
+
+func executeSubRange(ctx context.Context, txnRead mvcc.TxnRead, key []byte, sr *pb.SubRange, rev int64) (*pb.SubRangeResponse, error) {
+	limit := sr.Limit
+	if limit > 0 {
+		// fetch one extra to continue from
+		limit = limit + 1
+	}
+	ro := mvcc.RangeOptions{
+		Limit: limit,
+		Rev:   rev,
+		Count: sr.CountOnly,
+	}
+	rr, err := txnRead.Range(ctx, key, mkGteRange(sr.RangeEnd), ro)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.SubRangeResponse{Count: int64(rr.Count)}
+	if sr.Limit > 0 && len(rr.KVs) > int(sr.Limit) {
+		resp.More = true
+		resp.Continuation = continuation{key: rr.KVs[sr.Limit].Key, end: sr.RangeEnd, rev: rev}.encode()
+		rr.KVs = rr.KVs[:sr.Limit]
+	}
+	resp.Kvs = make([]*mvccpb.KeyValue, len(rr.KVs))
+	for i := range rr.KVs {
+		if sr.KeysOnly {
+			rr.KVs[i].Value = nil
+		}
+		resp.Kvs[i] = &rr.KVs[i]
+	}
+	return resp, nil
+}
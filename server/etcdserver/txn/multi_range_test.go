@@ -0,0 +1,108 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txn
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/etcdserver/errors"
+	"go.etcd.io/etcd/server/v3/lease"
+	betesting "go.etcd.io/etcd/server/v3/storage/backend/testing"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+)
+
+func TestMultiRange(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, b)
+	s := mvcc.NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, mvcc.StoreConfig{})
+	defer s.Close()
+
+	for _, key := range []string{"a", "b1", "b2", "b3", "c"} {
+		s.Put([]byte(key), []byte("v"), lease.NoLease)
+	}
+
+	req := &pb.MultiRangeRequest{Ranges: []*pb.SubRange{
+		{Key: []byte("a")},
+		{Key: []byte("b"), RangeEnd: []byte("c"), Limit: 2},
+		{Key: []byte("c"), CountOnly: true},
+	}}
+	resp, _, err := MultiRange(context.TODO(), zaptest.NewLogger(t), s, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Revision != 6 {
+		t.Fatalf("revision = %d, want 6", resp.Revision)
+	}
+	wkeys := [][]string{{"a"}, {"b1", "b2"}, {}}
+	for i, r := range resp.Responses {
+		if keys := subRangeKeys(r); !equalKeys(keys, wkeys[i]) {
+			t.Errorf("#%d: keys = %q, want %q", i, keys, wkeys[i])
+		}
+	}
+	if sr := resp.Responses[1]; !sr.More || sr.Count != 3 || len(sr.Continuation) == 0 {
+		t.Fatalf("more = %v, count = %d, continuation = %q", sr.More, sr.Count, sr.Continuation)
+	}
+	if resp.Responses[2].Count != 1 {
+		t.Errorf("count = %d, want 1", resp.Responses[2].Count)
+	}
+
+	// the continuation reads the rest of the range at the same revision
+	s.Put([]byte("b4"), []byte("v"), lease.NoLease)
+	s.DeleteRange([]byte("b3"), nil)
+	creq := &pb.MultiRangeRequest{Ranges: []*pb.SubRange{
+		{Key: []byte("b"), RangeEnd: []byte("c"), Limit: 2, Continuation: resp.Responses[1].Continuation},
+	}}
+	cresp, _, err := MultiRange(context.TODO(), zaptest.NewLogger(t), s, creq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cresp.Revision != 6 || cresp.Header.Revision != 8 {
+		t.Errorf("revision = %d, header revision = %d, want 6 and 8", cresp.Revision, cresp.Header.Revision)
+	}
+	sr := cresp.Responses[0]
+	if keys := subRangeKeys(sr); !equalKeys(keys, []string{"b3"}) || sr.More || len(sr.Continuation) != 0 {
+		t.Errorf("keys = %q, more = %v, continuation = %q", keys, sr.More, sr.Continuation)
+	}
+
+	// a continuation cannot be read at another revision
+	creq.Revision = 7
+	if _, _, err = MultiRange(context.TODO(), zaptest.NewLogger(t), s, creq); err != errors.ErrInvalidContinuation {
+		t.Errorf("err = %v, want %v", err, errors.ErrInvalidContinuation)
+	}
+}
+
+func subRangeKeys(r *pb.SubRangeResponse) []string {
+	keys := make([]string, len(r.Kvs))
+	for i, kv := range r.Kvs {
+		keys[i] = string(kv.Key)
+	}
+	return keys
+}
+
+func equalKeys(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,137 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txn
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"go.uber.org/zap"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/pkg/v3/traceutil"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+)
+
+// RangeStats aggregates the keys in the range of the given request by child prefix.
+func RangeStats(ctx context.Context, lg *zap.Logger, kv mvcc.KV, r *pb.RangeStatsRequest) (resp *pb.RangeStatsResponse, trace *traceutil.Trace, err error) {
+	trace = traceutil.Get(ctx)
+	if trace.IsEmpty() {
+		trace = traceutil.New("range_stats", lg)
+		ctx = context.WithValue(ctx, traceutil.TraceKey, trace)
+	}
+	txnRead := kv.Read(mvcc.ConcurrentReadTxMode, trace)
+	defer txnRead.End()
+
+	agg := newStatsAggregator(r.Key, r.Delimiter)
+	ro := mvcc.RangeOptions{
+		Rev: r.Revision,
+		// aggregate every key-value pair as it is read from the backend
+		// instead of holding the whole range in memory
+		Filter: func(kv *mvccpb.KeyValue) bool {
+			agg.add(kv)
+			return false
+		},
+	}
+	rr, err := txnRead.Range(ctx, r.Key, mkGteRange(r.RangeEnd), ro)
+	if err != nil {
+		return nil, trace, err
+	}
+	trace.Step("aggregate the key-value pairs")
+
+	resp = &pb.RangeStatsResponse{
+		Header: &pb.ResponseHeader{Revision: rr.Rev},
+		Total:  agg.total,
+	}
+	resp.Children, resp.More = agg.children(r.Limit)
+	return resp, trace, nil
+}
+
+// statsAggregator sums the key-value pairs of a range by child prefix.
+type statsAggregator struct {
+	key       []byte
+	delimiter []byte
+
+	total *pb.PrefixStats
+	// last is the child prefix of the previous key; keys are read in
+	// order, so the keys of a child prefix mostly come one after another.
+	last  *pb.PrefixStats
+	byPfx map[string]*pb.PrefixStats
+}
+
+func newStatsAggregator(key, delimiter []byte) *statsAggregator {
+	return &statsAggregator{
+		key:       key,
+		delimiter: delimiter,
+		total:     &pb.PrefixStats{Prefix: key},
+		byPfx:     make(map[string]*pb.PrefixStats),
+	}
+}
+
+func (a *statsAggregator) add(kv *mvccpb.KeyValue) {
+	addStats(a.total, kv)
+	if len(a.delimiter) == 0 {
+		return
+	}
+
+	pfx := a.childPrefix(kv.Key)
+	if a.last == nil || !bytes.Equal(a.last.Prefix, pfx) {
+		st, ok := a.byPfx[string(pfx)]
+		if !ok {
+			st = &pb.PrefixStats{Prefix: pfx}
+			a.byPfx[string(pfx)] = st
+		}
+		a.last = st
+	}
+	addStats(a.last, kv)
+}
+
+// childPrefix returns the prefix of the key up to the first delimiter
+// following the bytes the key shares with the start of the range.
+func (a *statsAggregator) childPrefix(key []byte) []byte {
+	n := 0
+	for n < len(key) && n < len(a.key) && key[n] == a.key[n] {
+		n++
+	}
+	if i := bytes.Index(key[n:], a.delimiter); i >= 0 {
+		return key[:n+i+len(a.delimiter)]
+	}
+	return key
+}
+
+// children returns at most limit child prefixes ordered by prefix,
+// and whether there are more.
+func (a *statsAggregator) children(limit int64) ([]*pb.PrefixStats, bool) {
+	ret := make([]*pb.PrefixStats, 0, len(a.byPfx))
+	for _, st := range a.byPfx {
+		ret = append(ret, st)
+	}
+	sort.Slice(ret, func(i, j int) bool { return bytes.Compare(ret[i].Prefix, ret[j].Prefix) < 0 })
+	if limit > 0 && int64(len(ret)) > limit {
+		return ret[:limit], true
+	}
+	return ret, false
+}
+
+func addStats(st *pb.PrefixStats, kv *mvccpb.KeyValue) {
+	st.Count++
+	st.KeyBytes += int64(len(kv.Key))
+	st.ValueBytes += int64(len(kv.Value))
+	if kv.ModRevision > st.MaxModRevision {
+		st.MaxModRevision = kv.ModRevision
+	}
+}
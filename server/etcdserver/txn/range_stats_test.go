@@ -0,0 +1,108 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package txn
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/server/v3/lease"
+	betesting "go.etcd.io/etcd/server/v3/storage/backend/testing"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
+)
+
+func TestRangeStats(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, b)
+	s := mvcc.NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, mvcc.StoreConfig{})
+	defer s.Close()
+
+	for _, kv := range []struct{ key, val string }{
+		{"/a/x/1", "1"},
+		{"/a/x/2", "22"},
+		{"/a/y", "333"},
+		{"/a/z/1", "4444"},
+		{"/b/1", "55555"},
+	} {
+		s.Put([]byte(kv.key), []byte(kv.val), lease.NoLease)
+	}
+	// deleted keys are not aggregated
+	s.Put([]byte("/a/z/2"), []byte("6"), lease.NoLease)
+	s.DeleteRange([]byte("/a/z/2"), nil)
+
+	tests := []struct {
+		name string
+		req  *pb.RangeStatsRequest
+
+		wtotal    *pb.PrefixStats
+		wchildren []*pb.PrefixStats
+		wmore     bool
+	}{
+		{
+			name:   "no delimiter",
+			req:    &pb.RangeStatsRequest{Key: []byte("/a/"), RangeEnd: []byte("/a0")},
+			wtotal: &pb.PrefixStats{Prefix: []byte("/a/"), Count: 4, KeyBytes: 22, ValueBytes: 10, MaxModRevision: 5},
+		},
+		{
+			name:   "child prefixes",
+			req:    &pb.RangeStatsRequest{Key: []byte("/a/"), RangeEnd: []byte("/a0"), Delimiter: []byte("/")},
+			wtotal: &pb.PrefixStats{Prefix: []byte("/a/"), Count: 4, KeyBytes: 22, ValueBytes: 10, MaxModRevision: 5},
+			wchildren: []*pb.PrefixStats{
+				{Prefix: []byte("/a/x/"), Count: 2, KeyBytes: 12, ValueBytes: 3, MaxModRevision: 3},
+				{Prefix: []byte("/a/y"), Count: 1, KeyBytes: 4, ValueBytes: 3, MaxModRevision: 4},
+				{Prefix: []byte("/a/z/"), Count: 1, KeyBytes: 6, ValueBytes: 4, MaxModRevision: 5},
+			},
+		},
+		{
+			name:   "limit",
+			req:    &pb.RangeStatsRequest{Key: []byte("/a/"), RangeEnd: []byte("/a0"), Delimiter: []byte("/"), Limit: 1},
+			wtotal: &pb.PrefixStats{Prefix: []byte("/a/"), Count: 4, KeyBytes: 22, ValueBytes: 10, MaxModRevision: 5},
+			wchildren: []*pb.PrefixStats{
+				{Prefix: []byte("/a/x/"), Count: 2, KeyBytes: 12, ValueBytes: 3, MaxModRevision: 3},
+			},
+			wmore: true,
+		},
+		{
+			name:   "revision",
+			req:    &pb.RangeStatsRequest{Key: []byte("/"), RangeEnd: []byte("0"), Delimiter: []byte("/"), Revision: 7},
+			wtotal: &pb.PrefixStats{Prefix: []byte("/"), Count: 6, KeyBytes: 32, ValueBytes: 16, MaxModRevision: 7},
+			wchildren: []*pb.PrefixStats{
+				{Prefix: []byte("/a/"), Count: 5, KeyBytes: 28, ValueBytes: 11, MaxModRevision: 7},
+				{Prefix: []byte("/b/"), Count: 1, KeyBytes: 4, ValueBytes: 5, MaxModRevision: 6},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, _, err := RangeStats(context.TODO(), zaptest.NewLogger(t), s, tt.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(resp.Total, tt.wtotal) {
+				t.Errorf("total = %+v, want %+v", resp.Total, tt.wtotal)
+			}
+			if len(resp.Children) != len(tt.wchildren) || (len(tt.wchildren) != 0 && !reflect.DeepEqual(resp.Children, tt.wchildren)) {
+				t.Errorf("children = %+v, want %+v", resp.Children, tt.wchildren)
+			}
+			if resp.More != tt.wmore {
+				t.Errorf("more = %v, want %v", resp.More, tt.wmore)
+			}
+		})
+	}
+}
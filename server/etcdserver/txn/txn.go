@@ -88,7 +88,11 @@ func put(ctx context.Context, txnWrite mvcc.TxnWrite, p *pb.PutRequest) (resp *p
 		}
 	}
 
-	resp.Header.Revision = txnWrite.Put(p.Key, val, leaseID)
+	var expireTime int64
+	if p.Ttl > 0 {
+		expireTime = RequestTime(ctx) + p.Ttl
+	}
+	resp.Header.Revision = txnWrite.PutWithExpiry(p.Key, val, leaseID, expireTime)
 	trace.AddField(traceutil.Field{Key: "response_revision", Value: resp.Header.Revision})
 	return resp, nil
 }
@@ -150,6 +154,23 @@ func executeRange(ctx context.Context, lg *zap.Logger, txnRead mvcc.TxnRead, r *
 	resp := &pb.RangeResponse{}
 	resp.Header = &pb.ResponseHeader{}
 
+	// Only ranges sorted by key in ascending order can be continued.
+	continuable := r.SortTarget == pb.RangeRequest_KEY && r.SortOrder != pb.RangeRequest_DESCEND
+	key, rev := r.Key, r.Revision
+	if len(r.Continuation) != 0 {
+		if !continuable {
+			return nil, errors.ErrInvalidContinuation
+		}
+		c, err := decodeContinuation(r.Continuation, r.Key, r.RangeEnd)
+		if err != nil {
+			return nil, err
+		}
+		if rev > 0 && c.rev != rev {
+			return nil, errors.ErrInvalidContinuation
+		}
+		key, rev = c.key, c.rev
+	}
+
 	limit := r.Limit
 	if r.SortOrder != pb.RangeRequest_NONE ||
 		r.MinModRevision != 0 || r.MaxModRevision != 0 ||
@@ -163,12 +184,13 @@ func executeRange(ctx context.Context, lg *zap.Logger, txnRead mvcc.TxnRead, r *
 	}
 
 	ro := mvcc.RangeOptions{
-		Limit: limit,
-		Rev:   r.Revision,
-		Count: r.CountOnly,
+		Limit:  limit,
+		Rev:    rev,
+		Count:  r.CountOnly,
+		Filter: ValueFilter(r.ValueFilter),
 	}
 
-	rr, err := txnRead.Range(ctx, r.Key, mkGteRange(r.RangeEnd), ro)
+	rr, err := txnRead.Range(ctx, key, mkGteRange(r.RangeEnd), ro)
 	if err != nil {
 		return nil, err
 	}
@@ -227,6 +249,12 @@ func executeRange(ctx context.Context, lg *zap.Logger, txnRead mvcc.TxnRead, r *
 	}
 
 	if r.Limit > 0 && len(rr.KVs) > int(r.Limit) {
+		if continuable {
+			if rev <= 0 {
+				rev = rr.Rev
+			}
+			resp.Continuation = continuation{key: rr.KVs[r.Limit].Key, end: r.RangeEnd, rev: rev}.encode()
+		}
 		rr.KVs = rr.KVs[:r.Limit]
 		resp.More = true
 	}
@@ -506,6 +534,29 @@ func pruneKVs(rr *mvcc.RangeResult, isPrunable func(*mvccpb.KeyValue) bool) {
 	rr.KVs = rr.KVs[:j]
 }
 
+// ValueFilter returns a function reporting whether a key-value pair
+// satisfies every condition set in f, or nil if f is nil.
+func ValueFilter(f *pb.ValueFilter) func(*mvccpb.KeyValue) bool {
+	if f == nil {
+		return nil
+	}
+	return func(kv *mvccpb.KeyValue) bool {
+		switch {
+		case len(f.Prefix) != 0 && !bytes.HasPrefix(kv.Value, f.Prefix):
+			return false
+		case len(f.Contains) != 0 && !bytes.Contains(kv.Value, f.Contains):
+			return false
+		case int64(len(kv.Value)) < f.MinSize:
+			return false
+		case f.MaxSize != 0 && int64(len(kv.Value)) > f.MaxSize:
+			return false
+		case f.Lease != 0 && kv.Lease != f.Lease:
+			return false
+		}
+		return true
+	}
+}
+
 type kvSort struct{ kvs []mvccpb.KeyValue }
 
 func (s *kvSort) Swap(i, j int) {
@@ -681,19 +732,46 @@ func IsTxnReadonly(r *pb.TxnRequest) bool {
 	return true
 }
 
-func CheckTxnAuth(as auth.AuthStore, ai *auth.AuthInfo, rt *pb.TxnRequest) error {
+// CheckTxnAuth checks the permission of the user to execute the operations of
+// the txn. The puts of keys the user may only create are checked against rv,
+// see CheckPutAuth.
+func CheckTxnAuth(as auth.AuthStore, ai *auth.AuthInfo, rv mvcc.ReadView, rt *pb.TxnRequest) error {
 	for _, c := range rt.Compare {
 		if err := as.IsRangePermitted(ai, c.Key, c.RangeEnd); err != nil {
 			return err
 		}
 	}
-	if err := checkTxnReqsPermission(as, ai, rt.Success); err != nil {
+	if err := checkTxnReqsPermission(as, ai, rv, rt.Success); err != nil {
 		return err
 	}
-	return checkTxnReqsPermission(as, ai, rt.Failure)
+	return checkTxnReqsPermission(as, ai, rv, rt.Failure)
+}
+
+// CheckPutAuth checks the permission of the user to put the key, which it may
+// also do with only the permission to create the key if the key does not exist
+// in rv. A nil rv leaves checking the existence of the key to the caller.
+func CheckPutAuth(as auth.AuthStore, ai *auth.AuthInfo, rv mvcc.ReadView, p *pb.PutRequest) error {
+	if err := as.IsPutPermitted(ai, p.Key); err != nil {
+		if err != auth.ErrPermissionDenied || as.IsCreatePermitted(ai, p.Key) != nil {
+			return err
+		}
+		if rv != nil {
+			r, rerr := rv.Range(context.TODO(), p.Key, nil, mvcc.RangeOptions{Count: true})
+			if rerr != nil {
+				return rerr
+			}
+			if r.Count != 0 {
+				return err
+			}
+		}
+	}
+	if p.Lease != int64(lease.NoLease) {
+		return as.IsLeasePermitted(ai, p.Key)
+	}
+	return nil
 }
 
-func checkTxnReqsPermission(as auth.AuthStore, ai *auth.AuthInfo, reqs []*pb.RequestOp) error {
+func checkTxnReqsPermission(as auth.AuthStore, ai *auth.AuthInfo, rv mvcc.ReadView, reqs []*pb.RequestOp) error {
 	for _, requ := range reqs {
 		switch tv := requ.Request.(type) {
 		case *pb.RequestOp_RequestRange:
@@ -710,7 +788,7 @@ func checkTxnReqsPermission(as auth.AuthStore, ai *auth.AuthInfo, reqs []*pb.Req
 				continue
 			}
 
-			if err := as.IsPutPermitted(ai, tv.RequestPut.Key); err != nil {
+			if err := CheckPutAuth(as, ai, rv, tv.RequestPut); err != nil {
 				return err
 			}
 
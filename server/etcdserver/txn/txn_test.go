@@ -24,7 +24,9 @@ import (
 
 	"go.etcd.io/etcd/api/v3/authpb"
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/pkg/v3/traceutil"
 	"go.etcd.io/etcd/server/v3/auth"
+	"go.etcd.io/etcd/server/v3/etcdserver/errors"
 	"go.etcd.io/etcd/server/v3/lease"
 	"go.etcd.io/etcd/server/v3/storage/backend"
 	betesting "go.etcd.io/etcd/server/v3/storage/backend/testing"
@@ -67,6 +69,99 @@ func TestReadonlyTxnError(t *testing.T) {
 	}
 }
 
+func TestRangeContinuation(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, b)
+	s := mvcc.NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, mvcc.StoreConfig{})
+	defer s.Close()
+
+	for _, key := range []string{"foo1", "foo2", "foo3", "foo4", "foo5"} {
+		s.Put([]byte(key), []byte("bar"), lease.NoLease)
+	}
+
+	req := &pb.RangeRequest{Key: []byte("foo"), RangeEnd: []byte("fop"), Limit: 2}
+	var keys []string
+	for i := 0; i < 3; i++ {
+		resp, _, err := Range(context.TODO(), zaptest.NewLogger(t), s, req)
+		require.NoError(t, err)
+		for _, kv := range resp.Kvs {
+			keys = append(keys, string(kv.Key))
+		}
+		assert.Equal(t, resp.More, len(resp.Continuation) != 0)
+		if !resp.More {
+			break
+		}
+		req.Continuation = resp.Continuation
+		// keys written after the first page are not returned
+		s.Put([]byte("foo0"), []byte("bar"), lease.NoLease)
+		s.Put([]byte("foo6"), []byte("bar"), lease.NoLease)
+		s.DeleteRange([]byte("foo5"), nil)
+	}
+	assert.Equal(t, []string{"foo1", "foo2", "foo3", "foo4", "foo5"}, keys)
+
+	// continuations only resume ranges sorted by key in ascending order
+	resp, _, err := Range(context.TODO(), zaptest.NewLogger(t), s, &pb.RangeRequest{Key: []byte("foo"), RangeEnd: []byte("fop"), Limit: 2, SortOrder: pb.RangeRequest_DESCEND})
+	require.NoError(t, err)
+	assert.True(t, resp.More)
+	assert.Empty(t, resp.Continuation)
+
+	resp, _, err = Range(context.TODO(), zaptest.NewLogger(t), s, &pb.RangeRequest{Key: []byte("foo"), RangeEnd: []byte("fop"), Limit: 2})
+	require.NoError(t, err)
+	req = &pb.RangeRequest{Key: []byte("foo"), RangeEnd: []byte("fop"), Limit: 2, Continuation: resp.Continuation, SortTarget: pb.RangeRequest_MOD}
+	_, _, err = Range(context.TODO(), zaptest.NewLogger(t), s, req)
+	assert.Equal(t, errors.ErrInvalidContinuation, err)
+
+	// the revision of the continuation is compacted
+	req.SortTarget = pb.RangeRequest_KEY
+	s.Put([]byte("foo7"), []byte("bar"), lease.NoLease)
+	done, err := s.Compact(traceutil.TODO(), s.Rev())
+	require.NoError(t, err)
+	<-done
+	_, _, err = Range(context.TODO(), zaptest.NewLogger(t), s, req)
+	assert.Equal(t, mvcc.ErrCompacted, err)
+}
+
+func TestExpireKeys(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, b)
+	s := mvcc.NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, mvcc.StoreConfig{})
+	defer s.Close()
+	lg := zaptest.NewLogger(t)
+
+	// the expire time is counted from the time the request was proposed
+	ctx := WithRequestTime(context.TODO(), 100)
+	_, _, err := Put(ctx, lg, &lease.FakeLessor{}, s, &pb.PutRequest{Key: []byte("foo1"), Value: []byte("bar"), Ttl: 10})
+	require.NoError(t, err)
+	_, _, err = Put(ctx, lg, &lease.FakeLessor{}, s, &pb.PutRequest{Key: []byte("foo2"), Value: []byte("bar"), Ttl: 10})
+	require.NoError(t, err)
+	rr, _, err := Range(context.TODO(), lg, s, &pb.RangeRequest{Key: []byte("foo1")})
+	require.NoError(t, err)
+	assert.Equal(t, int64(110), rr.Kvs[0].ExpireTime)
+
+	expired := s.ExpiredKeys(110, 10)
+	require.Len(t, expired, 2)
+	req := &pb.KeyExpireRequest{}
+	for _, k := range expired {
+		req.Keys = append(req.Keys, &pb.ExpiredKey{Key: k.Key, ExpireTime: k.ExpireTime})
+	}
+
+	// foo2 is put again with a new TTL before the expired keys are deleted
+	_, _, err = Put(WithRequestTime(context.TODO(), 105), lg, &lease.FakeLessor{}, s, &pb.PutRequest{Key: []byte("foo2"), Value: []byte("bar"), Ttl: 10})
+	require.NoError(t, err)
+
+	resp, _, err := ExpireKeys(context.TODO(), lg, s, req)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), resp.Deleted)
+	assert.Equal(t, s.Rev(), resp.Header.Revision)
+
+	rr, _, err = Range(context.TODO(), lg, s, &pb.RangeRequest{Key: []byte("foo"), RangeEnd: []byte("fop")})
+	require.NoError(t, err)
+	require.Len(t, rr.Kvs, 1)
+	assert.Equal(t, "foo2", string(rr.Kvs[0].Key))
+	assert.Equal(t, int64(115), rr.Kvs[0].ExpireTime)
+	assert.Empty(t, s.ExpiredKeys(110, 10))
+}
+
 func TestWriteTxnPanic(t *testing.T) {
 	b, _ := betesting.NewDefaultTmpBackend(t)
 	defer betesting.Close(t, b)
@@ -238,12 +333,60 @@ func TestCheckTxnAuth(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := CheckTxnAuth(as, &auth.AuthInfo{Username: "foo", Revision: 8}, tt.txnRequest)
+			err := CheckTxnAuth(as, &auth.AuthInfo{Username: "foo", Revision: 8}, nil, tt.txnRequest)
 			assert.Equal(t, tt.err, err)
 		})
 	}
 }
 
+func TestCheckPutAuthCreateOnly(t *testing.T) {
+	be, _ := betesting.NewDefaultTmpBackend(t)
+	defer betesting.Close(t, be)
+	as := setupAuth(t, be)
+	s := mvcc.NewStore(zaptest.NewLogger(t), be, &lease.FakeLessor{}, mvcc.StoreConfig{})
+	defer s.Close()
+	s.Put([]byte("new/existing"), []byte("v"), lease.NoLease)
+
+	_, err := as.RoleAdd(&pb.AuthRoleAddRequest{Name: "creator"})
+	require.NoError(t, err)
+	for _, typ := range []authpb.Permission_Type{authpb.CREATE, authpb.DELETE} {
+		_, err = as.RoleGrantPermission(&pb.AuthRoleGrantPermissionRequest{
+			Name: "creator",
+			Perm: &authpb.Permission{PermType: typ, Key: []byte("new/"), RangeEnd: []byte("new0")},
+		})
+		require.NoError(t, err)
+	}
+	_, err = as.UserAdd(&pb.AuthUserAddRequest{Name: "bar", Password: "bar"})
+	require.NoError(t, err)
+	_, err = as.UserGrantRole(&pb.AuthUserGrantRoleRequest{User: "bar", Role: "creator"})
+	require.NoError(t, err)
+	ai := &auth.AuthInfo{Username: "bar", Revision: as.Revision()}
+
+	tests := []struct {
+		name string
+		put  *pb.PutRequest
+		rv   mvcc.ReadView
+		err  error
+	}{
+		{name: "Creating a key is authorized", put: &pb.PutRequest{Key: []byte("new/key")}, rv: s},
+		{name: "Updating a key is unauthorized", put: &pb.PutRequest{Key: []byte("new/existing")}, rv: s, err: auth.ErrPermissionDenied},
+		{name: "Updating a key is left to the apply without a read view", put: &pb.PutRequest{Key: []byte("new/existing")}},
+		{name: "Creating a key out of range is unauthorized", put: &pb.PutRequest{Key: []byte("foo")}, rv: s, err: auth.ErrPermissionDenied},
+		{name: "Creating a key with a lease is unauthorized", put: &pb.PutRequest{Key: []byte("new/key"), Lease: 1}, rv: s, err: auth.ErrPermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.err, CheckPutAuth(as, ai, tt.rv, tt.put))
+		})
+	}
+
+	// deleting, which the role permits, does not need the permission to write
+	err = CheckTxnAuth(as, ai, s, &pb.TxnRequest{Success: []*pb.RequestOp{{
+		Request: &pb.RequestOp_RequestDeleteRange{RequestDeleteRange: &pb.DeleteRangeRequest{Key: []byte("new/existing")}},
+	}}})
+	assert.NoError(t, err)
+}
+
 // CheckTxnAuth test setup.
 func setupAuth(t *testing.T, be backend.Backend) auth.AuthStore {
 	lg := zaptest.NewLogger(t)
@@ -37,7 +37,7 @@ import (
 
 	"github.com/gogo/protobuf/proto"
 	"go.uber.org/zap"
-	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/grpc/peer"
 )
 
 const (
@@ -60,6 +60,8 @@ type RaftKV interface {
 	DeleteRange(ctx context.Context, r *pb.DeleteRangeRequest) (*pb.DeleteRangeResponse, error)
 	Txn(ctx context.Context, r *pb.TxnRequest) (*pb.TxnResponse, error)
 	Compact(ctx context.Context, r *pb.CompactionRequest) (*pb.CompactionResponse, error)
+	RangeStats(ctx context.Context, r *pb.RangeStatsRequest) (*pb.RangeStatsResponse, error)
+	MultiRange(ctx context.Context, r *pb.MultiRangeRequest) (*pb.MultiRangeResponse, error)
 }
 
 type Lessor interface {
@@ -97,6 +99,11 @@ type Authenticator interface {
 	RoleDelete(ctx context.Context, r *pb.AuthRoleDeleteRequest) (*pb.AuthRoleDeleteResponse, error)
 	UserList(ctx context.Context, r *pb.AuthUserListRequest) (*pb.AuthUserListResponse, error)
 	RoleList(ctx context.Context, r *pb.AuthRoleListRequest) (*pb.AuthRoleListResponse, error)
+	UserSetLimits(ctx context.Context, r *pb.AuthUserSetLimitsRequest) (*pb.AuthUserSetLimitsResponse, error)
+	RoleSetLimits(ctx context.Context, r *pb.AuthRoleSetLimitsRequest) (*pb.AuthRoleSetLimitsResponse, error)
+	TokenList(ctx context.Context, r *pb.AuthTokenListRequest) (*pb.AuthTokenListResponse, error)
+	TokenRevoke(ctx context.Context, r *pb.AuthTokenRevokeRequest) (*pb.AuthTokenRevokeResponse, error)
+	AuthExplain(ctx context.Context, r *pb.AuthExplainRequest) (*pb.AuthExplainResponse, error)
 }
 
 func (s *EtcdServer) Range(ctx context.Context, r *pb.RangeRequest) (*pb.RangeResponse, error) {
@@ -120,7 +127,21 @@ func (s *EtcdServer) Range(ctx context.Context, r *pb.RangeRequest) (*pb.RangeRe
 		trace.LogIfLong(traceThreshold)
 	}(time.Now())
 
-	if !r.Serializable {
+	serializable := r.Serializable
+	if r.MaxStalenessRevisions > 0 || r.MaxStalenessMs > 0 {
+		serializable = s.isReadFresh(r)
+		switch {
+		case serializable:
+			boundedStalenessReads.WithLabelValues("local").Inc()
+		case r.FailStale:
+			boundedStalenessReads.WithLabelValues("rejected").Inc()
+			err = errors.ErrStaleRead
+			return nil, err
+		default:
+			boundedStalenessReads.WithLabelValues("linearizable").Inc()
+		}
+	}
+	if !serializable {
 		err = s.linearizableReadNotify(ctx)
 		trace.Step("agreement among raft nodes before linearized reading")
 		if err != nil {
@@ -139,6 +160,68 @@ func (s *EtcdServer) Range(ctx context.Context, r *pb.RangeRequest) (*pb.RangeRe
 	return resp, err
 }
 
+func (s *EtcdServer) RangeStats(ctx context.Context, r *pb.RangeStatsRequest) (*pb.RangeStatsResponse, error) {
+	trace := traceutil.New("range_stats",
+		s.Logger(),
+		traceutil.Field{Key: "range_begin", Value: string(r.Key)},
+		traceutil.Field{Key: "range_end", Value: string(r.RangeEnd)},
+	)
+	ctx = context.WithValue(ctx, traceutil.TraceKey, trace)
+	defer trace.LogIfLong(traceThreshold)
+
+	if !r.Serializable {
+		err := s.linearizableReadNotify(ctx)
+		trace.Step("agreement among raft nodes before linearized reading")
+		if err != nil {
+			return nil, err
+		}
+	}
+	chk := func(ai *auth.AuthInfo) error {
+		return s.authStore.IsRangePermitted(ai, r.Key, r.RangeEnd)
+	}
+
+	var resp *pb.RangeStatsResponse
+	var err error
+	get := func() { resp, _, err = txn.RangeStats(ctx, s.Logger(), s.KV(), r) }
+	if serr := s.doSerialize(ctx, chk, get); serr != nil {
+		return nil, serr
+	}
+	return resp, err
+}
+
+func (s *EtcdServer) MultiRange(ctx context.Context, r *pb.MultiRangeRequest) (*pb.MultiRangeResponse, error) {
+	trace := traceutil.New("multi_range",
+		s.Logger(),
+		traceutil.Field{Key: "ranges", Value: len(r.Ranges)},
+	)
+	ctx = context.WithValue(ctx, traceutil.TraceKey, trace)
+	defer trace.LogIfLong(traceThreshold)
+
+	if !r.Serializable {
+		err := s.linearizableReadNotify(ctx)
+		trace.Step("agreement among raft nodes before linearized reading")
+		if err != nil {
+			return nil, err
+		}
+	}
+	chk := func(ai *auth.AuthInfo) error {
+		for _, sr := range r.Ranges {
+			if err := s.authStore.IsRangePermitted(ai, sr.Key, sr.RangeEnd); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var resp *pb.MultiRangeResponse
+	var err error
+	get := func() { resp, _, err = txn.MultiRange(ctx, s.Logger(), s.KV(), r) }
+	if serr := s.doSerialize(ctx, chk, get); serr != nil {
+		return nil, serr
+	}
+	return resp, err
+}
+
 func (s *EtcdServer) Put(ctx context.Context, r *pb.PutRequest) (*pb.PutResponse, error) {
 	ctx = context.WithValue(ctx, traceutil.StartTimeKey, time.Now())
 	resp, err := s.raftRequest(ctx, pb.InternalRaftRequest{Put: r})
@@ -173,7 +256,7 @@ func (s *EtcdServer) Txn(ctx context.Context, r *pb.TxnRequest) (*pb.TxnResponse
 		var resp *pb.TxnResponse
 		var err error
 		chk := func(ai *auth.AuthInfo) error {
-			return txn.CheckTxnAuth(s.authStore, ai, r)
+			return txn.CheckTxnAuth(s.authStore, ai, nil, r)
 		}
 
 		defer func(start time.Time) {
@@ -353,7 +436,10 @@ func (s *EtcdServer) leaseTimeToLive(ctx context.Context, r *pb.LeaseTimeToLiveR
 			return nil, lease.ErrLeaseNotFound
 		}
 		// TODO: fill out ResponseHeader
-		resp := &pb.LeaseTimeToLiveResponse{Header: &pb.ResponseHeader{}, ID: r.ID, TTL: int64(le.Remaining().Seconds()), GrantedTTL: le.TTL()}
+		resp := &pb.LeaseTimeToLiveResponse{Header: &pb.ResponseHeader{}, ID: r.ID, TTL: int64(le.Remaining().Seconds()), GrantedTTL: le.TTL(), Parent: int64(le.Parent())}
+		for _, id := range le.Children() {
+			resp.Children = append(resp.Children, int64(id))
+		}
 		if r.Keys {
 			ks := le.Keys()
 			kbs := make([][]byte, len(ks))
@@ -430,7 +516,7 @@ func (s *EtcdServer) LeaseLeases(_ context.Context, _ *pb.LeaseLeasesRequest) (*
 	ls := s.lessor.Leases()
 	lss := make([]*pb.LeaseStatus, len(ls))
 	for i := range ls {
-		lss[i] = &pb.LeaseStatus{ID: int64(ls[i].ID)}
+		lss[i] = &pb.LeaseStatus{ID: int64(ls[i].ID), Parent: int64(ls[i].Parent())}
 	}
 	return &pb.LeaseLeasesResponse{Header: s.newHeader(), Leases: lss}, nil
 }
@@ -484,7 +570,15 @@ func (s *EtcdServer) AuthStatus(ctx context.Context, r *pb.AuthStatusRequest) (*
 	if err != nil {
 		return nil, err
 	}
-	return resp.(*pb.AuthStatusResponse), nil
+	sresp := resp.(*pb.AuthStatusResponse)
+	// tell the requester who it is authenticated as, so that proxies can
+	// check its permissions themselves
+	if authInfo, err := s.AuthInfoFromCtx(ctx); err == nil && authInfo != nil {
+		sresp.User = authInfo.Username
+		sresp.Roles = authInfo.Roles
+		sresp.Scope = authInfo.Scope
+	}
+	return sresp, nil
 }
 
 func (s *EtcdServer) Authenticate(ctx context.Context, r *pb.AuthenticateRequest) (*pb.AuthenticateResponse, error) {
@@ -525,6 +619,20 @@ func (s *EtcdServer) Authenticate(ctx context.Context, r *pb.AuthenticateRequest
 		internalReq := &pb.InternalAuthenticateRequest{
 			Name:        r.Name,
 			SimpleToken: st,
+			Created:     time.Now().Unix(),
+			Scope:       r.Scope,
+		}
+		if p, ok := peer.FromContext(ctx); ok {
+			internalReq.Address = p.Addr.String()
+		}
+		// the password is rehashed here rather than when applied, which would
+		// block the apply loop, and replaces the old hash only if unchanged.
+		if s.AuthStore().PasswordNeedsRehash(r.Name) {
+			internalReq.HashedPassword, internalReq.HashedPasswordAlgorithm, err = s.AuthStore().HashPassword(r.Password)
+			if err != nil {
+				return nil, err
+			}
+			internalReq.AuthRevision = checkedRevision
 		}
 
 		resp, err = s.raftRequestOnce(ctx, pb.InternalRaftRequest{Authenticate: internalReq})
@@ -543,11 +651,12 @@ func (s *EtcdServer) Authenticate(ctx context.Context, r *pb.AuthenticateRequest
 
 func (s *EtcdServer) UserAdd(ctx context.Context, r *pb.AuthUserAddRequest) (*pb.AuthUserAddResponse, error) {
 	if r.Options == nil || !r.Options.NoPassword {
-		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(r.Password), s.authStore.BcryptCost())
+		hashedPassword, alg, err := s.authStore.HashPassword(r.Password)
 		if err != nil {
 			return nil, err
 		}
 		r.HashedPassword = base64.StdEncoding.EncodeToString(hashedPassword)
+		r.HashedPasswordAlgorithm = alg
 		r.Password = ""
 	}
 
@@ -568,11 +677,12 @@ func (s *EtcdServer) UserDelete(ctx context.Context, r *pb.AuthUserDeleteRequest
 
 func (s *EtcdServer) UserChangePassword(ctx context.Context, r *pb.AuthUserChangePasswordRequest) (*pb.AuthUserChangePasswordResponse, error) {
 	if r.Password != "" {
-		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(r.Password), s.authStore.BcryptCost())
+		hashedPassword, alg, err := s.authStore.HashPassword(r.Password)
 		if err != nil {
 			return nil, err
 		}
 		r.HashedPassword = base64.StdEncoding.EncodeToString(hashedPassword)
+		r.HashedPasswordAlgorithm = alg
 		r.Password = ""
 	}
 
@@ -655,6 +765,54 @@ func (s *EtcdServer) RoleRevokePermission(ctx context.Context, r *pb.AuthRoleRev
 	return resp.(*pb.AuthRoleRevokePermissionResponse), nil
 }
 
+func (s *EtcdServer) UserSetLimits(ctx context.Context, r *pb.AuthUserSetLimitsRequest) (*pb.AuthUserSetLimitsResponse, error) {
+	resp, err := s.raftRequest(ctx, pb.InternalRaftRequest{AuthUserSetLimits: r})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.AuthUserSetLimitsResponse), nil
+}
+
+func (s *EtcdServer) RoleSetLimits(ctx context.Context, r *pb.AuthRoleSetLimitsRequest) (*pb.AuthRoleSetLimitsResponse, error) {
+	resp, err := s.raftRequest(ctx, pb.InternalRaftRequest{AuthRoleSetLimits: r})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.AuthRoleSetLimitsResponse), nil
+}
+
+func (s *EtcdServer) TokenList(ctx context.Context, r *pb.AuthTokenListRequest) (*pb.AuthTokenListResponse, error) {
+	resp, err := s.raftRequest(ctx, pb.InternalRaftRequest{AuthTokenList: r})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.AuthTokenListResponse), nil
+}
+
+func (s *EtcdServer) TokenRevoke(ctx context.Context, r *pb.AuthTokenRevokeRequest) (*pb.AuthTokenRevokeResponse, error) {
+	resp, err := s.raftRequest(ctx, pb.InternalRaftRequest{AuthTokenRevoke: r})
+	if err != nil {
+		return nil, err
+	}
+	return resp.(*pb.AuthTokenRevokeResponse), nil
+}
+
+// AuthExplain only reads the auth store, so it is served by this member once
+// it has applied every change committed before the request.
+func (s *EtcdServer) AuthExplain(ctx context.Context, r *pb.AuthExplainRequest) (*pb.AuthExplainResponse, error) {
+	if err := s.linearizableReadNotify(ctx); err != nil {
+		return nil, err
+	}
+	authInfo, err := s.AuthInfoFromCtx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err = s.AuthStore().IsAdminPermitted(authInfo); err != nil {
+		return nil, err
+	}
+	return s.AuthStore().Explain(r)
+}
+
 func (s *EtcdServer) RoleDelete(ctx context.Context, r *pb.AuthRoleDeleteRequest) (*pb.AuthRoleDeleteResponse, error) {
 	resp, err := s.raftRequest(ctx, pb.InternalRaftRequest{AuthRoleDelete: r})
 	if err != nil {
@@ -722,6 +880,11 @@ func (s *EtcdServer) processInternalRaftRequestOnce(ctx context.Context, r pb.In
 	r.Header = &pb.RequestHeader{
 		ID: s.reqIDGen.Next(),
 	}
+	if txn.HasKeyTTL(&r) || (r.AuthUserGrantRole != nil && r.AuthUserGrantRole.Ttl != 0) {
+		// the key and role grant TTLs are counted from the proposal time so
+		// that all the members agree on their expire time
+		r.Header.Timestamp = time.Now().Unix()
+	}
 
 	// check authinfo if it is not InternalAuthenticateRequest
 	if r.Authenticate == nil {
@@ -732,6 +895,11 @@ func (s *EtcdServer) processInternalRaftRequestOnce(ctx context.Context, r pb.In
 		if authInfo != nil {
 			r.Header.Username = authInfo.Username
 			r.Header.AuthRevision = authInfo.Revision
+			r.Header.Roles = authInfo.Roles
+			r.Header.Scope = authInfo.Scope
+			// the roles granted with a ttl are checked at the proposal time
+			// so that all the members agree on whether they expired
+			r.Header.Timestamp = time.Now().Unix()
 		}
 	}
 
@@ -800,6 +968,7 @@ func (s *EtcdServer) linearizableReadLoop() {
 		s.readNotifier = nextnr
 		s.readMu.Unlock()
 
+		requested := time.Now()
 		confirmedIndex, err := s.requestCurrentIndex(leaderChangedNotifier, requestId)
 		if isStopped(err) {
 			return
@@ -808,6 +977,8 @@ func (s *EtcdServer) linearizableReadLoop() {
 			nr.notify(err)
 			continue
 		}
+		// nothing after the confirmed index was committed when it was requested
+		s.leaderContacts.observe(confirmedIndex, requested, s.getAppliedIndex())
 
 		trace.Step("read index received")
 
@@ -966,7 +1137,7 @@ func (s *EtcdServer) AuthInfoFromCtx(ctx context.Context) (*auth.AuthInfo, error
 	if !s.Cfg.ClientCertAuthEnabled {
 		return nil, nil
 	}
-	authInfo = s.AuthStore().AuthInfoFromTLS(ctx)
+	authInfo = s.AuthStore().AuthInfoFromTLS(ctx, s.Cfg.ClientCertIdentity)
 	return authInfo, nil
 }
 
@@ -16,6 +16,7 @@ package lease
 
 import (
 	"math"
+	"sort"
 	"sync"
 	"time"
 
@@ -32,11 +33,17 @@ type Lease struct {
 	expiryMu sync.RWMutex
 	// expiry is time when lease should expire. no expiration when expiry.IsZero() is true
 	expiry time.Time
-
-	// mu protects concurrent accesses to itemSet
-	mu      sync.RWMutex
-	itemSet map[LeaseItem]struct{}
-	revokec chan struct{}
+	// parent is the lease owning this lease, if any. A child lease expires with its parent,
+	// so its own expiry is unused.
+	parent *Lease
+	// owner is the user the lease was granted to, if any.
+	owner string
+
+	// mu protects concurrent accesses to itemSet and children
+	mu       sync.RWMutex
+	itemSet  map[LeaseItem]struct{}
+	children map[LeaseID]*Lease
+	revokec  chan struct{}
 }
 
 func NewLease(id LeaseID, ttl int64) *Lease {
@@ -53,7 +60,7 @@ func (l *Lease) expired() bool {
 }
 
 func (l *Lease) persistTo(b backend.Backend) {
-	lpb := leasepb.Lease{ID: int64(l.ID), TTL: l.ttl, RemainingTTL: l.remainingTTL}
+	lpb := leasepb.Lease{ID: int64(l.ID), TTL: l.ttl, RemainingTTL: l.remainingTTL, Parent: int64(l.Parent()), Owner: l.owner}
 	tx := b.BatchTx()
 	tx.LockInsideApply()
 	defer tx.Unlock()
@@ -65,6 +72,68 @@ func (l *Lease) TTL() int64 {
 	return l.ttl
 }
 
+// Owner returns the user the Lease was granted to, if any.
+func (l *Lease) Owner() string {
+	return l.owner
+}
+
+// Parent returns the ID of the lease owning the Lease, or NoLease.
+func (l *Lease) Parent() LeaseID {
+	if l.parent == nil {
+		return NoLease
+	}
+	return l.parent.ID
+}
+
+// Children returns the IDs of the leases owned by the Lease.
+func (l *Lease) Children() []LeaseID {
+	l.mu.RLock()
+	ids := make([]LeaseID, 0, len(l.children))
+	for id := range l.children {
+		ids = append(ids, id)
+	}
+	l.mu.RUnlock()
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func (l *Lease) addChild(c *Lease) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.children == nil {
+		l.children = make(map[LeaseID]*Lease)
+	}
+	l.children[c.ID] = c
+}
+
+func (l *Lease) removeChild(c *Lease) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.children, c.ID)
+}
+
+// group returns the Lease and all the leases it owns, directly or not,
+// with the Lease first and every lease before the leases it owns.
+func (l *Lease) group() []*Lease {
+	ls := []*Lease{l}
+	for i := 0; i < len(ls); i++ {
+		ls[i].mu.RLock()
+		for _, c := range ls[i].children {
+			ls = append(ls, c)
+		}
+		ls[i].mu.RUnlock()
+	}
+	return ls
+}
+
+// root returns the lease whose expiry the Lease follows.
+func (l *Lease) root() *Lease {
+	for l.parent != nil {
+		l = l.parent
+	}
+	return l
+}
+
 // SetLeaseItem sets the given lease item, this func is thread-safe
 func (l *Lease) SetLeaseItem(item LeaseItem) {
 	l.mu.Lock()
@@ -108,6 +177,7 @@ func (l *Lease) Keys() []string {
 
 // Remaining returns the remaining time of the lease.
 func (l *Lease) Remaining() time.Duration {
+	l = l.root()
 	l.expiryMu.RLock()
 	defer l.expiryMu.RUnlock()
 	if l.expiry.IsZero() {
@@ -29,6 +29,8 @@ type Lease struct {
 	ID                   int64    `protobuf:"varint,1,opt,name=ID,proto3" json:"ID,omitempty"`
 	TTL                  int64    `protobuf:"varint,2,opt,name=TTL,proto3" json:"TTL,omitempty"`
 	RemainingTTL         int64    `protobuf:"varint,3,opt,name=RemainingTTL,proto3" json:"RemainingTTL,omitempty"`
+	Parent               int64    `protobuf:"varint,4,opt,name=Parent,proto3" json:"Parent,omitempty"`
+	Owner                string   `protobuf:"bytes,5,opt,name=Owner,proto3" json:"Owner,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -156,23 +158,25 @@ func init() {
 func init() { proto.RegisterFile("lease.proto", fileDescriptor_3dd57e402472b33a) }
 
 var fileDescriptor_3dd57e402472b33a = []byte{
-	// 256 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0xce, 0x49, 0x4d, 0x2c,
-	0x4e, 0xd5, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x07, 0x73, 0x0a, 0x92, 0xa4, 0x44, 0xd2,
-	0xf3, 0xd3, 0xf3, 0xc1, 0x62, 0xfa, 0x20, 0x16, 0x44, 0x5a, 0x4a, 0x3e, 0xb5, 0x24, 0x39, 0x45,
-	0x3f, 0xb1, 0x20, 0x53, 0x1f, 0xc4, 0x28, 0x4e, 0x2d, 0x2a, 0x4b, 0x2d, 0x2a, 0x48, 0xd2, 0x2f,
-	0x2a, 0x48, 0x86, 0x28, 0x50, 0xf2, 0xe5, 0x62, 0xf5, 0x01, 0x99, 0x20, 0xc4, 0xc7, 0xc5, 0xe4,
-	0xe9, 0x22, 0xc1, 0xa8, 0xc0, 0xa8, 0xc1, 0x1c, 0xc4, 0xe4, 0xe9, 0x22, 0x24, 0xc0, 0xc5, 0x1c,
-	0x12, 0xe2, 0x23, 0xc1, 0x04, 0x16, 0x00, 0x31, 0x85, 0x94, 0xb8, 0x78, 0x82, 0x52, 0x73, 0x13,
-	0x33, 0xf3, 0x32, 0xf3, 0xd2, 0x41, 0x52, 0xcc, 0x60, 0x29, 0x14, 0x31, 0xa5, 0x12, 0x2e, 0x11,
-	0xb0, 0x71, 0x9e, 0x79, 0x25, 0xa9, 0x45, 0x79, 0x89, 0x39, 0x41, 0xa9, 0x85, 0xa5, 0xa9, 0xc5,
-	0x25, 0x42, 0x31, 0x5c, 0x62, 0x60, 0xf1, 0x90, 0xcc, 0xdc, 0xd4, 0x90, 0x7c, 0x9f, 0xcc, 0xb2,
-	0x54, 0xa8, 0x0c, 0xd8, 0x46, 0x6e, 0x23, 0x15, 0x3d, 0x64, 0xf7, 0xe9, 0x61, 0x57, 0x1b, 0x84,
-	0xc3, 0x0c, 0xa5, 0x0a, 0x2e, 0x51, 0x34, 0x5b, 0x8b, 0x0b, 0xf2, 0xf3, 0x8a, 0x53, 0x85, 0xe2,
-	0xb9, 0xc4, 0x31, 0xb4, 0x40, 0xa4, 0xa0, 0xf6, 0xaa, 0x12, 0xb0, 0x17, 0xa2, 0x38, 0x08, 0x97,
-	0x29, 0x4e, 0x12, 0x27, 0x1e, 0xca, 0x31, 0x5c, 0x78, 0x28, 0xc7, 0x70, 0xe2, 0x91, 0x1c, 0xe3,
-	0x85, 0x47, 0x72, 0x8c, 0x0f, 0x1e, 0xc9, 0x31, 0xce, 0x78, 0x2c, 0xc7, 0x90, 0xc4, 0x06, 0x0e,
-	0x5f, 0x63, 0x40, 0x00, 0x00, 0x00, 0xff, 0xff, 0x7b, 0x8a, 0x94, 0xb9, 0xae, 0x01, 0x00, 0x00,
+	// 285 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x91, 0xdf, 0x4a, 0xc3, 0x30,
+	0x14, 0xc6, 0x97, 0xd5, 0x4e, 0x3c, 0x13, 0x91, 0x50, 0x67, 0xd9, 0x45, 0x1c, 0x45, 0x61, 0x57,
+	0x2d, 0xe8, 0x1b, 0xc8, 0x6e, 0x0a, 0x05, 0x25, 0xf4, 0x52, 0x90, 0x76, 0x1e, 0x4a, 0x61, 0x4b,
+	0x62, 0x1a, 0xa7, 0xe0, 0x8b, 0xf8, 0x48, 0xbb, 0xdc, 0x23, 0xb8, 0xfa, 0x22, 0xd2, 0xb4, 0x17,
+	0xfe, 0x1b, 0xde, 0x9d, 0xf3, 0xfd, 0xbe, 0x7c, 0x5f, 0x42, 0x60, 0xb8, 0xc0, 0xac, 0xc2, 0x50,
+	0x69, 0x69, 0x24, 0xdd, 0xb7, 0x8b, 0xca, 0xc7, 0x5e, 0x21, 0x0b, 0x69, 0xb5, 0xa8, 0x99, 0x5a,
+	0x3c, 0x3e, 0x43, 0x33, 0x7f, 0x88, 0x32, 0x55, 0x46, 0xcd, 0x50, 0xa1, 0x5e, 0xa1, 0x56, 0x79,
+	0xa4, 0xd5, 0xbc, 0x35, 0x04, 0xaf, 0xe0, 0x26, 0x4d, 0x02, 0x3d, 0x82, 0x7e, 0x3c, 0xf3, 0xc9,
+	0x84, 0x4c, 0x1d, 0xde, 0x8f, 0x67, 0xf4, 0x18, 0x9c, 0x34, 0x4d, 0xfc, 0xbe, 0x15, 0x9a, 0x91,
+	0x06, 0x70, 0xc8, 0x71, 0x99, 0x95, 0xa2, 0x14, 0x45, 0x83, 0x1c, 0x8b, 0xbe, 0x69, 0x74, 0x04,
+	0x83, 0xdb, 0x4c, 0xa3, 0x30, 0xfe, 0x9e, 0xa5, 0xdd, 0x46, 0x3d, 0x70, 0x6f, 0x9e, 0x05, 0x6a,
+	0xdf, 0x9d, 0x90, 0xe9, 0x01, 0x6f, 0x97, 0xc0, 0x80, 0x67, 0xcb, 0x63, 0x61, 0x50, 0x8b, 0x6c,
+	0xc1, 0xf1, 0xf1, 0x09, 0x2b, 0x43, 0xef, 0x60, 0x64, 0xf5, 0xb4, 0x5c, 0x62, 0x2a, 0x93, 0x72,
+	0x85, 0x1d, 0xb1, 0xf7, 0x1b, 0x5e, 0x9e, 0x87, 0x5f, 0x5f, 0x13, 0xfe, 0xed, 0xe5, 0x3b, 0x32,
+	0x82, 0x17, 0x38, 0xf9, 0xd1, 0x5a, 0x29, 0x29, 0x2a, 0xa4, 0xf7, 0x70, 0xfa, 0xeb, 0x48, 0x8b,
+	0xba, 0xde, 0x8b, 0x7f, 0x7a, 0x5b, 0x33, 0xdf, 0x95, 0x72, 0xed, 0xaf, 0xb7, 0xac, 0xb7, 0xd9,
+	0xb2, 0xde, 0xba, 0x66, 0x64, 0x53, 0x33, 0xf2, 0x5e, 0x33, 0xf2, 0xf6, 0xc1, 0x7a, 0xf9, 0xc0,
+	0xfe, 0xc6, 0xd5, 0xe7, 0x00, 0xe1, 0x60, 0x2e, 0xd4, 0xdc, 0x01, 0x00, 0x00,
 }
 
 func (m *Lease) Marshal() (dAtA []byte, err error) {
@@ -199,6 +203,18 @@ func (m *Lease) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.Owner) > 0 {
+		i -= len(m.Owner)
+		copy(dAtA[i:], m.Owner)
+		i = encodeVarintLease(dAtA, i, uint64(len(m.Owner)))
+		i--
+		dAtA[i] = 0x2a
+	}
+	if m.Parent != 0 {
+		i = encodeVarintLease(dAtA, i, uint64(m.Parent))
+		i--
+		dAtA[i] = 0x20
+	}
 	if m.RemainingTTL != 0 {
 		i = encodeVarintLease(dAtA, i, uint64(m.RemainingTTL))
 		i--
@@ -321,6 +337,13 @@ func (m *Lease) Size() (n int) {
 	if m.RemainingTTL != 0 {
 		n += 1 + sovLease(uint64(m.RemainingTTL))
 	}
+	if m.Parent != 0 {
+		n += 1 + sovLease(uint64(m.Parent))
+	}
+	l = len(m.Owner)
+	if l > 0 {
+		n += 1 + l + sovLease(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -451,6 +474,57 @@ func (m *Lease) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Parent", wireType)
+			}
+			m.Parent = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLease
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Parent |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Owner", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowLease
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthLease
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthLease
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Owner = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipLease(dAtA[iNdEx:])
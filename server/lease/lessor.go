@@ -90,9 +90,13 @@ type Lessor interface {
 
 	// Grant grants a lease that expires at least after TTL seconds.
 	Grant(id LeaseID, ttl int64) (*Lease, error)
-	// Revoke revokes a lease with given ID. The item attached to the
-	// given lease will be removed. If the ID does not exist, an error
-	// will be returned.
+	// GrantChild grants a lease owned by the lease with the given parent ID.
+	// The child lease expires and is renewed with its parent, and is revoked
+	// with it. If the parent does not exist, an error will be returned.
+	GrantChild(id, parent LeaseID) (*Lease, error)
+	// Revoke revokes a lease with given ID and the leases it owns. The item
+	// attached to the revoked leases will be removed. If the ID does not
+	// exist, an error will be returned.
 	Revoke(id LeaseID) error
 
 	// Checkpoint applies the remainingTTL of a lease. The remainingTTL is used in Promote to set
@@ -120,12 +124,19 @@ type Lessor interface {
 	Demote()
 
 	// Renew renews a lease with given ID. It returns the renewed TTL. If the ID does not exist,
-	// an error will be returned.
+	// an error will be returned. Renewing a child lease renews the lease it expires with.
 	Renew(id LeaseID) (int64, error)
 
 	// Lookup gives the lease at a given lease id, if any
 	Lookup(id LeaseID) *Lease
 
+	// SetOwner records the user the lease with given LeaseID was granted to.
+	// If the lease does not exist, an error will be returned.
+	SetOwner(id LeaseID, owner string) error
+
+	// Owned returns the number of leases granted to the given user.
+	Owned(owner string) int
+
 	// Leases lists all leases.
 	Leases() []*Lease
 
@@ -153,6 +164,8 @@ type lessor struct {
 	leaseExpiredNotifier *LeaseExpiredNotifier
 	leaseCheckpointHeap  LeaseQueue
 	itemMap              map[LeaseItem]LeaseID
+	// owned is the number of leases granted to every owner.
+	owned map[string]int
 
 	// When a lease expires, the lessor will delete the
 	// leased range (or key) by the RangeDeleter.
@@ -216,6 +229,7 @@ func newLessor(lg *zap.Logger, b backend.Backend, cluster cluster, cfg LessorCon
 	l := &lessor{
 		leaseMap:                  make(map[LeaseID]*Lease),
 		itemMap:                   make(map[LeaseItem]LeaseID),
+		owned:                     make(map[string]int),
 		leaseExpiredNotifier:      newLeaseExpiredNotifier(),
 		leaseCheckpointHeap:       make(LeaseQueue, 0),
 		b:                         b,
@@ -313,6 +327,35 @@ func (le *lessor) Grant(id LeaseID, ttl int64) (*Lease, error) {
 	return l, nil
 }
 
+func (le *lessor) GrantChild(id, parent LeaseID) (*Lease, error) {
+	if id == NoLease {
+		return nil, ErrLeaseNotFound
+	}
+
+	le.mu.Lock()
+	defer le.mu.Unlock()
+
+	if _, ok := le.leaseMap[id]; ok {
+		return nil, ErrLeaseExists
+	}
+	p := le.leaseMap[parent]
+	if p == nil {
+		return nil, ErrLeaseNotFound
+	}
+
+	// the child is neither in the expired notifier nor checkpointed, since
+	// it expires with its parent.
+	l := NewLease(id, p.ttl)
+	l.parent = p
+	p.addChild(l)
+
+	le.leaseMap[id] = l
+	l.persistTo(le.b)
+
+	leaseGranted.Inc()
+	return l, nil
+}
+
 func (le *lessor) Revoke(id LeaseID) error {
 	le.mu.Lock()
 
@@ -325,9 +368,20 @@ func (le *lessor) Revoke(id LeaseID) error {
 	// We shouldn't delete the lease inside the transaction lock, otherwise
 	// it may lead to deadlock with Grant or Checkpoint operations, which
 	// acquire the le.mu firstly and then the batchTx lock.
-	delete(le.leaseMap, id)
+	ls := l.group()
+	for _, l := range ls {
+		delete(le.leaseMap, l.ID)
+		le.unsafeCountOwned(l.owner, -1)
+	}
+	if l.parent != nil {
+		l.parent.removeChild(l)
+	}
 
-	defer close(l.revokec)
+	defer func() {
+		for _, l := range ls {
+			close(l.revokec)
+		}
+	}()
 	// unlock before doing external work
 	le.mu.Unlock()
 
@@ -339,7 +393,10 @@ func (le *lessor) Revoke(id LeaseID) error {
 
 	// sort keys so deletes are in same order among all members,
 	// otherwise the backend hashes will be different
-	keys := l.Keys()
+	var keys []string
+	for _, l := range ls {
+		keys = append(keys, l.Keys()...)
+	}
 	sort.StringSlice(keys).Sort()
 	for _, key := range keys {
 		txn.DeleteRange([]byte(key), nil)
@@ -348,11 +405,13 @@ func (le *lessor) Revoke(id LeaseID) error {
 	// lease deletion needs to be in the same backend transaction with the
 	// kv deletion. Or we might end up with not executing the revoke or not
 	// deleting the keys if etcdserver fails in between.
-	schema.UnsafeDeleteLease(le.b.BatchTx(), &leasepb.Lease{ID: int64(l.ID)})
+	for _, l := range ls {
+		schema.UnsafeDeleteLease(le.b.BatchTx(), &leasepb.Lease{ID: int64(l.ID)})
+	}
 
 	txn.End()
 
-	leaseRevoked.Inc()
+	leaseRevoked.Add(float64(len(ls)))
 	return nil
 }
 
@@ -400,6 +459,7 @@ func (le *lessor) Renew(id LeaseID) (int64, error) {
 		le.mu.RUnlock()
 		return -1, ErrLeaseNotFound
 	}
+	l = l.root()
 	// Clear remaining TTL when we renew if it is set
 	clearRemainingTTL := le.cp != nil && l.remainingTTL > 0
 
@@ -443,6 +503,39 @@ func (le *lessor) Lookup(id LeaseID) *Lease {
 	return le.leaseMap[id]
 }
 
+func (le *lessor) SetOwner(id LeaseID, owner string) error {
+	le.mu.Lock()
+	defer le.mu.Unlock()
+
+	l := le.leaseMap[id]
+	if l == nil {
+		return ErrLeaseNotFound
+	}
+	le.unsafeCountOwned(l.owner, -1)
+	l.owner = owner
+	le.unsafeCountOwned(owner, 1)
+	l.persistTo(le.b)
+	return nil
+}
+
+func (le *lessor) Owned(owner string) int {
+	le.mu.RLock()
+	defer le.mu.RUnlock()
+	return le.owned[owner]
+}
+
+// unsafeCountOwned adds delta to the number of leases granted to owner.
+func (le *lessor) unsafeCountOwned(owner string, delta int) {
+	if owner == "" {
+		return
+	}
+	if n := le.owned[owner] + delta; n > 0 {
+		le.owned[owner] = n
+	} else {
+		delete(le.owned, owner)
+	}
+}
+
 func (le *lessor) unsafeLeases() []*Lease {
 	leases := make([]*Lease, 0, len(le.leaseMap))
 	for _, l := range le.leaseMap {
@@ -465,8 +558,11 @@ func (le *lessor) Promote(extend time.Duration) {
 
 	le.demotec = make(chan struct{})
 
-	// refresh the expiries of all leases.
+	// refresh the expiries of all leases, but the child leases which expire with their parent.
 	for _, l := range le.leaseMap {
+		if l.parent != nil {
+			continue
+		}
 		l.refresh(extend)
 		item := &LeaseWithTime{id: l.ID, time: l.expiry}
 		le.leaseExpiredNotifier.RegisterOrUpdate(item)
@@ -480,6 +576,16 @@ func (le *lessor) Promote(extend time.Duration) {
 
 	// adjust expiries in case of overlap
 	leases := le.unsafeLeases()
+	roots := leases[:0]
+	for _, l := range leases {
+		if l.parent == nil {
+			roots = append(roots, l)
+		}
+	}
+	leases = roots
+	if len(leases) == 0 {
+		return
+	}
 	sort.Sort(leasesByExpiry(leases))
 
 	baseWindow := leases[0].Remaining()
@@ -587,6 +693,7 @@ func (le *lessor) Recover(b backend.Backend, rd RangeDeleter) {
 	le.rd = rd
 	le.leaseMap = make(map[LeaseID]*Lease)
 	le.itemMap = make(map[LeaseItem]LeaseID)
+	le.owned = make(map[string]int)
 	le.initAndRecover()
 }
 
@@ -806,6 +913,20 @@ func (le *lessor) initAndRecover() {
 			expiry:       forever,
 			revokec:      make(chan struct{}),
 			remainingTTL: lpb.RemainingTTL,
+			owner:        lpb.Owner,
+		}
+		le.unsafeCountOwned(lpb.Owner, 1)
+	}
+	for _, lpb := range lpbs {
+		if lpb.Parent == int64(NoLease) {
+			continue
+		}
+		// a parent is revoked in the same transaction as its children
+		l, p := le.leaseMap[LeaseID(lpb.ID)], le.leaseMap[LeaseID(lpb.Parent)]
+		if p != nil {
+			l.parent = p
+			l.ttl = p.ttl
+			p.addChild(l)
 		}
 	}
 	le.leaseExpiredNotifier.Init()
@@ -824,6 +945,8 @@ func (fl *FakeLessor) SetCheckpointer(cp Checkpointer) {}
 
 func (fl *FakeLessor) Grant(id LeaseID, ttl int64) (*Lease, error) { return nil, nil }
 
+func (fl *FakeLessor) GrantChild(id, parent LeaseID) (*Lease, error) { return nil, nil }
+
 func (fl *FakeLessor) Revoke(id LeaseID) error { return nil }
 
 func (fl *FakeLessor) Checkpoint(id LeaseID, remainingTTL int64) error { return nil }
@@ -841,6 +964,10 @@ func (fl *FakeLessor) Renew(id LeaseID) (int64, error) { return 10, nil }
 
 func (fl *FakeLessor) Lookup(id LeaseID) *Lease { return nil }
 
+func (fl *FakeLessor) SetOwner(id LeaseID, owner string) error { return nil }
+
+func (fl *FakeLessor) Owned(owner string) int { return 0 }
+
 func (fl *FakeLessor) Leases() []*Lease { return nil }
 
 func (fl *FakeLessor) ExpiredLeasesC() <-chan []*Lease { return nil }
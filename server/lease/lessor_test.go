@@ -100,7 +100,7 @@ func TestLessorGrant(t *testing.T) {
 	defer tx.Unlock()
 	lpb := schema.MustUnsafeGetLease(tx, int64(l.ID))
 	if lpb == nil {
-		t.Errorf("lpb = %d, want not nil", lpb)
+		t.Errorf("lpb = %v, want not nil", lpb)
 	}
 }
 
@@ -204,7 +204,121 @@ func TestLessorRevoke(t *testing.T) {
 	defer tx.Unlock()
 	lpb := schema.MustUnsafeGetLease(tx, int64(l.ID))
 	if lpb != nil {
-		t.Errorf("lpb = %d, want nil", lpb)
+		t.Errorf("lpb = %v, want nil", lpb)
+	}
+}
+
+// TestLessorGrantChild ensures child leases expire and are renewed with their parent.
+func TestLessorGrantChild(t *testing.T) {
+	lg := zap.NewNop()
+	dir, be := NewTestBackend(t)
+	defer os.RemoveAll(dir)
+	defer be.Close()
+
+	le := newLessor(lg, be, clusterLatest(), LessorConfig{MinLeaseTTL: minLeaseTTL})
+	defer le.Stop()
+	le.Promote(0)
+
+	p, err := le.Grant(1, 100)
+	if err != nil {
+		t.Fatalf("could not grant lease (%v)", err)
+	}
+	if _, err = le.GrantChild(2, 3); err != ErrLeaseNotFound {
+		t.Errorf("err = %v, want %v", err, ErrLeaseNotFound)
+	}
+	if _, err = le.GrantChild(1, 1); err != ErrLeaseExists {
+		t.Errorf("err = %v, want %v", err, ErrLeaseExists)
+	}
+	c1, err := le.GrantChild(2, p.ID)
+	if err != nil {
+		t.Fatalf("could not grant child lease (%v)", err)
+	}
+	c2, err := le.GrantChild(3, c1.ID)
+	if err != nil {
+		t.Fatalf("could not grant child lease (%v)", err)
+	}
+
+	if c1.Parent() != p.ID || c2.Parent() != c1.ID || p.Parent() != NoLease {
+		t.Errorf("parents = %x, %x, %x, want %x, %x, %x", p.Parent(), c1.Parent(), c2.Parent(), NoLease, p.ID, c1.ID)
+	}
+	if !reflect.DeepEqual(p.Children(), []LeaseID{c1.ID}) {
+		t.Errorf("children = %v, want %v", p.Children(), []LeaseID{c1.ID})
+	}
+	if c2.TTL() != p.TTL() {
+		t.Errorf("ttl = %d, want %d", c2.TTL(), p.TTL())
+	}
+
+	// renewing a child renews the lease it expires with
+	le.mu.Lock()
+	p.ttl = 200
+	le.mu.Unlock()
+	if ttl := renew(t, le, c2.ID); ttl != 200 {
+		t.Errorf("ttl = %d, want 200", ttl)
+	}
+	if c2.root() != p || c2.Remaining() < 199*time.Second {
+		t.Errorf("remaining = %v, want about 200s", c2.Remaining())
+	}
+}
+
+// TestLessorRevokeGroup ensures revoking a lease revokes the leases it owns.
+func TestLessorRevokeGroup(t *testing.T) {
+	lg := zap.NewNop()
+	dir, be := NewTestBackend(t)
+	defer os.RemoveAll(dir)
+	defer be.Close()
+
+	le := newLessor(lg, be, clusterLatest(), LessorConfig{MinLeaseTTL: minLeaseTTL})
+	defer le.Stop()
+	var fd *fakeDeleter
+	le.SetRangeDeleter(func() TxnDelete {
+		fd = newFakeDeleter(be)
+		return fd
+	})
+
+	p, err := le.Grant(1, 100)
+	if err != nil {
+		t.Fatalf("could not grant lease (%v)", err)
+	}
+	for i, key := range []string{"foo", "bar", "baz"} {
+		id := LeaseID(i + 2)
+		if _, err = le.GrantChild(id, p.ID); err != nil {
+			t.Fatalf("could not grant child lease (%v)", err)
+		}
+		if err = le.Attach(id, []LeaseItem{{key}}); err != nil {
+			t.Fatalf("failed to attach items to the lease: %v", err)
+		}
+	}
+	if err = le.Attach(p.ID, []LeaseItem{{"qux"}}); err != nil {
+		t.Fatalf("failed to attach items to the lease: %v", err)
+	}
+
+	// revoking a child only revokes the child
+	if err = le.Revoke(4); err != nil {
+		t.Fatal("failed to revoke lease:", err)
+	}
+	if !reflect.DeepEqual(fd.deleted, []string{"baz_"}) {
+		t.Errorf("deleted= %v, want %v", fd.deleted, []string{"baz_"})
+	}
+	if !reflect.DeepEqual(p.Children(), []LeaseID{2, 3}) {
+		t.Errorf("children = %v, want %v", p.Children(), []LeaseID{2, 3})
+	}
+
+	if err = le.Revoke(p.ID); err != nil {
+		t.Fatal("failed to revoke lease:", err)
+	}
+	wdeleted := []string{"bar_", "foo_", "qux_"}
+	if !reflect.DeepEqual(fd.deleted, wdeleted) {
+		t.Errorf("deleted= %v, want %v", fd.deleted, wdeleted)
+	}
+	if len(le.Leases()) != 0 {
+		t.Errorf("leases = %v, want none", le.Leases())
+	}
+
+	tx := be.BatchTx()
+	tx.Lock()
+	defer tx.Unlock()
+	if lpbs := schema.MustUnsafeGetAllLeases(tx); len(lpbs) != 0 {
+		t.Errorf("lpbs = %v, want none", lpbs)
 	}
 }
 
@@ -398,6 +512,55 @@ func TestLessorDetach(t *testing.T) {
 
 // TestLessorRecover ensures Lessor recovers leases from
 // persist backend.
+// TestLessorSetOwner ensures the owners of leases are counted and recovered.
+func TestLessorSetOwner(t *testing.T) {
+	lg := zap.NewNop()
+	dir, be := NewTestBackend(t)
+	defer os.RemoveAll(dir)
+	defer be.Close()
+
+	le := newLessor(lg, be, clusterLatest(), LessorConfig{MinLeaseTTL: minLeaseTTL})
+	defer le.Stop()
+	for id := LeaseID(1); id <= 3; id++ {
+		if _, err := le.Grant(id, 10); err != nil {
+			t.Fatalf("could not grant lease %d (%v)", id, err)
+		}
+	}
+	if err := le.SetOwner(4, "foo"); err != ErrLeaseNotFound {
+		t.Errorf("err = %v, want %v", err, ErrLeaseNotFound)
+	}
+	for _, id := range []LeaseID{1, 2} {
+		if err := le.SetOwner(id, "foo"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := le.Owned("foo"); n != 2 {
+		t.Errorf("owned = %d, want 2", n)
+	}
+
+	// Create a new lessor with the same backend
+	nle := newLessor(lg, be, clusterLatest(), LessorConfig{MinLeaseTTL: minLeaseTTL})
+	defer nle.Stop()
+	if owner := nle.Lookup(1).Owner(); owner != "foo" {
+		t.Errorf("owner = %q, want %q", owner, "foo")
+	}
+	if n := nle.Owned("foo"); n != 2 {
+		t.Errorf("owned = %d, want 2", n)
+	}
+	if err := nle.Revoke(1); err != nil {
+		t.Fatal(err)
+	}
+	if n := nle.Owned("foo"); n != 1 {
+		t.Errorf("owned = %d, want 1", n)
+	}
+	if err := nle.SetOwner(2, "bar"); err != nil {
+		t.Fatal(err)
+	}
+	if n, m := nle.Owned("foo"), nle.Owned("bar"); n != 0 || m != 1 {
+		t.Errorf("owned = %d, %d, want 0, 1", n, m)
+	}
+}
+
 func TestLessorRecover(t *testing.T) {
 	lg := zap.NewNop()
 	dir, be := NewTestBackend(t)
@@ -426,6 +589,35 @@ func TestLessorRecover(t *testing.T) {
 	}
 }
 
+func TestLessorRecoverGroup(t *testing.T) {
+	lg := zap.NewNop()
+	dir, be := NewTestBackend(t)
+	defer os.RemoveAll(dir)
+	defer be.Close()
+
+	le := newLessor(lg, be, clusterLatest(), LessorConfig{MinLeaseTTL: minLeaseTTL})
+	defer le.Stop()
+	p, err1 := le.Grant(1, 10)
+	c, err2 := le.GrantChild(2, 1)
+	if err1 != nil || err2 != nil {
+		t.Fatalf("could not grant initial leases (%v, %v)", err1, err2)
+	}
+
+	nle := newLessor(lg, be, clusterLatest(), LessorConfig{MinLeaseTTL: minLeaseTTL})
+	defer nle.Stop()
+	nle.Promote(0)
+	np, nc := nle.Lookup(p.ID), nle.Lookup(c.ID)
+	if nc == nil || nc.Parent() != p.ID {
+		t.Fatalf("nc = %v, want parent %x", nc, p.ID)
+	}
+	if !reflect.DeepEqual(np.Children(), []LeaseID{c.ID}) {
+		t.Errorf("children = %v, want %v", np.Children(), []LeaseID{c.ID})
+	}
+	if nc.root() != np || nc.Remaining() > 10*time.Second {
+		t.Errorf("remaining = %v, want about 10s", nc.Remaining())
+	}
+}
+
 func TestLessorExpire(t *testing.T) {
 	lg := zap.NewNop()
 	dir, be := NewTestBackend(t)
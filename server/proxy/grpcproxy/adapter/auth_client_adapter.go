@@ -64,6 +64,10 @@ func (s *as2ac) RoleRevokePermission(ctx context.Context, in *pb.AuthRoleRevokeP
 	return s.as.RoleRevokePermission(ctx, in)
 }
 
+func (s *as2ac) RoleSetLimits(ctx context.Context, in *pb.AuthRoleSetLimitsRequest, opts ...grpc.CallOption) (*pb.AuthRoleSetLimitsResponse, error) {
+	return s.as.RoleSetLimits(ctx, in)
+}
+
 func (s *as2ac) RoleGrantPermission(ctx context.Context, in *pb.AuthRoleGrantPermissionRequest, opts ...grpc.CallOption) (*pb.AuthRoleGrantPermissionResponse, error) {
 	return s.as.RoleGrantPermission(ctx, in)
 }
@@ -92,6 +96,57 @@ func (s *as2ac) UserRevokeRole(ctx context.Context, in *pb.AuthUserRevokeRoleReq
 	return s.as.UserRevokeRole(ctx, in)
 }
 
+func (s *as2ac) UserSetLimits(ctx context.Context, in *pb.AuthUserSetLimitsRequest, opts ...grpc.CallOption) (*pb.AuthUserSetLimitsResponse, error) {
+	return s.as.UserSetLimits(ctx, in)
+}
+
+func (s *as2ac) TokenList(ctx context.Context, in *pb.AuthTokenListRequest, opts ...grpc.CallOption) (*pb.AuthTokenListResponse, error) {
+	return s.as.TokenList(ctx, in)
+}
+
+func (s *as2ac) TokenRevoke(ctx context.Context, in *pb.AuthTokenRevokeRequest, opts ...grpc.CallOption) (*pb.AuthTokenRevokeResponse, error) {
+	return s.as.TokenRevoke(ctx, in)
+}
+
+func (s *as2ac) AuthExplain(ctx context.Context, in *pb.AuthExplainRequest, opts ...grpc.CallOption) (*pb.AuthExplainResponse, error) {
+	return s.as.AuthExplain(ctx, in)
+}
+
 func (s *as2ac) UserChangePassword(ctx context.Context, in *pb.AuthUserChangePasswordRequest, opts ...grpc.CallOption) (*pb.AuthUserChangePasswordResponse, error) {
 	return s.as.UserChangePassword(ctx, in)
 }
+
+func (s *as2ac) AuthWatch(ctx context.Context, in *pb.AuthWatchRequest, opts ...grpc.CallOption) (pb.Auth_AuthWatchClient, error) {
+	cs := newPipeStream(ctx, func(ss chanServerStream) error {
+		return s.as.AuthWatch(in, &aws2awcServerStream{ss})
+	})
+	return &aws2awcClientStream{cs}, nil
+}
+
+// aws2awcClientStream implements Auth_AuthWatchClient
+type aws2awcClientStream struct{ chanClientStream }
+
+// aws2awcServerStream implements Auth_AuthWatchServer
+type aws2awcServerStream struct{ chanServerStream }
+
+func (s *aws2awcClientStream) Send(rr *pb.AuthWatchRequest) error {
+	return s.SendMsg(rr)
+}
+func (s *aws2awcClientStream) Recv() (*pb.AuthWatchResponse, error) {
+	var v interface{}
+	if err := s.RecvMsg(&v); err != nil {
+		return nil, err
+	}
+	return v.(*pb.AuthWatchResponse), nil
+}
+
+func (s *aws2awcServerStream) Send(rr *pb.AuthWatchResponse) error {
+	return s.SendMsg(rr)
+}
+func (s *aws2awcServerStream) Recv() (*pb.AuthWatchRequest, error) {
+	var v interface{}
+	if err := s.RecvMsg(&v); err != nil {
+		return nil, err
+	}
+	return v.(*pb.AuthWatchRequest), nil
+}
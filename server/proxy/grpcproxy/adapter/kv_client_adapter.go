@@ -32,6 +32,14 @@ func (s *kvs2kvc) Range(ctx context.Context, in *pb.RangeRequest, opts ...grpc.C
 	return s.kvs.Range(ctx, in)
 }
 
+func (s *kvs2kvc) RangeStats(ctx context.Context, in *pb.RangeStatsRequest, opts ...grpc.CallOption) (*pb.RangeStatsResponse, error) {
+	return s.kvs.RangeStats(ctx, in)
+}
+
+func (s *kvs2kvc) MultiRange(ctx context.Context, in *pb.MultiRangeRequest, opts ...grpc.CallOption) (*pb.MultiRangeResponse, error) {
+	return s.kvs.MultiRange(ctx, in)
+}
+
 func (s *kvs2kvc) Put(ctx context.Context, in *pb.PutRequest, opts ...grpc.CallOption) (*pb.PutResponse, error) {
 	return s.kvs.Put(ctx, in)
 }
@@ -90,3 +90,38 @@ func (s *ss2scServerStream) Recv() (*pb.SnapshotRequest, error) {
 	}
 	return v.(*pb.SnapshotRequest), nil
 }
+
+func (s *mts2mtc) SnapshotDelta(ctx context.Context, in *pb.SnapshotDeltaRequest, opts ...grpc.CallOption) (pb.Maintenance_SnapshotDeltaClient, error) {
+	cs := newPipeStream(ctx, func(ss chanServerStream) error {
+		return s.mts.SnapshotDelta(in, &sds2sdcServerStream{ss})
+	})
+	return &sds2sdcClientStream{cs}, nil
+}
+
+// sds2sdcClientStream implements Maintenance_SnapshotDeltaClient
+type sds2sdcClientStream struct{ chanClientStream }
+
+// sds2sdcServerStream implements Maintenance_SnapshotDeltaServer
+type sds2sdcServerStream struct{ chanServerStream }
+
+func (s *sds2sdcClientStream) Send(rr *pb.SnapshotDeltaRequest) error {
+	return s.SendMsg(rr)
+}
+func (s *sds2sdcClientStream) Recv() (*pb.SnapshotDeltaResponse, error) {
+	var v interface{}
+	if err := s.RecvMsg(&v); err != nil {
+		return nil, err
+	}
+	return v.(*pb.SnapshotDeltaResponse), nil
+}
+
+func (s *sds2sdcServerStream) Send(rr *pb.SnapshotDeltaResponse) error {
+	return s.SendMsg(rr)
+}
+func (s *sds2sdcServerStream) Recv() (*pb.SnapshotDeltaRequest, error) {
+	var v interface{}
+	if err := s.RecvMsg(&v); err != nil {
+		return nil, err
+	}
+	return v.(*pb.SnapshotDeltaRequest), nil
+}
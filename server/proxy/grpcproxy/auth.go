@@ -16,6 +16,7 @@ package grpcproxy
 
 import (
 	"context"
+	"io"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	clientv3 "go.etcd.io/etcd/client/v3"
@@ -65,6 +66,10 @@ func (ap *AuthProxy) RoleRevokePermission(ctx context.Context, r *pb.AuthRoleRev
 	return ap.authClient.RoleRevokePermission(ctx, r)
 }
 
+func (ap *AuthProxy) RoleSetLimits(ctx context.Context, r *pb.AuthRoleSetLimitsRequest) (*pb.AuthRoleSetLimitsResponse, error) {
+	return ap.authClient.RoleSetLimits(ctx, r)
+}
+
 func (ap *AuthProxy) RoleGrantPermission(ctx context.Context, r *pb.AuthRoleGrantPermissionRequest) (*pb.AuthRoleGrantPermissionResponse, error) {
 	return ap.authClient.RoleGrantPermission(ctx, r)
 }
@@ -93,6 +98,46 @@ func (ap *AuthProxy) UserRevokeRole(ctx context.Context, r *pb.AuthUserRevokeRol
 	return ap.authClient.UserRevokeRole(ctx, r)
 }
 
+func (ap *AuthProxy) UserSetLimits(ctx context.Context, r *pb.AuthUserSetLimitsRequest) (*pb.AuthUserSetLimitsResponse, error) {
+	return ap.authClient.UserSetLimits(ctx, r)
+}
+
+func (ap *AuthProxy) TokenList(ctx context.Context, r *pb.AuthTokenListRequest) (*pb.AuthTokenListResponse, error) {
+	return ap.authClient.TokenList(ctx, r)
+}
+
+func (ap *AuthProxy) TokenRevoke(ctx context.Context, r *pb.AuthTokenRevokeRequest) (*pb.AuthTokenRevokeResponse, error) {
+	return ap.authClient.TokenRevoke(ctx, r)
+}
+
+func (ap *AuthProxy) AuthExplain(ctx context.Context, r *pb.AuthExplainRequest) (*pb.AuthExplainResponse, error) {
+	return ap.authClient.AuthExplain(ctx, r)
+}
+
 func (ap *AuthProxy) UserChangePassword(ctx context.Context, r *pb.AuthUserChangePasswordRequest) (*pb.AuthUserChangePasswordResponse, error) {
 	return ap.authClient.UserChangePassword(ctx, r)
 }
+
+func (ap *AuthProxy) AuthWatch(r *pb.AuthWatchRequest, stream pb.Auth_AuthWatchServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	ctx = withClientAuthToken(ctx, stream.Context())
+
+	wc, err := ap.authClient.AuthWatch(ctx, r)
+	if err != nil {
+		return err
+	}
+	for {
+		resp, err := wc.Recv()
+		if err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if err = stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
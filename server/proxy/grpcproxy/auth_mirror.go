@@ -0,0 +1,211 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcproxy
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/auth"
+)
+
+// authMirrorRetryInterval is the interval to wait before syncing the auth
+// store again, once its watch fails.
+const authMirrorRetryInterval = 500 * time.Millisecond
+
+// AuthMirror keeps a copy of the users and roles of the cluster, synced by
+// watching its auth store, to check the permissions of the requests served from
+// the proxy cache or from coalesced watches without forwarding them.
+//
+// The identity of the user of a token is only known to the cluster, it is
+// resolved with AuthStatus and cached for the identity TTL, or until the auth
+// store changes, such as when tokens are revoked. Whenever the
+// permission of a request cannot be decided locally, the request must be
+// checked by the cluster.
+type AuthMirror struct {
+	lg          *zap.Logger
+	ac          pb.AuthClient
+	m           *auth.Mirror
+	identityTTL time.Duration
+
+	mu sync.Mutex
+	// synced is true while the mirror is watching the auth store
+	synced bool
+	// identities caches the users of the tokens of the requests
+	identities map[string]*identity
+	// identitiesGen is incremented whenever identities is dropped, so that the
+	// identities resolved before are not cached
+	identitiesGen uint64
+}
+
+type identity struct {
+	authInfo *auth.AuthInfo
+	expire   time.Time
+}
+
+func NewAuthMirror(ctx context.Context, lg *zap.Logger, c *clientv3.Client, identityTTL time.Duration) *AuthMirror {
+	if lg == nil {
+		lg = zap.NewNop()
+	}
+	am := &AuthMirror{
+		lg:          lg,
+		ac:          pb.NewAuthClient(c.ActiveConnection()),
+		m:           auth.NewMirror(lg),
+		identityTTL: identityTTL,
+		identities:  make(map[string]*identity),
+	}
+	go am.run(ctx)
+	return am
+}
+
+func (am *AuthMirror) run(ctx context.Context) {
+	var rev uint64
+	for {
+		err := am.sync(ctx, &rev)
+		am.mu.Lock()
+		am.synced = false
+		am.mu.Unlock()
+		if ctx.Err() != nil {
+			return
+		}
+		am.lg.Warn("failed to watch auth store; retrying", zap.Uint64("revision", rev), zap.Error(err))
+		select {
+		case <-time.After(authMirrorRetryInterval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sync watches the auth store from the given revision, and updates the mirror
+// and the revision with every state received.
+func (am *AuthMirror) sync(ctx context.Context, rev *uint64) error {
+	wc, err := am.ac.AuthWatch(ctx, &pb.AuthWatchRequest{Revision: *rev})
+	if err != nil {
+		return err
+	}
+	for {
+		resp, err := wc.Recv()
+		if err != nil {
+			return err
+		}
+		am.m.Update(&auth.State{
+			Enabled:  resp.Enabled,
+			Revision: resp.Revision,
+			Users:    resp.Users,
+			Roles:    resp.Roles,
+		})
+		am.mu.Lock()
+		if resp.Revision != *rev {
+			// the tokens may have been revoked, or their users changed
+			am.identities = make(map[string]*identity)
+			am.identitiesGen++
+		}
+		*rev = resp.Revision
+		if !am.synced {
+			am.lg.Info("synced auth store", zap.Uint64("revision", resp.Revision))
+		}
+		am.synced = true
+		am.mu.Unlock()
+	}
+}
+
+// isRangePermitted returns whether the permission of the caller of ctx to read
+// the range is known, and the error if it is not permitted.
+func (am *AuthMirror) isRangePermitted(ctx context.Context, key, rangeEnd []byte) (bool, error) {
+	return am.check(ctx, func(ai *auth.AuthInfo) error {
+		return am.m.IsRangePermitted(ai, key, rangeEnd)
+	})
+}
+
+// isWatchPermitted returns whether the permission of the caller of ctx to watch
+// the range is known, and the error if it is not permitted.
+func (am *AuthMirror) isWatchPermitted(ctx context.Context, key, rangeEnd []byte) (bool, error) {
+	return am.check(ctx, func(ai *auth.AuthInfo) error {
+		return am.m.IsWatchPermitted(ai, key, rangeEnd)
+	})
+}
+
+func (am *AuthMirror) check(ctx context.Context, isPermitted func(*auth.AuthInfo) error) (bool, error) {
+	if am == nil {
+		return false, nil
+	}
+	am.mu.Lock()
+	synced := am.synced
+	am.mu.Unlock()
+	if !synced {
+		return false, nil
+	}
+	if !am.m.IsAuthEnabled() {
+		return true, nil
+	}
+	token := getAuthTokenFromClient(ctx)
+	if token == "" {
+		// the user may be identified by its client certificate, which only
+		// the cluster sees
+		return false, nil
+	}
+	ai := am.authInfo(ctx, token)
+	if ai == nil {
+		return false, nil
+	}
+	switch err := isPermitted(ai); err {
+	case nil:
+		return true, nil
+	case auth.ErrPermissionDenied:
+		return true, rpctypes.ErrGRPCPermissionDenied
+	default:
+		// e.g. the user was changed since the identity was resolved
+		return false, nil
+	}
+}
+
+// authInfo returns the user of the token, or nil if it cannot be resolved.
+func (am *AuthMirror) authInfo(ctx context.Context, token string) *auth.AuthInfo {
+	now := time.Now()
+	am.mu.Lock()
+	id, ok := am.identities[token]
+	gen := am.identitiesGen
+	am.mu.Unlock()
+	if ok && now.Before(id.expire) {
+		return id.authInfo
+	}
+
+	resp, err := am.ac.AuthStatus(ctx, &pb.AuthStatusRequest{})
+	if err != nil || resp.User == "" {
+		return nil
+	}
+	id = &identity{
+		authInfo: &auth.AuthInfo{Username: resp.User, Roles: resp.Roles, Scope: resp.Scope},
+		expire:   now.Add(am.identityTTL),
+	}
+	am.mu.Lock()
+	for t, other := range am.identities {
+		if !now.Before(other.expire) {
+			delete(am.identities, t)
+		}
+	}
+	if gen == am.identitiesGen {
+		am.identities[token] = id
+	}
+	am.mu.Unlock()
+	return id.authInfo
+}
@@ -0,0 +1,332 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+
+	"github.com/google/btree"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+)
+
+// Replica is an in-memory multi-version copy of the keys of a range, built
+// from a range of the cluster and kept up to date with the events of a watch.
+// It serves ranges at its current revision, and at the older revisions of a
+// sliding window of history.
+type Replica struct {
+	key, end []byte
+	history  int64
+
+	mu   sync.RWMutex
+	keys *btree.BTreeG[*replicaKey]
+	// header is the header of the responses, from the last update
+	header pb.ResponseHeader
+	// rev is the revision the replica is up to date with
+	rev int64
+	// minRev is the oldest revision the replica may serve ranges at
+	minRev int64
+	// trimRev is the revision the history of all keys was last trimmed at
+	trimRev int64
+	ready   bool
+}
+
+type replicaKey struct {
+	key []byte
+	// revs are the versions of the key in revision order, a deletion having no
+	// key-value
+	revs []replicaRev
+}
+
+type replicaRev struct {
+	rev int64
+	kv  *mvccpb.KeyValue
+}
+
+// NewReplica creates an empty replica of the range [key, end), end being
+// "\x00" for all the keys from key. It keeps the versions of history
+// revisions.
+func NewReplica(key, end []byte, history int64) *Replica {
+	if history < 1 {
+		history = 1
+	}
+	return &Replica{
+		key:     key,
+		end:     end,
+		history: history,
+		keys: btree.NewG(32, func(a, b *replicaKey) bool {
+			return bytes.Compare(a.key, b.key) < 0
+		}),
+	}
+}
+
+// Reset replaces the content of the replica with the key-values of a range at
+// the revision of its header.
+func (rp *Replica) Reset(kvs []*mvccpb.KeyValue, h *pb.ResponseHeader) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	rp.keys.Clear(false)
+	for _, kv := range kvs {
+		rp.keys.ReplaceOrInsert(&replicaKey{key: kv.Key, revs: []replicaRev{{rev: kv.ModRevision, kv: kv}}})
+	}
+	rp.header = *h
+	rp.rev, rp.minRev, rp.trimRev = h.Revision, h.Revision, h.Revision
+	rp.ready = true
+}
+
+// Apply applies the events of a watch response to the replica.
+func (rp *Replica) Apply(evs []*mvccpb.Event, h *pb.ResponseHeader) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	rp.updateHeader(h)
+	for _, ev := range evs {
+		rev := ev.Kv.ModRevision
+		if rev <= rp.rev {
+			continue
+		}
+		rk, ok := rp.keys.Get(&replicaKey{key: ev.Kv.Key})
+		if !ok {
+			if ev.Type == mvccpb.DELETE {
+				continue
+			}
+			rk = &replicaKey{key: ev.Kv.Key}
+			rp.keys.ReplaceOrInsert(rk)
+		}
+		rr := replicaRev{rev: rev}
+		if ev.Type == mvccpb.PUT {
+			rr.kv = ev.Kv
+		}
+		rk.revs = append(rk.revs, rr)
+	}
+	if n := len(evs); n > 0 && evs[n-1].Kv.ModRevision > rp.rev {
+		rp.advance(evs[n-1].Kv.ModRevision)
+	}
+}
+
+// Progress records that the replica is up to date with the revision of the
+// header of a progress notification.
+func (rp *Replica) Progress(h *pb.ResponseHeader) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	rp.updateHeader(h)
+	if h.Revision > rp.rev {
+		rp.advance(h.Revision)
+	}
+}
+
+// Invalidate stops the replica from serving ranges, until it is reset.
+func (rp *Replica) Invalidate() {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	rp.ready = false
+}
+
+// Rev returns the revision the replica is up to date with.
+func (rp *Replica) Rev() int64 {
+	rp.mu.RLock()
+	defer rp.mu.RUnlock()
+	return rp.rev
+}
+
+func (rp *Replica) updateHeader(h *pb.ResponseHeader) {
+	rp.header.ClusterId, rp.header.MemberId, rp.header.RaftTerm = h.ClusterId, h.MemberId, h.RaftTerm
+}
+
+// advance moves the replica to the revision, and trims the history which falls
+// out of the window.
+func (rp *Replica) advance(rev int64) {
+	rp.rev = rev
+	if rev-rp.history > rp.minRev {
+		rp.minRev = rev - rp.history
+	}
+	// the history of every key is trimmed once per window
+	if rp.minRev-rp.trimRev < rp.history {
+		return
+	}
+	var deleted []*replicaKey
+	rp.keys.Ascend(func(rk *replicaKey) bool {
+		if rk.trim(rp.minRev) {
+			deleted = append(deleted, rk)
+		}
+		return true
+	})
+	for _, rk := range deleted {
+		rp.keys.Delete(rk)
+	}
+	rp.trimRev = rp.minRev
+}
+
+// trim drops the versions of the key which are superseded at minRev, and
+// returns whether the key was deleted at minRev and has no later version.
+func (rk *replicaKey) trim(minRev int64) bool {
+	i := sort.Search(len(rk.revs), func(i int) bool { return rk.revs[i].rev > minRev })
+	if i > 1 {
+		rk.revs = append(rk.revs[:0], rk.revs[i-1:]...)
+	}
+	return len(rk.revs) == 1 && rk.revs[0].kv == nil && rk.revs[0].rev <= minRev
+}
+
+// at returns the version of the key at the revision, or nil if the key does
+// not exist at the revision.
+func (rk *replicaKey) at(rev int64) *mvccpb.KeyValue {
+	i := sort.Search(len(rk.revs), func(i int) bool { return rk.revs[i].rev > rev })
+	if i == 0 {
+		return nil
+	}
+	return rk.revs[i-1].kv
+}
+
+// IsServable returns whether the range is within the replica, and of a kind
+// the replica serves. Ranges which are continued or continuable, or filter
+// values, are left to the cluster.
+func (rp *Replica) IsServable(r *pb.RangeRequest) bool {
+	continuable := r.SortTarget == pb.RangeRequest_KEY && r.SortOrder != pb.RangeRequest_DESCEND
+	return len(r.Continuation) == 0 && r.ValueFilter == nil && (r.Limit == 0 || !continuable) &&
+		rp.contains(r.Key, r.RangeEnd)
+}
+
+// Range returns the response of the range at its revision, or at the revision
+// of the replica if it has none. It returns false if the replica cannot serve
+// the range.
+func (rp *Replica) Range(r *pb.RangeRequest) (*pb.RangeResponse, bool) {
+	if !rp.IsServable(r) {
+		return nil, false
+	}
+	rp.mu.RLock()
+	defer rp.mu.RUnlock()
+	if !rp.ready {
+		return nil, false
+	}
+	rev := r.Revision
+	if rev <= 0 {
+		rev = rp.rev
+	}
+	if rev < rp.minRev || rev > rp.rev {
+		return nil, false
+	}
+
+	var kvs []*mvccpb.KeyValue
+	visit := func(rk *replicaKey) bool {
+		if kv := rk.at(rev); kv != nil {
+			kvs = append(kvs, kv)
+		}
+		return true
+	}
+	switch {
+	case len(r.RangeEnd) == 0:
+		if rk, ok := rp.keys.Get(&replicaKey{key: r.Key}); ok {
+			visit(rk)
+		}
+	case isFromKey(r.RangeEnd):
+		rp.keys.AscendGreaterOrEqual(&replicaKey{key: r.Key}, visit)
+	default:
+		rp.keys.AscendRange(&replicaKey{key: r.Key}, &replicaKey{key: r.RangeEnd}, visit)
+	}
+
+	header := rp.header
+	header.Revision = rp.rev
+	resp := &pb.RangeResponse{Header: &header, Count: int64(len(kvs))}
+	if r.CountOnly {
+		return resp, true
+	}
+	kvs = filterKVs(r, kvs)
+	sortKVs(r, kvs)
+	if r.Limit > 0 && int64(len(kvs)) > r.Limit {
+		kvs = kvs[:r.Limit]
+		resp.More = true
+	}
+	if r.KeysOnly {
+		for i, kv := range kvs {
+			keyOnly := *kv
+			keyOnly.Value = nil
+			kvs[i] = &keyOnly
+		}
+	}
+	resp.Kvs = kvs
+	return resp, true
+}
+
+// contains returns whether the range [key, end) is within the range of the
+// replica.
+func (rp *Replica) contains(key, end []byte) bool {
+	if bytes.Compare(key, rp.key) < 0 {
+		return false
+	}
+	switch {
+	case isFromKey(rp.end):
+		return true
+	case len(end) == 0:
+		return bytes.Compare(key, rp.end) < 0
+	case isFromKey(end):
+		return false
+	}
+	return bytes.Compare(end, rp.end) <= 0
+}
+
+func isFromKey(end []byte) bool {
+	return len(end) == 1 && end[0] == 0
+}
+
+func filterKVs(r *pb.RangeRequest, kvs []*mvccpb.KeyValue) []*mvccpb.KeyValue {
+	if r.MinModRevision == 0 && r.MaxModRevision == 0 && r.MinCreateRevision == 0 && r.MaxCreateRevision == 0 {
+		return kvs
+	}
+	filtered := kvs[:0]
+	for _, kv := range kvs {
+		if (r.MaxModRevision != 0 && kv.ModRevision > r.MaxModRevision) ||
+			(r.MinModRevision != 0 && kv.ModRevision < r.MinModRevision) ||
+			(r.MaxCreateRevision != 0 && kv.CreateRevision > r.MaxCreateRevision) ||
+			(r.MinCreateRevision != 0 && kv.CreateRevision < r.MinCreateRevision) {
+			continue
+		}
+		filtered = append(filtered, kv)
+	}
+	return filtered
+}
+
+// sortKVs sorts the key-values, which are sorted by key, the way the cluster
+// does.
+func sortKVs(r *pb.RangeRequest, kvs []*mvccpb.KeyValue) {
+	order := r.SortOrder
+	if r.SortTarget != pb.RangeRequest_KEY && order == pb.RangeRequest_NONE {
+		order = pb.RangeRequest_ASCEND
+	}
+	if order == pb.RangeRequest_NONE || (r.SortTarget == pb.RangeRequest_KEY && order == pb.RangeRequest_ASCEND) {
+		return
+	}
+	var less func(a, b *mvccpb.KeyValue) bool
+	switch r.SortTarget {
+	case pb.RangeRequest_KEY:
+		less = func(a, b *mvccpb.KeyValue) bool { return bytes.Compare(a.Key, b.Key) < 0 }
+	case pb.RangeRequest_VERSION:
+		less = func(a, b *mvccpb.KeyValue) bool { return a.Version < b.Version }
+	case pb.RangeRequest_CREATE:
+		less = func(a, b *mvccpb.KeyValue) bool { return a.CreateRevision < b.CreateRevision }
+	case pb.RangeRequest_MOD:
+		less = func(a, b *mvccpb.KeyValue) bool { return a.ModRevision < b.ModRevision }
+	case pb.RangeRequest_VALUE:
+		less = func(a, b *mvccpb.KeyValue) bool { return bytes.Compare(a.Value, b.Value) < 0 }
+	default:
+		return
+	}
+	if order == pb.RangeRequest_DESCEND {
+		sort.SliceStable(kvs, func(i, j int) bool { return less(kvs[j], kvs[i]) })
+	} else {
+		sort.SliceStable(kvs, func(i, j int) bool { return less(kvs[i], kvs[j]) })
+	}
+}
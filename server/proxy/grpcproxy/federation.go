@@ -0,0 +1,278 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcproxy
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/etcdhttp"
+)
+
+// federationHealthInterval is the interval between the health checks of the
+// backends of a federation.
+const federationHealthInterval = 10 * time.Second
+
+var (
+	ErrGRPCCrossClusterRequest = status.Error(codes.InvalidArgument, "grpcproxy: request spans several clusters")
+	ErrGRPCFederatedCompact    = status.Error(codes.Unimplemented, "grpcproxy: compaction must be requested to each cluster")
+	ErrGRPCFederatedLease      = status.Error(codes.InvalidArgument, "grpcproxy: leases are only granted by the default cluster, and cannot be attached to keys of other clusters")
+	ErrGRPCFederatedAuth       = status.Error(codes.PermissionDenied, "grpcproxy: auth tokens are only issued by the default cluster, and cannot access keys of other clusters")
+)
+
+// Backend is a cluster of a federation.
+type Backend struct {
+	Name   string
+	Client *clientv3.Client
+}
+
+// Router routes the keys of a federation to the backends owning them. A key
+// is owned by the backend of the longest prefix routed to it, or by the default
+// backend if no prefix is.
+//
+// The lease and auth requests are served by the default backend, so the leases
+// it grants cannot be attached to the keys of other backends, and the users it
+// authenticates cannot access them. Such requests are rejected.
+type Router struct {
+	lg       *zap.Logger
+	def      *Backend
+	routes   map[string]*Backend
+	backends []*Backend
+	// segments split the key space into the ranges owned by a backend, in key
+	// order
+	segments []segment
+}
+
+type segment struct {
+	start   string
+	backend *Backend
+}
+
+// NewRouter creates a router of the prefixes of routes to their backends, and
+// monitors the health of the backends until ctx is done.
+func NewRouter(ctx context.Context, lg *zap.Logger, def *Backend, routes map[string]*Backend) *Router {
+	if lg == nil {
+		lg = zap.NewNop()
+	}
+	rt := &Router{lg: lg, def: def, routes: routes, backends: []*Backend{def}}
+	seen := map[*Backend]bool{def: true}
+	starts := map[string]bool{"": true}
+	for prefix, b := range routes {
+		if !seen[b] {
+			seen[b] = true
+			rt.backends = append(rt.backends, b)
+		}
+		starts[prefix] = true
+		if end := clientv3.GetPrefixRangeEnd(prefix); end != "\x00" {
+			starts[end] = true
+		}
+	}
+	sort.Slice(rt.backends[1:], func(i, j int) bool { return rt.backends[i+1].Name < rt.backends[j+1].Name })
+
+	sorted := make([]string, 0, len(starts))
+	for start := range starts {
+		sorted = append(sorted, start)
+	}
+	sort.Strings(sorted)
+	for _, start := range sorted {
+		b := rt.backendOf(start)
+		if n := len(rt.segments); n > 0 && rt.segments[n-1].backend == b {
+			continue
+		}
+		rt.segments = append(rt.segments, segment{start: start, backend: b})
+	}
+
+	go rt.monitorHealth(ctx)
+	return rt
+}
+
+// Backends returns the backends of the federation, the default one first.
+func (rt *Router) Backends() []*Backend {
+	return rt.backends
+}
+
+// backendOf returns the backend owning the key.
+func (rt *Router) backendOf(key string) *Backend {
+	b, longest := rt.def, -1
+	for prefix, pb := range rt.routes {
+		if len(prefix) > longest && strings.HasPrefix(key, prefix) {
+			b, longest = pb, len(prefix)
+		}
+	}
+	return b
+}
+
+// route returns the backend owning all the keys of the range [key, end).
+func (rt *Router) route(key, end []byte) (*Backend, error) {
+	return rt.count(rt.lookup(key, end))
+}
+
+// routeTxn returns the backend owning all the keys of the transaction.
+func (rt *Router) routeTxn(r *pb.TxnRequest) (*Backend, error) {
+	var b *Backend
+	add := func(key, end []byte) error {
+		kb, err := rt.lookup(key, end)
+		if err != nil {
+			return err
+		}
+		if b != nil && kb != b {
+			return ErrGRPCCrossClusterRequest
+		}
+		b = kb
+		return nil
+	}
+	var addOps func(ops []*pb.RequestOp) error
+	addOps = func(ops []*pb.RequestOp) error {
+		for _, op := range ops {
+			var err error
+			switch tv := op.Request.(type) {
+			case *pb.RequestOp_RequestRange:
+				err = add(tv.RequestRange.Key, tv.RequestRange.RangeEnd)
+			case *pb.RequestOp_RequestPut:
+				err = add(tv.RequestPut.Key, nil)
+			case *pb.RequestOp_RequestDeleteRange:
+				err = add(tv.RequestDeleteRange.Key, tv.RequestDeleteRange.RangeEnd)
+			case *pb.RequestOp_RequestTxn:
+				err = addTxn(tv.RequestTxn, add, addOps)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := addTxn(r, add, addOps); err != nil {
+		return rt.count(nil, err)
+	}
+	if b == nil {
+		b = rt.def
+	}
+	return rt.count(b, nil)
+}
+
+func (rt *Router) lookup(key, end []byte) (*Backend, error) {
+	i := sort.Search(len(rt.segments), func(i int) bool { return rt.segments[i].start > string(key) }) - 1
+	if len(end) != 0 && i+1 < len(rt.segments) {
+		if (len(end) == 1 && end[0] == 0) || rt.segments[i+1].start < string(end) {
+			return nil, ErrGRPCCrossClusterRequest
+		}
+	}
+	return rt.segments[i].backend, nil
+}
+
+func (rt *Router) count(b *Backend, err error) (*Backend, error) {
+	if err != nil {
+		federationRejected.Inc()
+		return nil, err
+	}
+	federationRequests.WithLabelValues(b.Name).Inc()
+	return b, nil
+}
+
+// checkAuth rejects the requests of users authenticated by the default backend
+// to another backend, which did not issue their token.
+func (rt *Router) checkAuth(ctx context.Context, b *Backend) error {
+	if b != rt.def && getAuthTokenFromClient(ctx) != "" {
+		return ErrGRPCFederatedAuth
+	}
+	return nil
+}
+
+// checkLease rejects the requests attaching a lease, granted by the default
+// backend, to the keys of another backend.
+func (rt *Router) checkLease(b *Backend, hasLease bool) error {
+	if b != rt.def && hasLease {
+		return ErrGRPCFederatedLease
+	}
+	return nil
+}
+
+// txnHasLease returns whether a put of the transaction attaches a lease.
+func txnHasLease(r *pb.TxnRequest) bool {
+	for _, ops := range [][]*pb.RequestOp{r.Success, r.Failure} {
+		for _, op := range ops {
+			switch tv := op.Request.(type) {
+			case *pb.RequestOp_RequestPut:
+				if tv.RequestPut.Lease != 0 {
+					return true
+				}
+			case *pb.RequestOp_RequestTxn:
+				if txnHasLease(tv.RequestTxn) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func addTxn(r *pb.TxnRequest, add func(key, end []byte) error, addOps func([]*pb.RequestOp) error) error {
+	for _, cmp := range r.Compare {
+		if err := add(cmp.Key, cmp.RangeEnd); err != nil {
+			return err
+		}
+	}
+	if err := addOps(r.Success); err != nil {
+		return err
+	}
+	return addOps(r.Failure)
+}
+
+func (rt *Router) monitorHealth(ctx context.Context) {
+	for {
+		for _, b := range rt.backends {
+			rt.checkHealth(b)
+		}
+		select {
+		case <-time.After(federationHealthInterval):
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (rt *Router) checkHealth(b *Backend) etcdhttp.Health {
+	h := checkHealth(b.Client)
+	if h.Health == "true" {
+		federationBackendHealthy.WithLabelValues(b.Name).Set(1)
+	} else {
+		federationBackendHealthy.WithLabelValues(b.Name).Set(0)
+		rt.lg.Warn("federation backend is unhealthy", zap.String("backend", b.Name), zap.String("reason", h.Reason))
+	}
+	return h
+}
+
+// HandleFederationHealth registers the health handler of every backend of the
+// federation on '/proxy/health/backend/<name>'.
+func HandleFederationHealth(lg *zap.Logger, mux *http.ServeMux, rt *Router) {
+	if lg == nil {
+		lg = zap.NewNop()
+	}
+	for _, b := range rt.backends {
+		b := b
+		mux.Handle(etcdhttp.PathProxyHealth+"/backend/"+b.Name, etcdhttp.NewHealthHandler(lg, func(excludedAlarms etcdhttp.AlarmSet, serializable bool) etcdhttp.Health {
+			return rt.checkHealth(b)
+		}))
+	}
+}
@@ -16,6 +16,7 @@ package grpcproxy
 
 import (
 	"context"
+	"time"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	clientv3 "go.etcd.io/etcd/client/v3"
@@ -25,12 +26,32 @@ import (
 type kvProxy struct {
 	kv    clientv3.KV
 	cache cache.Cache
+	// am checks the permissions of the reads served from the cache
+	am *AuthMirror
+	// replica serves the reads within its prefix, if set
+	replica *KVReplica
 }
 
 func NewKvProxy(c *clientv3.Client) (pb.KVServer, <-chan struct{}) {
+	return NewKvProxyWithAuth(c, nil)
+}
+
+// NewKvProxyWithAuth creates a kv proxy which only serves the reads the auth
+// mirror permits from its cache, and forwards the others.
+func NewKvProxyWithAuth(c *clientv3.Client, am *AuthMirror) (pb.KVServer, <-chan struct{}) {
+	return NewKvProxyWithReplica(c, am, nil)
+}
+
+// NewKvProxyWithReplica creates a kv proxy which serves the reads within the
+// prefix of the replica from it, including linearizable and historic reads.
+// Without an auth mirror their permissions are not checked, so one is needed
+// if the cluster has auth enabled.
+func NewKvProxyWithReplica(c *clientv3.Client, am *AuthMirror, replica *KVReplica) (pb.KVServer, <-chan struct{}) {
 	kv := &kvProxy{
-		kv:    c.KV,
-		cache: cache.NewCache(cache.DefaultMaxEntries),
+		kv:      c.KV,
+		cache:   cache.NewCache(cache.DefaultMaxEntries),
+		am:      am,
+		replica: replica,
 	}
 	donec := make(chan struct{})
 	close(donec)
@@ -38,7 +59,14 @@ func NewKvProxy(c *clientv3.Client) (pb.KVServer, <-chan struct{}) {
 }
 
 func (p *kvProxy) Range(ctx context.Context, r *pb.RangeRequest) (*pb.RangeResponse, error) {
-	if r.Serializable {
+	if p.replica != nil && p.isCachePermitted(ctx, r) {
+		if resp, ok := p.replica.Range(ctx, r); ok {
+			replicaHits.Inc()
+			return resp, nil
+		}
+	}
+
+	if r.Serializable && p.isCachePermitted(ctx, r) {
 		resp, err := p.cache.Get(r)
 		switch err {
 		case nil:
@@ -67,6 +95,48 @@ func (p *kvProxy) Range(ctx context.Context, r *pb.RangeRequest) (*pb.RangeRespo
 	return gresp, nil
 }
 
+// isCachePermitted returns whether the range may be served from the cache. With
+// an auth mirror, it is only served if the mirror permits it, so that denied or
+// undecided reads are checked by the cluster.
+func (p *kvProxy) isCachePermitted(ctx context.Context, r *pb.RangeRequest) bool {
+	if p.am == nil {
+		return true
+	}
+	known, err := p.am.isRangePermitted(ctx, r.Key, r.RangeEnd)
+	return known && err == nil
+}
+
+func (p *kvProxy) RangeStats(ctx context.Context, r *pb.RangeStatsRequest) (*pb.RangeStatsResponse, error) {
+	opts := []clientv3.OpOption{
+		clientv3.WithStatsDelimiter(string(r.Delimiter)),
+		clientv3.WithRev(r.Revision),
+		clientv3.WithLimit(r.Limit),
+	}
+	if len(r.RangeEnd) != 0 {
+		opts = append(opts, clientv3.WithRange(string(r.RangeEnd)))
+	}
+	if r.Serializable {
+		opts = append(opts, clientv3.WithSerializable())
+	}
+
+	resp, err := p.kv.RangeStats(ctx, string(r.Key), opts...)
+	return (*pb.RangeStatsResponse)(resp), err
+}
+
+func (p *kvProxy) MultiRange(ctx context.Context, r *pb.MultiRangeRequest) (*pb.MultiRangeResponse, error) {
+	ops := make([]clientv3.Op, len(r.Ranges))
+	for i, sr := range r.Ranges {
+		ops[i] = SubRangeToOp(sr)
+	}
+	opts := []clientv3.OpOption{clientv3.WithRev(r.Revision)}
+	if r.Serializable {
+		opts = append(opts, clientv3.WithSerializable())
+	}
+
+	resp, err := p.kv.MultiRange(ctx, ops, opts...)
+	return (*pb.MultiRangeResponse)(resp), err
+}
+
 func (p *kvProxy) Put(ctx context.Context, r *pb.PutRequest) (*pb.PutResponse, error) {
 	p.cache.Invalidate(r.Key, nil)
 	cacheKeys.Set(float64(p.cache.Size()))
@@ -128,10 +198,21 @@ func (p *kvProxy) Compact(ctx context.Context, r *pb.CompactionRequest) (*pb.Com
 	if r.Physical {
 		opts = append(opts, clientv3.WithCompactPhysical())
 	}
+	for _, cr := range r.Retention {
+		opts = append(opts, clientv3.WithCompactRetention(string(cr.Key), string(cr.RangeEnd), cr.Revision))
+	}
 
 	resp, err := p.kv.Compact(ctx, r.Revision, opts...)
 	if err == nil {
-		p.cache.Compact(r.Revision)
+		// retained ranges keep older revisions; let the server decide
+		// which requests at those revisions are compacted
+		compactRev := r.Revision
+		for _, cr := range r.Retention {
+			if cr.Revision < compactRev {
+				compactRev = cr.Revision
+			}
+		}
+		p.cache.Compact(compactRev)
 	}
 
 	cacheKeys.Set(float64(p.cache.Size()))
@@ -139,6 +220,118 @@ func (p *kvProxy) Compact(ctx context.Context, r *pb.CompactionRequest) (*pb.Com
 	return (*pb.CompactionResponse)(resp), err
 }
 
+// federatedKVProxy routes the requests to the kv proxy of the backend owning
+// their keys.
+type federatedKVProxy struct {
+	rt      *Router
+	proxies map[*Backend]pb.KVServer
+}
+
+// NewFederatedKvProxy creates a kv proxy which routes every request to the
+// backend of the router owning its keys, and rejects the requests spanning
+// several backends.
+func NewFederatedKvProxy(rt *Router) (pb.KVServer, <-chan struct{}) {
+	p := &federatedKVProxy{rt: rt, proxies: make(map[*Backend]pb.KVServer)}
+	for _, b := range rt.Backends() {
+		p.proxies[b], _ = NewKvProxy(b.Client)
+	}
+	donec := make(chan struct{})
+	close(donec)
+	return p, donec
+}
+
+func (p *federatedKVProxy) route(ctx context.Context, key, end []byte) (pb.KVServer, error) {
+	b, err := p.rt.route(key, end)
+	if err != nil {
+		return nil, err
+	}
+	if err = p.rt.checkAuth(ctx, b); err != nil {
+		return nil, err
+	}
+	return p.proxies[b], nil
+}
+
+func (p *federatedKVProxy) Range(ctx context.Context, r *pb.RangeRequest) (*pb.RangeResponse, error) {
+	kp, err := p.route(ctx, r.Key, r.RangeEnd)
+	if err != nil {
+		return nil, err
+	}
+	return kp.Range(ctx, r)
+}
+
+func (p *federatedKVProxy) RangeStats(ctx context.Context, r *pb.RangeStatsRequest) (*pb.RangeStatsResponse, error) {
+	kp, err := p.route(ctx, r.Key, r.RangeEnd)
+	if err != nil {
+		return nil, err
+	}
+	return kp.RangeStats(ctx, r)
+}
+
+func (p *federatedKVProxy) MultiRange(ctx context.Context, r *pb.MultiRangeRequest) (*pb.MultiRangeResponse, error) {
+	// the revision of the ranges is only consistent within a backend
+	var b *Backend
+	for _, sr := range r.Ranges {
+		sb, err := p.rt.lookup(sr.Key, sr.RangeEnd)
+		if err == nil && b != nil && sb != b {
+			err = ErrGRPCCrossClusterRequest
+		}
+		if err != nil {
+			_, err = p.rt.count(nil, err)
+			return nil, err
+		}
+		b = sb
+	}
+	if b == nil {
+		b = p.rt.def
+	}
+	p.rt.count(b, nil)
+	if err := p.rt.checkAuth(ctx, b); err != nil {
+		return nil, err
+	}
+	return p.proxies[b].MultiRange(ctx, r)
+}
+
+func (p *federatedKVProxy) Put(ctx context.Context, r *pb.PutRequest) (*pb.PutResponse, error) {
+	b, err := p.rt.route(r.Key, nil)
+	if err == nil {
+		err = p.rt.checkLease(b, r.Lease != 0)
+	}
+	if err == nil {
+		err = p.rt.checkAuth(ctx, b)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p.proxies[b].Put(ctx, r)
+}
+
+func (p *federatedKVProxy) DeleteRange(ctx context.Context, r *pb.DeleteRangeRequest) (*pb.DeleteRangeResponse, error) {
+	kp, err := p.route(ctx, r.Key, r.RangeEnd)
+	if err != nil {
+		return nil, err
+	}
+	return kp.DeleteRange(ctx, r)
+}
+
+func (p *federatedKVProxy) Txn(ctx context.Context, r *pb.TxnRequest) (*pb.TxnResponse, error) {
+	b, err := p.rt.routeTxn(r)
+	if err == nil {
+		err = p.rt.checkLease(b, txnHasLease(r))
+	}
+	if err == nil {
+		err = p.rt.checkAuth(ctx, b)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p.proxies[b].Txn(ctx, r)
+}
+
+func (p *federatedKVProxy) Compact(ctx context.Context, r *pb.CompactionRequest) (*pb.CompactionResponse, error) {
+	// the revisions of the backends are unrelated
+	return nil, ErrGRPCFederatedCompact
+}
+
 func requestOpToOp(union *pb.RequestOp) clientv3.Op {
 	switch tv := union.Request.(type) {
 	case *pb.RequestOp_RequestRange:
@@ -176,6 +369,14 @@ func RangeRequestToOp(r *pb.RangeRequest) clientv3.Op {
 	opts = append(opts, clientv3.WithMinCreateRev(r.MinCreateRevision))
 	opts = append(opts, clientv3.WithMaxModRev(r.MaxModRevision))
 	opts = append(opts, clientv3.WithMinModRev(r.MinModRevision))
+	if f := r.ValueFilter; f != nil {
+		opts = append(opts, clientv3.WithValueFilterPrefix(string(f.Prefix)))
+		opts = append(opts, clientv3.WithValueFilterContains(string(f.Contains)))
+		opts = append(opts, clientv3.WithValueFilterMinSize(f.MinSize))
+		opts = append(opts, clientv3.WithValueFilterMaxSize(f.MaxSize))
+		opts = append(opts, clientv3.WithValueFilterLease(clientv3.LeaseID(f.Lease)))
+	}
+	opts = append(opts, clientv3.WithContinuation(r.Continuation))
 	if r.CountOnly {
 		opts = append(opts, clientv3.WithCountOnly())
 	}
@@ -185,7 +386,28 @@ func RangeRequestToOp(r *pb.RangeRequest) clientv3.Op {
 	if r.Serializable {
 		opts = append(opts, clientv3.WithSerializable())
 	}
+	opts = append(opts, clientv3.WithMaxStalenessRevisions(r.MaxStalenessRevisions))
+	opts = append(opts, clientv3.WithMaxStaleness(time.Duration(r.MaxStalenessMs)*time.Millisecond))
+	if r.FailStale {
+		opts = append(opts, clientv3.WithFailStale())
+	}
+
+	return clientv3.OpGet(string(r.Key), opts...)
+}
 
+func SubRangeToOp(r *pb.SubRange) clientv3.Op {
+	var opts []clientv3.OpOption
+	if len(r.RangeEnd) != 0 {
+		opts = append(opts, clientv3.WithRange(string(r.RangeEnd)))
+	}
+	opts = append(opts, clientv3.WithLimit(r.Limit))
+	opts = append(opts, clientv3.WithContinuation(r.Continuation))
+	if r.CountOnly {
+		opts = append(opts, clientv3.WithCountOnly())
+	}
+	if r.KeysOnly {
+		opts = append(opts, clientv3.WithKeysOnly())
+	}
 	return clientv3.OpGet(string(r.Key), opts...)
 }
 
@@ -201,6 +423,7 @@ func PutRequestToOp(r *pb.PutRequest) clientv3.Op {
 	if r.PrevKv {
 		opts = append(opts, clientv3.WithPrevKV())
 	}
+	opts = append(opts, clientv3.WithTTL(r.Ttl))
 	return clientv3.OpPut(string(r.Key), string(r.Value), opts...)
 }
 
@@ -0,0 +1,239 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcproxy
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/metadata"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/proxy/grpcproxy/cache"
+)
+
+const (
+	// replicaRetryInterval is the interval to wait before syncing the replica
+	// again, once its watch fails.
+	replicaRetryInterval = 500 * time.Millisecond
+	// replicaProgressTimeout is the time to wait for the progress of the
+	// replica before forwarding a linearizable range to the cluster.
+	replicaProgressTimeout = time.Second
+)
+
+var errReplicaNotSynced = errors.New("grpcproxy: replica is not synced")
+
+// KVReplica keeps a replica of the keys under a prefix, synced from a range
+// and a watch of the cluster, to serve the ranges within the prefix without
+// forwarding them.
+//
+// Serializable ranges are served at the revision of the replica, and ranges at
+// a revision within its window of history. Linearizable ranges first read the
+// revision of the cluster with a linearizable range of a single key, and are
+// served once the replica caught up with it. Since the events of the watch only
+// advance the replica for writes to its keys, progress notifications are
+// requested while ranges wait, only one being outstanding at a time.
+type KVReplica struct {
+	lg *zap.Logger
+	c  *clientv3.Client
+	r  *cache.Replica
+	// ctx is the context of the watch, on its own stream so that the progress
+	// notifications it receives are only the ones it requests
+	ctx      context.Context
+	key, end string
+
+	mu sync.Mutex
+	// synced is true while the replica is watching its keys
+	synced bool
+	// advancec is closed once the replica advances or stops being synced
+	advancec chan struct{}
+	// progressing is true while a progress notification is requested
+	progressing bool
+}
+
+// NewKVReplica creates a replica of the keys with the prefix, or of all the
+// keys if it is empty, which keeps history revisions of history.
+func NewKVReplica(ctx context.Context, lg *zap.Logger, c *clientv3.Client, prefix string, history int64) *KVReplica {
+	if lg == nil {
+		lg = zap.NewNop()
+	}
+	end := clientv3.GetPrefixRangeEnd(prefix)
+	kr := &KVReplica{
+		lg:  lg,
+		c:   c,
+		r:   cache.NewReplica([]byte(prefix), []byte(end), history),
+		ctx: metadata.AppendToOutgoingContext(clientv3.WithRequireLeader(ctx), "grpc-proxy-replica", "true"),
+		key: prefix,
+		end: end,
+
+		advancec: make(chan struct{}),
+	}
+	go kr.run()
+	return kr
+}
+
+func (kr *KVReplica) run() {
+	for {
+		err := kr.sync()
+		kr.r.Invalidate()
+		kr.mu.Lock()
+		kr.synced = false
+		kr.advanced()
+		kr.mu.Unlock()
+		if kr.ctx.Err() != nil {
+			return
+		}
+		kr.lg.Warn("failed to watch replicated keys; retrying", zap.String("prefix", kr.key), zap.Error(err))
+		select {
+		case <-time.After(replicaRetryInterval):
+		case <-kr.ctx.Done():
+			return
+		}
+	}
+}
+
+// sync loads the keys of the replica, and applies the events of their watch
+// until it fails.
+func (kr *KVReplica) sync() error {
+	ctx, cancel := context.WithCancel(kr.ctx)
+	defer cancel()
+	resp, err := kr.c.KV.Get(ctx, kr.key, clientv3.WithRange(kr.end))
+	if err != nil {
+		return err
+	}
+	kr.r.Reset(resp.Kvs, resp.Header)
+
+	wch := kr.c.Watcher.Watch(ctx, kr.key,
+		clientv3.WithRange(kr.end),
+		clientv3.WithRev(resp.Header.Revision+1),
+		clientv3.WithCreatedNotify(),
+	)
+	for wr := range wch {
+		if err = wr.Err(); err != nil {
+			return err
+		}
+		switch {
+		case wr.Created:
+			kr.mu.Lock()
+			kr.synced = true
+			kr.mu.Unlock()
+			kr.lg.Info("synced replicated keys", zap.String("prefix", kr.key), zap.Int64("revision", resp.Header.Revision))
+		case wr.IsProgressNotify():
+			kr.r.Progress(&wr.Header)
+			kr.mu.Lock()
+			kr.progressing = false
+			kr.advanced()
+			kr.mu.Unlock()
+		default:
+			evs := make([]*mvccpb.Event, len(wr.Events))
+			for i, ev := range wr.Events {
+				evs[i] = (*mvccpb.Event)(ev)
+			}
+			kr.r.Apply(evs, &wr.Header)
+			kr.mu.Lock()
+			kr.advanced()
+			kr.mu.Unlock()
+		}
+	}
+	if err = kr.ctx.Err(); err != nil {
+		return err
+	}
+	return errors.New("grpcproxy: replica watch closed")
+}
+
+// waitProgress waits until the replica is up to date with the revision of the
+// cluster at the time of the call, as read by a linearizable range.
+func (kr *KVReplica) waitProgress(ctx context.Context) error {
+	kr.mu.Lock()
+	synced := kr.synced
+	kr.mu.Unlock()
+	if !synced {
+		return errReplicaNotSynced
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, replicaProgressTimeout)
+	defer cancel()
+	// the revision is read with the credentials of the proxy, not of the user
+	resp, err := kr.c.KV.Get(metadata.NewIncomingContext(ctx, metadata.MD{}), kr.key, clientv3.WithCountOnly())
+	if err != nil {
+		return err
+	}
+	return kr.waitRev(ctx, resp.Header.Revision)
+}
+
+// waitRev waits until the replica is up to date with the revision rev of the
+// cluster.
+func (kr *KVReplica) waitRev(ctx context.Context, rev int64) error {
+	for {
+		kr.mu.Lock()
+		if !kr.synced {
+			kr.mu.Unlock()
+			return errReplicaNotSynced
+		}
+		if kr.r.Rev() >= rev {
+			kr.mu.Unlock()
+			return nil
+		}
+		advancec := kr.advancec
+		if !kr.progressing {
+			kr.progressing = true
+			go kr.requestProgress()
+		}
+		kr.mu.Unlock()
+
+		select {
+		case <-advancec:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (kr *KVReplica) requestProgress() {
+	if err := kr.c.Watcher.RequestProgress(kr.ctx); err != nil {
+		kr.lg.Warn("failed to request the progress of replicated keys", zap.String("prefix", kr.key), zap.Error(err))
+		kr.mu.Lock()
+		kr.progressing = false
+		kr.mu.Unlock()
+	}
+}
+
+// advanced wakes up the ranges waiting for the replica to advance. It is
+// called with mu held.
+func (kr *KVReplica) advanced() {
+	close(kr.advancec)
+	kr.advancec = make(chan struct{})
+}
+
+// Range serves the range from the replica, or returns false if the range must
+// be forwarded to the cluster.
+func (kr *KVReplica) Range(ctx context.Context, r *pb.RangeRequest) (*pb.RangeResponse, bool) {
+	if !kr.r.IsServable(r) {
+		return nil, false
+	}
+	// the history of the replica is immutable, only the ranges at the latest
+	// revision need to be up to date
+	if !r.Serializable && r.Revision <= 0 {
+		if err := kr.waitProgress(ctx); err != nil {
+			return nil, false
+		}
+	}
+	return kr.r.Range(r)
+}
@@ -110,6 +110,10 @@ func (lp *leaseProxy) LeaseTimeToLive(ctx context.Context, rr *pb.LeaseTimeToLiv
 		TTL:        r.TTL,
 		GrantedTTL: r.GrantedTTL,
 		Keys:       r.Keys,
+		Parent:     int64(r.Parent),
+	}
+	for _, id := range r.Children {
+		rp.Children = append(rp.Children, int64(id))
 	}
 	return rp, err
 }
@@ -121,7 +125,7 @@ func (lp *leaseProxy) LeaseLeases(ctx context.Context, rr *pb.LeaseLeasesRequest
 	}
 	leases := make([]*pb.LeaseStatus, len(r.Leases))
 	for i := range r.Leases {
-		leases[i] = &pb.LeaseStatus{ID: int64(r.Leases[i].ID)}
+		leases[i] = &pb.LeaseStatus{ID: int64(r.Leases[i].ID), Parent: int64(r.Leases[i].Parent)}
 	}
 	rp := &pb.LeaseLeasesResponse{
 		Header: r.ResponseHeader,
@@ -59,6 +59,30 @@ var (
 		Name:      "cache_misses_total",
 		Help:      "Total number of cache misses",
 	})
+	replicaHits = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "etcd",
+		Subsystem: "grpc_proxy",
+		Name:      "replica_hits_total",
+		Help:      "Total number of ranges served from the replica",
+	})
+	federationRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "etcd",
+		Subsystem: "grpc_proxy",
+		Name:      "federation_requests_total",
+		Help:      "Total number of requests routed to each backend of the federation",
+	}, []string{"backend"})
+	federationRejected = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: "etcd",
+		Subsystem: "grpc_proxy",
+		Name:      "federation_rejected_total",
+		Help:      "Total number of requests rejected for spanning several backends of the federation",
+	})
+	federationBackendHealthy = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "etcd",
+		Subsystem: "grpc_proxy",
+		Name:      "federation_backend_healthy",
+		Help:      "Whether each backend of the federation is healthy (1) or not (0)",
+	}, []string{"backend"})
 )
 
 func init() {
@@ -67,6 +91,10 @@ func init() {
 	prometheus.MustRegister(cacheKeys)
 	prometheus.MustRegister(cacheHits)
 	prometheus.MustRegister(cachedMisses)
+	prometheus.MustRegister(replicaHits)
+	prometheus.MustRegister(federationRequests)
+	prometheus.MustRegister(federationRejected)
+	prometheus.MustRegister(federationBackendHealthy)
 }
 
 // HandleMetrics performs a GET request against etcd endpoint and returns '/metrics'.
@@ -45,10 +45,50 @@ type watchProxy struct {
 
 	// kv is used for permission checking
 	kv clientv3.KV
+	// am checks permissions locally, if set
+	am *AuthMirror
 	lg *zap.Logger
+
+	// rt routes the watches to the proxies of the backends, if set
+	rt       *Router
+	backends map[*Backend]*watchProxy
 }
 
 func NewWatchProxy(ctx context.Context, lg *zap.Logger, c *clientv3.Client) (pb.WatchServer, <-chan struct{}) {
+	return NewWatchProxyWithAuth(ctx, lg, c, nil)
+}
+
+// NewWatchProxyWithAuth creates a watch proxy which checks the permissions of
+// the watches with the auth mirror, instead of the cluster, when it can.
+func NewWatchProxyWithAuth(ctx context.Context, lg *zap.Logger, c *clientv3.Client, am *AuthMirror) (pb.WatchServer, <-chan struct{}) {
+	return newWatchProxy(ctx, lg, c, am)
+}
+
+// NewFederatedWatchProxy creates a watch proxy which routes every watch to the
+// backend of the router owning its range, and rejects the watches spanning
+// several backends.
+func NewFederatedWatchProxy(ctx context.Context, lg *zap.Logger, rt *Router) (pb.WatchServer, <-chan struct{}) {
+	backends := make(map[*Backend]*watchProxy)
+	var donecs []<-chan struct{}
+	for _, b := range rt.Backends() {
+		bwp, donec := newWatchProxy(ctx, lg, b.Client, nil)
+		backends[b] = bwp
+		donecs = append(donecs, donec)
+	}
+	// the default backend serves the streams, which require its leader
+	wp := backends[rt.def]
+	wp.rt, wp.backends = rt, backends
+	ch := make(chan struct{})
+	go func() {
+		defer close(ch)
+		for _, donec := range donecs {
+			<-donec
+		}
+	}()
+	return wp, ch
+}
+
+func newWatchProxy(ctx context.Context, lg *zap.Logger, c *clientv3.Client, am *AuthMirror) (*watchProxy, <-chan struct{}) {
 	cctx, cancel := context.WithCancel(ctx)
 	wp := &watchProxy{
 		cw:     c.Watcher,
@@ -56,6 +96,7 @@ func NewWatchProxy(ctx context.Context, lg *zap.Logger, c *clientv3.Client) (pb.
 		leader: newLeader(cctx, c.Watcher),
 
 		kv: c.KV, // for permission checking
+		am: am,
 		lg: lg,
 	}
 	wp.ranges = newWatchRanges(wp)
@@ -77,6 +118,41 @@ func NewWatchProxy(ctx context.Context, lg *zap.Logger, c *clientv3.Client) (pb.
 	return wp, ch
 }
 
+// route returns the proxy of the backend owning the range [key, end). The
+// proxy of another backend does not stop until the watcher is released.
+func (wp *watchProxy) route(ctx context.Context, key, end []byte) (*watchProxy, error) {
+	if wp.rt == nil {
+		return wp, nil
+	}
+	b, err := wp.rt.route(key, end)
+	if err == nil {
+		err = wp.rt.checkAuth(ctx, b)
+	}
+	if err != nil {
+		return nil, err
+	}
+	bwp := wp.backends[b]
+	if bwp == wp {
+		return wp, nil
+	}
+	bwp.mu.Lock()
+	defer bwp.mu.Unlock()
+	select {
+	case <-bwp.ctx.Done():
+		return nil, status.Errorf(codes.Unavailable, "grpcproxy: backend %q is closing", b.Name)
+	default:
+		bwp.wg.Add(1)
+	}
+	return bwp, nil
+}
+
+// release lets the proxy of a backend returned by route stop.
+func (wp *watchProxy) release(bwp *watchProxy) {
+	if bwp != wp {
+		bwp.wg.Done()
+	}
+}
+
 func (wp *watchProxy) Watch(stream pb.Watch_WatchServer) (err error) {
 	wp.mu.Lock()
 	select {
@@ -95,13 +171,12 @@ func (wp *watchProxy) Watch(stream pb.Watch_WatchServer) (err error) {
 
 	ctx, cancel := context.WithCancel(stream.Context())
 	wps := &watchProxyStream{
-		ranges:   wp.ranges,
+		wp:       wp,
 		watchers: make(map[int64]*watcher),
 		stream:   stream,
 		watchCh:  make(chan *pb.WatchResponse, 1024),
 		ctx:      ctx,
 		cancel:   cancel,
-		kv:       wp.kv,
 		lg:       wp.lg,
 	}
 
@@ -166,7 +241,7 @@ func (wp *watchProxy) Watch(stream pb.Watch_WatchServer) (err error) {
 
 // watchProxyStream forwards etcd watch events to a proxied client stream.
 type watchProxyStream struct {
-	ranges *watchRanges
+	wp *watchProxy
 
 	// mu protects watchers and nextWatcherID
 	mu sync.Mutex
@@ -183,8 +258,6 @@ type watchProxyStream struct {
 	ctx    context.Context
 	cancel context.CancelFunc
 
-	// kv is used for permission checking
-	kv clientv3.KV
 	lg *zap.Logger
 }
 
@@ -195,7 +268,8 @@ func (wps *watchProxyStream) close() {
 	wg.Add(len(wps.watchers))
 	for _, wpsw := range wps.watchers {
 		go func(w *watcher) {
-			wps.ranges.delete(w)
+			w.wp.ranges.delete(w)
+			wps.wp.release(w.wp)
 			wg.Done()
 		}(wpsw)
 	}
@@ -207,13 +281,16 @@ func (wps *watchProxyStream) close() {
 	close(wps.watchCh)
 }
 
-func (wps *watchProxyStream) checkPermissionForWatch(key, rangeEnd []byte) error {
+func (wps *watchProxyStream) checkPermissionForWatch(wp *watchProxy, key, rangeEnd []byte) error {
 	if len(key) == 0 {
 		// If the length of the key is 0, we need to obtain full range.
 		// look at clientv3.WithPrefix()
 		key = []byte{0}
 		rangeEnd = []byte{0}
 	}
+	if known, err := wp.am.isWatchPermitted(wps.ctx, key, rangeEnd); known {
+		return err
+	}
 	req := &pb.RangeRequest{
 		Serializable: true,
 		Key:          key,
@@ -221,7 +298,7 @@ func (wps *watchProxyStream) checkPermissionForWatch(key, rangeEnd []byte) error
 		CountOnly:    true,
 		Limit:        1,
 	}
-	_, err := wps.kv.Do(wps.ctx, RangeRequestToOp(req))
+	_, err := wp.kv.Do(wps.ctx, RangeRequestToOp(req))
 	return err
 }
 
@@ -235,7 +312,30 @@ func (wps *watchProxyStream) recvLoop() error {
 		case *pb.WatchRequest_CreateRequest:
 			cr := uv.CreateRequest
 
-			if err := wps.checkPermissionForWatch(cr.Key, cr.RangeEnd); err != nil {
+			wp, err := wps.wp.route(wps.stream.Context(), cr.Key, cr.RangeEnd)
+			if err == nil {
+				if err = wps.checkPermissionForWatch(wp, cr.Key, cr.RangeEnd); err != nil {
+					wps.wp.release(wp)
+				}
+			}
+			if err != nil {
+				wps.watchCh <- &pb.WatchResponse{
+					Header:       &pb.ResponseHeader{},
+					WatchId:      clientv3.InvalidWatchID,
+					Created:      true,
+					Canceled:     true,
+					CancelReason: err.Error(),
+				}
+				continue
+			}
+			tokens := v3rpc.NewResumeTokens(cr)
+			rev, skip := cr.StartRevision, int64(0)
+			err = v3rpc.CheckWatchFilters(cr)
+			if err == nil && len(cr.ResumeToken) != 0 {
+				rev, skip, err = tokens.Decode(cr.ResumeToken)
+			}
+			if err != nil {
+				wps.wp.release(wp)
 				wps.watchCh <- &pb.WatchResponse{
 					Header:       &pb.ResponseHeader{},
 					WatchId:      clientv3.InvalidWatchID,
@@ -251,8 +351,12 @@ func (wps *watchProxyStream) recvLoop() error {
 				wr:  watchRange{string(cr.Key), string(cr.RangeEnd)},
 				id:  wps.nextWatcherID,
 				wps: wps,
+				wp:  wp,
 
-				nextrev:  cr.StartRevision,
+				nextrev:  rev,
+				tokens:   tokens,
+				skipRev:  rev,
+				skip:     skip,
 				progress: cr.ProgressNotify,
 				prevKV:   cr.PrevKv,
 				filters:  v3rpc.FiltersFromRequest(cr),
@@ -260,12 +364,12 @@ func (wps *watchProxyStream) recvLoop() error {
 			if !w.wr.valid() {
 				w.post(&pb.WatchResponse{WatchId: clientv3.InvalidWatchID, Created: true, Canceled: true})
 				wps.mu.Unlock()
+				wps.wp.release(wp)
 				continue
 			}
 			wps.nextWatcherID++
-			w.nextrev = cr.StartRevision
 			wps.watchers[w.id] = w
-			wps.ranges.add(w)
+			wp.ranges.add(w)
 			wps.mu.Unlock()
 			wps.lg.Debug("create watcher", zap.String("key", w.wr.key), zap.String("end", w.wr.end), zap.Int64("watcherId", wps.nextWatcherID))
 		case *pb.WatchRequest_CancelRequest:
@@ -302,7 +406,8 @@ func (wps *watchProxyStream) delete(id int64) {
 	if !ok {
 		return
 	}
-	wps.ranges.delete(w)
+	w.wp.ranges.delete(w)
+	wps.wp.release(w.wp)
 	delete(wps.watchers, id)
 	resp := &pb.WatchResponse{
 		Header:   &w.lastHeader,
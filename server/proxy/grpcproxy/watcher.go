@@ -20,6 +20,7 @@ import (
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/api/v3/mvccpb"
 	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3rpc"
 	"go.etcd.io/etcd/server/v3/storage/mvcc"
 )
 
@@ -43,11 +44,19 @@ type watcher struct {
 	id int64
 	// nextrev is the minimum expected next event revision.
 	nextrev int64
+	// tokens issues the resume tokens of the watcher.
+	tokens v3rpc.ResumeTokens
+	// skip is the number of events of revision skipRev the client has
+	// received before resuming the watcher.
+	skipRev int64
+	skip    int64
 	// lastHeader has the last header sent over the stream.
 	lastHeader pb.ResponseHeader
 
 	// wps is the parent.
 	wps *watchProxyStream
+	// wp is the proxy of the backend the watcher is routed to.
+	wp *watchProxy
 }
 
 // send filters out repeated events by discarding revisions older
@@ -88,6 +97,10 @@ func (w *watcher) send(wr clientv3.WatchResponse) {
 		if filtered {
 			continue
 		}
+		if w.skip > 0 && ev.Kv.ModRevision == w.skipRev {
+			w.skip--
+			continue
+		}
 
 		if !w.prevKV {
 			evCopy := *ev
@@ -119,6 +132,7 @@ func (w *watcher) send(wr clientv3.WatchResponse) {
 
 // post puts a watch response on the watcher's proxy stream channel
 func (w *watcher) post(wr *pb.WatchResponse) bool {
+	wr.ResumeToken = w.resumeToken(wr)
 	select {
 	case w.wps.watchCh <- wr:
 	case <-time.After(50 * time.Millisecond):
@@ -128,3 +142,18 @@ func (w *watcher) post(wr *pb.WatchResponse) bool {
 	}
 	return true
 }
+
+// resumeToken returns the token resuming the watcher right after the response.
+func (w *watcher) resumeToken(wr *pb.WatchResponse) []byte {
+	switch {
+	case wr.Canceled || wr.WatchId != w.id:
+		return nil
+	case len(wr.Events) > 0:
+		// responses from etcd hold whole revisions
+		return w.tokens.Encode(wr.Events[len(wr.Events)-1].Kv.ModRevision+1, 0)
+	case wr.Created:
+		return w.tokens.Encode(w.nextrev, w.skip)
+	default:
+		return w.tokens.Encode(wr.Header.Revision+1, 0)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvcc
+
+import (
+	"fmt"
+	"math"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/server/v3/storage/backend"
+	"go.etcd.io/etcd/server/v3/storage/schema"
+)
+
+// RevisionKeyValue is a key-value written to the key space, along with the
+// revision of the write.
+type RevisionKeyValue struct {
+	Revision    int64
+	SubRevision int64
+	Tombstone   bool
+	KV          mvccpb.KeyValue
+}
+
+// UnsafeRangeRevisions returns at most limit key-values written at or after the
+// given revision and sub revision, and at or before the end revision, in
+// revision order.
+func UnsafeRangeRevisions(tx backend.UnsafeReader, rev, sub, end, limit int64) ([]RevisionKeyValue, error) {
+	min, max := newRevBytes(), newRevBytes()
+	revToBytes(revision{main: rev, sub: sub}, min)
+	revToBytes(revision{main: end + 1}, max)
+	keys, vals := tx.UnsafeRange(schema.Key, min, max, limit)
+	rkvs := make([]RevisionKeyValue, len(keys))
+	for i, key := range keys {
+		r := bytesToRev(key)
+		rkvs[i] = RevisionKeyValue{Revision: r.main, SubRevision: r.sub, Tombstone: isTombstone(key)}
+		if err := rkvs[i].KV.Unmarshal(vals[i]); err != nil {
+			return nil, fmt.Errorf("cannot unmarshal revision %d_%d: %w", r.main, r.sub, err)
+		}
+	}
+	return rkvs, nil
+}
+
+// UnsafePutRevision writes the key-value to the key space at its revision. It
+// does not update the key expiry index, see UnsafeRebuildKeyExpiry.
+func UnsafePutRevision(tx backend.UnsafeWriter, rkv RevisionKeyValue) error {
+	key := newRevBytes()
+	revToBytes(revision{main: rkv.Revision, sub: rkv.SubRevision}, key)
+	if rkv.Tombstone {
+		key = append(key, markTombstone)
+	}
+	val, err := rkv.KV.Marshal()
+	if err != nil {
+		return err
+	}
+	tx.UnsafeSeqPut(schema.Key, key, val)
+	return nil
+}
+
+// UnsafeRebuildKeyExpiry rebuilds the key expiry index from the latest
+// revision of every key of the key space.
+func UnsafeRebuildKeyExpiry(tx backend.UnsafeReadWriter) error {
+	tx.UnsafeDeleteBucket(schema.KeyExpiry)
+	schema.UnsafeCreateKeyExpiryBucket(tx)
+
+	min, max := newRevBytes(), newRevBytes()
+	revToBytes(revision{main: 1}, min)
+	revToBytes(revision{main: math.MaxInt64, sub: math.MaxInt64}, max)
+	expiries := make(map[string]int64)
+	for {
+		keys, vals := tx.UnsafeRange(schema.Key, min, max, int64(restoreChunkKeys))
+		for i, key := range keys {
+			var kv mvccpb.KeyValue
+			if err := kv.Unmarshal(vals[i]); err != nil {
+				return err
+			}
+			if kv.ExpireTime != 0 && !isTombstone(key) {
+				expiries[string(kv.Key)] = kv.ExpireTime
+			} else {
+				delete(expiries, string(kv.Key))
+			}
+		}
+		if len(keys) < restoreChunkKeys {
+			break
+		}
+		next := bytesToRev(keys[len(keys)-1][:revBytesLen])
+		next.sub++
+		revToBytes(next, min)
+	}
+	for k, exp := range expiries {
+		schema.UnsafePutKeyExpiry(tx, []byte(k), exp)
+	}
+	return nil
+}
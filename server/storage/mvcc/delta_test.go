@@ -0,0 +1,103 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvcc
+
+import (
+	"reflect"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+
+	"go.etcd.io/etcd/pkg/v3/traceutil"
+	"go.etcd.io/etcd/server/v3/lease"
+	betesting "go.etcd.io/etcd/server/v3/storage/backend/testing"
+	"go.etcd.io/etcd/server/v3/storage/schema"
+)
+
+// TestReplayRevisions ensures that the revisions ranged from a store, once put
+// into another one, restore the same key space and key expiries.
+func TestReplayRevisions(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	s := NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
+	defer b.Close()
+	defer s.Close()
+
+	txn := s.Write(traceutil.TODO())
+	txn.PutWithExpiry([]byte("foo1"), []byte("bar"), lease.NoLease, 20)
+	txn.PutWithExpiry([]byte("foo2"), []byte("bar"), lease.NoLease, 10)
+	txn.Put([]byte("foo3"), []byte("bar"), lease.NoLease)
+	txn.End()
+	s.DeleteRange([]byte("foo2"), nil)
+	s.PutWithExpiry([]byte("foo3"), []byte("baz"), lease.NoLease, 30)
+	s.Commit()
+
+	// range in small batches to continue from sub revisions
+	var rkvs []RevisionKeyValue
+	tx := b.ReadTx()
+	tx.RLock()
+	rev, sub := int64(1), int64(0)
+	for {
+		batch, err := UnsafeRangeRevisions(tx, rev, sub, s.Rev(), 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rkvs = append(rkvs, batch...)
+		if len(batch) < 2 {
+			break
+		}
+		rev, sub = batch[len(batch)-1].Revision, batch[len(batch)-1].SubRevision+1
+	}
+	tx.RUnlock()
+	if len(rkvs) != 5 {
+		t.Fatalf("len(revisions) = %d, want 5", len(rkvs))
+	}
+	if !rkvs[3].Tombstone || string(rkvs[3].KV.Key) != "foo2" {
+		t.Errorf("revision %d_%d = %+v, want foo2 tombstone", rkvs[3].Revision, rkvs[3].SubRevision, rkvs[3])
+	}
+
+	b2, _ := betesting.NewDefaultTmpBackend(t)
+	defer b2.Close()
+	tx2 := b2.BatchTx()
+	tx2.LockOutsideApply()
+	tx2.UnsafeCreateBucket(schema.Key)
+	for _, rkv := range rkvs {
+		if err := UnsafePutRevision(tx2, rkv); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := UnsafeRebuildKeyExpiry(tx2); err != nil {
+		t.Fatal(err)
+	}
+	tx2.Unlock()
+	b2.ForceCommit()
+
+	s2 := NewStore(zaptest.NewLogger(t), b2, &lease.FakeLessor{}, StoreConfig{})
+	defer s2.Close()
+	h, _, err := s.HashStorage().HashByRev(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h2, _, err := s2.HashStorage().HashByRev(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h != h2 {
+		t.Errorf("hash = %+v, want %+v", h2, h)
+	}
+	want := []schema.ExpiringKey{{Key: []byte("foo1"), ExpireTime: 20}, {Key: []byte("foo3"), ExpireTime: 30}}
+	if got := s2.ExpiredKeys(40, 10); !reflect.DeepEqual(got, want) {
+		t.Errorf("expired keys = %+v, want %+v", got, want)
+	}
+}
@@ -28,8 +28,8 @@ type index interface {
 	CountRevisions(key, end []byte, atRev int64) int
 	Put(key []byte, rev revision)
 	Tombstone(key []byte, rev revision) error
-	Compact(rev int64) map[revision]struct{}
-	Keep(rev int64) map[revision]struct{}
+	Compact(rev int64, retention []KeyRetention) map[revision]struct{}
+	Keep(rev int64, retention []KeyRetention) map[revision]struct{}
 	Equal(b index) bool
 
 	Insert(ki *keyIndex)
@@ -189,7 +189,9 @@ func (ti *treeIndex) Tombstone(key []byte, rev revision) error {
 	return ki.tombstone(ti.lg, rev.main, rev.sub)
 }
 
-func (ti *treeIndex) Compact(rev int64) map[revision]struct{} {
+// Compact compacts the index at the given rev, except for the keys retained by
+// the given retention sorted by key, and returns the revisions to be kept.
+func (ti *treeIndex) Compact(rev int64, retention []KeyRetention) map[revision]struct{} {
 	available := make(map[revision]struct{})
 	ti.lg.Info("compact tree index", zap.Int64("revision", rev))
 	ti.Lock()
@@ -200,7 +202,11 @@ func (ti *treeIndex) Compact(rev int64) map[revision]struct{} {
 		// Lock is needed here to prevent modification to the keyIndex while
 		// compaction is going on or revision added to empty before deletion
 		ti.Lock()
-		keyi.compact(ti.lg, rev, available)
+		atRev := retentionRev(retention, keyi.key, rev)
+		keyi.compact(ti.lg, atRev, available)
+		if atRev < rev {
+			keyi.retain(atRev, rev, available)
+		}
 		if keyi.isEmpty() {
 			_, ok := ti.tree.Delete(keyi)
 			if !ok {
@@ -214,12 +220,16 @@ func (ti *treeIndex) Compact(rev int64) map[revision]struct{} {
 }
 
 // Keep finds all revisions to be kept for a Compaction at the given rev.
-func (ti *treeIndex) Keep(rev int64) map[revision]struct{} {
+func (ti *treeIndex) Keep(rev int64, retention []KeyRetention) map[revision]struct{} {
 	available := make(map[revision]struct{})
 	ti.RLock()
 	defer ti.RUnlock()
 	ti.tree.Ascend(func(keyi *keyIndex) bool {
-		keyi.keep(rev, available)
+		atRev := retentionRev(retention, keyi.key, rev)
+		keyi.keep(atRev, available)
+		if atRev < rev {
+			keyi.retain(atRev, rev, available)
+		}
 		return true
 	})
 	return available
@@ -265,8 +265,8 @@ func TestIndexCompactAndKeep(t *testing.T) {
 		}
 	}
 	for i := int64(1); i < maxRev; i++ {
-		am := ti.Compact(i)
-		keep := ti.Keep(i)
+		am := ti.Compact(i, nil)
+		keep := ti.Keep(i, nil)
 		if !(reflect.DeepEqual(am, keep)) {
 			t.Errorf("#%d: compact keep %v != Keep keep %v", i, am, keep)
 		}
@@ -297,8 +297,8 @@ func TestIndexCompactAndKeep(t *testing.T) {
 				ti.Put(tt.key, tt.rev)
 			}
 		}
-		am := ti.Compact(i)
-		keep := ti.Keep(i)
+		am := ti.Compact(i, nil)
+		keep := ti.Keep(i, nil)
 		if !(reflect.DeepEqual(am, keep)) {
 			t.Errorf("#%d: compact keep %v != Keep keep %v", i, am, keep)
 		}
@@ -248,6 +248,17 @@ func (ki *keyIndex) keep(atRev int64, available map[revision]struct{}) {
 	}
 }
 
+// retain adds the revisions of the keyIndex in (since, atRev] to available.
+func (ki *keyIndex) retain(since, atRev int64, available map[revision]struct{}) {
+	for _, g := range ki.generations {
+		for _, rev := range g.revs {
+			if rev.main > since && rev.main <= atRev {
+				available[rev] = struct{}{}
+			}
+		}
+	}
+}
+
 func (ki *keyIndex) doCompact(atRev int64, available map[revision]struct{}) (genIdx int, revIndex int) {
 	// walk until reaching the first revision smaller or equal to "atRev",
 	// and add the revision to the available map
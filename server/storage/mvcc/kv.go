@@ -21,12 +21,16 @@ import (
 	"go.etcd.io/etcd/pkg/v3/traceutil"
 	"go.etcd.io/etcd/server/v3/lease"
 	"go.etcd.io/etcd/server/v3/storage/backend"
+	"go.etcd.io/etcd/server/v3/storage/schema"
 )
 
 type RangeOptions struct {
 	Limit int64
 	Rev   int64
 	Count bool
+	// Filter, if not nil, drops every key-value pair for which it returns false.
+	// It is applied before Limit and Count are evaluated.
+	Filter func(kv *mvccpb.KeyValue) bool
 }
 
 type RangeResult struct {
@@ -79,6 +83,11 @@ type WriteView interface {
 	// A put also increases the rev of the store, and generates one event in the event history.
 	// The returned rev is the current revision of the KV when the operation is executed.
 	Put(key, value []byte, lease lease.LeaseID) (rev int64)
+
+	// PutWithExpiry is like Put, but the key-value pair also expires at expireTime,
+	// the unix time in seconds recorded in the key-value pair and in the key
+	// expiry index. An expireTime of 0 means the key does not expire.
+	PutWithExpiry(key, value []byte, lease lease.LeaseID, expireTime int64) (rev int64)
 }
 
 // TxnWrite represents a transaction that can modify the store.
@@ -96,6 +105,9 @@ func (trw *txnReadWrite) DeleteRange(key, end []byte) (n, rev int64) { panic("un
 func (trw *txnReadWrite) Put(key, value []byte, lease lease.LeaseID) (rev int64) {
 	panic("unexpected Put")
 }
+func (trw *txnReadWrite) PutWithExpiry(key, value []byte, lease lease.LeaseID, expireTime int64) (rev int64) {
+	panic("unexpected PutWithExpiry")
+}
 func (trw *txnReadWrite) Changes() []mvccpb.KeyValue { return nil }
 
 func NewReadOnlyTxnWrite(txn TxnRead) TxnWrite { return &txnReadWrite{txn} }
@@ -125,6 +137,19 @@ type KV interface {
 	// Compact frees all superseded keys with revisions less than rev.
 	Compact(trace *traceutil.Trace, rev int64) (<-chan struct{}, error)
 
+	// CompactWithRetention is like Compact, but compacts the history of the
+	// key ranges in retention only up to their given revisions.
+	CompactWithRetention(trace *traceutil.Trace, rev int64, retention []KeyRetention) (<-chan struct{}, error)
+
+	// ExpiredKeys returns at most limit keys whose expire time is at or before
+	// the given unix time in seconds, the earliest expired first.
+	ExpiredKeys(now int64, limit int) []schema.ExpiringKey
+
+	// RangeBytes returns the bytes of the keys and values in the range
+	// [key, end) at the current revision. Once asked for, the usage of the
+	// range is kept up to date by writes instead of being counted again.
+	RangeBytes(key, end []byte) int64
+
 	// Commit commits outstanding txns into the underlying backend.
 	Commit()
 
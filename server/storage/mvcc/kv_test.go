@@ -256,6 +256,51 @@ func testKVRangeLimit(t *testing.T, f rangeFunc) {
 	}
 }
 
+func TestKVRangeFilter(t *testing.T)    { testKVRangeFilter(t, normalRangeFunc) }
+func TestKVTxnRangeFilter(t *testing.T) { testKVRangeFilter(t, txnRangeFunc) }
+
+func testKVRangeFilter(t *testing.T, f rangeFunc) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	s := NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
+	defer cleanup(s, b)
+
+	kvs := put3TestKVs(s)
+	notFoo := func(kv *mvccpb.KeyValue) bool { return string(kv.Key) != "foo" }
+
+	wrev := int64(4)
+	tests := []struct {
+		filter func(kv *mvccpb.KeyValue) bool
+		limit  int64
+		count  bool
+
+		wcount int
+		wkvs   []mvccpb.KeyValue
+	}{
+		{notFoo, 0, false, 2, kvs[1:]},
+		// limit is applied to the filtered key-value pairs
+		{notFoo, 1, false, 2, kvs[1:2]},
+		// count only returns the number of filtered key-value pairs
+		{notFoo, 0, true, 2, nil},
+		{func(kv *mvccpb.KeyValue) bool { return false }, 0, false, 0, nil},
+		{func(kv *mvccpb.KeyValue) bool { return kv.Lease == 3 }, 0, false, 1, kvs[2:]},
+	}
+	for i, tt := range tests {
+		r, err := f(s, []byte("foo"), []byte("foo3"), RangeOptions{Limit: tt.limit, Count: tt.count, Filter: tt.filter})
+		if err != nil {
+			t.Fatalf("#%d: range error (%v)", i, err)
+		}
+		if !reflect.DeepEqual(r.KVs, tt.wkvs) {
+			t.Errorf("#%d: kvs = %+v, want %+v", i, r.KVs, tt.wkvs)
+		}
+		if r.Rev != wrev {
+			t.Errorf("#%d: rev = %d, want %d", i, r.Rev, wrev)
+		}
+		if r.Count != tt.wcount {
+			t.Errorf("#%d: count = %d, want %d", i, r.Count, tt.wcount)
+		}
+	}
+}
+
 func TestKVPutMultipleTimes(t *testing.T)    { testKVPutMultipleTimes(t, normalPutFunc) }
 func TestKVTxnPutMultipleTimes(t *testing.T) { testKVPutMultipleTimes(t, txnPutFunc) }
 
@@ -54,3 +54,9 @@ func (wv *writeView) Put(key, value []byte, lease lease.LeaseID) (rev int64) {
 	defer tw.End()
 	return tw.Put(key, value, lease)
 }
+
+func (wv *writeView) PutWithExpiry(key, value []byte, lease lease.LeaseID, expireTime int64) (rev int64) {
+	tw := wv.kv.Write(traceutil.TODO())
+	defer tw.End()
+	return tw.PutWithExpiry(key, value, lease, expireTime)
+}
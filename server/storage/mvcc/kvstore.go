@@ -53,6 +53,8 @@ var minimumBatchInterval = 10 * time.Millisecond
 type StoreConfig struct {
 	CompactionBatchLimit    int
 	CompactionSleepInterval time.Duration
+	// WatchBacklog limits the events held in memory for slow watchers.
+	WatchBacklog WatchBacklogConfig
 }
 
 type store struct {
@@ -77,6 +79,17 @@ type store struct {
 	currentRev int64
 	// compactMainRev is the main revision of the last compaction.
 	compactMainRev int64
+	// retention is the key ranges that the last compaction left compacted
+	// only up to an earlier revision, sorted by key. It is protected like
+	// compactMainRev.
+	retention []KeyRetention
+
+	// keyExpiries is the expire time of the keys put with a TTL, as in the key
+	// expiry index. It is only accessed by write txns and on restore.
+	keyExpiries map[string]int64
+
+	// usage is the bytes stored in the key ranges asked for by RangeBytes.
+	usage *rangeUsage
 
 	fifoSched schedule.Scheduler
 
@@ -108,6 +121,8 @@ func NewStore(lg *zap.Logger, b backend.Backend, le lease.Lessor, cfg StoreConfi
 		currentRev:     1,
 		compactMainRev: -1,
 
+		usage: newRangeUsage(),
+
 		fifoSched: schedule.NewFIFOScheduler(lg),
 
 		stopc: make(chan struct{}),
@@ -125,6 +140,7 @@ func NewStore(lg *zap.Logger, b backend.Backend, le lease.Lessor, cfg StoreConfi
 	tx.LockOutsideApply()
 	tx.UnsafeCreateBucket(schema.Key)
 	schema.UnsafeCreateMetaBucket(tx)
+	schema.UnsafeCreateKeyExpiryBucket(tx)
 	tx.Unlock()
 	s.b.ForceCommit()
 
@@ -175,6 +191,7 @@ func (s *store) hashByRev(rev int64) (hash KeyValueHash, currentRev int64, err e
 	s.mu.RLock()
 	s.revMu.RLock()
 	compactRev, currentRev = s.compactMainRev, s.currentRev
+	retention := s.retention
 	s.revMu.RUnlock()
 
 	if rev > 0 && rev < compactRev {
@@ -187,7 +204,7 @@ func (s *store) hashByRev(rev int64) (hash KeyValueHash, currentRev int64, err e
 	if rev == 0 {
 		rev = currentRev
 	}
-	keep := s.kvindex.Keep(rev)
+	keep := s.kvindex.Keep(rev, retention)
 
 	tx := s.b.ReadTx()
 	tx.RLock()
@@ -198,23 +215,29 @@ func (s *store) hashByRev(rev int64) (hash KeyValueHash, currentRev int64, err e
 	return hash, currentRev, err
 }
 
-func (s *store) updateCompactRev(rev int64) (<-chan struct{}, int64, error) {
+func (s *store) updateCompactRev(rev int64, retention []KeyRetention) (<-chan struct{}, []KeyRetention, int64, error) {
 	s.revMu.Lock()
 	if rev <= s.compactMainRev {
 		ch := make(chan struct{})
 		f := schedule.NewJob("kvstore_updateCompactRev_compactBarrier", func(ctx context.Context) { s.compactBarrier(ctx, ch) })
 		s.fifoSched.Schedule(f)
 		s.revMu.Unlock()
-		return ch, 0, ErrCompacted
+		return ch, nil, 0, ErrCompacted
 	}
 	if rev > s.currentRev {
 		s.revMu.Unlock()
-		return nil, 0, ErrFutureRev
+		return nil, nil, 0, ErrFutureRev
 	}
+	retention = s.normalizeRetention(rev, retention)
+	persistRetention := len(retention) != 0 || len(s.retention) != 0
 	compactMainRev := s.compactMainRev
 	s.compactMainRev = rev
+	s.retention = retention
 
 	SetScheduledCompact(s.b.BatchTx(), rev)
+	if persistRetention {
+		SetCompactRetention(s.b.BatchTx(), retention)
+	}
 	// ensure that desired compaction is persisted
 	// gofail: var compactBeforeCommitScheduledCompact struct{}
 	s.b.ForceCommit()
@@ -222,7 +245,7 @@ func (s *store) updateCompactRev(rev int64) (<-chan struct{}, int64, error) {
 
 	s.revMu.Unlock()
 
-	return nil, compactMainRev, nil
+	return nil, retention, compactMainRev, nil
 }
 
 // checkPrevCompactionCompleted checks whether the previous scheduled compaction is completed.
@@ -235,14 +258,14 @@ func (s *store) checkPrevCompactionCompleted() bool {
 	return scheduledCompact == finishedCompact && scheduledCompactFound == finishedCompactFound
 }
 
-func (s *store) compact(trace *traceutil.Trace, rev, prevCompactRev int64, prevCompactionCompleted bool) (<-chan struct{}, error) {
+func (s *store) compact(trace *traceutil.Trace, rev, prevCompactRev int64, retention []KeyRetention, prevCompactionCompleted bool) (<-chan struct{}, error) {
 	ch := make(chan struct{})
 	j := schedule.NewJob("kvstore_compact", func(ctx context.Context) {
 		if ctx.Err() != nil {
 			s.compactBarrier(ctx, ch)
 			return
 		}
-		hash, err := s.scheduleCompaction(rev, prevCompactRev)
+		hash, err := s.scheduleCompaction(rev, prevCompactRev, retention)
 		if err != nil {
 			s.lg.Warn("Failed compaction", zap.Error(err))
 			s.compactBarrier(context.TODO(), ch)
@@ -263,20 +286,24 @@ func (s *store) compact(trace *traceutil.Trace, rev, prevCompactRev int64, prevC
 	return ch, nil
 }
 
-func (s *store) compactLockfree(rev int64) (<-chan struct{}, error) {
+func (s *store) compactLockfree(rev int64, retention []KeyRetention) (<-chan struct{}, error) {
 	prevCompactionCompleted := s.checkPrevCompactionCompleted()
-	ch, prevCompactRev, err := s.updateCompactRev(rev)
+	ch, retention, prevCompactRev, err := s.updateCompactRev(rev, retention)
 	if err != nil {
 		return ch, err
 	}
 
-	return s.compact(traceutil.TODO(), rev, prevCompactRev, prevCompactionCompleted)
+	return s.compact(traceutil.TODO(), rev, prevCompactRev, retention, prevCompactionCompleted)
 }
 
 func (s *store) Compact(trace *traceutil.Trace, rev int64) (<-chan struct{}, error) {
+	return s.CompactWithRetention(trace, rev, nil)
+}
+
+func (s *store) CompactWithRetention(trace *traceutil.Trace, rev int64, retention []KeyRetention) (<-chan struct{}, error) {
 	s.mu.Lock()
 	prevCompactionCompleted := s.checkPrevCompactionCompleted()
-	ch, prevCompactRev, err := s.updateCompactRev(rev)
+	ch, retention, prevCompactRev, err := s.updateCompactRev(rev, retention)
 	trace.Step("check and update compact revision")
 	if err != nil {
 		s.mu.Unlock()
@@ -284,7 +311,16 @@ func (s *store) Compact(trace *traceutil.Trace, rev int64) (<-chan struct{}, err
 	}
 	s.mu.Unlock()
 
-	return s.compact(trace, rev, prevCompactRev, prevCompactionCompleted)
+	return s.compact(trace, rev, prevCompactRev, retention, prevCompactionCompleted)
+}
+
+func (s *store) ExpiredKeys(now int64, limit int) []schema.ExpiringKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	tx := s.b.BatchTx()
+	tx.LockOutsideApply()
+	defer tx.Unlock()
+	return schema.UnsafeGetExpiredKeys(tx, now, int64(limit))
 }
 
 func (s *store) Commit() {
@@ -302,12 +338,14 @@ func (s *store) Restore(b backend.Backend) error {
 
 	s.b = b
 	s.kvindex = newTreeIndex(s.lg)
+	s.usage.reset()
 
 	{
 		// During restore the metrics might report 'special' values
 		s.revMu.Lock()
 		s.currentRev = 1
 		s.compactMainRev = -1
+		s.retention = nil
 		s.revMu.Unlock()
 	}
 
@@ -325,6 +363,7 @@ func (s *store) restore() error {
 	revToBytes(revision{main: math.MaxInt64, sub: math.MaxInt64}, max)
 
 	keyToLease := make(map[string]lease.LeaseID)
+	s.keyExpiries = make(map[string]int64)
 
 	// restore index
 	tx := s.b.ReadTx()
@@ -343,6 +382,14 @@ func (s *store) restore() error {
 		s.revMu.Unlock()
 	}
 	scheduledCompact, _ := UnsafeReadScheduledCompact(tx)
+	retention, err := UnsafeReadCompactRetention(tx)
+	if err != nil {
+		tx.RUnlock()
+		return fmt.Errorf("failed to read compaction retention: %w", err)
+	}
+	s.revMu.Lock()
+	s.retention = retention
+	s.revMu.Unlock()
 	// index keys concurrently as they're loaded in from tx
 	keysGauge.Set(0)
 	rkvc, revc := restoreIntoIndex(s.lg, s.kvindex)
@@ -353,7 +400,7 @@ func (s *store) restore() error {
 		}
 		// rkvc blocks if the total pending keys exceeds the restore
 		// chunk size to keep keys from consuming too much memory.
-		restoreChunk(s.lg, rkvc, keys, vals, keyToLease)
+		restoreChunk(s.lg, rkvc, keys, vals, keyToLease, s.keyExpiries)
 		if len(keys) < restoreChunkKeys {
 			// partial set implies final set
 			break
@@ -402,7 +449,7 @@ func (s *store) restore() error {
 	s.lg.Info("kvstore restored", zap.Int64("current-rev", s.currentRev))
 
 	if scheduledCompact != 0 {
-		if _, err := s.compactLockfree(scheduledCompact); err != nil {
+		if _, err := s.compactLockfree(scheduledCompact, retention); err != nil {
 			s.lg.Warn("compaction encountered error", zap.Error(err))
 		}
 
@@ -468,7 +515,7 @@ func restoreIntoIndex(lg *zap.Logger, idx index) (chan<- revKeyValue, <-chan int
 	return rkvc, revc
 }
 
-func restoreChunk(lg *zap.Logger, kvc chan<- revKeyValue, keys, vals [][]byte, keyToLease map[string]lease.LeaseID) {
+func restoreChunk(lg *zap.Logger, kvc chan<- revKeyValue, keys, vals [][]byte, keyToLease map[string]lease.LeaseID, keyExpiries map[string]int64) {
 	for i, key := range keys {
 		rkv := revKeyValue{key: key}
 		if err := rkv.kv.Unmarshal(vals[i]); err != nil {
@@ -482,6 +529,11 @@ func restoreChunk(lg *zap.Logger, kvc chan<- revKeyValue, keys, vals [][]byte, k
 		} else {
 			delete(keyToLease, rkv.kstr)
 		}
+		if exp := rkv.kv.ExpireTime; exp != 0 && !isTombstone(key) {
+			keyExpiries[rkv.kstr] = exp
+		} else {
+			delete(keyExpiries, rkv.kstr)
+		}
 		kvc <- rkv
 	}
 }
@@ -15,8 +15,10 @@
 package mvcc
 
 import (
+	"bytes"
 	"encoding/binary"
 	"fmt"
+	"sort"
 	"time"
 
 	"go.uber.org/zap"
@@ -24,9 +26,92 @@ import (
 	"go.etcd.io/etcd/server/v3/storage/schema"
 )
 
-func (s *store) scheduleCompaction(compactMainRev, prevCompactRev int64) (KeyValueHash, error) {
+// KeyRetention limits the compaction of the keys in [Key, End) to Revision,
+// so their history after Revision survives a compaction at a later revision.
+// A nil End selects only Key and an empty End selects all keys greater than
+// or equal to Key.
+type KeyRetention struct {
+	Key      []byte `json:"key"`
+	End      []byte `json:"end"`
+	Revision int64  `json:"revision"`
+}
+
+// retentionRev returns the revision up to which a compaction at rev compacts
+// the given key under the retention sorted by key.
+func retentionRev(retention []KeyRetention, key []byte, rev int64) int64 {
+	for _, r := range retention {
+		if bytes.Compare(key, r.Key) < 0 {
+			break
+		}
+		if (len(r.End) == 0 || bytes.Compare(key, r.End) < 0) && r.Revision < rev {
+			rev = r.Revision
+		}
+	}
+	return rev
+}
+
+// normalizeRetention returns the part of the given retention that a compaction
+// at rev can honor, sorted by key. History that was already compacted cannot be
+// retained, so no range is kept below the revision it has been compacted to.
+// The caller must hold revMu.
+func (s *store) normalizeRetention(rev int64, retention []KeyRetention) []KeyRetention {
+	var ret []KeyRetention
+	for _, r := range retention {
+		end := r.End
+		if end == nil {
+			end = append(append([]byte{}, r.Key...), 0)
+		}
+		floor := r.Revision
+		if compactRev := s.rangeCompactRev(r.Key, end); floor < compactRev {
+			floor = compactRev
+		}
+		if floor >= rev {
+			continue
+		}
+		ret = append(ret, KeyRetention{Key: r.Key, End: end, Revision: floor})
+	}
+	sort.Slice(ret, func(i, j int) bool { return bytes.Compare(ret[i].Key, ret[j].Key) < 0 })
+	return ret
+}
+
+// rangeCompactRev returns the revision up to which the history of the keys in
+// [key, end) has been compacted. A nil end selects only key and an empty end
+// selects all keys greater than or equal to key. Unless the range is fully
+// covered by the retention of the last compaction, it is compactMainRev.
+// The caller must hold revMu or mu.
+func (s *store) rangeCompactRev(key, end []byte) int64 {
+	if len(s.retention) == 0 {
+		return s.compactMainRev
+	}
+	if end == nil {
+		end = append(append([]byte{}, key...), 0)
+	}
+	rev, pos := int64(0), key
+	for _, r := range s.retention {
+		if len(r.End) != 0 && bytes.Compare(r.End, pos) <= 0 {
+			continue
+		}
+		if bytes.Compare(r.Key, pos) > 0 {
+			// keys in [pos, r.Key) are not retained
+			break
+		}
+		if r.Revision > rev {
+			rev = r.Revision
+		}
+		if len(r.End) == 0 {
+			return rev
+		}
+		pos = r.End
+		if len(end) != 0 && bytes.Compare(pos, end) >= 0 {
+			return rev
+		}
+	}
+	return s.compactMainRev
+}
+
+func (s *store) scheduleCompaction(compactMainRev, prevCompactRev int64, retention []KeyRetention) (KeyValueHash, error) {
 	totalStart := time.Now()
-	keep := s.kvindex.Compact(compactMainRev)
+	keep := s.kvindex.Compact(compactMainRev, retention)
 	indexCompactionPauseMs.Observe(float64(time.Since(totalStart) / time.Millisecond))
 
 	totalStart = time.Now()
@@ -16,6 +16,7 @@ package mvcc
 
 import (
 	"context"
+	"fmt"
 	"reflect"
 	"testing"
 	"time"
@@ -83,7 +84,7 @@ func TestScheduleCompaction(t *testing.T) {
 		}
 		tx.Unlock()
 
-		_, err := s.scheduleCompaction(tt.rev, 0)
+		_, err := s.scheduleCompaction(tt.rev, 0, nil)
 		if err != nil {
 			t.Error(err)
 		}
@@ -147,3 +148,110 @@ func TestCompactAllAndRestore(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestCompactWithRetentionAndRestore(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	s0 := NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
+	defer b.Close()
+
+	for i := 1; i <= 3; i++ {
+		s0.Put([]byte("a"), []byte(fmt.Sprintf("a%d", i)), lease.NoLease)
+		s0.Put([]byte("b"), []byte(fmt.Sprintf("b%d", i)), lease.NoLease)
+	}
+
+	compact := func(s *store, rev int64, retention []KeyRetention) {
+		done, err := s.CompactWithRetention(traceutil.TODO(), rev, retention)
+		if err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatal("timeout waiting for compaction to finish")
+		}
+	}
+	// keep the history of "a" since revision 3
+	compact(s0, 6, []KeyRetention{{Key: []byte("a"), Revision: 3}})
+
+	tests := []struct {
+		key, end []byte
+		rev      int64
+
+		wval []byte
+		werr error
+	}{
+		{[]byte("a"), nil, 3, []byte("a1"), nil},
+		{[]byte("a"), nil, 4, []byte("a2"), nil},
+		{[]byte("a"), nil, 2, nil, ErrCompacted},
+		{[]byte("b"), nil, 4, nil, ErrCompacted},
+		{[]byte("b"), nil, 6, []byte("b2"), nil},
+		{[]byte("a"), []byte("c"), 4, nil, ErrCompacted},
+	}
+	check := func(s *store) {
+		for i, tt := range tests {
+			r, err := s.Range(context.TODO(), tt.key, tt.end, RangeOptions{Rev: tt.rev})
+			if err != tt.werr {
+				t.Errorf("#%d: err = %v, want %v", i, err, tt.werr)
+				continue
+			}
+			if tt.werr == nil && (len(r.KVs) != 1 || !reflect.DeepEqual(r.KVs[0].Value, tt.wval)) {
+				t.Errorf("#%d: kvs = %+v, want value %q", i, r.KVs, tt.wval)
+			}
+		}
+	}
+	check(s0)
+
+	if err := s0.Close(); err != nil {
+		t.Fatal(err)
+	}
+	s1 := NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
+	check(s1)
+
+	// history that was compacted cannot be retained again
+	s1.Put([]byte("a"), []byte("a4"), lease.NoLease)
+	compact(s1, 7, nil)
+	compact(s1, 8, []KeyRetention{{Key: []byte("a"), Revision: 2}})
+	if _, err := s1.Range(context.TODO(), []byte("a"), nil, RangeOptions{Rev: 6}); err != ErrCompacted {
+		t.Errorf("err = %v, want %v", err, ErrCompacted)
+	}
+	r, err := s1.Range(context.TODO(), []byte("a"), nil, RangeOptions{Rev: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.KVs) != 1 || string(r.KVs[0].Value) != "a3" {
+		t.Errorf("kvs = %+v, want value %q", r.KVs, "a3")
+	}
+	if err := s1.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRangeCompactRev(t *testing.T) {
+	s := &store{
+		compactMainRev: 10,
+		retention: []KeyRetention{
+			{Key: []byte("a"), End: []byte("c"), Revision: 5},
+			{Key: []byte("c"), End: []byte("d"), Revision: 7},
+			{Key: []byte("x"), End: []byte{}, Revision: 3},
+		},
+	}
+	tests := []struct {
+		key, end []byte
+
+		wrev int64
+	}{
+		{[]byte("a"), nil, 5},
+		{[]byte("b"), []byte("c"), 5},
+		{[]byte("a"), []byte("d"), 7},
+		{[]byte("a"), []byte("e"), 10},
+		{[]byte("d"), nil, 10},
+		{[]byte("x"), []byte{}, 3},
+		{[]byte("y"), []byte("z"), 3},
+		{[]byte("w"), []byte{}, 10},
+	}
+	for i, tt := range tests {
+		if rev := s.rangeCompactRev(tt.key, tt.end); rev != tt.wrev {
+			t.Errorf("#%d: rangeCompactRev(%q, %q) = %d, want %d", i, tt.key, tt.end, rev, tt.wrev)
+		}
+	}
+}
@@ -398,6 +398,7 @@ func TestStoreRestore(t *testing.T) {
 	}
 	b.tx.rangeRespc <- rangeResp{[][]byte{schema.FinishedCompactKeyName}, [][]byte{newTestRevBytes(revision{3, 0})}}
 	b.tx.rangeRespc <- rangeResp{[][]byte{schema.ScheduledCompactKeyName}, [][]byte{newTestRevBytes(revision{3, 0})}}
+	b.tx.rangeRespc <- rangeResp{nil, nil}
 
 	b.tx.rangeRespc <- rangeResp{[][]byte{putkey, delkey}, [][]byte{putkvb, delkvb}}
 	b.tx.rangeRespc <- rangeResp{nil, nil}
@@ -413,6 +414,7 @@ func TestStoreRestore(t *testing.T) {
 	wact := []testutil.Action{
 		{Name: "range", Params: []interface{}{schema.Meta, schema.FinishedCompactKeyName, []byte(nil), int64(0)}},
 		{Name: "range", Params: []interface{}{schema.Meta, schema.ScheduledCompactKeyName, []byte(nil), int64(0)}},
+		{Name: "range", Params: []interface{}{schema.Meta, schema.CompactRetentionKeyName, []byte(nil), int64(0)}},
 		{Name: "range", Params: []interface{}{schema.Key, newTestRevBytes(revision{1, 0}), newTestRevBytes(revision{math.MaxInt64, math.MaxInt64}), int64(restoreChunkKeys)}},
 	}
 	if g := b.tx.Action(); !reflect.DeepEqual(g, wact) {
@@ -483,6 +485,62 @@ func TestRestoreDelete(t *testing.T) {
 	}
 }
 
+func TestStoreExpiredKeys(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	s := NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
+	defer b.Close()
+
+	putWithExpiry := func(s *store, key string, expireTime int64) {
+		txn := s.Write(traceutil.TODO())
+		txn.PutWithExpiry([]byte(key), []byte("bar"), lease.NoLease, expireTime)
+		txn.End()
+	}
+	putWithExpiry(s, "foo1", 20)
+	putWithExpiry(s, "foo2", 10)
+	putWithExpiry(s, "foo3", 10)
+	putWithExpiry(s, "foo4", 30)
+	// foo3 no longer expires, foo4 is deleted before it expires
+	s.Put([]byte("foo3"), []byte("bar"), lease.NoLease)
+	s.DeleteRange([]byte("foo4"), nil)
+
+	r, err := s.Range(context.TODO(), []byte("foo1"), nil, RangeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.KVs[0].ExpireTime != 20 {
+		t.Errorf("expire time = %d, want 20", r.KVs[0].ExpireTime)
+	}
+
+	tests := []struct {
+		now   int64
+		limit int
+		want  []schema.ExpiringKey
+	}{
+		{9, 10, []schema.ExpiringKey{}},
+		{10, 10, []schema.ExpiringKey{{Key: []byte("foo2"), ExpireTime: 10}}},
+		{40, 10, []schema.ExpiringKey{{Key: []byte("foo2"), ExpireTime: 10}, {Key: []byte("foo1"), ExpireTime: 20}}},
+		{40, 1, []schema.ExpiringKey{{Key: []byte("foo2"), ExpireTime: 10}}},
+	}
+	check := func(s *store) {
+		for i, tt := range tests {
+			if got := s.ExpiredKeys(tt.now, tt.limit); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("#%d: expired keys = %+v, want %+v", i, got, tt.want)
+			}
+		}
+	}
+	check(s)
+	s.Close()
+
+	// the index is rebuilt on restore, so that overwriting foo2 moves it
+	s = NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
+	defer s.Close()
+	check(s)
+	putWithExpiry(s, "foo2", 15)
+	if got := s.ExpiredKeys(10, 10); len(got) != 0 {
+		t.Errorf("expired keys = %+v, want none", got)
+	}
+}
+
 func TestRestoreContinueUnfinishedCompaction(t *testing.T) {
 	tests := []string{"recreate", "restore"}
 	for _, test := range tests {
@@ -915,6 +973,7 @@ func newFakeStore(lg *zap.Logger) *store {
 		compactMainRev: -1,
 		fifoSched:      schedule.NewFIFOScheduler(lg),
 		stopc:          make(chan struct{}),
+		usage:          newRangeUsage(),
 		lg:             lg,
 	}
 	s.ReadView, s.WriteView = &readView{s}, &writeView{s}
@@ -1046,11 +1105,11 @@ func (i *fakeIndex) RangeSince(key, end []byte, rev int64) []revision {
 	r := <-i.indexRangeEventsRespc
 	return r.revs
 }
-func (i *fakeIndex) Compact(rev int64) map[revision]struct{} {
+func (i *fakeIndex) Compact(rev int64, retention []KeyRetention) map[revision]struct{} {
 	i.Recorder.Record(testutil.Action{Name: "compact", Params: []interface{}{rev}})
 	return <-i.indexCompactRespc
 }
-func (i *fakeIndex) Keep(rev int64) map[revision]struct{} {
+func (i *fakeIndex) Keep(rev int64, retention []KeyRetention) map[revision]struct{} {
 	i.Recorder.Record(testutil.Action{Name: "keep", Params: []interface{}{rev}})
 	return <-i.indexCompactRespc
 }
@@ -1076,3 +1135,47 @@ func createBytesSlice(bytesN, sliceN int) [][]byte {
 	}
 	return rs
 }
+
+func TestStoreRangeBytes(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	s := NewStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
+	defer s.Close()
+
+	s.Put([]byte("foo1"), []byte("bar"), lease.NoLease)
+	s.Put([]byte("foo2"), []byte("bar"), lease.NoLease)
+	s.Put([]byte("zoo"), []byte("bar"), lease.NoLease)
+	if n := s.RangeBytes([]byte("foo"), []byte("fop")); n != 14 {
+		t.Fatalf("range bytes = %d, want 14", n)
+	}
+	if n := s.RangeBytes([]byte("foo1"), nil); n != 7 {
+		t.Fatalf("key bytes = %d, want 7", n)
+	}
+	if n := s.RangeBytes([]byte("foo2"), []byte{}); n != 13 {
+		t.Fatalf("bytes from key = %d, want 13", n)
+	}
+
+	// the usage of the tracked ranges follows writes
+	s.Put([]byte("foo1"), []byte("barbar"), lease.NoLease)
+	s.Put([]byte("foo3"), []byte("bar"), lease.NoLease)
+	s.DeleteRange([]byte("foo2"), nil)
+	txn := s.Write(traceutil.TODO())
+	txn.Put([]byte("zoo"), []byte("b"), lease.NoLease)
+	txn.DeleteRange([]byte("foo3"), nil)
+	txn.End()
+	if n := s.RangeBytes([]byte("foo"), []byte("fop")); n != 10 {
+		t.Errorf("range bytes = %d, want 10", n)
+	}
+	if n := s.RangeBytes([]byte("foo1"), nil); n != 10 {
+		t.Errorf("key bytes = %d, want 10", n)
+	}
+	if n := s.RangeBytes([]byte("foo2"), []byte{}); n != 4 {
+		t.Errorf("bytes from key = %d, want 4", n)
+	}
+	if n := s.RangeBytes([]byte{}, []byte{}); n != 14 {
+		t.Errorf("bytes of all keys = %d, want 14", n)
+	}
+	s.Put([]byte("a"), []byte("b"), lease.NoLease)
+	if n := s.RangeBytes([]byte{}, []byte{}); n != 16 {
+		t.Errorf("bytes of all keys = %d, want 16", n)
+	}
+}
@@ -77,9 +77,12 @@ func (tr *storeTxnCommon) rangeKeys(ctx context.Context, key, end []byte, curRev
 	if rev <= 0 {
 		rev = curRev
 	}
-	if rev < tr.s.compactMainRev {
+	if rev < tr.s.rangeCompactRev(key, end) {
 		return &RangeResult{KVs: nil, Count: -1, Rev: 0}, ErrCompacted
 	}
+	if ro.Filter != nil {
+		return tr.filterKeys(ctx, key, end, rev, curRev, ro)
+	}
 	if ro.Count {
 		total := tr.s.kvindex.CountRevisions(key, end, rev)
 		tr.trace.Step("count revisions from in-memory index tree")
@@ -131,6 +134,56 @@ func (tr *storeTxnCommon) rangeKeys(ctx context.Context, key, end []byte, curRev
 	return &RangeResult{KVs: kvs, Count: total, Rev: curRev}, nil
 }
 
+// filterKeys is like rangeKeys, but it has to read every key-value pair in
+// the range from the backend so that ro.Filter is applied before the limit
+// and count are computed.
+func (tr *storeTxnCommon) filterKeys(ctx context.Context, key, end []byte, rev, curRev int64, ro RangeOptions) (*RangeResult, error) {
+	revpairs, _ := tr.s.kvindex.Revisions(key, end, rev, 0)
+	tr.trace.Step("range keys from in-memory index tree")
+
+	var kvs []mvccpb.KeyValue
+	total := 0
+	revBytes := newRevBytes()
+	for _, revpair := range revpairs {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("filterKeys: context cancelled: %w", ctx.Err())
+		default:
+		}
+		revToBytes(revpair, revBytes)
+		_, vs := tr.tx.UnsafeRange(schema.Key, revBytes, nil, 0)
+		if len(vs) != 1 {
+			tr.s.lg.Fatal(
+				"range failed to find revision pair",
+				zap.Int64("revision-main", revpair.main),
+				zap.Int64("revision-sub", revpair.sub),
+				zap.Int64("revision-current", curRev),
+				zap.Int64("range-option-rev", ro.Rev),
+				zap.Binary("key", key),
+				zap.Binary("end", end),
+				zap.Int("len-revpairs", len(revpairs)),
+				zap.Int("len-values", len(vs)),
+			)
+		}
+		var kv mvccpb.KeyValue
+		if err := kv.Unmarshal(vs[0]); err != nil {
+			tr.s.lg.Fatal(
+				"failed to unmarshal mvccpb.KeyValue",
+				zap.Error(err),
+			)
+		}
+		if !ro.Filter(&kv) {
+			continue
+		}
+		total++
+		if !ro.Count && (ro.Limit <= 0 || int64(len(kvs)) < ro.Limit) {
+			kvs = append(kvs, kv)
+		}
+	}
+	tr.trace.Step("filter keys from bolt db")
+	return &RangeResult{KVs: kvs, Count: total, Rev: curRev}, nil
+}
+
 func (tr *storeTxnRead) End() {
 	tr.tx.RUnlock() // RUnlock signals the end of concurrentReadTx.
 	tr.s.mu.RUnlock()
@@ -175,7 +228,12 @@ func (tw *storeTxnWrite) DeleteRange(key, end []byte) (int64, int64) {
 }
 
 func (tw *storeTxnWrite) Put(key, value []byte, lease lease.LeaseID) int64 {
-	tw.put(key, value, lease)
+	tw.put(key, value, lease, 0)
+	return tw.beginRev + 1
+}
+
+func (tw *storeTxnWrite) PutWithExpiry(key, value []byte, lease lease.LeaseID, expireTime int64) int64 {
+	tw.put(key, value, lease, expireTime)
 	return tw.beginRev + 1
 }
 
@@ -193,7 +251,7 @@ func (tw *storeTxnWrite) End() {
 	tw.s.mu.RUnlock()
 }
 
-func (tw *storeTxnWrite) put(key, value []byte, leaseID lease.LeaseID) {
+func (tw *storeTxnWrite) put(key, value []byte, leaseID lease.LeaseID, expireTime int64) {
 	rev := tw.beginRev + 1
 	c := rev
 	oldLease := lease.NoLease
@@ -218,6 +276,7 @@ func (tw *storeTxnWrite) put(key, value []byte, leaseID lease.LeaseID) {
 		ModRevision:    rev,
 		Version:        ver,
 		Lease:          int64(leaseID),
+		ExpireTime:     expireTime,
 	}
 
 	d, err := kv.Marshal()
@@ -233,6 +292,8 @@ func (tw *storeTxnWrite) put(key, value []byte, leaseID lease.LeaseID) {
 	tw.s.kvindex.Put(key, idxRev)
 	tw.changes = append(tw.changes, kv)
 	tw.trace.Step("store kv pair into bolt db")
+	tw.setKeyExpiry(key, expireTime)
+	tw.s.usage.update(key, kvSize(key, value))
 
 	if oldLease == leaseID {
 		tw.trace.Step("attach lease to kv pair")
@@ -305,6 +366,8 @@ func (tw *storeTxnWrite) delete(key []byte) {
 		)
 	}
 	tw.changes = append(tw.changes, kv)
+	tw.setKeyExpiry(key, 0)
+	tw.s.usage.update(key, 0)
 
 	item := lease.LeaseItem{Key: string(key)}
 	leaseID := tw.s.le.GetLease(item)
@@ -320,4 +383,24 @@ func (tw *storeTxnWrite) delete(key []byte) {
 	}
 }
 
+// setKeyExpiry moves the key to its new expire time in the key expiry index,
+// or removes it from the index if expireTime is 0.
+func (tw *storeTxnWrite) setKeyExpiry(key []byte, expireTime int64) {
+	old, ok := tw.s.keyExpiries[string(key)]
+	if ok && old == expireTime {
+		return
+	}
+	if ok {
+		schema.UnsafeDeleteKeyExpiry(tw.tx, key, old)
+		delete(tw.s.keyExpiries, string(key))
+	}
+	if expireTime != 0 {
+		if tw.s.keyExpiries == nil {
+			tw.s.keyExpiries = make(map[string]int64)
+		}
+		schema.UnsafePutKeyExpiry(tw.tx, key, expireTime)
+		tw.s.keyExpiries[string(key)] = expireTime
+	}
+}
+
 func (tw *storeTxnWrite) Changes() []mvccpb.KeyValue { return tw.changes }
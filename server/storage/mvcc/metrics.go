@@ -85,6 +85,32 @@ var (
 			Help:      "Total number of unsynced slow watchers.",
 		})
 
+	slowWatcherBacklogEventsGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "etcd_debugging",
+			Subsystem: "mvcc",
+			Name:      "slow_watcher_backlog_events",
+			Help:      "Number of events held in memory for watchers blocked on their watch stream.",
+		})
+
+	slowWatcherBacklogBytesGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "etcd_debugging",
+			Subsystem: "mvcc",
+			Name:      "slow_watcher_backlog_bytes",
+			Help:      "Size in bytes of the events held in memory for watchers blocked on their watch stream.",
+		})
+
+	slowWatcherPolicyCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "etcd_debugging",
+			Subsystem: "mvcc",
+			Name:      "slow_watcher_policy_total",
+			Help:      "Total number of times a slow watcher policy applied to watchers exceeding the backlog limits.",
+		},
+		[]string{"policy"},
+	)
+
 	totalEventsCounter = prometheus.NewCounter(
 		prometheus.CounterOpts{
 			Namespace: "etcd_debugging",
@@ -276,6 +302,9 @@ func init() {
 	prometheus.MustRegister(watchStreamGauge)
 	prometheus.MustRegister(watcherGauge)
 	prometheus.MustRegister(slowWatcherGauge)
+	prometheus.MustRegister(slowWatcherBacklogEventsGauge)
+	prometheus.MustRegister(slowWatcherBacklogBytesGauge)
+	prometheus.MustRegister(slowWatcherPolicyCounter)
 	prometheus.MustRegister(totalEventsCounter)
 	prometheus.MustRegister(pendingEventsGauge)
 	prometheus.MustRegister(indexCompactionPauseMs)
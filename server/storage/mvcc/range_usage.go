@@ -0,0 +1,160 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvcc
+
+import (
+	"sync"
+
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/pkg/v3/adt"
+	"go.etcd.io/etcd/server/v3/storage/schema"
+)
+
+// maxTrackedRanges is the number of key ranges whose usage is tracked before
+// all of them are dropped and counted again on their next use.
+const maxTrackedRanges = 1024
+
+// usageRange is a key range whose usage is tracked. An empty end is the range
+// of all the keys from key.
+type usageRange struct {
+	key, end string
+	single   bool
+}
+
+func newUsageRange(key, end []byte) usageRange {
+	if end != nil && len(key) == 0 {
+		// keys are never empty, so all of them are from "\x00"
+		key = []byte{0}
+	}
+	return usageRange{key: string(key), end: string(end), single: end == nil}
+}
+
+func (r usageRange) interval() adt.Interval {
+	if r.single {
+		return adt.NewStringAffinePoint(r.key)
+	}
+	return adt.NewStringAffineInterval(r.key, r.end)
+}
+
+// rangeUsage is the bytes of the keys and values in the key ranges whose usage
+// was asked for. Write txns keep it up to date, so that the usage of a range
+// is only counted by going over its keys the first time it is asked for.
+type rangeUsage struct {
+	mu sync.Mutex
+	// ranges maps every tracked range to its usage.
+	ranges adt.IntervalTree
+	// sizes is the size of every key in a tracked range.
+	sizes map[string]int64
+}
+
+func newRangeUsage() *rangeUsage {
+	return &rangeUsage{ranges: adt.NewIntervalTree(), sizes: make(map[string]int64)}
+}
+
+func (u *rangeUsage) get(r usageRange) (int64, bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if iv := u.ranges.Find(r.interval()); iv != nil {
+		return *iv.Val.(*int64), true
+	}
+	return 0, false
+}
+
+// track starts tracking the usage of r from the sizes of its keys.
+func (u *rangeUsage) track(r usageRange, sizes map[string]int64) int64 {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.ranges.Len() >= maxTrackedRanges {
+		u.ranges = adt.NewIntervalTree()
+		u.sizes = make(map[string]int64)
+	}
+	n := int64(0)
+	for k, size := range sizes {
+		u.sizes[k] = size
+		n += size
+	}
+	u.ranges.Insert(r.interval(), &n)
+	return n
+}
+
+// update records that key now has the given size, or was deleted if size is 0.
+func (u *rangeUsage) update(key []byte, size int64) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.ranges.Len() == 0 {
+		return
+	}
+	k := string(key)
+	tracked := false
+	u.ranges.Visit(adt.NewStringAffinePoint(k), func(iv *adt.IntervalValue) bool {
+		*iv.Val.(*int64) += size - u.sizes[k]
+		tracked = true
+		return true
+	})
+	switch {
+	case !tracked:
+	case size == 0:
+		delete(u.sizes, k)
+	default:
+		u.sizes[k] = size
+	}
+}
+
+func (u *rangeUsage) reset() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.ranges = adt.NewIntervalTree()
+	u.sizes = make(map[string]int64)
+}
+
+// RangeBytes returns the bytes of the keys and values in the range [key, end)
+// at the current revision. It must not be called within a txn.
+func (s *store) RangeBytes(key, end []byte) int64 {
+	r := newUsageRange(key, end)
+	if n, ok := s.usage.get(r); ok {
+		return n
+	}
+
+	// no write txn may change the range while its keys are counted
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n, ok := s.usage.get(r); ok {
+		return n
+	}
+	s.revMu.RLock()
+	rev := s.currentRev
+	s.revMu.RUnlock()
+	tx := s.b.ReadTx()
+	tx.RLock()
+	defer tx.RUnlock()
+	revpairs, _ := s.kvindex.Revisions([]byte(r.key), end, rev, 0)
+	sizes := make(map[string]int64, len(revpairs))
+	revBytes := newRevBytes()
+	for _, revpair := range revpairs {
+		revToBytes(revpair, revBytes)
+		_, vs := tx.UnsafeRange(schema.Key, revBytes, nil, 0)
+		if len(vs) != 1 {
+			continue
+		}
+		var kv mvccpb.KeyValue
+		if err := kv.Unmarshal(vs[0]); err != nil {
+			continue
+		}
+		sizes[string(kv.Key)] = kvSize(kv.Key, kv.Value)
+	}
+	return s.usage.track(r, sizes)
+}
+
+func kvSize(key, value []byte) int64 { return int64(len(key) + len(value)) }
@@ -15,6 +15,8 @@
 package mvcc
 
 import (
+	"encoding/json"
+
 	"go.etcd.io/etcd/server/v3/storage/backend"
 	"go.etcd.io/etcd/server/v3/storage/schema"
 )
@@ -58,3 +60,32 @@ func UnsafeSetFinishedCompact(tx backend.UnsafeWriter, value int64) {
 	revToBytes(revision{main: value}, rbytes)
 	tx.UnsafePut(schema.Meta, schema.FinishedCompactKeyName, rbytes)
 }
+
+// UnsafeReadCompactRetention returns the retention of the last scheduled compaction.
+func UnsafeReadCompactRetention(tx backend.UnsafeReader) ([]KeyRetention, error) {
+	_, retentionBytes := tx.UnsafeRange(schema.Meta, schema.CompactRetentionKeyName, nil, 0)
+	if len(retentionBytes) == 0 {
+		return nil, nil
+	}
+	var retention []KeyRetention
+	if err := json.Unmarshal(retentionBytes[0], &retention); err != nil {
+		return nil, err
+	}
+	return retention, nil
+}
+
+func SetCompactRetention(tx backend.BatchTx, retention []KeyRetention) {
+	tx.LockInsideApply()
+	defer tx.Unlock()
+	UnsafeSetCompactRetention(tx, retention)
+}
+
+func UnsafeSetCompactRetention(tx backend.UnsafeWriter, retention []KeyRetention) {
+	if len(retention) == 0 {
+		tx.UnsafeDelete(schema.Meta, schema.CompactRetentionKeyName)
+		return
+	}
+	// marshaling a slice of plain structs cannot fail
+	rbytes, _ := json.Marshal(retention)
+	tx.UnsafePut(schema.Meta, schema.CompactRetentionKeyName, rbytes)
+}
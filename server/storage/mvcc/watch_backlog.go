@@ -0,0 +1,134 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mvcc
+
+import (
+	"go.etcd.io/etcd/api/v3/mvccpb"
+)
+
+// SlowWatcherPolicy is what happens to a watcher blocked on its watch stream
+// when holding its events would exceed the backlog limits.
+type SlowWatcherPolicy string
+
+const (
+	// SlowWatcherBlock holds none of the events of the watcher, which reads
+	// them again from the backend once its watch stream has room.
+	SlowWatcherBlock SlowWatcherPolicy = "block"
+	// SlowWatcherDrop cancels the watcher, whose client must resync.
+	SlowWatcherDrop SlowWatcherPolicy = "drop"
+	// SlowWatcherCoalesce only holds the latest event of each key, skipping
+	// the revisions in between, and blocks the watcher if that still exceeds
+	// the limits.
+	SlowWatcherCoalesce SlowWatcherPolicy = "coalesce"
+)
+
+// WatchBacklogConfig limits the events held in memory for the watchers blocked
+// on their watch stream. A zero limit means no limit.
+type WatchBacklogConfig struct {
+	// MaxStreamEvents and MaxStreamBytes limit the events held for the
+	// watchers of a watch stream.
+	MaxStreamEvents int
+	MaxStreamBytes  int
+	// MaxEvents and MaxBytes limit the events held for all the watchers.
+	MaxEvents int
+	MaxBytes  int
+	// Policy applies to the watchers whose events exceed the limits,
+	// SlowWatcherBlock if empty.
+	Policy SlowWatcherPolicy
+}
+
+// watchBacklog counts the events held for blocked watchers.
+type watchBacklog struct {
+	events int
+	bytes  int
+}
+
+func (b *watchBacklog) fits(maxEvents, maxBytes, events, bytes int) bool {
+	return (maxEvents == 0 || b.events+events <= maxEvents) && (maxBytes == 0 || b.bytes+bytes <= maxBytes)
+}
+
+// holdVictim returns the events to hold for the watcher blocked on its watch
+// stream, applying the slow watcher policy if they exceed the backlog limits.
+// A returned batch without events blocks the watcher from its minimum revision.
+func (s *watchableStore) holdVictim(w *watcher, eb *eventBatch) *eventBatch {
+	if s.fitsBacklog(w, eb.evs) {
+		s.updateBacklog(w, eb.evs, 1)
+		return eb
+	}
+
+	switch s.store.cfg.WatchBacklog.Policy {
+	case SlowWatcherDrop:
+		slowWatcherPolicyCounter.WithLabelValues(string(SlowWatcherDrop)).Inc()
+		return &eventBatch{drop: true}
+	case SlowWatcherCoalesce:
+		ceb := &eventBatch{evs: coalesceEvents(eb.evs), revs: eb.revs, moreRev: eb.moreRev}
+		if s.fitsBacklog(w, ceb.evs) {
+			slowWatcherPolicyCounter.WithLabelValues(string(SlowWatcherCoalesce)).Inc()
+			s.updateBacklog(w, ceb.evs, 1)
+			return ceb
+		}
+	}
+	slowWatcherPolicyCounter.WithLabelValues(string(SlowWatcherBlock)).Inc()
+	w.minRev = eb.evs[0].Kv.ModRevision
+	return &eventBatch{}
+}
+
+// releaseVictim releases the events held for the watcher.
+func (s *watchableStore) releaseVictim(w *watcher, eb *eventBatch) {
+	s.updateBacklog(w, eb.evs, -1)
+}
+
+func (s *watchableStore) fitsBacklog(w *watcher, evs []mvccpb.Event) bool {
+	cfg := s.store.cfg.WatchBacklog
+	bytes := eventsSize(evs)
+	if w.backlog != nil && !w.backlog.fits(cfg.MaxStreamEvents, cfg.MaxStreamBytes, len(evs), bytes) {
+		return false
+	}
+	return s.backlog.fits(cfg.MaxEvents, cfg.MaxBytes, len(evs), bytes)
+}
+
+func (s *watchableStore) updateBacklog(w *watcher, evs []mvccpb.Event, sign int) {
+	events, bytes := sign*len(evs), sign*eventsSize(evs)
+	if w.backlog != nil {
+		w.backlog.events += events
+		w.backlog.bytes += bytes
+	}
+	s.backlog.events += events
+	s.backlog.bytes += bytes
+	slowWatcherBacklogEventsGauge.Add(float64(events))
+	slowWatcherBacklogBytesGauge.Add(float64(bytes))
+}
+
+func eventsSize(evs []mvccpb.Event) (size int) {
+	for i := range evs {
+		size += evs[i].Size()
+	}
+	return size
+}
+
+// coalesceEvents keeps the latest event of each key, in revision order.
+func coalesceEvents(evs []mvccpb.Event) []mvccpb.Event {
+	latest := make(map[string]int, len(evs))
+	for i := range evs {
+		latest[string(evs[i].Kv.Key)] = i
+	}
+	cevs := make([]mvccpb.Event, 0, len(latest))
+	for i := range evs {
+		if latest[string(evs[i].Kv.Key)] == i {
+			cevs = append(cevs, evs[i])
+		}
+	}
+	return cevs
+}
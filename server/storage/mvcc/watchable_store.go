@@ -40,7 +40,7 @@ var (
 )
 
 type watchable interface {
-	watch(key, end []byte, startRev int64, id WatchID, ch chan<- WatchResponse, fcs ...FilterFunc) (*watcher, cancelFunc)
+	watch(key, end []byte, startRev int64, id WatchID, ch chan<- WatchResponse, backlog *watchBacklog, fcs ...FilterFunc) (*watcher, cancelFunc)
 	progress(w *watcher)
 	progressAll(watchers map[WatchID]*watcher) bool
 	rev() int64
@@ -56,6 +56,8 @@ type watchableStore struct {
 	// victims are watcher batches that were blocked on the watch channel
 	victims []watcherBatch
 	victimc chan struct{}
+	// backlog counts the events held in the victims
+	backlog watchBacklog
 
 	// contains all unsynced watchers that needs to sync with events that have happened
 	unsynced watcherGroup
@@ -115,14 +117,15 @@ func (s *watchableStore) NewWatchStream() WatchStream {
 	}
 }
 
-func (s *watchableStore) watch(key, end []byte, startRev int64, id WatchID, ch chan<- WatchResponse, fcs ...FilterFunc) (*watcher, cancelFunc) {
+func (s *watchableStore) watch(key, end []byte, startRev int64, id WatchID, ch chan<- WatchResponse, backlog *watchBacklog, fcs ...FilterFunc) (*watcher, cancelFunc) {
 	wa := &watcher{
-		key:    key,
-		end:    end,
-		minRev: startRev,
-		id:     id,
-		ch:     ch,
-		fcs:    fcs,
+		key:     key,
+		end:     end,
+		minRev:  startRev,
+		id:      id,
+		ch:      ch,
+		backlog: backlog,
+		fcs:     fcs,
 	}
 
 	s.mu.Lock()
@@ -157,7 +160,7 @@ func (s *watchableStore) cancelWatcher(wa *watcher) {
 		} else if s.synced.delete(wa) {
 			watcherGauge.Dec()
 			break
-		} else if wa.compacted {
+		} else if wa.compacted || wa.dropped {
 			watcherGauge.Dec()
 			break
 		} else if wa.ch == nil {
@@ -180,6 +183,7 @@ func (s *watchableStore) cancelWatcher(wa *watcher) {
 		if victimBatch != nil {
 			slowWatcherGauge.Dec()
 			watcherGauge.Dec()
+			s.releaseVictim(wa, victimBatch[wa])
 			delete(victimBatch, wa)
 			break
 		}
@@ -284,7 +288,7 @@ func (s *watchableStore) moveVictims() (moved int) {
 		for w, eb := range wb {
 			// watcher has observed the store up to, but not including, w.minRev
 			rev := w.minRev - 1
-			if w.send(WatchResponse{WatchID: w.id, Events: eb.evs, Revision: rev}) {
+			if w.sendVictim(eb, rev) {
 				pendingEventsGauge.Add(float64(len(eb.evs)))
 			} else {
 				if newVictim == nil {
@@ -305,7 +309,14 @@ func (s *watchableStore) moveVictims() (moved int) {
 				// couldn't send watch response; stays victim
 				continue
 			}
+			s.releaseVictim(w, eb)
 			w.victim = false
+			if eb.drop {
+				// removed from the store like compacted watchers
+				w.dropped = true
+				slowWatcherGauge.Dec()
+				continue
+			}
 			if eb.moreRev != 0 {
 				w.minRev = eb.moreRev
 			}
@@ -349,7 +360,7 @@ func (s *watchableStore) syncWatchers() int {
 	// find min revision index, and these revisions can be used to
 	// query the backend store of key-value pairs
 	curRev := s.store.currentRev
-	compactionRev := s.store.compactMainRev
+	compactionRev := func(w *watcher) int64 { return s.store.rangeCompactRev(w.key, w.end) }
 
 	wg, minRev := s.unsynced.choose(maxWatchersPerSync, curRev, compactionRev)
 	minBytes, maxBytes := newRevBytes(), newRevBytes()
@@ -391,7 +402,7 @@ func (s *watchableStore) syncWatchers() int {
 		}
 
 		if w.victim {
-			victims[w] = eb
+			victims[w] = s.holdVictim(w, eb)
 		} else {
 			if eb.moreRev != 0 {
 				// stay unsynced; more to read
@@ -446,19 +457,19 @@ func (s *watchableStore) notify(rev int64, evs []mvccpb.Event) {
 				zap.Int("number-of-revisions", eb.revs),
 			)
 		}
+		// always update minRev
+		// in case 'send' returns true and watcher stays synced, this is needed for Restore when all watchers become unsynced
+		// in case 'send' returns false, this is needed for syncWatchers
+		w.minRev = rev + 1
 		if w.send(WatchResponse{WatchID: w.id, Events: eb.evs, Revision: rev}) {
 			pendingEventsGauge.Add(float64(len(eb.evs)))
 		} else {
 			// move slow watcher to victims
 			w.victim = true
-			victim[w] = eb
+			victim[w] = s.holdVictim(w, eb)
 			s.synced.delete(w)
 			slowWatcherGauge.Inc()
 		}
-		// always update minRev
-		// in case 'send' returns true and watcher stays synced, this is needed for Restore when all watchers become unsynced
-		// in case 'send' returns false, this is needed for syncWatchers
-		w.minRev = rev + 1
 	}
 	s.addVictim(victim)
 }
@@ -520,6 +531,9 @@ type watcher struct {
 	// compacted is set when the watcher is removed because of compaction
 	compacted bool
 
+	// dropped is set when the watcher is removed because it is too slow
+	dropped bool
+
 	// restore is true when the watcher is being restored from leader snapshot
 	// which means that this watcher has just been moved from "synced" to "unsynced"
 	// watcher group, possibly with a future revision when it was first added
@@ -536,6 +550,21 @@ type watcher struct {
 	// a chan to send out the watch response.
 	// The chan might be shared with other watchers.
 	ch chan<- WatchResponse
+	// backlog counts the events held for the blocked watchers sharing ch.
+	backlog *watchBacklog
+}
+
+// sendVictim sends the events held for the watcher, or resumes the blocked
+// watcher if its chan has room.
+func (w *watcher) sendVictim(eb *eventBatch, rev int64) bool {
+	switch {
+	case eb.drop:
+		return w.send(WatchResponse{WatchID: w.id, Revision: rev, Dropped: true})
+	case len(eb.evs) == 0:
+		// the events are read again once synced
+		return len(w.ch) < cap(w.ch)
+	}
+	return w.send(WatchResponse{WatchID: w.id, Events: eb.evs, Revision: rev})
 }
 
 func (w *watcher) send(wr WatchResponse) bool {
@@ -250,6 +250,55 @@ func TestWatchCompacted(t *testing.T) {
 	}
 }
 
+// TestWatchCompactedWithRetention tests that watchers on a retained range can
+// start from revisions that are compacted for the other keys.
+func TestWatchCompactedWithRetention(t *testing.T) {
+	b, _ := betesting.NewDefaultTmpBackend(t)
+	s := newWatchableStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
+	defer cleanup(s, b)
+
+	for i := 0; i < 5; i++ {
+		s.Put([]byte("foo"), []byte("bar"), lease.NoLease)
+		s.Put([]byte("retained"), []byte("bar"), lease.NoLease)
+	}
+	compactRev, retainRev := int64(9), int64(4)
+	_, err := s.CompactWithRetention(traceutil.TODO(), compactRev, []KeyRetention{{Key: []byte("retained"), Revision: retainRev}})
+	if err != nil {
+		t.Fatalf("failed to compact kv (%v)", err)
+	}
+
+	w := s.NewWatchStream()
+	defer w.Close()
+
+	tests := []struct {
+		key      []byte
+		startRev int64
+
+		wcompactRev int64
+	}{
+		{[]byte("retained"), retainRev, 0},
+		{[]byte("retained"), retainRev - 1, retainRev},
+		{[]byte("foo"), retainRev, compactRev},
+	}
+	for i, tt := range tests {
+		wt, _ := w.Watch(0, tt.key, nil, tt.startRev)
+		select {
+		case resp := <-w.Chan():
+			if resp.WatchID != wt {
+				t.Errorf("#%d: resp.WatchID = %x, want %x", i, resp.WatchID, wt)
+			}
+			if resp.CompactRevision != tt.wcompactRev {
+				t.Errorf("#%d: resp.CompactRevision = %v, want %v", i, resp.CompactRevision, tt.wcompactRev)
+			}
+			if tt.wcompactRev == 0 && (len(resp.Events) == 0 || resp.Events[0].Kv.ModRevision < tt.startRev) {
+				t.Errorf("#%d: resp.Events = %+v, want events since %d", i, resp.Events, tt.startRev)
+			}
+		case <-time.After(1 * time.Second):
+			t.Fatalf("#%d: failed to receive response (timeout)", i)
+		}
+	}
+}
+
 func TestWatchFutureRev(t *testing.T) {
 	b, _ := betesting.NewDefaultTmpBackend(t)
 	s := newWatchableStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, StoreConfig{})
@@ -640,3 +689,68 @@ func TestStressWatchCancelClose(t *testing.T) {
 
 	wg.Wait()
 }
+
+// TestWatchBacklogPolicy ensures that the events of a blocked watcher exceeding
+// the backlog limits are handled according to the slow watcher policy.
+func TestWatchBacklogPolicy(t *testing.T) {
+	oldChanBufLen := chanBufLen
+	defer func() { chanBufLen = oldChanBufLen }()
+	chanBufLen = 1
+
+	tests := []struct {
+		policy SlowWatcherPolicy
+
+		wkeys    []string
+		wdropped bool
+	}{
+		{SlowWatcherBlock, []string{"foo1", "foo2", "foo1"}, false},
+		{SlowWatcherDrop, nil, true},
+		{SlowWatcherCoalesce, []string{"foo2", "foo1"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			b, _ := betesting.NewDefaultTmpBackend(t)
+			cfg := StoreConfig{WatchBacklog: WatchBacklogConfig{MaxStreamEvents: 2, Policy: tt.policy}}
+			s := newWatchableStore(zaptest.NewLogger(t), b, &lease.FakeLessor{}, cfg)
+			defer cleanup(s, b)
+
+			w := s.NewWatchStream()
+			defer w.Close()
+			id, _ := w.Watch(0, []byte("foo"), []byte("fop"), 0)
+
+			// fill the chan, so that the next events are held for the watcher
+			s.Put([]byte("foo0"), []byte("bar"), lease.NoLease)
+			txn := s.Write(traceutil.TODO())
+			for _, k := range []string{"foo1", "foo2", "foo1"} {
+				txn.Put([]byte(k), []byte("bar"), lease.NoLease)
+			}
+			txn.End()
+
+			s.mu.RLock()
+			backlog := s.backlog
+			s.mu.RUnlock()
+			if tt.policy == SlowWatcherCoalesce && backlog.events != 2 || tt.policy != SlowWatcherCoalesce && backlog.events != 0 {
+				t.Errorf("held %d events", backlog.events)
+			}
+
+			if wr := <-w.Chan(); len(wr.Events) != 1 || string(wr.Events[0].Kv.Key) != "foo0" {
+				t.Fatalf("expected event on foo0, got %+v", wr)
+			}
+			select {
+			case wr := <-w.Chan():
+				if wr.WatchID != id || wr.Dropped != tt.wdropped {
+					t.Fatalf("expected dropped %v, got %+v", tt.wdropped, wr)
+				}
+				var keys []string
+				for _, ev := range wr.Events {
+					keys = append(keys, string(ev.Kv.Key))
+				}
+				if !reflect.DeepEqual(keys, tt.wkeys) {
+					t.Errorf("got events on %v, expected %v", keys, tt.wkeys)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("failed to receive the held events")
+			}
+		})
+	}
+}
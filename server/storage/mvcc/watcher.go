@@ -92,6 +92,10 @@ type WatchResponse struct {
 
 	// CompactRevision is set when the watcher is cancelled due to compaction.
 	CompactRevision int64
+
+	// Dropped is set when the watcher is cancelled because it is too slow
+	// to receive its events, see SlowWatcherDrop.
+	Dropped bool
 }
 
 // watchStream contains a collection of watchers that share
@@ -106,6 +110,8 @@ type watchStream struct {
 	closed   bool
 	cancels  map[WatchID]cancelFunc
 	watchers map[WatchID]*watcher
+	// backlog counts the events held for the blocked watchers of the stream
+	backlog watchBacklog
 }
 
 // Watch creates a new watcher in the stream and returns its WatchID.
@@ -132,7 +138,7 @@ func (ws *watchStream) Watch(id WatchID, key, end []byte, startRev int64, fcs ..
 		return -1, ErrWatcherDuplicateID
 	}
 
-	w, c := ws.watchable.watch(key, end, startRev, id, ws.ch, fcs...)
+	w, c := ws.watchable.watch(key, end, startRev, id, ws.ch, &ws.backlog, fcs...)
 
 	ws.cancels[id] = c
 	ws.watchers[id] = w
@@ -36,6 +36,8 @@ type eventBatch struct {
 	revs int
 	// moreRev is first revision with more events following this batch
 	moreRev int64
+	// drop is set when the watcher is to be dropped rather than sent events
+	drop bool
 }
 
 func (eb *eventBatch) add(ev mvccpb.Event) {
@@ -221,7 +223,7 @@ func (wg *watcherGroup) delete(wa *watcher) bool {
 }
 
 // choose selects watchers from the watcher group to update
-func (wg *watcherGroup) choose(maxWatchers int, curRev, compactRev int64) (*watcherGroup, int64) {
+func (wg *watcherGroup) choose(maxWatchers int, curRev int64, compactRev func(w *watcher) int64) (*watcherGroup, int64) {
 	if len(wg.watchers) < maxWatchers {
 		return wg, wg.chooseAll(curRev, compactRev)
 	}
@@ -236,7 +238,10 @@ func (wg *watcherGroup) choose(maxWatchers int, curRev, compactRev int64) (*watc
 	return &ret, ret.chooseAll(curRev, compactRev)
 }
 
-func (wg *watcherGroup) chooseAll(curRev, compactRev int64) int64 {
+// chooseAll returns the minimum revision of the watchers in the group, after
+// removing the watchers whose minimum revision has been compacted according to
+// compactRev.
+func (wg *watcherGroup) chooseAll(curRev int64, compactRev func(w *watcher) int64) int64 {
 	minRev := int64(math.MaxInt64)
 	for w := range wg.watchers {
 		if w.minRev > curRev {
@@ -250,9 +255,9 @@ func (wg *watcherGroup) chooseAll(curRev, compactRev int64) int64 {
 			// mark 'restore' done, since it's chosen
 			w.restore = false
 		}
-		if w.minRev < compactRev {
+		if wCompactRev := compactRev(w); w.minRev < wCompactRev {
 			select {
-			case w.ch <- WatchResponse{WatchID: w.id, CompactRevision: compactRev}:
+			case w.ch <- WatchResponse{WatchID: w.id, CompactRevision: wCompactRev}:
 				w.compacted = true
 				wg.delete(w)
 			default:
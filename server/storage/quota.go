@@ -15,10 +15,13 @@
 package storage
 
 import (
+	"bytes"
+	"sort"
 	"sync"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/server/v3/storage/backend"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
 
 	humanize "github.com/dustin/go-humanize"
 	"go.uber.org/zap"
@@ -174,3 +177,136 @@ func costTxn(r *pb.TxnRequest) int {
 func (b *BackendQuota) Remaining() int64 {
 	return b.maxBackendBytes - b.be.Size()
 }
+
+// KeyRange is the range of keys from Key to RangeEnd. An empty RangeEnd is the
+// single key Key, and a RangeEnd of "\x00" all the keys from Key.
+type KeyRange struct {
+	Key      []byte
+	RangeEnd []byte
+}
+
+func (r KeyRange) contains(key []byte) bool {
+	if len(r.RangeEnd) == 0 {
+		return bytes.Equal(key, r.Key)
+	}
+	if bytes.Compare(key, r.Key) < 0 {
+		return false
+	}
+	return bytes.Equal(r.RangeEnd, []byte{0}) || bytes.Compare(key, r.RangeEnd) < 0
+}
+
+// RangesQuota is a quota on the bytes of the keys and values stored in a set
+// of key ranges, such as the ranges a user may write.
+type RangesQuota struct {
+	kv       mvcc.KV
+	ranges   []KeyRange
+	maxBytes int64
+}
+
+// NewRangesQuota creates a quota limiting the keys and values stored in the
+// given ranges of kv to maxBytes.
+func NewRangesQuota(kv mvcc.KV, ranges []KeyRange, maxBytes int64) Quota {
+	return &RangesQuota{kv: kv, ranges: mergeKeyRanges(ranges), maxBytes: maxBytes}
+}
+
+func (q *RangesQuota) Available(v interface{}) bool {
+	cost := q.Cost(v)
+	if cost == 0 {
+		return true
+	}
+	return q.Remaining() >= int64(cost)
+}
+
+func (q *RangesQuota) Cost(v interface{}) int {
+	switch r := v.(type) {
+	case *pb.PutRequest:
+		return q.costPut(r)
+	case *pb.TxnRequest:
+		return q.costTxn(r)
+	case *pb.LeaseGrantRequest:
+		return 0
+	default:
+		panic("unexpected cost")
+	}
+}
+
+func (q *RangesQuota) costTxn(r *pb.TxnRequest) int {
+	sizeSuccess, sizeFailure := 0, 0
+	for _, u := range r.Success {
+		sizeSuccess += q.costTxnReq(u)
+	}
+	for _, u := range r.Failure {
+		sizeFailure += q.costTxnReq(u)
+	}
+	if sizeFailure > sizeSuccess {
+		return sizeFailure
+	}
+	return sizeSuccess
+}
+
+func (q *RangesQuota) costTxnReq(u *pb.RequestOp) int {
+	if rt := u.GetRequestTxn(); rt != nil {
+		return q.costTxn(rt)
+	}
+	return q.costPut(u.GetRequestPut())
+}
+
+func (q *RangesQuota) costPut(r *pb.PutRequest) int {
+	if r == nil {
+		return 0
+	}
+	for _, kr := range q.ranges {
+		if kr.contains(r.Key) {
+			return len(r.Key) + len(r.Value)
+		}
+	}
+	return 0
+}
+
+func (q *RangesQuota) Remaining() int64 {
+	used := int64(0)
+	for _, kr := range q.ranges {
+		end := kr.RangeEnd
+		if kr.open() {
+			end = []byte{}
+		}
+		used += q.kv.RangeBytes(kr.Key, end)
+	}
+	return q.maxBytes - used
+}
+
+// mergeKeyRanges returns the ranges as sorted ranges of keys from Key to
+// RangeEnd that do not overlap, so that no key is counted twice.
+func mergeKeyRanges(ranges []KeyRange) []KeyRange {
+	rs := make([]KeyRange, 0, len(ranges))
+	for _, kr := range ranges {
+		switch {
+		case len(kr.RangeEnd) == 0:
+			kr.RangeEnd = append(append([]byte{}, kr.Key...), 0)
+		case !kr.open() && bytes.Compare(kr.Key, kr.RangeEnd) >= 0:
+			continue
+		}
+		rs = append(rs, kr)
+	}
+	sort.Slice(rs, func(i, j int) bool { return bytes.Compare(rs[i].Key, rs[j].Key) < 0 })
+	merged := rs[:0]
+	for _, kr := range rs {
+		n := len(merged)
+		if n == 0 || !merged[n-1].reaches(kr.Key) {
+			merged = append(merged, kr)
+			continue
+		}
+		if !merged[n-1].open() && (kr.open() || !merged[n-1].reaches(kr.RangeEnd)) {
+			merged[n-1].RangeEnd = kr.RangeEnd
+		}
+	}
+	return merged
+}
+
+// open returns whether the range has no end.
+func (r KeyRange) open() bool { return bytes.Equal(r.RangeEnd, []byte{0}) }
+
+// reaches returns whether the range ends at or after key.
+func (r KeyRange) reaches(key []byte) bool {
+	return r.open() || bytes.Compare(r.RangeEnd, key) >= 0
+}
@@ -15,8 +15,12 @@
 package schema
 
 import (
+	"bytes"
+	"fmt"
+
 	"go.uber.org/zap"
 
+	"go.etcd.io/etcd/api/v3/authpb"
 	"go.etcd.io/etcd/server/v3/storage/backend"
 )
 
@@ -64,6 +68,98 @@ func restoreFieldValueAction(tx backend.UnsafeReader, bucket backend.Bucket, fie
 	}
 }
 
+type noopAction struct{}
+
+func (a noopAction) unsafeDo(tx backend.UnsafeReadWriter) (action, error) {
+	return noopAction{}, nil
+}
+
+// actionSequence executes its actions in order as a single action.
+type actionSequence []action
+
+func (s actionSequence) unsafeDo(tx backend.UnsafeReadWriter) (action, error) {
+	reverts := make(actionSequence, 0, len(s))
+	for _, a := range s {
+		revert, err := a.unsafeDo(tx)
+		if err != nil {
+			reverts.unsafeDo(tx)
+			return nil, err
+		}
+		reverts = append(actionSequence{revert}, reverts...)
+	}
+	return reverts, nil
+}
+
+// dropPermissionTypesAction removes the permissions of the given types from
+// all roles.
+type dropPermissionTypesAction struct {
+	Types []authpb.Permission_Type
+}
+
+func (a dropPermissionTypesAction) unsafeDo(tx backend.UnsafeReadWriter) (action, error) {
+	var puts actionSequence
+	err := tx.UnsafeForEach(AuthRoles, func(k, v []byte) error {
+		role := &authpb.Role{}
+		if err := role.Unmarshal(v); err != nil {
+			return err
+		}
+		perms := role.KeyPermission[:0]
+		for _, perm := range role.KeyPermission {
+			if !a.dropped(perm.PermType) {
+				perms = append(perms, perm)
+			}
+		}
+		if len(perms) == len(role.KeyPermission) {
+			return nil
+		}
+		role.KeyPermission = perms
+		b, err := role.Marshal()
+		if err != nil {
+			return err
+		}
+		puts = append(puts, setKeyAction{Bucket: AuthRoles, FieldName: bytes.Clone(k), FieldValue: b})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return puts.unsafeDo(tx)
+}
+
+func (a dropPermissionTypesAction) dropped(typ authpb.Permission_Type) bool {
+	for _, t := range a.Types {
+		if t == typ {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPasswordAlgorithmsAction fails if the password of a user is hashed
+// with one of the given algorithms.
+type checkPasswordAlgorithmsAction struct {
+	Algorithms []authpb.PasswordAlgorithm
+}
+
+func (a checkPasswordAlgorithmsAction) unsafeDo(tx backend.UnsafeReadWriter) (action, error) {
+	err := tx.UnsafeForEach(AuthUsers, func(k, v []byte) error {
+		user := &authpb.User{}
+		if err := user.Unmarshal(v); err != nil {
+			return err
+		}
+		for _, alg := range a.Algorithms {
+			if user.PasswordAlgorithm == alg {
+				return fmt.Errorf("password of user %q is hashed with %v, change it with bcrypt before downgrading", user.Name, alg)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return noopAction{}, nil
+}
+
 type ActionList []action
 
 // unsafeExecute executes actions one by one. If one of actions returns error,
@@ -87,7 +87,15 @@ func (atx *authReadTx) UnsafeGetAllRoles() []*authpb.Role {
 }
 
 func unsafeGetAllRoles(lg *zap.Logger, tx backend.UnsafeReader) []*authpb.Role {
-	_, vs := tx.UnsafeRange(AuthRoles, []byte{0}, []byte{0xff}, -1)
+	var vs [][]byte
+	err := tx.UnsafeForEach(AuthRoles, func(k []byte, v []byte) error {
+		vs = append(vs, v)
+		return nil
+	})
+	if err != nil {
+		lg.Panic("failed to get roles",
+			zap.Error(err))
+	}
 	if len(vs) == 0 {
 		return nil
 	}
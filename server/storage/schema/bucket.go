@@ -27,6 +27,8 @@ var (
 	leaseBucketName = []byte("lease")
 	alarmBucketName = []byte("alarm")
 
+	keyExpiryBucketName = []byte("keyExpiry")
+
 	clusterBucketName = []byte("cluster")
 
 	membersBucketName        = []byte("members")
@@ -46,6 +48,8 @@ var (
 	Alarm   = backend.Bucket(bucket{id: 4, name: alarmBucketName, safeRangeBucket: false})
 	Cluster = backend.Bucket(bucket{id: 5, name: clusterBucketName, safeRangeBucket: false})
 
+	KeyExpiry = backend.Bucket(bucket{id: 6, name: keyExpiryBucketName, safeRangeBucket: false})
+
 	Members        = backend.Bucket(bucket{id: 10, name: membersBucketName, safeRangeBucket: false})
 	MembersRemoved = backend.Bucket(bucket{id: 11, name: membersRemovedBucketName, safeRangeBucket: false})
 
@@ -80,7 +84,8 @@ var (
 	ClusterClusterVersionKeyName = []byte("clusterVersion")
 	ClusterDowngradeKeyName      = []byte("downgrade")
 	// Since v3.6
-	MetaStorageVersionName = []byte("storageVersion")
+	MetaStorageVersionName  = []byte("storageVersion")
+	CompactRetentionKeyName = []byte("compactRetention")
 	// Before adding new meta key please update server/etcdserver/version
 )
 
@@ -14,7 +14,10 @@
 
 package schema
 
-import "go.etcd.io/etcd/server/v3/storage/backend"
+import (
+	"go.etcd.io/etcd/api/v3/authpb"
+	"go.etcd.io/etcd/server/v3/storage/backend"
+)
 
 type schemaChange interface {
 	upgradeAction() action
@@ -36,6 +39,26 @@ func addNewField(bucket backend.Bucket, fieldName []byte, fieldValue []byte) sch
 	}
 }
 
+// addNewPermissionTypes represents adding new types of role permissions when
+// upgrading. Downgrade will remove the permissions of these types from the
+// roles, denying the operations they permitted rather than granting others.
+func addNewPermissionTypes(types ...authpb.Permission_Type) schemaChange {
+	return simpleSchemaChange{
+		upgrade:   noopAction{},
+		downgrade: dropPermissionTypesAction{Types: types},
+	}
+}
+
+// addNewPasswordAlgorithms represents adding new algorithms to hash user
+// passwords with when upgrading. Downgrade is refused while passwords are
+// hashed with these algorithms, as their users could not authenticate anymore.
+func addNewPasswordAlgorithms(algs ...authpb.PasswordAlgorithm) schemaChange {
+	return simpleSchemaChange{
+		upgrade:   noopAction{},
+		downgrade: checkPasswordAlgorithmsAction{Algorithms: algs},
+	}
+}
+
 type simpleSchemaChange struct {
 	upgrade   action
 	downgrade action
@@ -18,6 +18,7 @@ import (
 	"testing"
 	"time"
 
+	"go.etcd.io/etcd/api/v3/authpb"
 	betesting "go.etcd.io/etcd/server/v3/storage/backend/testing"
 )
 
@@ -59,3 +60,75 @@ func TestUpgradeDowngrade(t *testing.T) {
 		})
 	}
 }
+
+func TestDowngradeNewPermissionTypes(t *testing.T) {
+	be, _ := betesting.NewTmpBackend(t, time.Microsecond, 10)
+	defer be.Close()
+	tx := be.BatchTx()
+	tx.Lock()
+	defer tx.Unlock()
+	UnsafeCreateAuthRolesBucket(tx)
+
+	put := func(role *authpb.Role) []byte {
+		b, err := role.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx.UnsafePut(AuthRoles, role.Name, b)
+		return b
+	}
+	legacy := put(&authpb.Role{Name: []byte("legacy"), KeyPermission: []*authpb.Permission{{PermType: authpb.READWRITE, Key: []byte("a")}}})
+	finer := put(&authpb.Role{Name: []byte("finer"), KeyPermission: []*authpb.Permission{
+		{PermType: authpb.READ, Key: []byte("a")},
+		{PermType: authpb.CREATE, Key: []byte("a")},
+		{PermType: authpb.WATCH, Key: []byte("b")},
+	}})
+	downgraded, err := (&authpb.Role{Name: []byte("finer"), KeyPermission: []*authpb.Permission{{PermType: authpb.READ, Key: []byte("a")}}}).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	change := addNewPermissionTypes(authpb.CREATE, authpb.DELETE, authpb.WATCH, authpb.LEASE)
+	if _, err = change.upgradeAction().unsafeDo(tx); err != nil {
+		t.Fatal(err)
+	}
+	assertBucketState(t, tx, AuthRoles, map[string]string{"legacy": string(legacy), "finer": string(finer)})
+
+	revert, err := change.downgradeAction().unsafeDo(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertBucketState(t, tx, AuthRoles, map[string]string{"legacy": string(legacy), "finer": string(downgraded)})
+
+	if _, err = revert.unsafeDo(tx); err != nil {
+		t.Fatal(err)
+	}
+	assertBucketState(t, tx, AuthRoles, map[string]string{"legacy": string(legacy), "finer": string(finer)})
+}
+
+func TestDowngradeNewPasswordAlgorithms(t *testing.T) {
+	be, _ := betesting.NewTmpBackend(t, time.Microsecond, 10)
+	defer be.Close()
+	tx := be.BatchTx()
+	tx.Lock()
+	defer tx.Unlock()
+	tx.UnsafeCreateBucket(AuthUsers)
+
+	put := func(user *authpb.User) {
+		b, err := user.Marshal()
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx.UnsafePut(AuthUsers, user.Name, b)
+	}
+	put(&authpb.User{Name: []byte("alice"), Password: []byte("$2a$04$hash")})
+
+	change := addNewPasswordAlgorithms(authpb.ARGON2ID)
+	if _, err := change.downgradeAction().unsafeDo(tx); err != nil {
+		t.Fatalf("expected downgrade of bcrypt passwords to succeed, got %v", err)
+	}
+	put(&authpb.User{Name: []byte("bob"), Password: []byte("$argon2id$hash"), PasswordAlgorithm: authpb.ARGON2ID})
+	if _, err := change.downgradeAction().unsafeDo(tx); err == nil {
+		t.Fatal("expected downgrade of argon2id passwords to fail")
+	}
+}
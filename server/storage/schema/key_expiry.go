@@ -0,0 +1,70 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schema
+
+import (
+	"encoding/binary"
+	"math"
+
+	"go.etcd.io/etcd/server/v3/storage/backend"
+)
+
+// ExpiringKey is an entry of the key expiry index: a key put with a TTL and the
+// unix time, in seconds, at which it expires.
+type ExpiringKey struct {
+	Key        []byte
+	ExpireTime int64
+}
+
+func UnsafeCreateKeyExpiryBucket(tx backend.UnsafeWriter) {
+	tx.UnsafeCreateBucket(KeyExpiry)
+}
+
+// UnsafePutKeyExpiry indexes the key by its expire time.
+func UnsafePutKeyExpiry(tx backend.UnsafeWriter, key []byte, expireTime int64) {
+	tx.UnsafePut(KeyExpiry, keyExpiryToBytes(key, expireTime), []byte{})
+}
+
+func UnsafeDeleteKeyExpiry(tx backend.UnsafeWriter, key []byte, expireTime int64) {
+	tx.UnsafeDelete(KeyExpiry, keyExpiryToBytes(key, expireTime))
+}
+
+// UnsafeGetExpiredKeys returns at most limit keys that expire at or before the
+// given unix time, ordered by expire time. As the index is not safe to range
+// over buffered reads, tx must be the batch tx.
+func UnsafeGetExpiredKeys(tx backend.UnsafeReader, now int64, limit int64) []ExpiringKey {
+	start := keyExpiryToBytes(nil, 0)
+	end := keyExpiryToBytes(nil, math.MaxInt64)
+	if now < math.MaxInt64 {
+		end = keyExpiryToBytes(nil, now+1)
+	}
+	ks, _ := tx.UnsafeRange(KeyExpiry, start, end, limit)
+	expired := make([]ExpiringKey, len(ks))
+	for i, k := range ks {
+		expired[i] = ExpiringKey{
+			Key:        append([]byte(nil), k[8:]...),
+			ExpireTime: int64(binary.BigEndian.Uint64(k[:8])),
+		}
+	}
+	return expired
+}
+
+// keyExpiryToBytes orders the entries of the index by expire time, then by key.
+func keyExpiryToBytes(key []byte, expireTime int64) []byte {
+	b := make([]byte, 8+len(key))
+	binary.BigEndian.PutUint64(b, uint64(expireTime))
+	copy(b[8:], key)
+	return b
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/coreos/go-semver/semver"
 	"go.uber.org/zap"
 
+	"go.etcd.io/etcd/api/v3/authpb"
 	"go.etcd.io/etcd/api/v3/version"
 
 	"go.etcd.io/etcd/server/v3/storage/backend"
@@ -130,6 +131,8 @@ var (
 	schemaChanges = map[semver.Version][]schemaChange{
 		version.V3_6: {
 			addNewField(Meta, MetaStorageVersionName, emptyStorageVersion),
+			addNewPermissionTypes(authpb.CREATE, authpb.DELETE, authpb.WATCH, authpb.LEASE),
+			addNewPasswordAlgorithms(authpb.ARGON2ID),
 		},
 	}
 	// emptyStorageVersion is used for v3.6 Step for the first time, in all other version StoragetVersion should be set by migrator.
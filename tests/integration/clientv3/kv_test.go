@@ -33,6 +33,7 @@ import (
 	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	"go.etcd.io/etcd/api/v3/version"
 	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/client/v3/namespace"
 	integration2 "go.etcd.io/etcd/tests/v3/framework/integration"
 )
 
@@ -108,6 +109,56 @@ func TestKVPutWithLease(t *testing.T) {
 	}
 }
 
+// TestKVPutWithTTL ensures that keys put with a TTL are deleted once they expire.
+func TestKVPutWithTTL(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 3})
+	defer clus.Terminate(t)
+
+	kv := clus.RandClient()
+	ctx := context.TODO()
+
+	if _, err := kv.Put(ctx, "foo", "bar", clientv3.WithTTL(-1)); err != rpctypes.ErrInvalidKeyTTL {
+		t.Fatalf("expected %v, got %v", rpctypes.ErrInvalidKeyTTL, err)
+	}
+
+	before := time.Now().Unix()
+	if _, err := kv.Put(ctx, "foo", "bar", clientv3.WithTTL(1)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := kv.Put(ctx, "foo-no-ttl", "bar"); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := kv.Get(ctx, "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Kvs) != 1 {
+		t.Fatalf("expected 1 key, got %d", len(resp.Kvs))
+	}
+	if et := resp.Kvs[0].ExpireTime; et < before+1 || et > time.Now().Unix()+1 {
+		t.Errorf("expire time = %d, want about %d", et, before+1)
+	}
+
+	for i := 0; ; i++ {
+		resp, err = kv.Get(ctx, "foo", clientv3.WithPrefix())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Kvs) == 1 {
+			break
+		}
+		if i == 50 {
+			t.Fatalf("expected foo to expire, got %d keys", len(resp.Kvs))
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if string(resp.Kvs[0].Key) != "foo-no-ttl" {
+		t.Errorf("key = %q, want foo-no-ttl", resp.Kvs[0].Key)
+	}
+}
+
 // TestKVPutWithIgnoreValue ensures that Put with WithIgnoreValue does not clobber the old value.
 func TestKVPutWithIgnoreValue(t *testing.T) {
 	integration2.BeforeTest(t)
@@ -393,6 +444,173 @@ func TestKVDeleteRange(t *testing.T) {
 	}
 }
 
+func TestKVRangeStats(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	kv := clus.RandClient()
+	ctx := context.TODO()
+
+	for _, k := range []string{"a/b/1", "a/b/2", "a/c", "d/1"} {
+		if _, err := kv.Put(ctx, k, "v"); err != nil {
+			t.Fatalf("couldn't put %q (%v)", k, err)
+		}
+	}
+
+	resp, err := kv.RangeStats(ctx, "a/", clientv3.WithPrefix(), clientv3.WithStatsDelimiter("/"))
+	if err != nil {
+		t.Fatalf("couldn't get range stats (%v)", err)
+	}
+	if resp.Header.Revision != 5 {
+		t.Errorf("revision = %d, want 5", resp.Header.Revision)
+	}
+	if resp.Total.Count != 3 || resp.Total.KeyBytes != 13 || resp.Total.ValueBytes != 3 || resp.Total.MaxModRevision != 4 {
+		t.Errorf("unexpected total %+v", resp.Total)
+	}
+	var pfxs []string
+	for _, st := range resp.Children {
+		pfxs = append(pfxs, string(st.Prefix))
+	}
+	if wpfxs := []string{"a/b/", "a/c"}; !reflect.DeepEqual(pfxs, wpfxs) {
+		t.Errorf("child prefixes = %v, want %v", pfxs, wpfxs)
+	}
+
+	// prefixes are relative to the namespace
+	nsKV := namespace.NewKV(kv.KV, "a/")
+	resp, err = nsKV.RangeStats(ctx, "", clientv3.WithPrefix(), clientv3.WithStatsDelimiter("/"))
+	if err != nil {
+		t.Fatalf("couldn't get range stats (%v)", err)
+	}
+	pfxs = nil
+	for _, st := range resp.Children {
+		pfxs = append(pfxs, string(st.Prefix))
+	}
+	if wpfxs := []string{"b/", "c"}; resp.Total.Count != 3 || !reflect.DeepEqual(pfxs, wpfxs) {
+		t.Errorf("child prefixes = %v (total %+v), want %v", pfxs, resp.Total, wpfxs)
+	}
+
+	if _, err = kv.RangeStats(ctx, ""); err != rpctypes.ErrEmptyKey {
+		t.Fatalf("expected %v, got %v", rpctypes.ErrEmptyKey, err)
+	}
+}
+
+func TestKVMultiRange(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	kv := clus.RandClient()
+	ctx := context.TODO()
+
+	for _, k := range []string{"a", "b/1", "b/2", "b/3"} {
+		if _, err := kv.Put(ctx, k, "v"); err != nil {
+			t.Fatalf("couldn't put %q (%v)", k, err)
+		}
+	}
+
+	ops := []clientv3.Op{clientv3.OpGet("a"), clientv3.OpGet("b/", clientv3.WithPrefix(), clientv3.WithLimit(2))}
+	resp, err := kv.MultiRange(ctx, ops)
+	if err != nil {
+		t.Fatalf("couldn't multi range (%v)", err)
+	}
+	if resp.Revision != 5 || len(resp.Responses) != 2 {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+	if kvs := resp.Responses[0].Kvs; len(kvs) != 1 || string(kvs[0].Key) != "a" {
+		t.Errorf("unexpected kvs %+v", kvs)
+	}
+	sr := resp.Responses[1]
+	if !sr.More || len(sr.Kvs) != 2 || len(sr.Continuation) == 0 {
+		t.Fatalf("unexpected sub range response %+v", sr)
+	}
+
+	// the continuation ignores later writes
+	if _, err = kv.Put(ctx, "b/4", "v"); err != nil {
+		t.Fatal(err)
+	}
+	ops = []clientv3.Op{clientv3.OpGet("b/", clientv3.WithPrefix(), clientv3.WithLimit(2), clientv3.WithContinuation(sr.Continuation))}
+	if resp, err = kv.MultiRange(ctx, ops); err != nil {
+		t.Fatalf("couldn't multi range (%v)", err)
+	}
+	if sr = resp.Responses[0]; resp.Revision != 5 || sr.More || len(sr.Kvs) != 1 || string(sr.Kvs[0].Key) != "b/3" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+
+	// keys are relative to the namespace
+	nsKV := namespace.NewKV(kv.KV, "b/")
+	if resp, err = nsKV.MultiRange(ctx, []clientv3.Op{clientv3.OpGet("1"), clientv3.OpGet("4")}); err != nil {
+		t.Fatalf("couldn't multi range (%v)", err)
+	}
+	for i, wkey := range []string{"1", "4"} {
+		if kvs := resp.Responses[i].Kvs; len(kvs) != 1 || string(kvs[0].Key) != wkey {
+			t.Errorf("#%d: unexpected kvs %+v", i, kvs)
+		}
+	}
+
+	ops = []clientv3.Op{clientv3.OpGet("b/", clientv3.WithPrefix(), clientv3.WithContinuation([]byte("bad")))}
+	if _, err = kv.MultiRange(ctx, ops); err != rpctypes.ErrInvalidContinuation {
+		t.Fatalf("expected %v, got %v", rpctypes.ErrInvalidContinuation, err)
+	}
+}
+
+func TestKVGetIterator(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	kv := clus.RandClient()
+	ctx := context.TODO()
+
+	for _, k := range []string{"a/1", "a/2", "a/3", "a/4", "a/5"} {
+		if _, err := kv.Put(ctx, k, "v"); err != nil {
+			t.Fatalf("couldn't put %q (%v)", k, err)
+		}
+	}
+
+	var keys []string
+	it := clientv3.NewGetIterator(namespace.NewKV(kv.KV, "a/"), "", 2, clientv3.WithFromKey())
+	for it.Next(ctx) {
+		for _, kv := range it.Response().Kvs {
+			keys = append(keys, string(kv.Key))
+		}
+		if it.Response().Header.Revision == 6 {
+			// the keys are read at the revision of the first page
+			if _, err := kv.Put(ctx, "a/0", "v"); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if wkeys := []string{"1", "2", "3", "4", "5"}; !reflect.DeepEqual(keys, wkeys) {
+		t.Errorf("keys = %v, want %v", keys, wkeys)
+	}
+
+	it = clientv3.NewGetIterator(kv, "a/", 2, clientv3.WithPrefix(), clientv3.WithSort(clientv3.SortByKey, clientv3.SortDescend))
+	if it.Next(ctx) || it.Err() != clientv3.ErrRangeNotContinuable {
+		t.Fatalf("expected %v, got %v", clientv3.ErrRangeNotContinuable, it.Err())
+	}
+
+	it = clientv3.NewGetIterator(kv, "a/", 2, clientv3.WithPrefix())
+	if !it.Next(ctx) {
+		t.Fatal(it.Err())
+	}
+	if _, err := kv.Put(ctx, "a/6", "v"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := kv.Compact(ctx, it.Response().Header.Revision+1); err != nil {
+		t.Fatal(err)
+	}
+	if it.Next(ctx) || it.Err() != rpctypes.ErrCompacted {
+		t.Fatalf("expected %v, got %v", rpctypes.ErrCompacted, it.Err())
+	}
+}
+
 func TestKVCompactError(t *testing.T) {
 	integration2.BeforeTest(t)
 
@@ -613,6 +831,89 @@ func TestKVGetStoppedServerAndClose(t *testing.T) {
 }
 
 // TestKVPutStoppedServerAndClose ensures closing after a failed Put works.
+// TestKVGetBoundedStaleness ensures that a follower serves the reads within
+// their staleness bounds locally, and rejects the others if asked to.
+func TestKVGetBoundedStaleness(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 3})
+	defer clus.Terminate(t)
+
+	li := clus.WaitLeader(t)
+	fi := (li + 1) % 3
+	follower := clus.Members[fi]
+	cli := clus.Client(fi)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := cli.Put(ctx, "foo", "bar"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the follower hears from the leader once it applied the put
+	var resp *clientv3.GetResponse
+	for {
+		var err error
+		resp, err = cli.Get(ctx, "foo", clientv3.WithMaxStaleness(time.Second), clientv3.WithMaxStalenessRevisions(1), clientv3.WithFailStale())
+		if err == nil {
+			break
+		}
+		if err != rpctypes.ErrStaleRead {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(resp.Kvs) != 1 || string(resp.Kvs[0].Value) != "bar" {
+		t.Fatalf("expected foo=bar, got %+v", resp.Kvs)
+	}
+
+	// without appends from the leader, the read index of a linearizable read
+	// bounds the staleness of the next reads
+	time.Sleep(time.Second)
+	if _, err := cli.Get(ctx, "foo", clientv3.WithMaxStaleness(500*time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cli.Get(ctx, "foo", clientv3.WithMaxStaleness(500*time.Millisecond), clientv3.WithFailStale()); err != nil {
+		t.Fatalf("expected the read to be served after the linearizable read, got %v", err)
+	}
+
+	// the leader serves the reads once a quorum acknowledged its leadership
+	for {
+		_, err := clus.Client(li).Get(ctx, "foo", clientv3.WithMaxStaleness(time.Second), clientv3.WithFailStale())
+		if err == nil {
+			break
+		}
+		if err != rpctypes.ErrStaleRead {
+			t.Fatal(err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	var others []*integration2.Member
+	for i, m := range clus.Members {
+		if i != fi {
+			others = append(others, m)
+		}
+	}
+	follower.InjectPartition(t, others...)
+	defer follower.RecoverPartition(t, others...)
+	time.Sleep(500 * time.Millisecond)
+
+	if _, err := cli.Get(ctx, "foo", clientv3.WithMaxStaleness(100*time.Millisecond), clientv3.WithFailStale()); err != rpctypes.ErrStaleRead {
+		t.Fatalf("expected %v, got %v", rpctypes.ErrStaleRead, err)
+	}
+	if resp, err := cli.Get(ctx, "foo", clientv3.WithMaxStaleness(time.Minute), clientv3.WithFailStale()); err != nil || len(resp.Kvs) != 1 {
+		t.Fatalf("expected foo to be served within a minute of staleness, got %+v, %v", resp, err)
+	}
+
+	// the stale read is linearizable, which the partitioned follower cannot serve
+	lctx, lcancel := context.WithTimeout(ctx, time.Second)
+	defer lcancel()
+	if _, err := cli.Get(lctx, "foo", clientv3.WithMaxStaleness(100*time.Millisecond)); err == nil {
+		t.Fatal("expected the linearizable read of the partitioned follower to fail")
+	}
+}
+
 func TestKVPutStoppedServerAndClose(t *testing.T) {
 	integration2.BeforeTest(t)
 
@@ -95,6 +95,90 @@ func TestLeaseRevoke(t *testing.T) {
 	}
 }
 
+// TestLeaseGrantChild ensures child leases are listed with their parent and
+// revoked with it.
+func TestLeaseGrantChild(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 3})
+	defer clus.Terminate(t)
+
+	lapi := clus.RandClient()
+	kv := clus.RandClient()
+	ctx := context.TODO()
+
+	if _, err := lapi.GrantChild(ctx, clientv3.LeaseID(12345)); err != rpctypes.ErrLeaseNotFound {
+		t.Fatalf("err = %v, want %v", err, rpctypes.ErrLeaseNotFound)
+	}
+
+	presp, err := lapi.Grant(ctx, 10)
+	if err != nil {
+		t.Fatalf("failed to create lease %v", err)
+	}
+	cresp, err := lapi.GrantChild(ctx, presp.ID)
+	if err != nil {
+		t.Fatalf("failed to create child lease %v", err)
+	}
+	if cresp.TTL != presp.TTL {
+		t.Errorf("ttl = %d, want %d", cresp.TTL, presp.TTL)
+	}
+	if _, err = kv.Put(ctx, "foo", "bar", clientv3.WithLease(cresp.ID)); err != nil {
+		t.Fatal(err)
+	}
+
+	tresp, err := lapi.TimeToLive(ctx, presp.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(tresp.Children, []clientv3.LeaseID{cresp.ID}) {
+		t.Errorf("children = %v, want %v", tresp.Children, []clientv3.LeaseID{cresp.ID})
+	}
+	tresp, err = lapi.TimeToLive(ctx, cresp.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tresp.Parent != presp.ID {
+		t.Errorf("parent = %x, want %x", tresp.Parent, presp.ID)
+	}
+
+	lresp, err := lapi.Leases(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, l := range lresp.Leases {
+		if l.ID == cresp.ID && l.Parent != presp.ID {
+			t.Errorf("parent = %x, want %x", l.Parent, presp.ID)
+		}
+	}
+
+	// keeping the parent alive keeps the child alive
+	kresp, err := lapi.KeepAliveOnce(ctx, presp.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kresp.TTL != presp.TTL {
+		t.Errorf("ttl = %d, want %d", kresp.TTL, presp.TTL)
+	}
+
+	if _, err = lapi.Revoke(ctx, presp.ID); err != nil {
+		t.Fatalf("failed to revoke lease %v", err)
+	}
+	tresp, err = lapi.TimeToLive(ctx, cresp.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tresp.TTL != -1 {
+		t.Errorf("ttl = %d, want -1", tresp.TTL)
+	}
+	gresp, err := kv.Get(ctx, "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gresp.Kvs) != 0 {
+		t.Errorf("expected foo to be deleted with the child lease, got %v", gresp.Kvs)
+	}
+}
+
 func TestLeaseKeepAliveOnce(t *testing.T) {
 	integration2.BeforeTest(t)
 
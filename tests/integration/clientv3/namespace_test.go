@@ -18,6 +18,7 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	"go.etcd.io/etcd/api/v3/mvccpb"
 	clientv3 "go.etcd.io/etcd/client/v3"
@@ -83,3 +84,34 @@ func TestNamespaceWatch(t *testing.T) {
 	// let client close teardown namespace watch
 	c.Watcher = nsWatcher
 }
+
+func TestNamespaceWatchKeyGlob(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	c := clus.Client(0)
+	nsKV := namespace.NewKV(c.KV, "f*o/")
+	nsWatcher := namespace.NewWatcher(c.Watcher, "f*o/")
+
+	for _, k := range []string{"/nodes/a/config", "/nodes/a/status"} {
+		if _, err := nsKV.Put(context.TODO(), k, "bar"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := c.Put(context.TODO(), "fxo//nodes/b/status", "bar"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the pattern matches the keys of the namespace, not the prefixed ones
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	nsWch := nsWatcher.Watch(ctx, "/nodes/", clientv3.WithPrefix(), clientv3.WithRev(1), clientv3.WithKeyGlob("/nodes/*/status"))
+	if wr := <-nsWch; len(wr.Events) != 1 || string(wr.Events[0].Kv.Key) != "/nodes/a/status" {
+		t.Errorf("expected an event of %q, got %+v", "/nodes/a/status", wr.Events)
+	}
+
+	// let client close teardown namespace watch
+	c.Watcher = nsWatcher
+}
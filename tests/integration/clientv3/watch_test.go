@@ -400,6 +400,59 @@ func TestWatchResumeInitRev(t *testing.T) {
 	}
 }
 
+// TestWatchResumeToken ensures that a filtered watcher resumes right after its
+// last response, both on reconnect and when created with the resume token.
+func TestWatchResumeToken(t *testing.T) {
+	integration2.BeforeTest(t)
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 2, UseBridge: true})
+	defer clus.Terminate(t)
+
+	cli := clus.Client(0)
+	opts := []clientv3.OpOption{clientv3.WithPrefix(), clientv3.WithFilterDelete()}
+	wch := cli.Watch(context.Background(), "a", opts...)
+	if _, err := cli.Put(context.TODO(), "a1", "1"); err != nil {
+		t.Fatal(err)
+	}
+	resp := <-wch
+	if len(resp.Events) != 1 || resp.ResumeToken == nil {
+		t.Fatalf("expected event with resume token, got %+v", resp)
+	}
+
+	clus.Members[0].Bridge().DropConnections()
+	clus.Members[0].Bridge().PauseConnections()
+	if _, err := clus.Client(1).Delete(context.TODO(), "a1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := clus.Client(1).Put(context.TODO(), "a2", "2"); err != nil {
+		t.Fatal(err)
+	}
+	clus.Members[0].Bridge().UnpauseConnections()
+
+	select {
+	case resp = <-wch:
+		if len(resp.Events) != 1 || string(resp.Events[0].Kv.Key) != "a2" {
+			t.Fatalf("expected event on a2 after reconnect, got %+v", resp)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch timed out")
+	}
+
+	if _, err := cli.Put(context.TODO(), "a3", "3"); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	wch = cli.Watch(ctx, "a", append(opts, clientv3.WithResumeToken(resp.ResumeToken))...)
+	select {
+	case resp = <-wch:
+		if len(resp.Events) != 1 || string(resp.Events[0].Kv.Key) != "a3" {
+			t.Fatalf("expected event on a3 after resume, got %+v", resp)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch timed out")
+	}
+}
+
 // TestWatchResumeCompacted checks that the watcher gracefully closes in case
 // that it tries to resume to a revision that's been compacted out of the store.
 // Since the watcher's server restarts with stale data, the watcher will receive
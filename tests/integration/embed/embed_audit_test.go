@@ -0,0 +1,101 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !cluster_proxy
+
+package embed_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.etcd.io/etcd/client/pkg/v3/testutil"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/embed"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3audit"
+	integration2 "go.etcd.io/etcd/tests/v3/framework/integration"
+)
+
+// TestEmbedEtcdAudit ensures that the mutations and admin operations served by
+// etcd are recorded to the audit log, along with the client certificate.
+func TestEmbedEtcdAudit(t *testing.T) {
+	testutil.SkipTestIfShortMode(t, "Cannot start embedded cluster in --short tests")
+
+	cfg := embed.NewConfig()
+	cfg.ClientTLSInfo = testTLSInfo
+	cfg.PeerTLSInfo = testTLSInfo
+	urls := newEmbedURLs(true, 2)
+	setupEmbedCfg(cfg, []url.URL{urls[0]}, []url.URL{urls[1]})
+	cfg.Dir = filepath.Join(t.TempDir(), "embed-etcd")
+	auditPath := filepath.Join(t.TempDir(), "audit.log")
+	cfg.ExperimentalAuditSink = v3audit.SinkFile
+	cfg.ExperimentalAuditFile = auditPath
+	cfg.ExperimentalAuditLevel = "request"
+
+	e, err := embed.StartEtcd(cfg)
+	require.NoError(t, err)
+	<-e.Server.ReadyNotify()
+
+	tls, err := testTLSInfo.ClientConfig()
+	require.NoError(t, err)
+	cli, err := integration2.NewClient(t, clientv3.Config{Endpoints: []string{urls[0].String()}, TLS: tls})
+	require.NoError(t, err)
+	defer cli.Close()
+
+	ctx := context.Background()
+	presp, err := cli.Put(ctx, "foo", "bar")
+	require.NoError(t, err)
+	_, err = cli.Get(ctx, "foo")
+	require.NoError(t, err)
+	_, err = cli.UserAdd(ctx, "alice", "secret")
+	require.NoError(t, err)
+	e.Close()
+
+	f, err := os.Open(auditPath)
+	require.NoError(t, err)
+	defer f.Close()
+	events := make(map[string]v3audit.Event)
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var ev v3audit.Event
+		require.NoError(t, json.Unmarshal(sc.Bytes(), &ev))
+		events[ev.Stage+"/"+ev.RPC] = ev
+	}
+	require.NoError(t, sc.Err())
+
+	// reads are not audited by default
+	assert.NotContains(t, events, v3audit.StageRPC+"/Range")
+
+	put := events[v3audit.StageRPC+"/Put"]
+	assert.Equal(t, "example.com", put.CommonName)
+	assert.NotEmpty(t, put.RemoteAddr)
+	assert.Equal(t, []v3audit.KeyRange{{Key: "foo"}}, put.Ranges)
+	assert.Equal(t, presp.Header.Revision, put.Revision)
+	assert.Equal(t, v3audit.OutcomeSuccess, put.Outcome)
+	assert.Equal(t, presp.Header.Revision, events[v3audit.StageApply+"/Put"].Revision)
+
+	for _, stage := range []string{v3audit.StageRPC, v3audit.StageApply} {
+		ev, ok := events[stage+"/UserAdd"]
+		require.Truef(t, ok, "missing %s UserAdd event", stage)
+		assert.Equal(t, map[string]string{"user": "alice"}, ev.Details)
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcproxy
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/proxy/grpcproxy"
+	integration2 "go.etcd.io/etcd/tests/v3/framework/integration"
+)
+
+// TestAuthMirrorProxy ensures that the reads served from the proxy cache and
+// the watches of the proxy are only permitted to the users the cluster permits.
+func TestAuthMirrorProxy(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	cli := clus.Client(0)
+	for _, name := range []string{"root", "alice", "bob"} {
+		if _, err := cli.UserAdd(ctx, name, "123"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := cli.RoleAdd(ctx, "root"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cli.UserGrantRole(ctx, "root", "root"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cli.RoleAdd(ctx, "foo"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cli.RoleGrantPermission(ctx, "foo", "foo", "", clientv3.PermissionType(clientv3.PermReadWrite)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cli.UserGrantRole(ctx, "alice", "foo"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cli.Put(ctx, "foo", "bar"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cli.AuthEnable(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// the proxy forwards the tokens of its clients, and syncs the auth store
+	// with the token of root
+	pcli, err := integration2.NewClient(t, clientv3.Config{
+		Endpoints:   []string{clus.Members[0].GRPCURL()},
+		DialTimeout: 5 * time.Second,
+		DialOptions: []grpc.DialOption{
+			grpc.WithUnaryInterceptor(grpcproxy.AuthUnaryClientInterceptor),
+			grpc.WithStreamInterceptor(grpcproxy.AuthStreamClientInterceptor),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pcli.Close()
+	aresp, err := pcli.Authenticate(ctx, "root", "123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	am := grpcproxy.NewAuthMirror(context.WithValue(pcli.Ctx(), rpctypes.TokenFieldNameGRPC, aresp.Token), zaptest.NewLogger(t), pcli, time.Second)
+	kvp, _ := grpcproxy.NewKvProxyWithAuth(pcli, am)
+	wp, _ := grpcproxy.NewWatchProxyWithAuth(pcli.Ctx(), zaptest.NewLogger(t), pcli, am)
+	srv := grpc.NewServer()
+	pb.RegisterKVServer(srv, kvp)
+	pb.RegisterWatchServer(srv, wp)
+	pb.RegisterAuthServer(srv, grpcproxy.NewAuthProxy(pcli))
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Serve(l)
+	defer srv.Stop()
+
+	userCli := func(name string) *clientv3.Client {
+		c, cerr := integration2.NewClient(t, clientv3.Config{
+			Endpoints:   []string{l.Addr().String()},
+			DialTimeout: 5 * time.Second,
+			Username:    name,
+			Password:    "123",
+		})
+		if cerr != nil {
+			t.Fatal(cerr)
+		}
+		return c
+	}
+	alice, bob := userCli("alice"), userCli("bob")
+	defer alice.Close()
+	defer bob.Close()
+
+	// cache the key for alice
+	if _, err = alice.Get(ctx, "foo", clientv3.WithSerializable()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = bob.Get(ctx, "foo", clientv3.WithSerializable()); err != rpctypes.ErrPermissionDenied {
+		t.Fatalf("expected %v reading the cached key, got %v", rpctypes.ErrPermissionDenied, err)
+	}
+	wresp := <-bob.Watch(ctx, "foo")
+	if !wresp.Canceled {
+		t.Fatalf("expected the watch of bob to be canceled, got %+v", wresp)
+	}
+	wch := alice.Watch(ctx, "foo")
+	if _, err = alice.Put(ctx, "foo", "baz"); err != nil {
+		t.Fatal(err)
+	}
+	if wresp = <-wch; wresp.Canceled || len(wresp.Events) != 1 {
+		t.Fatalf("expected the put to be watched by alice, got %+v", wresp)
+	}
+
+	// revoking the role must deny the cached key
+	if _, err = alice.Get(ctx, "foo", clientv3.WithSerializable()); err != nil {
+		t.Fatal(err)
+	}
+	root, err := integration2.NewClient(t, clientv3.Config{
+		Endpoints:   []string{clus.Members[0].GRPCURL()},
+		DialTimeout: 5 * time.Second,
+		Username:    "root",
+		Password:    "123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer root.Close()
+	if _, err = root.UserRevokeRole(ctx, "alice", "foo"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = alice.Get(ctx, "foo", clientv3.WithSerializable()); err != rpctypes.ErrPermissionDenied {
+		t.Fatalf("expected %v reading the cached key after the role was revoked, got %v", rpctypes.ErrPermissionDenied, err)
+	}
+}
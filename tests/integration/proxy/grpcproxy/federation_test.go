@@ -0,0 +1,170 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package grpcproxy
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/proxy/grpcproxy"
+	integration2 "go.etcd.io/etcd/tests/v3/framework/integration"
+)
+
+// TestFederationProxy ensures that the federation proxy routes the requests and
+// watches to the cluster owning their keys, and rejects the transactions
+// spanning several clusters and the leases and auth tokens of the default
+// cluster used on the keys of another one.
+func TestFederationProxy(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	// the members of both clusters are named alike, so they cannot listen on
+	// unix sockets
+	clus1 := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1, UseTCP: true})
+	defer clus1.Terminate(t)
+	clus2 := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1, UseTCP: true})
+	defer clus2.Terminate(t)
+
+	lg := zaptest.NewLogger(t)
+	c1, err := integration2.NewClient(t, clientv3.Config{Endpoints: []string{clus1.Members[0].GRPCURL()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c1.Close()
+	c2, err := integration2.NewClient(t, clientv3.Config{Endpoints: []string{clus2.Members[0].GRPCURL()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c2.Close()
+	def, other := &grpcproxy.Backend{Name: "default", Client: c1}, &grpcproxy.Backend{Name: "other", Client: c2}
+	rt := grpcproxy.NewRouter(c1.Ctx(), lg, def, map[string]*grpcproxy.Backend{"b/": other})
+
+	kvp, _ := grpcproxy.NewFederatedKvProxy(rt)
+	watchp, _ := grpcproxy.NewFederatedWatchProxy(c1.Ctx(), lg, rt)
+	leasep, _ := grpcproxy.NewLeaseProxy(c1.Ctx(), c1)
+	server := grpc.NewServer()
+	pb.RegisterKVServer(server, kvp)
+	pb.RegisterWatchServer(server, watchp)
+	pb.RegisterLeaseServer(server, leasep)
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go server.Serve(l)
+	defer server.Stop()
+
+	pcli, err := integration2.NewClient(t, clientv3.Config{Endpoints: []string{l.Addr().String()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pcli.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	wch := pcli.Watch(ctx, "b/", clientv3.WithPrefix(), clientv3.WithCreatedNotify())
+	if wresp := <-wch; !wresp.Created {
+		t.Fatalf("expected the watch to be created, got %+v", wresp)
+	}
+
+	if _, err = pcli.Put(ctx, "a/1", "1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = pcli.Put(ctx, "b/1", "2"); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		c   *clientv3.Client
+		key string
+		ok  bool
+	}{
+		{c1, "a/1", true},
+		{c1, "b/1", false},
+		{c2, "a/1", false},
+		{c2, "b/1", true},
+	} {
+		resp, err := tt.c.Get(ctx, tt.key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok := len(resp.Kvs) == 1; ok != tt.ok {
+			t.Errorf("expected %s to be in cluster %v: %v, got %v", tt.key, tt.c.Endpoints(), tt.ok, ok)
+		}
+	}
+	resp, err := pcli.Get(ctx, "b/", clientv3.WithPrefix())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Kvs) != 1 || string(resp.Kvs[0].Value) != "2" {
+		t.Fatalf("expected b/1=2, got %+v", resp.Kvs)
+	}
+
+	select {
+	case wresp := <-wch:
+		if len(wresp.Events) != 1 || string(wresp.Events[0].Kv.Key) != "b/1" {
+			t.Fatalf("expected the put of b/1, got %+v", wresp.Events)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for the put of b/1")
+	}
+
+	_, err = pcli.Txn(ctx).If(clientv3.Compare(clientv3.Version("b/1"), "=", 1)).Then(clientv3.OpPut("b/2", "3")).Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = pcli.Txn(ctx).Then(clientv3.OpPut("a/2", "4"), clientv3.OpPut("b/3", "5")).Commit(); err == nil {
+		t.Fatal("expected the transaction spanning both clusters to be rejected")
+	}
+	if _, err = pcli.Get(ctx, "", clientv3.WithFromKey()); err == nil {
+		t.Fatal("expected the range spanning both clusters to be rejected")
+	}
+
+	// a watch spanning both clusters is canceled on creation
+	wresp := <-pcli.Watch(ctx, "", clientv3.WithFromKey())
+	if !wresp.Canceled {
+		t.Fatalf("expected the watch spanning both clusters to be canceled, got %+v", wresp)
+	}
+
+	// leases are granted by the default cluster, so they can only be attached
+	// to its keys
+	lresp, err := pcli.Grant(ctx, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = pcli.Put(ctx, "a/3", "6", clientv3.WithLease(lresp.ID)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = pcli.Put(ctx, "b/4", "7", clientv3.WithLease(lresp.ID)); err == nil || err.Error() != grpcproxy.ErrGRPCFederatedLease.Error() {
+		t.Fatalf("expected %v attaching a lease to a key of the other cluster, got %v", grpcproxy.ErrGRPCFederatedLease, err)
+	}
+	nested := clientv3.OpTxn(nil, []clientv3.Op{clientv3.OpPut("b/4", "7", clientv3.WithLease(lresp.ID))}, nil)
+	if _, err = pcli.Txn(ctx).Then(nested).Commit(); err == nil || err.Error() != grpcproxy.ErrGRPCFederatedLease.Error() {
+		t.Fatalf("expected %v attaching a lease in a transaction of the other cluster, got %v", grpcproxy.ErrGRPCFederatedLease, err)
+	}
+
+	// auth tokens are issued by the default cluster, so they cannot access the
+	// keys of the other cluster
+	tctx := metadata.AppendToOutgoingContext(ctx, rpctypes.TokenFieldNameGRPC, "token")
+	if _, err = pcli.Get(tctx, "b/1"); err == nil || err.Error() != grpcproxy.ErrGRPCFederatedAuth.Error() {
+		t.Fatalf("expected %v reading a key of the other cluster with a token, got %v", grpcproxy.ErrGRPCFederatedAuth, err)
+	}
+}
@@ -16,10 +16,13 @@ package grpcproxy
 
 import (
 	"context"
+	"fmt"
 	"net"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap/zaptest"
 	"google.golang.org/grpc"
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
@@ -53,6 +56,117 @@ func TestKVProxyRange(t *testing.T) {
 	client.Close()
 }
 
+func TestKVProxyReplica(t *testing.T) {
+	integration2.BeforeTest(t)
+
+	clus := integration2.NewCluster(t, &integration2.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cli := clus.Client(0)
+	presp, err := cli.Put(ctx, "foo/a", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kvts := newKVProxyServerWithReplica([]string{clus.Members[0].GRPCURL()}, "foo/", t)
+	defer kvts.close()
+	pcli, err := integration2.NewClient(t, clientv3.Config{Endpoints: []string{kvts.l.Addr().String()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pcli.Close()
+
+	// wait for the replica to serve the prefix
+	hits := replicaHits(t)
+	for replicaHits(t) == hits {
+		if _, err = pcli.Get(ctx, "foo/", clientv3.WithPrefix(), clientv3.WithSerializable()); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case <-time.After(10 * time.Millisecond):
+		case <-ctx.Done():
+			t.Fatal("replica never served a range")
+		}
+	}
+
+	// linearizable ranges see the writes made to the cluster before them
+	for i := 0; i < 10; i++ {
+		if _, err = cli.Put(ctx, "foo/b", fmt.Sprint(i)); err != nil {
+			t.Fatal(err)
+		}
+		hits = replicaHits(t)
+		resp, err := pcli.Get(ctx, "foo/b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Kvs) != 1 || string(resp.Kvs[0].Value) != fmt.Sprint(i) {
+			t.Fatalf("expected foo/b=%d, got %+v", i, resp.Kvs)
+		}
+		if replicaHits(t) == hits {
+			t.Fatalf("expected linearizable range %d to be served by the replica", i)
+		}
+	}
+
+	// historic ranges are served from the history of the replica
+	if _, err = cli.Delete(ctx, "foo/a"); err != nil {
+		t.Fatal(err)
+	}
+	hits = replicaHits(t)
+	resp, err := pcli.Get(ctx, "foo/", clientv3.WithPrefix(), clientv3.WithRev(presp.Header.Revision))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Kvs) != 1 || string(resp.Kvs[0].Key) != "foo/a" || resp.Count != 1 {
+		t.Fatalf("expected only foo/a at revision %d, got %+v", presp.Header.Revision, resp.Kvs)
+	}
+	if resp, err = pcli.Get(ctx, "foo/", clientv3.WithPrefix(), clientv3.WithCountOnly()); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Count != 1 {
+		t.Fatalf("expected 1 key, got %d", resp.Count)
+	}
+	if replicaHits(t) != hits+2 {
+		t.Fatalf("expected both ranges to be served by the replica")
+	}
+
+	// the keys out of the prefix are forwarded
+	if _, err = cli.Put(ctx, "bar", "1"); err != nil {
+		t.Fatal(err)
+	}
+	if resp, err = pcli.Get(ctx, "bar"); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Kvs) != 1 || replicaHits(t) != hits+2 {
+		t.Fatalf("expected bar to be forwarded, got %+v", resp.Kvs)
+	}
+
+	// the replica catches up with the revision of the cluster, advanced by
+	// the write out of the prefix, before serving a linearizable range
+	if resp, err = pcli.Get(ctx, "foo/b"); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Header.Revision < presp.Header.Revision+12 || replicaHits(t) != hits+3 {
+		t.Fatalf("expected foo/b to be served by the replica at the revision of bar, got revision %d", resp.Header.Revision)
+	}
+}
+
+// replicaHits returns the number of ranges served by the replicas of the
+// proxies.
+func replicaHits(t *testing.T) float64 {
+	mfs, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, mf := range mfs {
+		if mf.GetName() == "etcd_grpc_proxy_replica_hits_total" {
+			return mf.GetMetric()[0].GetCounter().GetValue()
+		}
+	}
+	return 0
+}
+
 type kvproxyTestServer struct {
 	kp     pb.KVServer
 	c      *clientv3.Client
@@ -67,6 +181,12 @@ func (kts *kvproxyTestServer) close() {
 }
 
 func newKVProxyServer(endpoints []string, t *testing.T) *kvproxyTestServer {
+	return newKVProxyServerWithReplica(endpoints, "", t)
+}
+
+// newKVProxyServerWithReplica creates a kv proxy which replicates the keys with
+// the prefix, if not empty.
+func newKVProxyServerWithReplica(endpoints []string, prefix string, t *testing.T) *kvproxyTestServer {
 	cfg := clientv3.Config{
 		Endpoints:   endpoints,
 		DialTimeout: 5 * time.Second,
@@ -76,7 +196,11 @@ func newKVProxyServer(endpoints []string, t *testing.T) *kvproxyTestServer {
 		t.Fatal(err)
 	}
 
-	kvp, _ := grpcproxy.NewKvProxy(client)
+	var replica *grpcproxy.KVReplica
+	if prefix != "" {
+		replica = grpcproxy.NewKVReplica(client.Ctx(), zaptest.NewLogger(t), client, prefix, 100)
+	}
+	kvp, _ := grpcproxy.NewKvProxyWithReplica(client, nil, replica)
 
 	kvts := &kvproxyTestServer{
 		kp: kvp,
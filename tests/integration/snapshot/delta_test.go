@@ -0,0 +1,183 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+
+	"go.etcd.io/etcd/client/pkg/v3/testutil"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	clientsnapshot "go.etcd.io/etcd/client/v3/snapshot"
+	"go.etcd.io/etcd/etcdutl/v3/snapshot"
+	"go.etcd.io/etcd/server/v3/embed"
+	integration2 "go.etcd.io/etcd/tests/v3/framework/integration"
+)
+
+// TestSnapshotV3RestoreDeltas ensures that a snapshot restored along with the
+// deltas saved since has the same key space, leases and users as the cluster
+// the deltas were saved from.
+func TestSnapshotV3RestoreDeltas(t *testing.T) {
+	integration2.BeforeTest(t)
+	kvs := []kv{{"foo1", "bar1"}, {"foo2", "bar2"}, {"foo3", "bar3"}}
+	srv, ccfg, dbPath := startSnapshotCluster(t, kvs)
+	defer srv.Close()
+
+	lg := zaptest.NewLogger(t)
+	st, err := snapshot.NewV3(lg).Status(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cli, err := integration2.NewClient(t, ccfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.RequestTimeout)
+	defer cancel()
+
+	lresp, err := cli.Grant(ctx, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cli.Put(ctx, "foo5", "bar5", clientv3.WithLease(lresp.ID)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cli.Delete(ctx, "foo1"); err != nil {
+		t.Fatal(err)
+	}
+	delta1 := filepath.Join(t.TempDir(), "delta1.db")
+	rev1, err := clientsnapshot.SaveDelta(ctx, lg, ccfg, st.Revision, 0, delta1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// compacting the cluster must not affect the deltas saved after
+	if _, err = cli.Compact(ctx, rev1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cli.Put(ctx, "foo2", "baz2"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = cli.UserAdd(ctx, "alice", "pass"); err != nil {
+		t.Fatal(err)
+	}
+	delta2 := filepath.Join(t.TempDir(), "delta2.db")
+	rev2, err := clientsnapshot.SaveDelta(ctx, lg, ccfg, rev1, 0, delta2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hresp, err := cli.HashKV(ctx, ccfg.Endpoints[0], rev2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// deltas must be replayed in order
+	cfg := newRestoreConfig(t, "s1")
+	err = restoreDeltas(t, cfg, dbPath, delta2, delta1)
+	if err == nil || !strings.Contains(err.Error(), "delta starts at revision") {
+		t.Fatalf("expected out of order deltas to be rejected, got %v", err)
+	}
+
+	cfg = newRestoreConfig(t, "s2")
+	if err = restoreDeltas(t, cfg, dbPath, delta1, delta2); err != nil {
+		t.Fatal(err)
+	}
+	rsrv, err := embed.StartEtcd(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rsrv.Close()
+	select {
+	case <-rsrv.Server.ReadyNotify():
+	case <-time.After(3 * time.Second):
+		t.Fatalf("failed to start restored etcd member")
+	}
+
+	endpoint := cfg.AdvertiseClientUrls[0].String()
+	rcli, err := integration2.NewClient(t, clientv3.Config{Endpoints: []string{endpoint}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rcli.Close()
+	rhresp, err := rcli.HashKV(ctx, endpoint, rev2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rhresp.Hash != hresp.Hash || rhresp.CompactRevision != rev1 {
+		t.Fatalf("expected hash %d compacted at %d, got hash %d compacted at %d", hresp.Hash, rev1, rhresp.Hash, rhresp.CompactRevision)
+	}
+
+	gresp, err := rcli.Get(ctx, "foo", clientv3.WithPrefix())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []kv{{"foo2", "baz2"}, {"foo3", "bar3"}, {"foo4", "bar4"}, {"foo5", "bar5"}}
+	if len(gresp.Kvs) != len(want) {
+		t.Fatalf("expected %d keys, got %d", len(want), len(gresp.Kvs))
+	}
+	for i := range want {
+		if string(gresp.Kvs[i].Key) != want[i].k || string(gresp.Kvs[i].Value) != want[i].v {
+			t.Fatalf("#%d: expected %s=%s, got %s=%s", i, want[i].k, want[i].v, gresp.Kvs[i].Key, gresp.Kvs[i].Value)
+		}
+	}
+	if gresp.Header.Revision != rev2 {
+		t.Fatalf("expected revision %d, got %d", rev2, gresp.Header.Revision)
+	}
+
+	ttl, err := rcli.TimeToLive(ctx, lresp.ID, clientv3.WithAttachedKeys())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ttl.TTL <= 0 || len(ttl.Keys) != 1 || string(ttl.Keys[0]) != "foo5" {
+		t.Fatalf("expected lease %x to be restored with key foo5, got %+v", lresp.ID, ttl)
+	}
+	if _, err = rcli.UserGet(ctx, "alice"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newRestoreConfig(t *testing.T, name string) *embed.Config {
+	urls := newEmbedURLs(t, 2)
+	cURLs, pURLs := urls[:1], urls[1:]
+
+	cfg := integration2.NewEmbedConfig(t, name)
+	cfg.InitialClusterToken = testClusterTkn
+	cfg.ClusterState = "existing"
+	cfg.ListenClientUrls, cfg.AdvertiseClientUrls = cURLs, cURLs
+	cfg.ListenPeerUrls, cfg.AdvertisePeerUrls = pURLs, pURLs
+	cfg.InitialCluster = fmt.Sprintf("%s=%s", cfg.Name, pURLs[0].String())
+	return cfg
+}
+
+func restoreDeltas(t *testing.T, cfg *embed.Config, dbPath string, deltas ...string) error {
+	sp := snapshot.NewV3(zaptest.NewLogger(t))
+	return sp.Restore(snapshot.RestoreConfig{
+		SnapshotPath:        dbPath,
+		Name:                cfg.Name,
+		OutputDataDir:       cfg.Dir,
+		InitialCluster:      cfg.InitialCluster,
+		InitialClusterToken: cfg.InitialClusterToken,
+		PeerURLs:            []string{cfg.AdvertisePeerUrls[0].String()},
+		Deltas:              deltas,
+	})
+}
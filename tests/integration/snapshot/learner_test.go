@@ -0,0 +1,207 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package snapshot_test
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zaptest"
+
+	"go.etcd.io/etcd/client/pkg/v3/testutil"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/etcdutl/v3/snapshot"
+	"go.etcd.io/etcd/server/v3/embed"
+	integration2 "go.etcd.io/etcd/tests/v3/framework/integration"
+)
+
+// TestSnapshotV3RestoreLearner ensures that a learner restored from a snapshot
+// of a running cluster joins it and catches up with the writes made since.
+func TestSnapshotV3RestoreLearner(t *testing.T) {
+	integration2.BeforeTest(t)
+	kvs := []kv{{"foo1", "bar1"}, {"foo2", "bar2"}, {"foo3", "bar3"}}
+	srv, ccfg, dbPath := startSnapshotCluster(t, kvs)
+	defer srv.Close()
+
+	cfg := newLearnerConfig(t, srv)
+	sp := snapshot.NewV3(zaptest.NewLogger(t))
+	if err := sp.Restore(snapshot.RestoreConfig{
+		SnapshotPath:  dbPath,
+		Name:          cfg.Name,
+		OutputDataDir: cfg.Dir,
+		PeerURLs:      []string{cfg.AdvertisePeerUrls[0].String()},
+		Join:          &ccfg,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	checkLearner(t, ccfg, cfg, kvs)
+}
+
+// TestSnapshotV3RestoreLearnerOnStart ensures that a member added as a learner
+// seeds its data directory from the snapshot it is started with.
+func TestSnapshotV3RestoreLearnerOnStart(t *testing.T) {
+	integration2.BeforeTest(t)
+	kvs := []kv{{"foo1", "bar1"}, {"foo2", "bar2"}, {"foo3", "bar3"}}
+	srv, ccfg, dbPath := startSnapshotCluster(t, kvs)
+	defer srv.Close()
+
+	cfg := newLearnerConfig(t, srv)
+	cfg.ExperimentalLearnerSnapshot = dbPath
+	cli, err := integration2.NewClient(t, ccfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+	if _, err = cli.MemberAddAsLearner(context.Background(), []string{cfg.AdvertisePeerUrls[0].String()}); err != nil {
+		t.Fatal(err)
+	}
+
+	checkLearner(t, ccfg, cfg, kvs)
+}
+
+// startSnapshotCluster starts a single member cluster, puts the given keys and
+// saves a snapshot of it, then puts "foo4" so that the snapshot lags behind.
+func startSnapshotCluster(t *testing.T, kvs []kv) (*embed.Etcd, clientv3.Config, string) {
+	testutil.SkipTestIfShortMode(t,
+		"Snapshot creation tests are depending on embedded etcd server so are integration-level tests.")
+	urls := newEmbedURLs(t, 2)
+	cURLs, pURLs := urls[:1], urls[1:]
+
+	cfg := integration2.NewEmbedConfig(t, "default")
+	cfg.ClusterState = "new"
+	cfg.ListenClientUrls, cfg.AdvertiseClientUrls = cURLs, cURLs
+	cfg.ListenPeerUrls, cfg.AdvertisePeerUrls = pURLs, pURLs
+	cfg.InitialCluster = fmt.Sprintf("%s=%s", cfg.Name, pURLs[0].String())
+	srv, err := embed.StartEtcd(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-srv.Server.ReadyNotify():
+	case <-time.After(3 * time.Second):
+		srv.Close()
+		t.Fatalf("failed to start embed.Etcd for creating snapshots")
+	}
+
+	ccfg := clientv3.Config{Endpoints: []string{cURLs[0].String()}}
+	cli, err := integration2.NewClient(t, ccfg)
+	if err != nil {
+		srv.Close()
+		t.Fatal(err)
+	}
+	defer cli.Close()
+	put := func(kvs []kv) {
+		for i := range kvs {
+			ctx, cancel := context.WithTimeout(context.Background(), testutil.RequestTimeout)
+			_, err = cli.Put(ctx, kvs[i].k, kvs[i].v)
+			cancel()
+			if err != nil {
+				srv.Close()
+				t.Fatal(err)
+			}
+		}
+	}
+	put(kvs)
+
+	sp := snapshot.NewV3(zaptest.NewLogger(t))
+	dbPath := filepath.Join(t.TempDir(), "snapshot.db")
+	if _, err = sp.Save(context.Background(), ccfg, dbPath); err != nil {
+		srv.Close()
+		t.Fatal(err)
+	}
+	put([]kv{{"foo4", "bar4"}})
+	return srv, ccfg, dbPath
+}
+
+func newLearnerConfig(t *testing.T, srv *embed.Etcd) *embed.Config {
+	urls := newEmbedURLs(t, 2)
+	cURLs, pURLs := urls[:1], urls[1:]
+
+	cfg := integration2.NewEmbedConfig(t, "learner")
+	cfg.ClusterState = "existing"
+	cfg.ListenClientUrls, cfg.AdvertiseClientUrls = cURLs, cURLs
+	cfg.ListenPeerUrls, cfg.AdvertisePeerUrls = pURLs, pURLs
+	cfg.InitialCluster = fmt.Sprintf("%s=%s,%s=%s",
+		srv.Config().Name, srv.Config().AdvertisePeerUrls[0].String(), cfg.Name, pURLs[0].String())
+	return cfg
+}
+
+// checkLearner starts the learner, waits for it to serve all the keys of kvs
+// and the key put after the snapshot, then promotes it.
+func checkLearner(t *testing.T, ccfg clientv3.Config, cfg *embed.Config, kvs []kv) {
+	srv, err := embed.StartEtcd(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Close()
+	select {
+	case <-srv.Server.ReadyNotify():
+	case <-time.After(10 * time.Second):
+		t.Fatalf("failed to start the learner")
+	}
+	if !srv.Server.IsLearner() {
+		t.Fatalf("expected the restored member to be a learner")
+	}
+
+	lcli, err := integration2.NewClient(t, clientv3.Config{Endpoints: []string{cfg.AdvertiseClientUrls[0].String()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lcli.Close()
+	want := append(kvs, kv{"foo4", "bar4"})
+	var gresp *clientv3.GetResponse
+	for i := 0; i < 50; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.RequestTimeout)
+		gresp, err = lcli.Get(ctx, "foo", clientv3.WithPrefix(), clientv3.WithSerializable())
+		cancel()
+		if err == nil && len(gresp.Kvs) == len(want) {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(gresp.Kvs) != len(want) {
+		t.Fatalf("expected %d keys, got %d", len(want), len(gresp.Kvs))
+	}
+	for i := range gresp.Kvs {
+		if string(gresp.Kvs[i].Key) != want[i].k || string(gresp.Kvs[i].Value) != want[i].v {
+			t.Fatalf("#%d: expected %s=%s, got %s=%s", i, want[i].k, want[i].v, gresp.Kvs[i].Key, gresp.Kvs[i].Value)
+		}
+	}
+
+	cli, err := integration2.NewClient(t, ccfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cli.Close()
+	for i := 0; ; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), testutil.RequestTimeout)
+		_, err = cli.MemberPromote(ctx, uint64(srv.Server.MemberId()))
+		cancel()
+		if err == nil {
+			break
+		}
+		if i == 50 {
+			t.Fatalf("failed to promote the learner: %v", err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
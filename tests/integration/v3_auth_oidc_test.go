@@ -0,0 +1,145 @@
+// Copyright 2023 The etcd Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"go.etcd.io/etcd/api/v3/authpb"
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/tests/v3/framework/integration"
+)
+
+const testOIDCIssuer = "https://issuer.example.com"
+
+// TestV3AuthOIDC ensures that the users authenticated by an external issuer are
+// granted the permissions of the roles mapped from the groups of their token,
+// including on the members applying their requests.
+func TestV3AuthOIDC(t *testing.T) {
+	integration.BeforeTest(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc := base64.RawURLEncoding.EncodeToString
+	jwks, err := json.Marshal(map[string]interface{}{"keys": []map[string]string{
+		{"kid": "k1", "kty": "EC", "crv": "P-256", "x": enc(key.X.FillBytes(make([]byte, 32))), "y": enc(key.Y.FillBytes(make([]byte, 32)))},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	jwksPath := filepath.Join(t.TempDir(), "jwks.json")
+	if err = os.WriteFile(jwksPath, jwks, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	clus := integration.NewCluster(t, &integration.ClusterConfig{
+		Size:      3,
+		AuthToken: fmt.Sprintf("oidc,issuer=%s,jwks=%s,groups-claim=groups", testOIDCIssuer, jwksPath),
+	})
+	defer clus.Terminate(t)
+
+	api := integration.ToGRPC(clus.Client(0))
+	if _, err = api.Auth.RoleAdd(context.TODO(), &pb.AuthRoleAddRequest{Name: "readers"}); err != nil {
+		t.Fatal(err)
+	}
+	perm := &authpb.Permission{PermType: authpb.READ, Key: []byte("foo")}
+	if _, err = api.Auth.RoleGrantPermission(context.TODO(), &pb.AuthRoleGrantPermissionRequest{Name: "readers", Perm: perm}); err != nil {
+		t.Fatal(err)
+	}
+	authSetupRoot(t, api.Auth)
+
+	newClient := func(groups ...string) *clientv3.Client {
+		token := testOIDCToken(t, key, map[string]interface{}{
+			"iss":    testOIDCIssuer,
+			"sub":    "workload",
+			"exp":    time.Now().Add(time.Hour).Unix(),
+			"groups": groups,
+		})
+		cli, cerr := integration.NewClient(t, clientv3.Config{
+			Endpoints:   clus.Client(0).Endpoints(),
+			DialOptions: []grpc.DialOption{grpc.WithPerRPCCredentials(oidcCredentials(token))},
+		})
+		if cerr != nil {
+			t.Fatal(cerr)
+		}
+		return cli
+	}
+
+	admin := newClient("root")
+	defer admin.Close()
+	if _, err = admin.Put(context.TODO(), "foo", "bar"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = admin.UserList(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := newClient("readers")
+	defer reader.Close()
+	if _, err = reader.Get(context.TODO(), "foo"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = reader.Put(context.TODO(), "foo", "baz"); err != rpctypes.ErrPermissionDenied {
+		t.Fatalf("expected %v, got %v", rpctypes.ErrPermissionDenied, err)
+	}
+	if _, err = reader.UserList(context.TODO()); err != rpctypes.ErrUserNotFound {
+		t.Fatalf("expected %v, got %v", rpctypes.ErrUserNotFound, err)
+	}
+}
+
+type oidcCredentials string
+
+func (c oidcCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{rpctypes.TokenFieldNameGRPC: string(c)}, nil
+}
+
+func (c oidcCredentials) RequireTransportSecurity() bool { return false }
+
+// testOIDCToken returns an ES256 signed JWT of the claims.
+func testOIDCToken(t *testing.T, key *ecdsa.PrivateKey, claims map[string]interface{}) string {
+	enc := base64.RawURLEncoding.EncodeToString
+	header, err := json.Marshal(map[string]string{"alg": "ES256", "typ": "JWT", "kid": "k1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := enc(header) + "." + enc(payload)
+	digest := sha256.Sum256([]byte(signed))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed + "." + enc(append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...))
+}
@@ -27,6 +27,8 @@ import (
 	"go.etcd.io/etcd/client/pkg/v3/testutil"
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.etcd.io/etcd/tests/v3/framework/integration"
+
+	"google.golang.org/grpc/metadata"
 )
 
 // TestV3AuthEmptyUserGet ensures that a get with an empty user will return an empty user error.
@@ -506,3 +508,383 @@ func TestV3AuthWatchErrorAndWatchId0(t *testing.T) {
 
 	<-watchEndCh
 }
+
+// TestV3AuthFinerPermissions ensures a role permitted to create and watch keys
+// can neither update, delete nor range them.
+func TestV3AuthFinerPermissions(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Second)
+	defer cancel()
+
+	authc := integration.ToGRPC(clus.Client(0)).Auth
+	authSetupUsers(t, authc, []user{{name: "user1", password: "user1-123", role: "role1"}})
+	for _, typ := range []authpb.Permission_Type{authpb.CREATE, authpb.WATCH} {
+		perm := &authpb.Permission{PermType: typ, Key: []byte("jobs/"), RangeEnd: []byte("jobs0")}
+		if _, err := authc.RoleGrantPermission(ctx, &pb.AuthRoleGrantPermissionRequest{Name: "role1", Perm: perm}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	authSetupRoot(t, authc)
+
+	c, cerr := integration.NewClient(t, clientv3.Config{Endpoints: clus.Client(0).Endpoints(), Username: "user1", Password: "user1-123"})
+	if cerr != nil {
+		t.Fatal(cerr)
+	}
+	defer c.Close()
+
+	wch := c.Watch(ctx, "jobs/", clientv3.WithPrefix(), clientv3.WithCreatedNotify())
+	if wresp := <-wch; wresp.Err() != nil {
+		t.Fatalf("unexpected error creating watch: %v", wresp.Err())
+	}
+
+	if _, err := c.Put(ctx, "jobs/1", "val"); err != nil {
+		t.Fatalf("unexpected error creating a key: %v", err)
+	}
+	wresp := <-wch
+	if len(wresp.Events) != 1 || string(wresp.Events[0].Kv.Key) != "jobs/1" {
+		t.Fatalf("expected the creation of jobs/1 to be watched, got %+v", wresp)
+	}
+
+	denied := []struct {
+		name string
+		op   clientv3.Op
+	}{
+		{name: "update", op: clientv3.OpPut("jobs/1", "val2")},
+		{name: "delete", op: clientv3.OpDelete("jobs/1")},
+		{name: "range", op: clientv3.OpGet("jobs/", clientv3.WithPrefix())},
+		{name: "create out of range", op: clientv3.OpPut("other", "val")},
+	}
+	for _, tt := range denied {
+		if _, err := c.Do(ctx, tt.op); err != rpctypes.ErrPermissionDenied {
+			t.Errorf("expected %s to be denied, got %v", tt.name, err)
+		}
+	}
+}
+
+// TestV3AuthLimits ensures that the watches, leases, stored bytes and request
+// rate of a user are limited by the limits of the user and of its roles.
+func TestV3AuthLimits(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Second)
+	defer cancel()
+
+	authc := integration.ToGRPC(clus.Client(0)).Auth
+	authSetupUsers(t, authc, []user{{name: "user1", password: "user1-123", role: "role1", key: "jobs/", end: "jobs0"}})
+	limits := &authpb.Limits{MaxWatches: 1, MaxLeases: 1, MaxBytes: 20}
+	if _, err := authc.RoleSetLimits(ctx, &pb.AuthRoleSetLimitsRequest{Role: "role1", Limits: limits}); err != nil {
+		t.Fatal(err)
+	}
+	authSetupRoot(t, authc)
+
+	root, cerr := integration.NewClient(t, clientv3.Config{Endpoints: clus.Client(0).Endpoints(), Username: "root", Password: "123"})
+	if cerr != nil {
+		t.Fatal(cerr)
+	}
+	defer root.Close()
+	c, cerr := integration.NewClient(t, clientv3.Config{Endpoints: clus.Client(0).Endpoints(), Username: "user1", Password: "user1-123"})
+	if cerr != nil {
+		t.Fatal(cerr)
+	}
+	defer c.Close()
+
+	wctx, wcancel := context.WithCancel(ctx)
+	wch := c.Watch(wctx, "jobs/", clientv3.WithPrefix(), clientv3.WithCreatedNotify())
+	if wresp := <-wch; wresp.Err() != nil {
+		t.Fatalf("unexpected error creating watch: %v", wresp.Err())
+	}
+	wctx2, wcancel2 := context.WithCancel(ctx)
+	defer wcancel2()
+	if wresp := <-c.Watch(wctx2, "jobs/1", clientv3.WithCreatedNotify()); wresp.Err() == nil || wresp.Err().Error() != rpctypes.ErrGRPCLimitExceeded.Error() {
+		t.Fatalf("expected %v creating a second watch, got %v", rpctypes.ErrGRPCLimitExceeded, wresp.Err())
+	}
+	wcancel()
+	// the watch is released once canceled
+	var werr error
+	for i := 0; i < 10; i++ {
+		wctx3, wcancel3 := context.WithCancel(ctx)
+		wresp := <-c.Watch(wctx3, "jobs/", clientv3.WithPrefix(), clientv3.WithCreatedNotify())
+		werr = wresp.Err()
+		wcancel3()
+		if werr == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if werr != nil {
+		t.Fatalf("unexpected error creating a watch once canceled: %v", werr)
+	}
+
+	lresp, err := c.Grant(ctx, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.Grant(ctx, 100); err != rpctypes.ErrLimitExceeded {
+		t.Fatalf("expected %v granting a second lease, got %v", rpctypes.ErrLimitExceeded, err)
+	}
+	if _, err = c.Revoke(ctx, lresp.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.Grant(ctx, 100); err != nil {
+		t.Fatalf("unexpected error granting a lease once revoked: %v", err)
+	}
+
+	// keys and values under jobs/ may take up to 20 bytes
+	if _, err = c.Put(ctx, "jobs/1", "0123456789"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.Put(ctx, "jobs/2", "0123456789"); err != rpctypes.ErrLimitExceeded {
+		t.Fatalf("expected %v exceeding the stored bytes, got %v", rpctypes.ErrLimitExceeded, err)
+	}
+	if _, err = root.Put(ctx, "jobs/2", "0123456789"); err != nil {
+		t.Fatalf("expected root to be unlimited, got %v", err)
+	}
+	// deletes free the stored bytes, and puts of nested txns count against them
+	if _, err = root.Delete(ctx, "jobs/", clientv3.WithPrefix()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.Put(ctx, "jobs/1", "0123456789"); err != nil {
+		t.Fatalf("unexpected error storing bytes once deleted: %v", err)
+	}
+	nested := clientv3.OpTxn(nil, []clientv3.Op{clientv3.OpPut("jobs/2", "0123456789")}, nil)
+	if _, err = c.Txn(ctx).Then(nested).Commit(); err != rpctypes.ErrLimitExceeded {
+		t.Fatalf("expected %v exceeding the stored bytes in a nested txn, got %v", rpctypes.ErrLimitExceeded, err)
+	}
+
+	if _, err = root.UserSetLimits(ctx, "user1", clientv3.Limits{RequestRate: 1}); err != nil {
+		t.Fatal(err)
+	}
+	limited := false
+	for i := 0; i < 5 && !limited; i++ {
+		_, err = c.Get(ctx, "jobs/1")
+		switch err {
+		case nil:
+		case rpctypes.ErrLimitExceeded:
+			limited = true
+		default:
+			t.Fatal(err)
+		}
+	}
+	if !limited {
+		t.Fatalf("expected requests exceeding the request rate to be rejected")
+	}
+}
+
+// TestV3AuthRehashPassword ensures passwords are rehashed with the password
+// hash of the member when their users authenticate.
+func TestV3AuthRehashPassword(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	authc := integration.ToGRPC(clus.Client(0)).Auth
+	authSetupUsers(t, authc, []user{{name: "user1", password: "user1-123", role: "role1", key: "foo", end: ""}})
+	authSetupRoot(t, authc)
+
+	m := clus.Members[0]
+	m.Stop(t)
+	m.PasswordHash = "argon2id,time=1,memory=1024,threads=1"
+	if err := m.Restart(t); err != nil {
+		t.Fatal(err)
+	}
+	// waiting for the leader ranges keys, which requires a user
+	<-m.Server.ReadyNotify()
+	if !m.Server.AuthStore().PasswordNeedsRehash("user1") {
+		t.Fatal("expected bcrypt password to need rehash")
+	}
+
+	c, cerr := integration.NewClient(t, clientv3.Config{Endpoints: []string{m.GRPCURL()}, Username: "user1", Password: "user1-123"})
+	if cerr != nil {
+		t.Fatal(cerr)
+	}
+	c.Close()
+	if m.Server.AuthStore().PasswordNeedsRehash("user1") {
+		t.Fatal("expected password to be rehashed with argon2id")
+	}
+
+	c, cerr = integration.NewClient(t, clientv3.Config{Endpoints: []string{m.GRPCURL()}, Username: "user1", Password: "user1-123"})
+	if cerr != nil {
+		t.Fatalf("expected rehashed password to authenticate, got %v", cerr)
+	}
+	c.Close()
+	_, cerr = integration.NewClient(t, clientv3.Config{Endpoints: []string{m.GRPCURL()}, Username: "user1", Password: "wrong"})
+	if cerr != rpctypes.ErrAuthFailed {
+		t.Fatalf("expected %v, got %v", rpctypes.ErrAuthFailed, cerr)
+	}
+}
+
+// TestV3AuthTokenListRevoke ensures that the tokens of a user are listed with
+// their scope, are restricted to it, and are rejected once revoked.
+func TestV3AuthTokenListRevoke(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	authc := integration.ToGRPC(clus.Client(0)).Auth
+	authSetupUsers(t, authc, []user{{name: "user1", password: "user1-123", role: "role1", key: "foo", end: "fop"}})
+	authSetupRoot(t, authc)
+
+	rc, cerr := integration.NewClient(t, clientv3.Config{Endpoints: clus.Client(0).Endpoints(), Username: "root", Password: "123"})
+	if cerr != nil {
+		t.Fatal(cerr)
+	}
+	defer rc.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testutil.RequestTimeout)
+	defer cancel()
+	aresp, err := rc.AuthenticateWithScope(ctx, "user1", "user1-123", clientv3.TokenScope{Prefix: []byte("foo/")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = rc.AuthenticateWithScope(ctx, "user1", "user1-123", clientv3.TokenScope{Roles: []string{"root"}}); err != rpctypes.ErrRoleNotGranted {
+		t.Fatalf("expected %v, got %v", rpctypes.ErrRoleNotGranted, err)
+	}
+
+	lresp, err := rc.TokenList(ctx, "user1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lresp.Tokens) != 1 {
+		t.Fatalf("expected 1 token, got %+v", lresp.Tokens)
+	}
+	tk := lresp.Tokens[0]
+	if tk.Name != "user1" || tk.Created == 0 || tk.Address == "" || tk.Scope == nil || string(tk.Scope.Prefix) != "foo/" {
+		t.Fatalf("unexpected token %+v", tk)
+	}
+
+	kvc := integration.ToGRPC(clus.Client(0)).KV
+	tctx := metadata.NewOutgoingContext(ctx, metadata.Pairs(rpctypes.TokenFieldNameGRPC, aresp.Token))
+	if _, err = kvc.Put(tctx, &pb.PutRequest{Key: []byte("foo/a"), Value: []byte("bar")}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = kvc.Put(tctx, &pb.PutRequest{Key: []byte("foo"), Value: []byte("bar")}); !eqErrGRPC(err, rpctypes.ErrGRPCPermissionDenied) {
+		t.Fatalf("expected %v, got %v", rpctypes.ErrGRPCPermissionDenied, err)
+	}
+
+	if _, err = rc.TokenRevoke(ctx, tk.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = kvc.Put(tctx, &pb.PutRequest{Key: []byte("foo/a"), Value: []byte("bar")}); !eqErrGRPC(err, rpctypes.ErrGRPCInvalidAuthToken) {
+		t.Fatalf("expected %v, got %v", rpctypes.ErrGRPCInvalidAuthToken, err)
+	}
+	if _, err = rc.TokenRevoke(ctx, tk.ID); err != rpctypes.ErrTokenNotFound {
+		t.Fatalf("expected %v, got %v", rpctypes.ErrTokenNotFound, err)
+	}
+}
+
+// TestV3AuthRoleGrantTTL ensures that a role granted with a ttl is revoked once
+// it expires, unless it was granted again without ttl.
+func TestV3AuthRoleGrantTTL(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	authc := integration.ToGRPC(clus.Client(0)).Auth
+	authSetupUsers(t, authc, []user{
+		{name: "user1", password: "user1-123", role: "role1", key: "foo", end: "fop"},
+		{name: "user2", password: "user2-123", role: "role2", key: "bar", end: "bas"},
+	})
+	authSetupRoot(t, authc)
+
+	rc, cerr := integration.NewClient(t, clientv3.Config{Endpoints: clus.Client(0).Endpoints(), Username: "root", Password: "123"})
+	if cerr != nil {
+		t.Fatal(cerr)
+	}
+	defer rc.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := rc.UserGrantRoleWithTTL(ctx, "user1", "role2", -1); err != rpctypes.ErrInvalidGrantTTL {
+		t.Fatalf("expected %v, got %v", rpctypes.ErrInvalidGrantTTL, err)
+	}
+	for _, name := range []string{"user1", "user2"} {
+		if _, err := rc.UserGrantRoleWithTTL(ctx, name, "role2", 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	uresp, err := rc.UserGet(ctx, "user1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(uresp.RoleExpiries) != 1 || uresp.RoleExpiries[0].Role != "role2" || uresp.RoleExpiries[0].ExpireTime == 0 {
+		t.Fatalf("expected role2 to expire, got %+v", uresp.RoleExpiries)
+	}
+	// granting the role without ttl makes it permanent
+	if _, err = rc.UserGrantRole(ctx, "user2", "role2"); err != nil {
+		t.Fatal(err)
+	}
+
+	for {
+		if uresp, err = rc.UserGet(ctx, "user1"); err != nil {
+			t.Fatal(err)
+		}
+		if len(uresp.Roles) == 1 {
+			break
+		}
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case <-ctx.Done():
+			t.Fatalf("expected role2 to be revoked from user1, got roles %v", uresp.Roles)
+		}
+	}
+	if uresp.Roles[0] != "role1" || len(uresp.RoleExpiries) != 0 {
+		t.Fatalf("expected only role1 without expiry, got %v, %+v", uresp.Roles, uresp.RoleExpiries)
+	}
+	if uresp, err = rc.UserGet(ctx, "user2"); err != nil {
+		t.Fatal(err)
+	}
+	if len(uresp.Roles) != 1 || len(uresp.RoleExpiries) != 0 {
+		t.Fatalf("expected role2 to remain granted to user2, got %v, %+v", uresp.Roles, uresp.RoleExpiries)
+	}
+}
+
+func TestV3AuthExplain(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	authc := integration.ToGRPC(clus.Client(0)).Auth
+	authSetupUsers(t, authc, []user{{name: "user1", password: "user1-123", role: "role1", key: "foo", end: "fop"}})
+	authSetupRoot(t, authc)
+
+	rc, cerr := integration.NewClient(t, clientv3.Config{Endpoints: clus.Client(0).Endpoints(), Username: "root", Password: "123"})
+	if cerr != nil {
+		t.Fatal(cerr)
+	}
+	defer rc.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	resp, err := rc.AuthExplain(ctx, "user1", clientv3.PermissionType(clientv3.PermWrite), "foo1", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Permitted || len(resp.Roles) != 1 || !resp.Roles[0].Permitted || len(resp.Roles[0].Matching) != 1 {
+		t.Fatalf("expected put of foo1 to be permitted by role1, got %+v", resp)
+	}
+	if resp, err = rc.AuthExplain(ctx, "user1", clientv3.PermissionType(clientv3.PermRead), "bar", ""); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Permitted || len(resp.Roles[0].Matching) != 0 || len(resp.Roles[0].NonMatching) != 1 {
+		t.Fatalf("expected get of bar not to be permitted, got %+v", resp)
+	}
+	for _, typ := range []clientv3.PermissionType{clientv3.PermissionType(clientv3.PermReadWrite), 100} {
+		if _, err = rc.AuthExplain(ctx, "user1", typ, "foo1", ""); err != rpctypes.ErrInvalidAuthMgmt {
+			t.Fatalf("expected %v for permission type %v, got %v", rpctypes.ErrInvalidAuthMgmt, typ, err)
+		}
+	}
+
+	uc, cerr := integration.NewClient(t, clientv3.Config{Endpoints: clus.Client(0).Endpoints(), Username: "user1", Password: "user1-123"})
+	if cerr != nil {
+		t.Fatal(cerr)
+	}
+	defer uc.Close()
+	if _, err = uc.AuthExplain(ctx, "user1", clientv3.PermissionType(clientv3.PermRead), "foo", ""); err != rpctypes.ErrPermissionDenied {
+		t.Fatalf("expected %v, got %v", rpctypes.ErrPermissionDenied, err)
+	}
+}
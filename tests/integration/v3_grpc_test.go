@@ -153,6 +153,46 @@ func TestV3CompactCurrentRev(t *testing.T) {
 	}
 }
 
+// TestV3CompactWithRetention ensures the history of retained keys survives compaction.
+func TestV3CompactWithRetention(t *testing.T) {
+	integration.BeforeTest(t)
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	kvc := integration.ToGRPC(clus.RandClient()).KV
+	for _, key := range []string{"foo", "bar"} {
+		for i := 0; i < 3; i++ {
+			if _, err := kvc.Put(context.Background(), &pb.PutRequest{Key: []byte(key), Value: []byte(fmt.Sprintf("%s%d", key, i))}); err != nil {
+				t.Fatalf("couldn't put key (%v)", err)
+			}
+		}
+	}
+
+	creq := &pb.CompactionRequest{
+		Revision:  7,
+		Retention: []*pb.CompactionRetention{{Key: []byte("foo"), RangeEnd: []byte("fop"), Revision: 2}, {Key: []byte("fooa")}},
+	}
+	if _, err := kvc.Compact(context.Background(), creq); !eqErrGRPC(err, rpctypes.ErrGRPCInvalidRetention) {
+		t.Fatalf("expected %v, got %v", rpctypes.ErrGRPCInvalidRetention, err)
+	}
+	creq.Retention = creq.Retention[:1]
+	if _, err := kvc.Compact(context.Background(), creq); err != nil {
+		t.Fatalf("couldn't compact kv space (%v)", err)
+	}
+
+	resp, err := kvc.Range(context.Background(), &pb.RangeRequest{Key: []byte("foo"), Revision: 3})
+	if err != nil {
+		t.Fatalf("couldn't get retained key after compaction (%v)", err)
+	}
+	if len(resp.Kvs) != 1 || string(resp.Kvs[0].Value) != "foo1" {
+		t.Fatalf("kvs = %+v, want value %q", resp.Kvs, "foo1")
+	}
+	_, err = kvc.Range(context.Background(), &pb.RangeRequest{Key: []byte("bar"), Revision: 5})
+	if !eqErrGRPC(err, rpctypes.ErrGRPCCompacted) {
+		t.Fatalf("expected %v, got %v", rpctypes.ErrGRPCCompacted, err)
+	}
+}
+
 // TestV3HashKV ensures that multiple calls of HashKV on same node return same hash and compact rev.
 func TestV3HashKV(t *testing.T) {
 	integration.BeforeTest(t)
@@ -1505,6 +1545,39 @@ func TestV3RangeRequest(t *testing.T) {
 			[]bool{false, false, false, false},
 			[]int64{3, 3, 3, 3},
 		},
+		{
+			"value filter",
+			[]string{"a", "b", "c", "d", "e"},
+			[]pb.RangeRequest{
+				{
+					Key: []byte("a"), RangeEnd: []byte("z"),
+					Limit:       2,
+					ValueFilter: &pb.ValueFilter{Prefix: []byte("ba")},
+				},
+				{
+					Key: []byte("a"), RangeEnd: []byte("z"),
+					ValueFilter: &pb.ValueFilter{Prefix: []byte("foo")},
+				},
+				{
+					Key: []byte("a"), RangeEnd: []byte("z"),
+					ValueFilter: &pb.ValueFilter{Contains: []byte("ar"), MinSize: 3, MaxSize: 3},
+				},
+				{
+					Key: []byte("a"), RangeEnd: []byte("z"),
+					CountOnly:   true,
+					ValueFilter: &pb.ValueFilter{MinSize: 4},
+				},
+			},
+
+			[][]string{
+				{"a", "b"},
+				{},
+				{"a", "b", "c", "d", "e"},
+				{},
+			},
+			[]bool{true, false, false, false},
+			[]int64{5, 0, 5, 0},
+		},
 	}
 
 	for i, tt := range tests {
@@ -28,8 +28,10 @@ import (
 
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
 	"go.etcd.io/etcd/api/v3/mvccpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"go.etcd.io/etcd/server/v3/etcdserver/api/v3rpc"
+	"go.etcd.io/etcd/server/v3/storage/mvcc"
 	"go.etcd.io/etcd/tests/v3/framework/integration"
 )
 
@@ -1244,6 +1246,224 @@ func TestV3WatchWithFilter(t *testing.T) {
 	}
 }
 
+// TestV3WatchWithValueFilterAndKeyGlob ensures that watch only sends the events
+// whose keys match the key glob and whose values satisfy the value filter.
+func TestV3WatchWithValueFilterAndKeyGlob(t *testing.T) {
+	integration.BeforeTest(t)
+
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1})
+	defer clus.Terminate(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	ws, werr := integration.ToGRPC(clus.RandClient()).Watch.Watch(ctx)
+	if werr != nil {
+		t.Fatal(werr)
+	}
+
+	// invalid key glob cancels the watch on creation
+	req := &pb.WatchRequest{RequestUnion: &pb.WatchRequest_CreateRequest{
+		CreateRequest: &pb.WatchCreateRequest{Key: []byte("foo/"), RangeEnd: []byte("foo0"), KeyGlob: "foo/["}}}
+	if err := ws.Send(req); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := ws.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Created || !resp.Canceled || resp.CancelReason != rpctypes.ErrGRPCInvalidKeyGlob.Error() {
+		t.Fatalf("expected canceled watch with %q, got %+v", rpctypes.ErrGRPCInvalidKeyGlob.Error(), resp)
+	}
+
+	req = &pb.WatchRequest{RequestUnion: &pb.WatchRequest_CreateRequest{
+		CreateRequest: &pb.WatchCreateRequest{
+			Key:         []byte("foo/"),
+			RangeEnd:    []byte("foo0"),
+			KeyGlob:     "foo/*/a",
+			ValueFilter: &pb.ValueFilter{Prefix: []byte("v")},
+		}}}
+	if err = ws.Send(req); err != nil {
+		t.Fatal(err)
+	}
+	if resp, err = ws.Recv(); err != nil || !resp.Created || resp.Canceled {
+		t.Fatalf("failed to create watch (%v, %+v)", err, resp)
+	}
+
+	kvc := integration.ToGRPC(clus.RandClient()).KV
+	for _, kv := range []struct{ key, val string }{
+		{"foo/x/a", "w"},  // value filtered out
+		{"foo/x/b", "v1"}, // key filtered out
+		{"foo/x/a/b", "v"},
+		{"foo/y/a", "v2"},
+	} {
+		if _, err = kvc.Put(context.TODO(), &pb.PutRequest{Key: []byte(kv.key), Value: []byte(kv.val)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// delete events carry no value
+	if _, err = kvc.DeleteRange(context.TODO(), &pb.DeleteRangeRequest{Key: []byte("foo/y/a")}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = kvc.Put(context.TODO(), &pb.PutRequest{Key: []byte("foo/z/a"), Value: []byte("v3")}); err != nil {
+		t.Fatal(err)
+	}
+
+	var keys []string
+	for len(keys) < 2 {
+		if resp, err = ws.Recv(); err != nil {
+			t.Fatal(err)
+		}
+		for _, ev := range resp.Events {
+			keys = append(keys, string(ev.Kv.Key))
+		}
+	}
+	if wkeys := []string{"foo/y/a", "foo/z/a"}; !reflect.DeepEqual(keys, wkeys) {
+		t.Fatalf("got events on %v, expected %v", keys, wkeys)
+	}
+}
+
+// TestV3WatchResumeToken ensures that a watch resumed with a resume token
+// receives every event after the token exactly once, on a new stream.
+func TestV3WatchResumeToken(t *testing.T) {
+	integration.BeforeTest(t)
+
+	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 3})
+	defer clus.Terminate(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	kvc := integration.ToGRPC(clus.Client(0)).KV
+	txn := &pb.TxnRequest{Success: []*pb.RequestOp{
+		{Request: &pb.RequestOp_RequestPut{RequestPut: &pb.PutRequest{Key: []byte("foo1"), Value: []byte("bar")}}},
+		{Request: &pb.RequestOp_RequestPut{RequestPut: &pb.PutRequest{Key: []byte("foo2"), Value: []byte("bar")}}},
+	}}
+	tresp, err := kvc.Txn(ctx, txn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rev := tresp.Header.Revision
+	if _, err = kvc.Put(ctx, &pb.PutRequest{Key: []byte("foo3"), Value: []byte("bar")}); err != nil {
+		t.Fatal(err)
+	}
+
+	creq := &pb.WatchCreateRequest{Key: []byte("foo"), RangeEnd: []byte("fop"), StartRevision: rev}
+	tokens := v3rpc.NewResumeTokens(creq)
+	watch := func(m int, token []byte) (pb.Watch_WatchClient, *pb.WatchResponse) {
+		ws, werr := integration.ToGRPC(clus.Client(m)).Watch.Watch(ctx)
+		if werr != nil {
+			t.Fatal(werr)
+		}
+		req := *creq
+		req.ResumeToken = token
+		if werr = ws.Send(&pb.WatchRequest{RequestUnion: &pb.WatchRequest_CreateRequest{CreateRequest: &req}}); werr != nil {
+			t.Fatal(werr)
+		}
+		resp, werr := ws.Recv()
+		if werr != nil || !resp.Created {
+			t.Fatalf("failed to create watch (%v, %+v)", werr, resp)
+		}
+		return ws, resp
+	}
+	recvKeys := func(ws pb.Watch_WatchClient, n int) (keys []string, token []byte) {
+		for len(keys) < n {
+			resp, rerr := ws.Recv()
+			if rerr != nil {
+				t.Fatal(rerr)
+			}
+			for _, ev := range resp.Events {
+				keys = append(keys, string(ev.Kv.Key))
+			}
+			token = resp.ResumeToken
+		}
+		return keys, token
+	}
+
+	ws, resp := watch(0, nil)
+	if wrev, skip, derr := tokens.Decode(resp.ResumeToken); derr != nil || wrev != rev || skip != 0 {
+		t.Fatalf("expected created token at %d, got %d after %d (%v)", rev, wrev, skip, derr)
+	}
+	keys, token := recvKeys(ws, 3)
+	if wkeys := []string{"foo1", "foo2", "foo3"}; !reflect.DeepEqual(keys, wkeys) {
+		t.Fatalf("got events on %v, expected %v", keys, wkeys)
+	}
+
+	// a token of another watch is rejected
+	other := v3rpc.NewResumeTokens(&pb.WatchCreateRequest{Key: []byte("foo")}).Encode(rev, 0)
+	if _, resp = watch(1, other); !resp.Canceled || resp.CancelReason != rpctypes.ErrGRPCInvalidResumeToken.Error() {
+		t.Fatalf("expected canceled watch with %q, got %+v", rpctypes.ErrGRPCInvalidResumeToken.Error(), resp)
+	}
+
+	// resuming in the middle of a revision skips the events already sent
+	ws, _ = watch(1, tokens.Encode(rev, 1))
+	if keys, _ = recvKeys(ws, 2); !reflect.DeepEqual(keys, []string{"foo2", "foo3"}) {
+		t.Fatalf("got events on %v, expected [foo2 foo3]", keys)
+	}
+
+	// resuming on another member after the last response only sends new events
+	ws, _ = watch(2, token)
+	if _, err = kvc.Put(ctx, &pb.PutRequest{Key: []byte("foo4"), Value: []byte("bar")}); err != nil {
+		t.Fatal(err)
+	}
+	if keys, _ = recvKeys(ws, 1); !reflect.DeepEqual(keys, []string{"foo4"}) {
+		t.Fatalf("got events on %v, expected [foo4]", keys)
+	}
+}
+
+// TestV3WatchSlowConsumerDropped ensures that a watcher whose client does not
+// receive its events is canceled with a dedicated reason by the drop policy.
+func TestV3WatchSlowConsumerDropped(t *testing.T) {
+	integration.BeforeTest(t)
+
+	clus := integration.NewCluster(t, &integration.ClusterConfig{
+		Size:         1,
+		WatchBacklog: mvcc.WatchBacklogConfig{MaxStreamBytes: 1024, Policy: mvcc.SlowWatcherDrop},
+	})
+	defer clus.Terminate(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	ws, err := integration.ToGRPC(clus.RandClient()).Watch.Watch(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &pb.WatchRequest{RequestUnion: &pb.WatchRequest_CreateRequest{
+		CreateRequest: &pb.WatchCreateRequest{Key: []byte("foo")}}}
+	if err = ws.Send(req); err != nil {
+		t.Fatal(err)
+	}
+	if resp, rerr := ws.Recv(); rerr != nil || !resp.Created {
+		t.Fatalf("failed to create watch (%v, %+v)", rerr, resp)
+	}
+
+	// the events fill the stream until the watcher is blocked
+	kvc := integration.ToGRPC(clus.RandClient()).KV
+	val := bytes.Repeat([]byte("a"), 8*1024)
+	for i := 0; i < 500; i++ {
+		if _, err = kvc.Put(ctx, &pb.PutRequest{Key: []byte("foo"), Value: val}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for {
+		resp, rerr := ws.Recv()
+		if rerr != nil {
+			t.Fatal(rerr)
+		}
+		if resp.Canceled {
+			if resp.CancelReason != rpctypes.ErrorDesc(rpctypes.ErrGRPCWatchTooSlow) {
+				t.Fatalf("expected cancel reason %q, got %+v", rpctypes.ErrorDesc(rpctypes.ErrGRPCWatchTooSlow), resp)
+			}
+			return
+		}
+		if len(resp.Events) == 0 {
+			t.Fatalf("unexpected response %+v", resp)
+		}
+	}
+}
+
 func TestV3WatchWithPrevKV(t *testing.T) {
 	integration.BeforeTest(t)
 	clus := integration.NewCluster(t, &integration.ClusterConfig{Size: 1})
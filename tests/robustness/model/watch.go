@@ -25,5 +25,8 @@ type WatchResponse struct {
 	Events           []WatchEvent
 	IsProgressNotify bool
 	Revision         int64
-	Time             time.Duration
+	// ResumeRevision is the revision the resume token of the response resumes
+	// the watch at, or zero if the response has no token.
+	ResumeRevision int64
+	Time           time.Duration
 }
@@ -26,6 +26,7 @@ import (
 
 	"go.etcd.io/etcd/api/v3/mvccpb"
 	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/etcdserver/api/v3rpc"
 	"go.etcd.io/etcd/tests/v3/robustness/identity"
 	"go.etcd.io/etcd/tests/v3/robustness/model"
 )
@@ -253,6 +254,14 @@ func ToWatchResponse(r clientv3.WatchResponse, baseTime time.Time) model.WatchRe
 	}
 	resp.IsProgressNotify = r.IsProgressNotify()
 	resp.Revision = r.Header.Revision
+	if r.ResumeToken != nil {
+		rev, skip, err := v3rpc.ResumeTokenPosition(r.ResumeToken)
+		if err != nil || skip != 0 {
+			// responses delivered to the client hold whole revisions
+			panic(fmt.Sprintf("Unexpected resume token %x: skip %d, err %v", r.ResumeToken, skip, err))
+		}
+		resp.ResumeRevision = rev
+	}
 	return resp
 }
 
@@ -39,6 +39,7 @@ func validateWatch(t *testing.T, cfg Config, reports []report.ClientReport) []mo
 	for _, r := range reports {
 		validateReliable(t, eventHistory, r)
 		validateResumable(t, eventHistory, r)
+		validateResumeTokens(t, eventHistory, r)
 	}
 	return eventHistory
 }
@@ -150,6 +151,37 @@ func validateResumable(t *testing.T, events []model.WatchEvent, report report.Cl
 	}
 }
 
+func validateResumeTokens(t *testing.T, events []model.WatchEvent, report report.ClientReport) {
+	for _, op := range report.Watch {
+		var resumeRevision int64
+		for _, resp := range op.Responses {
+			if resumeRevision != 0 && len(resp.Events) > 0 {
+				// A watch resumed at the token gets the events after it exactly once, so must the watch itself.
+				index := 0
+				for index < len(events) && (events[index].Revision < resumeRevision || !events[index].Match(op.Request)) {
+					index++
+				}
+				if index < len(events) && events[index] != resp.Events[0] {
+					t.Errorf("Broke watch guarantee: Resume token - a watch resumed with the resume token of a response receives every event after the response exactly once, resumeRevision: %d, event missing: %+v, client: %d", resumeRevision, events[index], report.ClientId)
+				}
+			}
+			if resp.ResumeRevision == 0 {
+				continue
+			}
+			var wantRevision int64
+			if len(resp.Events) > 0 {
+				wantRevision = resp.Events[len(resp.Events)-1].Revision + 1
+			} else {
+				wantRevision = resp.Revision + 1
+			}
+			if resp.ResumeRevision != wantRevision || resp.ResumeRevision < resumeRevision {
+				t.Errorf("Broke watch guarantee: Resume token - a resume token resumes the watch right after its response, resumeRevision: %d, expected: %d, previousResumeRevision: %d, client: %d", resp.ResumeRevision, wantRevision, resumeRevision, report.ClientId)
+			}
+			resumeRevision = resp.ResumeRevision
+		}
+	}
+}
+
 func firstRevision(op model.WatchOperation) int64 {
 	for _, resp := range op.Responses {
 		for _, event := range resp.Events {